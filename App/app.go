@@ -5,6 +5,7 @@ import (
 	"os"
 	"tln-backend/Config"
 	"tln-backend/Database"
+	entities "tln-backend/Entities"
 	"tln-backend/Handlers"
 	"tln-backend/Repository"
 	"tln-backend/Server"
@@ -43,14 +44,24 @@ func InitializeDatabase() (*gorm.DB, error) {
 	return Database.NewDB()
 }
 
-func InitializeServer(userRepo *Repository.UserRepository, providerRepo *Repository.ProviderRepository) *Server.Server {
-	return Server.NewServer(userRepo, providerRepo)
+func InitializeServer(userRepo *Repository.UserRepository, providerRepo *Repository.ProviderRepository, staffRepo *Repository.StaffRepository, adminRepo *Repository.AdminRepository) *Server.Server {
+	return Server.NewServer(userRepo, providerRepo, staffRepo, adminRepo)
 }
 
-func InitializeHandlers(db *gorm.DB) (*Handlers.AllHandlers, *Repository.UserRepository, *Repository.ProviderRepository, error) {
+func InitializeHandlers(db *gorm.DB) (*Handlers.AllHandlers, *Repository.UserRepository, *Repository.ProviderRepository, *Repository.StaffRepository, *Repository.AdminRepository, error) {
 	hashService := Services.NewHashService()
 	paymentService := Services.NewPaymentService()
 
+	mediaStorage, err := Services.NewMediaStorage()
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	mediaRepo := Repository.NewMediaRepository(db)
+	mediaService := Services.NewMediaService()
+	mediaUseCase := Usecase.NewMediaUseCase(mediaRepo, mediaStorage, mediaService)
+	mediaHandler := Handlers.NewMediaHandler(mediaUseCase)
+	entities.MediaURLs = mediaUseCase.URLs
+
 	userRepo := Repository.NewUserRepository(db)
 	userUseCase := Usecase.NewUserUseCase(userRepo)
 	userHandler := Handlers.NewUserHandler(userUseCase)
@@ -67,18 +78,117 @@ func InitializeHandlers(db *gorm.DB) (*Handlers.AllHandlers, *Repository.UserRep
 	providerUseCase := Usecase.NewProviderUseCase(providerRepo)
 	providerHandler := Handlers.NewMarketProvider(providerUseCase)
 
+	staffRepo := Repository.NewStaffRepository(db)
+	staffUseCase := Usecase.NewStaffUseCase(staffRepo, hashService)
+	staffHandler := Handlers.NewStaffHandler(staffUseCase)
+
+	adminRepo := Repository.NewAdminRepository(db)
+	adminUseCase := Usecase.NewAdminUseCase(adminRepo, hashService)
+	if err := adminUseCase.SeedAdmin(); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	adminHandler := Handlers.NewAdminHandler(adminUseCase)
+
+	reviewRepo := Repository.NewReviewRepository(db)
+	reviewUseCase := Usecase.NewReviewUseCase(reviewRepo)
+	reviewHandler := Handlers.NewReviewHandler(reviewUseCase)
+
 	marketRepo := Repository.NewMarketRepository(db)
-	marketUseCase := Usecase.NewMarketUseCase(marketRepo)
+	searchService := Services.NewSearchService()
+	textSearchRepo := Repository.NewTextSearchRepository(db)
+	textSearchService := Services.NewTextSearchService()
+	textSearchUseCase := Usecase.NewTextSearchUseCase(textSearchRepo, textSearchService, searchService)
+	textSearchHandler := Handlers.NewTextSearchHandler(textSearchUseCase)
+	marketUseCase := Usecase.NewMarketUseCase(marketRepo, searchService, textSearchUseCase, reviewUseCase)
 	marketHandler := Handlers.NewMarketHandler(marketUseCase)
 
+	vendorProfileRepo := Repository.NewVendorProfileRepository(db)
+	vendorProfileUseCase := Usecase.NewVendorProfileUseCase(vendorProfileRepo, textSearchUseCase, reviewUseCase)
+	vendorProfileHandler := Handlers.NewVendorProfileHandler(vendorProfileUseCase)
+
+	zoneRepo := Repository.NewZoneRepository(db)
+	zoneUseCase := Usecase.NewZoneUseCase(zoneRepo)
+	zoneHandler := Handlers.NewZoneHandler(zoneUseCase)
+
+	amenityRepo := Repository.NewAmenityRepository(db)
+	amenityUseCase := Usecase.NewAmenityUseCase(amenityRepo)
+	amenityHandler := Handlers.NewAmenityHandler(amenityUseCase)
+
+	openingHoursRepo := Repository.NewOpeningHoursRepository(db)
+	openingHoursUseCase := Usecase.NewOpeningHoursUseCase(openingHoursRepo)
+	openingHoursHandler := Handlers.NewOpeningHoursHandler(openingHoursUseCase)
+
+	notificationRepo := Repository.NewNotificationRepository(db)
+	notificationUseCase := Usecase.NewNotificationUseCase(notificationRepo)
+	notificationHandler := Handlers.NewNotificationHandler(notificationUseCase)
+
+	announcementRepo := Repository.NewAnnouncementRepository(db)
+	announcementUseCase := Usecase.NewAnnouncementUseCase(announcementRepo, notificationUseCase)
+	announcementHandler := Handlers.NewAnnouncementHandler(announcementUseCase)
+
+	layoutVersionRepo := Repository.NewLayoutVersionRepository(db)
+	layoutVersionUseCase := Usecase.NewLayoutVersionUseCase(layoutVersionRepo)
+	layoutVersionHandler := Handlers.NewLayoutVersionHandler(layoutVersionUseCase)
+
 	slotRepo := Repository.NewSlotRepository(db)
-	slotUseCase := Usecase.NewSlotUseCase(slotRepo)
+	slotUseCase := Usecase.NewSlotUseCase(slotRepo, notificationUseCase, amenityUseCase, layoutVersionUseCase)
 	slotHandler := Handlers.NewSlotHandler(slotUseCase)
 
+	marketMapRepo := Repository.NewMarketMapRepository(db)
+	mapService := Services.NewMapService()
+	marketMapUseCase := Usecase.NewMarketMapUseCase(marketMapRepo, mapService)
+	marketMapHandler := Handlers.NewMarketMapHandler(marketMapUseCase)
+
+	templateRepo := Repository.NewLayoutTemplateRepository(db)
+	templateUseCase := Usecase.NewLayoutTemplateUseCase(templateRepo, layoutVersionUseCase)
+	templateHandler := Handlers.NewLayoutTemplateHandler(templateUseCase)
+
+	scheduleRepo := Repository.NewScheduleRepository(db)
+	scheduleService := Services.NewScheduleService(scheduleRepo, templateUseCase)
+	scheduleUseCase := Usecase.NewScheduleUseCase(scheduleRepo, scheduleService)
+	scheduleHandler := Handlers.NewScheduleHandler(scheduleUseCase)
+
+	ledgerRepo := Repository.NewLedgerRepository(db)
+	ledgerService := Services.NewLedgerService(ledgerRepo)
+	ledgerUseCase := Usecase.NewLedgerUseCase(ledgerRepo, ledgerService)
+	ledgerHandler := Handlers.NewLedgerHandler(ledgerUseCase)
+
+	walletRepo := Repository.NewWalletRepository(db)
+	walletService := Services.NewWalletService(walletRepo, ledgerUseCase)
+	walletUseCase := Usecase.NewWalletUseCase(walletRepo, ledgerUseCase, walletService)
+	walletHandler := Handlers.NewWalletHandler(walletUseCase)
+
+	pricingRepo := Repository.NewPricingRepository(db)
+	pricingService := Services.NewPricingService()
+	pricingUseCase := Usecase.NewPricingUseCase(pricingRepo, pricingService, amenityUseCase)
+	pricingHandler := Handlers.NewPricingHandler(pricingUseCase)
+
+	slotSearchRepo := Repository.NewSlotSearchRepository(db)
+	slotSearchUseCase := Usecase.NewSlotSearchUseCase(slotSearchRepo, searchService, pricingUseCase)
+	slotSearchHandler := Handlers.NewSlotSearchHandler(slotSearchUseCase)
+
+	promotionRepo := Repository.NewPromotionRepository(db)
+	promotionUseCase := Usecase.NewPromotionUseCase(promotionRepo, pricingUseCase)
+	promotionHandler := Handlers.NewPromotionHandler(promotionUseCase)
+
+	applicationRepo := Repository.NewVendorApplicationRepository(db)
+	applicationUseCase := Usecase.NewVendorApplicationUseCase(applicationRepo, notificationUseCase)
+	applicationHandler := Handlers.NewVendorApplicationHandler(applicationUseCase)
+
+	termsRepo := Repository.NewMarketTermsRepository(db)
+	termsUseCase := Usecase.NewMarketTermsUseCase(termsRepo)
+	termsHandler := Handlers.NewMarketTermsHandler(termsUseCase)
+
 	bookingRepo := Repository.NewBookingRepository(db)
-	bookingService := Services.NewBookingService(bookingRepo, paymentRepo, slotUseCase)
-	bookingUseCase := Usecase.NewBookingUseCase(bookingRepo, paymentRepo, paymentUseCase, bookingService, slotUseCase)
+	bookingService := Services.NewBookingService(bookingRepo, paymentRepo, slotUseCase, ledgerUseCase, walletUseCase, promotionUseCase)
+	bookingUseCase := Usecase.NewBookingUseCase(bookingRepo, paymentRepo, paymentUseCase, bookingService, slotUseCase, ledgerUseCase, walletUseCase, promotionUseCase, pricingUseCase, zoneUseCase, amenityUseCase, openingHoursUseCase, mediaUseCase, marketUseCase, applicationUseCase, termsUseCase)
 	bookingHandler := Handlers.NewBookingHandler(bookingUseCase)
+	slotUseCase.UseBookings(bookingUseCase)
+
+	slipRepo := Repository.NewSlipRepository(db)
+	slipService := Services.NewSlipService()
+	slipUseCase := Usecase.NewSlipUseCase(slipRepo, bookingRepo, slipService, bookingService, mediaUseCase)
+	slipHandler := Handlers.NewSlipHandler(slipUseCase)
 
 	dashboardRep := Repository.NewDashboardRepository(db)
 	dashboardService := Services.NewDashboardService(dashboardRep)
@@ -86,17 +196,40 @@ func InitializeHandlers(db *gorm.DB) (*Handlers.AllHandlers, *Repository.UserRep
 	dashboardHandler := Handlers.NewDashboardHandler(dashboardUseCase)
 
 	allHandlers := &Handlers.AllHandlers{
-		UserHandler:      userHandler,
-		AuthHandler:      authHandler,
-		PaymentHandler:   paymentHandler,
-		MarketProvider:   providerHandler,
-		MarketHandler:    marketHandler,
-		BookingHandler:   bookingHandler,
-		SlotHandler:      slotHandler,
-		DashboardHandler: dashboardHandler,
+		UserHandler:          userHandler,
+		AuthHandler:          authHandler,
+		PaymentHandler:       paymentHandler,
+		MarketProvider:       providerHandler,
+		MarketHandler:        marketHandler,
+		MarketMapHandler:     marketMapHandler,
+		ZoneHandler:          zoneHandler,
+		AmenityHandler:       amenityHandler,
+		OpeningHoursHandler:  openingHoursHandler,
+		BookingHandler:       bookingHandler,
+		SlotHandler:          slotHandler,
+		LayoutVersionHandler: layoutVersionHandler,
+		DashboardHandler:     dashboardHandler,
+		LedgerHandler:        ledgerHandler,
+		WalletHandler:        walletHandler,
+		PromotionHandler:     promotionHandler,
+		PricingHandler:       pricingHandler,
+		SlipHandler:          slipHandler,
+		TemplateHandler:      templateHandler,
+		ScheduleHandler:      scheduleHandler,
+		NotificationHandler:  notificationHandler,
+		SlotSearchHandler:    slotSearchHandler,
+		TextSearchHandler:    textSearchHandler,
+		VendorProfileHandler: vendorProfileHandler,
+		MediaHandler:         mediaHandler,
+		StaffHandler:         staffHandler,
+		ApplicationHandler:   applicationHandler,
+		TermsHandler:         termsHandler,
+		ReviewHandler:        reviewHandler,
+		AnnouncementHandler:  announcementHandler,
+		AdminHandler:         adminHandler,
 	}
 
-	return allHandlers, userRepo, providerRepo, nil
+	return allHandlers, userRepo, providerRepo, staffRepo, adminRepo, nil
 }
 
 func StartServer(server *Server.Server, address string) {
@@ -3,6 +3,7 @@ package Database
 import (
 	"fmt"
 	"os"
+	"strings"
 	entities "tln-backend/Entities"
 	"tln-backend/Entities/dtos"
 
@@ -28,6 +29,10 @@ func NewDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := migrateMarketCoordinates(db); err != nil {
+		return nil, err
+	}
+
 	if err := db.AutoMigrate(
 		&entities.Vendor{},
 		&entities.MarketDashboardStats{},
@@ -41,9 +46,76 @@ func NewDB() (*gorm.DB, error) {
 		&entities.Payment{},
 		&entities.MarketProvider{},
 		&entities.Transaction{},
+		&entities.LedgerAccount{},
+		&entities.JournalEntry{},
+		&entities.JournalLine{},
+		&entities.WalletCredit{},
+		&entities.WalletTransaction{},
+		&entities.Promotion{},
+		&entities.Redemption{},
+		&entities.BankSlip{},
+		&entities.LayoutTemplate{},
+		&entities.TemplateStall{},
+		&entities.MarketSchedule{},
+		&entities.ScheduleException{},
+		&entities.Holiday{},
+		&entities.LayoutVersion{},
+		&entities.LayoutVersionStall{},
+		&entities.PricingRule{},
+		&entities.Zone{},
+		&entities.ComboPrice{},
+		&entities.BookingGroup{},
+		&entities.Notification{},
+		&entities.Amenity{},
+		&entities.OpeningSession{},
+		&entities.OpeningException{},
+		&entities.VendorProfile{},
+		&entities.SearchDocument{},
+		&entities.SearchTerm{},
+		&entities.Media{},
+		&entities.MarketStatusChange{},
+		&entities.StaffMember{},
+		&entities.StaffAssignment{},
+		&entities.VendorApplication{},
+		&entities.VendorApplicationEvent{},
+		&entities.MarketTerms{},
+		&entities.TermsAcceptance{},
+		&entities.Review{},
+		&entities.ReviewReport{},
+		&entities.Announcement{},
+		&entities.AnnouncementReceipt{},
+		&entities.Admin{},
 	); err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
+
+// migrateMarketCoordinates turns the text latitude and longitude columns of markets into numbers before
+// AutoMigrate sees them. Values that are not numbers or are out of range become NULL.
+func migrateMarketCoordinates(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entities.Market{}) {
+		return nil
+	}
+	columns, err := db.Migrator().ColumnTypes(&entities.Market{})
+	if err != nil {
+		return err
+	}
+
+	limits := map[string]int{"latitude": 90, "longitude": 180}
+	for _, column := range columns {
+		limit, ok := limits[column.Name()]
+		if !ok || !strings.Contains(strings.ToLower(column.DatabaseTypeName()), "char") {
+			continue
+		}
+		convert := fmt.Sprintf(`ALTER TABLE markets ALTER COLUMN %[1]s TYPE decimal(9,6) USING CASE
+			WHEN TRIM(%[1]s) !~ '^-?[0-9]{1,3}(\.[0-9]+)?$' THEN NULL
+			WHEN ABS(TRIM(%[1]s)::numeric) > %[2]d THEN NULL
+			ELSE ROUND(TRIM(%[1]s)::numeric, 6) END`, column.Name(), limit)
+		if err := db.Exec(convert).Error; err != nil {
+			return fmt.Errorf("failed to convert markets.%s to a number: %w", column.Name(), err)
+		}
+	}
+	return nil
+}
@@ -25,5 +25,6 @@ type SlotNameSummary struct {
 }
 
 type DashboardResponse struct {
-	Stats []MarketDashboardStats `json:"stats"` // Changed to slice
+	Stats        []MarketDashboardStats `json:"stats"`         // Changed to slice
+	MarketStatus MarketStatus           `json:"market_status"` // Archived markets show their stats as they were left
 }
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Admin is a platform operator. Admins settle and adjust the ledger, keep the holiday calendar, rebuild the
+// search index, suspend markets and moderate reviews. The first one is created from ADMIN_USERNAME,
+// ADMIN_EMAIL and ADMIN_PASSWORD at startup.
+type Admin struct {
+	ID        string    `gorm:"primaryKey;column:id" json:"id"`
+	Username  string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"username"`
+	Email     string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"email"`
+	Password  string    `gorm:"type:varchar(255);not null" json:"-"`
+	Disabled  bool      `gorm:"not null;default:false" json:"disabled"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package entities
+
+import "time"
+
+// Amenity is something a market offers at its stalls. Features such as electricity or shade come with the slots
+// they are assigned to; add-ons are extras a vendor picks and pays for when booking.
+type Amenity struct {
+	ID          string      `gorm:"primaryKey;column:id" json:"id"`
+	MarketID    string      `gorm:"type:varchar(36);not null;uniqueIndex:idx_market_amenity_name" json:"market_id"`
+	Name        string      `gorm:"type:varchar(100);not null;uniqueIndex:idx_market_amenity_name" json:"name"`
+	Kind        AmenityKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Description string      `gorm:"type:text" json:"description"`
+	Price       float64     `gorm:"type:decimal(10,2);not null;default:0" json:"price"` // Per unit, add-ons only
+	MaxQuantity int         `gorm:"type:int;not null;default:0" json:"max_quantity"`    // Units one booking may take, add-ons only
+	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type AmenityKind string
+
+const (
+	AmenityFeature AmenityKind = "feature"
+	AmenityAddOn   AmenityKind = "addon"
+)
+
+// AddOn is an add-on as it was priced into a booking, kept on the booking so later price changes do not alter it.
+type AddOn struct {
+	AmenityID string  `json:"amenity_id"`
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+}
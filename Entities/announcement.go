@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// Announcement is a message from a market to a group of its vendors, such as a changed unloading gate or a
+// rain plan for a market day.
+type Announcement struct {
+	ID             string               `gorm:"primaryKey;column:id" json:"id"`
+	MarketID       string               `gorm:"type:varchar(36);not null;index" json:"market_id"`
+	Title          string               `gorm:"type:varchar(200);not null" json:"title"`
+	Message        string               `gorm:"type:text;not null" json:"message"`
+	Audience       AnnouncementAudience `gorm:"type:varchar(20);not null" json:"audience"`
+	MarketDate     *time.Time           `gorm:"type:date;index" json:"market_date,omitempty"` // The market day it is about; optional for the roster
+	ZoneID         string               `gorm:"type:varchar(36)" json:"zone_id,omitempty"`    // Set for the zone audience
+	Category       Category             `gorm:"type:varchar(50)" json:"category,omitempty"`   // Set for the category audience
+	SentBy         string               `gorm:"type:varchar(36);not null" json:"sent_by"`     // The provider or staff member
+	RecipientCount int                  `gorm:"not null;default:0" json:"recipient_count"`    // Vendors notified when it was sent
+	ReadCount      int                  `gorm:"-" json:"read_count,omitempty"`                // Filled for the market
+	ReadAt         *time.Time           `gorm:"-" json:"read_at,omitempty"`                   // Filled for a vendor, nil while unread
+	CreatedAt      time.Time            `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// AnnouncementAudience is who an announcement goes to.
+type AnnouncementAudience string
+
+const (
+	AudienceDate     AnnouncementAudience = "date"     // Every vendor booked on the market day
+	AudienceZone     AnnouncementAudience = "zone"     // Vendors booked in one zone on the market day
+	AudienceCategory AnnouncementAudience = "category" // Vendors booked in stalls of one category on the market day
+	AudienceRoster   AnnouncementAudience = "roster"   // Every vendor on the market's approved roster
+)
+
+// AnnouncementReceipt records that an announcement reached a vendor, and when the vendor read it. Vendors
+// who book after an announcement was sent get one when they first read it.
+type AnnouncementReceipt struct {
+	ID             string     `gorm:"primaryKey;column:id" json:"id"`
+	AnnouncementID string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_announcement_receipt_vendor" json:"announcement_id"`
+	VendorID       string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_announcement_receipt_vendor;index" json:"vendor_id"`
+	Vendor         *Vendor    `gorm:"foreignKey:VendorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"vendor,omitempty"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -17,8 +17,19 @@ type Booking struct {
 	Price       float64       `gorm:"type:decimal(10,2);not null" json:"price"`
 	Payment     *Payment      `gorm:"foreignKey:BookingID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"payment"`
 	CreatedAt   time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt   time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP"json:"updated_at"`
+	UpdatedAt   time.Time     `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	ExpiresAt   time.Time     `gorm:"type:timestamp;not null" json:"expires_at"`
+	GroupID     string        `gorm:"type:varchar(36);index" json:"group_id,omitempty"`
+	AddOns      []AddOn       `gorm:"type:text;serializer:json" json:"add_ons,omitempty"`
+	Group       *BookingGroup `gorm:"-" json:"group,omitempty"` // Filled on lead bookings when listing a market's bookings
+
+	// Set when the vendor is checked in at the market on the booking date, by the provider or a staff member.
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInBy string     `gorm:"type:varchar(36)" json:"checked_in_by,omitempty"`
+
+	// The vendor's acceptance of the market's terms in force when the booking was made, empty when the market
+	// had none.
+	TermsAcceptanceID string `gorm:"type:varchar(36);index" json:"terms_acceptance_id,omitempty"`
 }
 type BookingStatus string
 
@@ -33,4 +44,5 @@ type Method string
 
 const (
 	MethodPromptPay Method = "PromptPay"
+	MethodWallet    Method = "Wallet"
 )
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// BookingGroup ties together the bookings of adjacent stalls a vendor reserved as one space. The lead booking
+// carries the group's price and payment; the other bookings only hold their slots, and the group is cancelled
+// as a unit through the lead.
+type BookingGroup struct {
+	ID            string    `gorm:"primaryKey;column:id" json:"id"`
+	MarketID      string    `gorm:"type:varchar(36);not null;index" json:"market_id"`
+	VendorID      string    `gorm:"type:varchar(36);not null" json:"vendor_id"`
+	LeadBookingID string    `gorm:"type:varchar(36);not null;uniqueIndex" json:"lead_booking_id"`
+	SlotIDs       []string  `gorm:"type:text;serializer:json" json:"slot_ids"`
+	Date          string    `gorm:"type:date;not null" json:"date"`
+	ListPrice     float64   `gorm:"type:decimal(10,2);not null" json:"list_price"` // Sum of the stalls' own prices
+	Price         float64   `gorm:"type:decimal(10,2);not null" json:"price"`      // Charged before promotions and credit
+	ComboID       string    `gorm:"type:varchar(36)" json:"combo_id,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
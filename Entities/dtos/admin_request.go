@@ -0,0 +1,6 @@
+package dtos
+
+type AdminLoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
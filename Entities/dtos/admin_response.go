@@ -0,0 +1,6 @@
+package dtos
+
+type AdminLoginResponse struct {
+	AccessToken string `json:"access_token"`
+	AdminID     string `json:"admin_id"`
+}
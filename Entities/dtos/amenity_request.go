@@ -0,0 +1,17 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type AmenityRequest struct {
+	Name        string               `json:"name" validate:"required"`                     // Required, unique within the market
+	Kind        entities.AmenityKind `json:"kind" validate:"required,oneof=feature addon"` // Required
+	Description string               `json:"description,omitempty"`                        // Optional
+	Price       float64              `json:"price,omitempty"`                              // Add-ons only, price per unit
+	MaxQuantity int                  `json:"max_quantity,omitempty"`                       // Add-ons only, units one booking may take, defaults to 1
+}
+
+// AddOnChoice is an add-on a vendor picks when booking.
+type AddOnChoice struct {
+	AmenityID string `json:"amenity_id"`         // Required
+	Quantity  int    `json:"quantity,omitempty"` // Optional, defaults to 1
+}
@@ -0,0 +1,12 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type AnnouncementRequest struct {
+	Title    string                        `json:"title" validate:"required"`    // Required, at most 200 characters
+	Message  string                        `json:"message" validate:"required"`  // Required, at most 2000 characters
+	Audience entities.AnnouncementAudience `json:"audience" validate:"required"` // Required, date, zone, category or roster
+	Date     string                        `json:"date,omitempty"`               // Required unless the audience is roster, the market day in YYYY-MM-DD
+	ZoneID   string                        `json:"zone_id,omitempty"`            // Required for the zone audience
+	Category entities.Category             `json:"category,omitempty"`           // Required for the category audience
+}
@@ -4,14 +4,36 @@ import entities "tln-backend/Entities"
 
 type BookingRequest struct {
 	SlotID      string          `gorm:"uniqueIndex;not null" json:"slot_id"`
-	VendorID    string          `json:"vendor_id" validate:"required,uuid"` // Required, selected by the user
+	VendorID    string          `json:"-"` // The signed-in vendor
 	BookingDate string          `json:"booking_date" validate:"required,datetime=2006-01-02"`
-	Price       float64         `json:"price" validate:"required,gt=0"`
+	Price       float64         `json:"price"` // Ignored, the slot's own price is charged
 	Method      entities.Method `json:"method" validate:"required,oneof=PromptPay"`
 	MarketID    string          `json:"market_id" validate:"required,uuid"` // Required, selected by the user
+	UseCredit   bool            `json:"use_credit,omitempty"`               // Optional: pay as much as possible from wallet credit
+	PromoCode   string          `json:"promo_code,omitempty"`               // Optional: discount code of the market's provider
+	AddOns      []AddOnChoice   `json:"add_ons,omitempty"`                  // Optional: the market's add-ons, charged on top of the slot
+}
+
+type GroupBookingRequest struct {
+	SlotIDs     []string        `json:"slot_ids" validate:"required,min=2"`                   // Required, adjacent slots of one market day
+	VendorID    string          `json:"-"`                                                    // The signed-in vendor
+	BookingDate string          `json:"booking_date" validate:"required,datetime=2006-01-02"` // Required
+	Method      entities.Method `json:"method" validate:"required,oneof=PromptPay"`           // Required
+	MarketID    string          `json:"market_id" validate:"required,uuid"`                   // Required, selected by the user
+	UseCredit   bool            `json:"use_credit,omitempty"`                                 // Optional: pay as much as possible from wallet credit
+	PromoCode   string          `json:"promo_code,omitempty"`                                 // Optional: discount code, applied to the group's price
+	AddOns      []AddOnChoice   `json:"add_ons,omitempty"`                                    // Optional: the market's add-ons, charged on top of the group
 }
 
 type CancelBookingRequest struct {
 	BookingID string `json:"booking_id" validate:"required"` // The ID of the booking to be canceled.
-	VendorID  string `json:"user_id,omitempty"`              // Optional: The ID of the user requesting the cancellation.
+	VendorID  string `json:"-"`                              // The signed-in vendor, who must own the booking.
+	// Optional: refund a paid booking as wallet credit instead of a bank refund.
+	RefundToWallet bool `json:"refund_to_wallet,omitempty"`
+}
+
+type CancelMarketDayRequest struct {
+	MarketID       string `json:"market_id" validate:"required,uuid"`
+	Date           string `json:"date" validate:"required,datetime=2006-01-02"`
+	RefundToWallet bool   `json:"refund_to_wallet,omitempty"`
 }
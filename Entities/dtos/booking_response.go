@@ -12,10 +12,21 @@ type BookingResponse struct {
 	TransactionID string                 `json:"transactionId"`
 	BookingDate   time.Time              `json:"bookingDate"`
 	Price         float64                `json:"price"`
+	Discount      float64                `json:"discount,omitempty"`
+	CreditUsed    float64                `json:"creditUsed,omitempty"`
+	AmountDue     float64                `json:"amountDue"`
 	Status        entities.BookingStatus `json:"status"`
 	Method        entities.Method        `json:"method"`
 	Image         string                 `json:"image,omitempty"`
+	ImageURL      string                 `json:"imageUrl,omitempty"` // Signed link to the payment QR
 	ExpiresAt     time.Time              `json:"expiresAt"`
+	GroupID       string                 `json:"groupId,omitempty"` // Set on combined bookings
+	SlotIDs       []string               `json:"slotIds,omitempty"` // Every slot a combined booking holds
+	AddOns        []entities.AddOn       `json:"addOns,omitempty"`
+
+	// The vendor's acceptance of the market's terms this booking was made under, for dispute resolution
+	TermsAcceptanceID string `json:"termsAcceptanceId,omitempty"`
+	TermsVersion      int    `json:"termsVersion,omitempty"`
 }
 
 type TransactionResponse struct {
@@ -0,0 +1,36 @@
+package dtos
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+// DaySheetRow is one stall of a market day, with its booking when it has one.
+type DaySheetRow struct {
+	SlotID     string                 `json:"slot_id"`
+	SlotName   string                 `json:"slot_name"`
+	Zone       string                 `json:"zone"`
+	Category   entities.Category      `json:"category"`
+	Amenities  []string               `json:"amenities"` // Names of the stall's features
+	BookingID  string                 `json:"booking_id,omitempty"`
+	GroupID    string                 `json:"group_id,omitempty"`
+	VendorID   string                 `json:"vendor_id,omitempty"`
+	VendorName string                 `json:"vendor_name,omitempty"`
+	Phone      string                 `json:"phone,omitempty"`
+	Status     entities.BookingStatus `json:"status,omitempty"`
+	Price      float64                `json:"price,omitempty"`
+	AddOns     []entities.AddOn       `json:"add_ons,omitempty"`
+	CheckedIn  *time.Time             `json:"checked_in_at,omitempty"` // When the vendor arrived, once checked in
+}
+
+// DaySheet is what a provider needs on the ground for one market day: who is in which stall and which add-ons
+// to hand out.
+type DaySheet struct {
+	MarketID    string         `json:"market_id"`
+	Date        string         `json:"date"`
+	Booked      int            `json:"booked"`
+	Free        int            `json:"free"`
+	CheckedIn   int            `json:"checked_in"`
+	AddOnTotals map[string]int `json:"add_on_totals"` // Units of each add-on booked for the day, by name
+	Rows        []DaySheetRow  `json:"rows"`
+}
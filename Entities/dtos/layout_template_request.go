@@ -0,0 +1,19 @@
+package dtos
+
+type TemplateZone struct {
+	ZoneID string  `json:"zone_id"`
+	Zone   string  `json:"zone,omitempty"` // Optional, looked up by name when zone_id is not given
+	Stalls []Stall `json:"stalls" validate:"required,dive"`
+}
+
+type LayoutTemplateRequest struct {
+	Name        string         `json:"name" validate:"required"`
+	Description string         `json:"description,omitempty"`
+	Layout      []TemplateZone `json:"layout" validate:"required,dive"`
+}
+
+type ApplyTemplateRequest struct {
+	DateRange DateRange `json:"date_range" validate:"required"`
+	// Optional: only stamp these weekdays inside the range, 0 = Sunday ... 6 = Saturday
+	Weekdays []int `json:"weekdays,omitempty" validate:"omitempty,dive,min=0,max=6"`
+}
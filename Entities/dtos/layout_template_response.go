@@ -0,0 +1,10 @@
+package dtos
+
+type TemplateApplyResponse struct {
+	TemplateID string   `json:"template_id"`
+	Dates      []string `json:"dates"`
+	Created    []string `json:"created"`
+	Updated    []string `json:"updated"`
+	Skipped    []string `json:"skipped"`           // Slots already matching the template, or booked and left untouched
+	Removed    []string `json:"removed,omitempty"` // Unused slots taken off dates a schedule no longer opens
+}
@@ -0,0 +1,48 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type StallShape struct {
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+	Price    float64           `json:"price"`
+	Category entities.Category `json:"category"`
+	entities.SlotGeometry
+}
+
+type StallChange struct {
+	SlotID string      `json:"slot_id"`
+	Date   string      `json:"date"`
+	Zone   string      `json:"zone"`
+	Name   string      `json:"name"`
+	Before *StallShape `json:"before,omitempty"`
+	After  *StallShape `json:"after,omitempty"`
+	Fields []string    `json:"fields,omitempty"` // Which of width, height, price, category, position, rotation and shape changed
+}
+
+type AffectedBooking struct {
+	BookingID string                 `json:"booking_id"`
+	SlotID    string                 `json:"slot_id"`
+	VendorID  string                 `json:"vendor_id"`
+	Status    entities.BookingStatus `json:"status"`
+	PaidPrice float64                `json:"paid_price"`
+	NewPrice  float64                `json:"new_price,omitempty"` // Set when the stall's price changes
+	Reason    string                 `json:"reason"`              // "removed" or "price_changed"
+}
+
+type LayoutDiff struct {
+	MarketID         string            `json:"market_id"`
+	Scopes           []string          `json:"scopes"` // The date/zone pairs the change replaces
+	Added            []StallChange     `json:"added"`
+	Removed          []StallChange     `json:"removed"`
+	Modified         []StallChange     `json:"modified"`
+	Unchanged        int               `json:"unchanged"`
+	AffectedBookings []AffectedBooking `json:"affected_bookings"`
+	CanApply         bool              `json:"can_apply"` // False while a stall to be removed still has an active booking
+}
+
+type LayoutApplyResponse struct {
+	Version *entities.LayoutVersion `json:"version"`
+	Diff    *LayoutDiff             `json:"diff"`
+	Slots   []*entities.Slot        `json:"slots"`
+}
@@ -0,0 +1,21 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type SettlementRequest struct {
+	ProviderID  string  `json:"provider_id" validate:"required,uuid"` // Required, provider being paid out
+	Amount      float64 `json:"amount" validate:"required,gt=0"`      // Required, amount transferred to the provider's bank
+	Description string  `json:"description,omitempty"`                // Optional, bank reference or note
+}
+
+type AdjustmentLine struct {
+	AccountType entities.LedgerAccountType `json:"account_type" validate:"required,oneof=vendor provider platform gateway_clearing"`
+	OwnerID     string                     `json:"owner_id" validate:"required"`
+	Debit       float64                    `json:"debit,omitempty"`
+	Credit      float64                    `json:"credit,omitempty"`
+}
+
+type AdjustmentRequest struct {
+	Description string           `json:"description" validate:"required"`
+	Lines       []AdjustmentLine `json:"lines" validate:"required,min=2,dive"`
+}
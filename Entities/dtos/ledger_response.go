@@ -0,0 +1,22 @@
+package dtos
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type AccountBalanceResponse struct {
+	Account     entities.LedgerAccount `json:"account"`
+	TotalDebit  float64                `json:"total_debit"`
+	TotalCredit float64                `json:"total_credit"`
+	Balance     float64                `json:"balance"` // Signed by the account's normal side
+}
+
+type LedgerInvariantReport struct {
+	CheckedAt         time.Time `json:"checked_at"`
+	EntryCount        int64     `json:"entry_count"`
+	TotalDebit        float64   `json:"total_debit"`
+	TotalCredit       float64   `json:"total_credit"`
+	UnbalancedEntries []string  `json:"unbalanced_entries"`
+	Balanced          bool      `json:"balanced"`
+}
@@ -0,0 +1,35 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type MapAvailability string
+
+const (
+	MapAvailable   MapAvailability = "available"
+	MapHeld        MapAvailability = "held" // A pending booking is waiting for payment
+	MapBooked      MapAvailability = "booked"
+	MapUnavailable MapAvailability = "unavailable"
+)
+
+type MapSlot struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Zone         string            `json:"zone"`
+	Category     entities.Category `json:"category"`
+	Price        float64           `json:"price"`
+	Availability MapAvailability   `json:"availability"`
+	Width        int               `json:"width"`
+	Height       int               `json:"height"`
+	entities.SlotGeometry
+	Placed bool   `json:"placed"` // False when the stall has no position yet and was laid out automatically
+	Fill   string `json:"fill"`   // Colour the SVG map uses for the stall
+}
+
+type MarketMap struct {
+	MarketID   string    `json:"market_id"`
+	MarketName string    `json:"market_name"`
+	Date       string    `json:"date"`
+	Width      float64   `json:"width"`
+	Height     float64   `json:"height"`
+	Slots      []MapSlot `json:"slots"`
+}
@@ -0,0 +1,43 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// NearbyMarketQuery is a "markets near me" search as it arrives in the query string.
+type NearbyMarketQuery struct {
+	Lat       string // Required
+	Lng       string // Required
+	RadiusKm  string // Defaults to 10 km
+	Date      string // Optional, YYYY-MM-DD, only markets open that day
+	Available string // Optional, true for only markets with a free slot
+	Category  string // Optional, only markets with slots of this category
+	Page      string
+	PageSize  string
+}
+
+// GeoArea is a circle around a point. The bounding box narrows a query before distances are measured;
+// MinLng is above MaxLng when the box crosses the antimeridian.
+type GeoArea struct {
+	Lat      float64
+	Lng      float64
+	RadiusKm float64
+	MinLat   float64
+	MaxLat   float64
+	MinLng   float64
+	MaxLng   float64
+}
+
+// NearbyMarketFilter is a validated NearbyMarketQuery.
+type NearbyMarketFilter struct {
+	GeoArea
+	Date      string
+	Available bool
+	Category  entities.Category
+	Page      int
+	PageSize  int
+}
+
+// MarketDistance is a market's distance from the search point.
+type MarketDistance struct {
+	ID         string
+	DistanceKm float64
+}
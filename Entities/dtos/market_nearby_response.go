@@ -0,0 +1,22 @@
+package dtos
+
+type NearbyMarket struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Address    string  `json:"address"`
+	Phone      string  `json:"phone"`
+	Image      string  `json:"image"`
+	OpenTime   string  `json:"open_time"`
+	CloseTime  string  `json:"close_time"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// NearbyMarketResponse is one page of markets, nearest first.
+type NearbyMarketResponse struct {
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Total    int            `json:"total"`
+	Markets  []NearbyMarket `json:"markets"`
+}
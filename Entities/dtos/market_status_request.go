@@ -0,0 +1,8 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type MarketStatusRequest struct {
+	Status entities.MarketStatus `json:"status" validate:"required"` // Required, published, suspended, archived or draft
+	Reason string                `json:"reason,omitempty"`           // Required when suspending, shown in the market's history
+}
@@ -0,0 +1,10 @@
+package dtos
+
+type MarketTermsRequest struct {
+	Title string `json:"title,omitempty"`          // Optional, at most 200 characters
+	Body  string `json:"body" validate:"required"` // Required, the full terms vendors agree to
+}
+
+type TermsAcceptRequest struct {
+	Version int `json:"version" validate:"required"` // Required, the version the vendor read; must still be the current one
+}
@@ -0,0 +1,10 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// MarketTermsResponse is a market's current terms, with the signed-in vendor's acceptance of them if any.
+type MarketTermsResponse struct {
+	Terms      *entities.MarketTerms     `json:"terms"`
+	Accepted   bool                      `json:"accepted"`
+	Acceptance *entities.TermsAcceptance `json:"acceptance,omitempty"`
+}
@@ -0,0 +1,23 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// OpeningHoursRequest replaces a market's usual week.
+type OpeningHoursRequest struct {
+	Timezone string                  `json:"timezone,omitempty"` // Optional, IANA name such as Asia/Bangkok; kept when empty
+	Sessions []OpeningSessionRequest `json:"sessions"`           // Required, an empty list clears the weekly hours
+}
+
+type OpeningSessionRequest struct {
+	Weekday int    `json:"weekday"` // Required, 0 = Sunday ... 6 = Saturday
+	Opens   string `json:"opens"`   // Required, HH:MM
+	Closes  string `json:"closes"`  // Required, HH:MM, before opens for a session past midnight
+}
+
+// OpeningExceptionRequest sets a market's hours on one date, replacing any exception already on it.
+type OpeningExceptionRequest struct {
+	Date     string                 `json:"date"`               // Required, YYYY-MM-DD
+	Kind     entities.ExceptionKind `json:"kind"`               // Required, closed or open
+	Reason   string                 `json:"reason,omitempty"`   // Optional, e.g. the holiday's name
+	Sessions []entities.TimeRange   `json:"sessions,omitempty"` // Required for open, not allowed for closed
+}
@@ -0,0 +1,37 @@
+package dtos
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type OpeningHoursResponse struct {
+	MarketID   string                      `json:"market_id"`
+	Timezone   string                      `json:"timezone"`
+	Sessions   []entities.OpeningSession   `json:"sessions"`
+	Exceptions []entities.OpeningException `json:"exceptions"` // From today on
+}
+
+// OpenDay is a date the market opens, with each session as local times and as instants.
+type OpenDay struct {
+	Date     string        `json:"date"`
+	Weekday  int           `json:"weekday"`
+	Sessions []OpenSession `json:"sessions"`
+	Special  bool          `json:"special"`          // Opened by an exception rather than the usual week
+	Reason   string        `json:"reason,omitempty"` // The exception's reason
+}
+
+type OpenSession struct {
+	Opens    string    `json:"opens"`
+	Closes   string    `json:"closes"`
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type OpenDaysResponse struct {
+	MarketID string    `json:"market_id"`
+	Timezone string    `json:"timezone"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Days     []OpenDay `json:"days"`
+}
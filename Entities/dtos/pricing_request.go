@@ -0,0 +1,25 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type PricingRuleRequest struct {
+	Name               string                   `json:"name" validate:"required"`                                                          // Required
+	Kind               entities.PricingRuleKind `json:"kind" validate:"required,oneof=base weekday holiday early_bird last_minute demand"` // Required
+	Zone               string                   `json:"zone,omitempty"`                                                                    // Optional, limit to one zone
+	Category           entities.Category        `json:"category,omitempty"`                                                                // Optional, limit to one slot category
+	Weekdays           []int                    `json:"weekdays,omitempty"`                                                                // Required for weekday rules
+	Price              float64                  `json:"price,omitempty"`                                                                   // Required for base rules
+	Multiplier         float64                  `json:"multiplier,omitempty"`                                                              // Optional, 0 is read as 1
+	Amount             float64                  `json:"amount,omitempty"`                                                                  // Optional, baht added after the multiplier
+	DaysBefore         int                      `json:"days_before,omitempty"`                                                             // Required for early bird and last minute rules
+	OccupancyThreshold float64                  `json:"occupancy_threshold,omitempty"`                                                     // Required for demand rules
+	Priority           int                      `json:"priority,omitempty"`                                                                // Optional
+	Active             *bool                    `json:"active,omitempty"`                                                                  // Optional, defaults to true
+}
+
+type ComboPriceRequest struct {
+	Name   string  `json:"name" validate:"required"`         // Required
+	Zone   string  `json:"zone,omitempty"`                   // Optional, limit to groups inside one zone
+	Stalls int     `json:"stalls" validate:"required,min=2"` // Required, how many stalls the group has
+	Price  float64 `json:"price" validate:"gte=0"`           // Required, charged for the whole group
+}
@@ -0,0 +1,39 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// PriceStep is one line of a quote's trace: a rule the engine looked at and what it did to the price.
+type PriceStep struct {
+	RuleID  string                   `json:"rule_id,omitempty"`
+	Name    string                   `json:"name"`
+	Kind    entities.PricingRuleKind `json:"kind"`
+	Applied bool                     `json:"applied"`
+	Reason  string                   `json:"reason"`
+	Before  float64                  `json:"before"`
+	After   float64                  `json:"after"`
+}
+
+type PriceQuote struct {
+	SlotID    string      `json:"slot_id"`
+	MarketID  string      `json:"market_id"`
+	Date      string      `json:"date"`
+	SlotPrice float64     `json:"slot_price"` // The price stored on the slot, used when no base rule matches
+	Price     float64     `json:"price"`
+	LeadDays  int         `json:"lead_days"` // Days between the quote and the market date
+	Occupancy float64     `json:"occupancy"` // Share of the day's slots already taken
+	Holiday   string      `json:"holiday,omitempty"`
+	Trace     []PriceStep `json:"trace"`
+
+	AddOns     []entities.AddOn `json:"add_ons,omitempty"`
+	AddOnTotal float64          `json:"add_on_total"`
+	Total      float64          `json:"total"` // Price plus add-ons
+}
+
+// GroupQuote prices adjacent stalls booked together: each stall through the rules, then a combo price if one fits.
+type GroupQuote struct {
+	Slots     []PriceQuote `json:"slots"`
+	ListPrice float64      `json:"list_price"` // Sum of the stalls' quoted prices
+	ComboID   string       `json:"combo_id,omitempty"`
+	ComboName string       `json:"combo_name,omitempty"`
+	Price     float64      `json:"price"`
+}
@@ -0,0 +1,34 @@
+package dtos
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type PromotionRequest struct {
+	Code             string                `json:"code" validate:"required"`                                      // Required, what vendors type at checkout
+	Description      string                `json:"description,omitempty"`                                         // Optional
+	DiscountType     entities.DiscountType `json:"discount_type" validate:"required,oneof=percent fixed"`         // Required
+	DiscountValue    float64               `json:"discount_value" validate:"required,gt=0"`                       // Required, percent or baht
+	MaxDiscount      float64               `json:"max_discount,omitempty"`                                        // Optional, caps percentage discounts
+	MarketID         string                `json:"market_id,omitempty"`                                           // Optional, limit to one market
+	ZoneID           string                `json:"zone_id,omitempty"`                                             // Optional, limit to one zone, and so to its market
+	Category         entities.Category     `json:"category,omitempty"`                                            // Optional, limit to one slot category
+	StartDate        string                `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"` // Optional, first booking date
+	EndDate          string                `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`   // Optional, last booking date
+	ValidFrom        *time.Time            `json:"valid_from,omitempty"`                                          // Optional, code usable from
+	ValidUntil       *time.Time            `json:"valid_until,omitempty"`                                         // Optional, code usable until
+	UsageLimit       int                   `json:"usage_limit,omitempty"`                                         // Optional, total uses
+	PerVendorLimit   int                   `json:"per_vendor_limit,omitempty"`                                    // Optional, uses per vendor
+	FirstBookingOnly bool                  `json:"first_booking_only,omitempty"`                                  // Optional, only for a vendor's first booking with the provider
+}
+
+type UpdatePromotionStatusRequest struct {
+	Status entities.PromoStatus `json:"status" validate:"required,oneof=active disabled"`
+}
+
+type PromotionQuoteRequest struct {
+	Code     string `json:"code" validate:"required"`
+	SlotID   string `json:"slot_id" validate:"required"`
+	VendorID string `json:"-"` // The signed-in vendor
+}
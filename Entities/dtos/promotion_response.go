@@ -0,0 +1,9 @@
+package dtos
+
+type PromotionQuoteResponse struct {
+	Code       string  `json:"code"`
+	SlotID     string  `json:"slot_id"`
+	ListPrice  float64 `json:"list_price"`
+	Discount   float64 `json:"discount"`
+	FinalPrice float64 `json:"final_price"`
+}
@@ -0,0 +1,36 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// MarketReviewRequest is a vendor's review of the market it booked at.
+type MarketReviewRequest struct {
+	Rating      int    `json:"rating" validate:"required,min=1,max=5"`                  // Required, 1 to 5
+	FootTraffic int    `json:"foot_traffic,omitempty" validate:"omitempty,min=1,max=5"` // Optional, 1 to 5
+	Comment     string `json:"comment,omitempty"`                                       // Optional, at most 1000 characters
+}
+
+// VendorReviewRequest is a market's rating of a vendor that booked there.
+type VendorReviewRequest struct {
+	Rating      int    `json:"rating" validate:"required,min=1,max=5"`      // Required, 1 to 5
+	Punctuality int    `json:"punctuality" validate:"required,min=1,max=5"` // Required, 1 to 5
+	Cleanliness int    `json:"cleanliness" validate:"required,min=1,max=5"` // Required, 1 to 5
+	Comment     string `json:"comment,omitempty"`                           // Optional, at most 1000 characters
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply" validate:"required"` // Required, at most 1000 characters; replaces any earlier reply
+}
+
+type ReviewReportRequest struct {
+	Reason string `json:"reason" validate:"required"` // Required, at most 500 characters
+}
+
+type ReportResolveRequest struct {
+	Action string `json:"action" validate:"required"` // Required, hide to take the review down or dismiss to keep it
+	Note   string `json:"note,omitempty"`             // Optional, why the review was hidden
+}
+
+type ReviewModerateRequest struct {
+	Status entities.ReviewStatus `json:"status" validate:"required"` // Required, visible or hidden
+	Note   string                `json:"note,omitempty"`             // Optional, why the review was hidden
+}
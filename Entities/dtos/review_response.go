@@ -0,0 +1,9 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// ReviewListResponse is the visible reviews of a market or a vendor, newest first, with their average.
+type ReviewListResponse struct {
+	Summary *entities.RatingSummary `json:"summary"`
+	Reviews []entities.Review       `json:"reviews"`
+}
@@ -0,0 +1,27 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type ScheduleRequest struct {
+	TemplateID   string                     `json:"template_id" validate:"required,uuid"`                        // Required, the market's default layout
+	Frequency    entities.ScheduleFrequency `json:"frequency" validate:"required,oneof=weekly monthly"`          // Required
+	Interval     int                        `json:"interval,omitempty"`                                          // Optional, weekly only: every N weeks
+	Weekdays     []int                      `json:"weekdays" validate:"required,dive,min=0,max=6"`               // Required, 0 = Sunday ... 6 = Saturday
+	MonthWeeks   []int                      `json:"month_weeks,omitempty"`                                       // Monthly only: 1-5 or -1 for last
+	StartDate    string                     `json:"start_date" validate:"required,datetime=2006-01-02"`          // Required
+	EndDate      string                     `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"` // Optional
+	WeeksAhead   int                        `json:"weeks_ahead,omitempty"`                                       // Optional, how far ahead to generate slots
+	SkipHolidays *bool                      `json:"skip_holidays,omitempty"`                                     // Optional, defaults to true
+	Active       *bool                      `json:"active,omitempty"`                                            // Optional, defaults to true
+}
+
+type ScheduleExceptionRequest struct {
+	Date   string                 `json:"date" validate:"required,datetime=2006-01-02"`
+	Kind   entities.ExceptionKind `json:"kind" validate:"required,oneof=closed open"`
+	Reason string                 `json:"reason,omitempty"`
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name string `json:"name" validate:"required"`
+}
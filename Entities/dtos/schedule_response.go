@@ -0,0 +1,8 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type ScheduleResponse struct {
+	Schedule      *entities.MarketSchedule `json:"schedule"`
+	UpcomingDates []string                 `json:"upcoming_dates"`
+}
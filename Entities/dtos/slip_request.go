@@ -0,0 +1,6 @@
+package dtos
+
+type ReviewSlipRequest struct {
+	Approve bool   `json:"approve"`        // true approves the slip and confirms the booking
+	Note    string `json:"note,omitempty"` // Optional, shown to the vendor on rejection
+}
@@ -0,0 +1,11 @@
+package dtos
+
+// SlipQRData is what the mini-QR on a Thai bank slip carries.
+type SlipQRData struct {
+	TransRef  string  `json:"trans_ref"`
+	BankCode  string  `json:"bank_code"`
+	BankName  string  `json:"bank_name,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	Amount    float64 `json:"amount,omitempty"`
+	HasAmount bool    `json:"has_amount"`
+}
@@ -29,23 +29,68 @@ type Stall struct {
 	Height    int     `json:"height"`
 	StallType string  `json:"stallType"`
 	Price     float64 `json:"price"`
+	entities.SlotGeometry
 }
 
 type ZoneLayout struct {
-	Zone   string    `json:"zone"`
+	ZoneID string    `json:"zone_id"`
+	Zone   string    `json:"zone,omitempty"` // Optional, looked up by name when zone_id is not given
 	Date   time.Time `json:"date"`
 	Stalls []Stall   `json:"stalls"`
 }
 
 type LayoutRequest struct {
 	Layout []ZoneLayout `json:"layout"`
+	Note   string       `json:"note,omitempty"` // Optional, kept on the layout version
 }
 
 type SlotUpdateDTO struct {
-	Name     *string             `json:"name,omitempty"`
-	Width    *float64            `json:"width,omitempty"`
-	Height   *float64            `json:"height,omitempty"`
-	Price    float64             `json:"price,omitempty"`
-	Category entities.Category   `json:"category,omitempty"`
-	Status   entities.SlotStatus `json:"status,omitempty"`
+	Name      *string             `json:"name,omitempty"`
+	Width     *float64            `json:"width,omitempty"`
+	Height    *float64            `json:"height,omitempty"`
+	Price     *float64            `json:"price,omitempty"` // A price of 0 makes the stall free
+	Category  entities.Category   `json:"category,omitempty"`
+	Status    entities.SlotStatus `json:"status,omitempty"`
+	X         *float64            `json:"x,omitempty"`
+	Y         *float64            `json:"y,omitempty"`
+	Rotation  *float64            `json:"rotation,omitempty"`
+	Shape     []entities.Point    `json:"shape,omitempty"`
+	Adjacent  *[]string           `json:"adjacent,omitempty"`  // Slot IDs on the same date next to this one; an empty list clears them
+	Amenities *[]string           `json:"amenities,omitempty"` // IDs of the market's feature amenities; an empty list clears them
+}
+
+// SlotDeleteRequest says what happens to each active booking of the slots being deleted.
+type SlotDeleteRequest struct {
+	Relocations    []BookingRelocation `json:"relocations,omitempty"`      // Required when the slots have pending or confirmed bookings
+	RefundToWallet bool                `json:"refund_to_wallet,omitempty"` // Optional, refunds go to wallet credit instead of the bank
+}
+
+type BookingRelocation struct {
+	BookingID string `json:"booking_id"`        // Required
+	SlotID    string `json:"slot_id,omitempty"` // Replacement slot on the same market day, or empty with refund set
+	Refund    bool   `json:"refund,omitempty"`
+}
+
+// SlotSelector picks the slots of one market a bulk edit applies to. Every filter narrows the selection.
+type SlotSelector struct {
+	DateFrom string              `json:"date_from" validate:"required,datetime=2006-01-02"` // Required
+	DateTo   string              `json:"date_to" validate:"required,datetime=2006-01-02"`   // Required
+	ZoneID   string              `json:"zone_id,omitempty"`                                 // Optional, a zone ID or, for older layouts, a zone name
+	Category entities.Category   `json:"category,omitempty"`                                // Optional
+	Status   entities.SlotStatus `json:"status,omitempty"`                                  // Optional
+	SlotIDs  []string            `json:"slot_ids,omitempty"`                                // Optional
+}
+
+// SlotChanges is what a bulk edit does to each selected slot. Unlike SlotUpdateDTO, a price can be set to 0.
+type SlotChanges struct {
+	Price        *float64             `json:"price,omitempty"`         // Optional, a new price
+	PricePercent *float64             `json:"price_percent,omitempty"` // Optional, e.g. 10 raises prices by 10%
+	PriceAmount  *float64             `json:"price_amount,omitempty"`  // Optional, added to the price, may be negative
+	Category     *entities.Category   `json:"category,omitempty"`      // Optional
+	Status       *entities.SlotStatus `json:"status,omitempty"`        // Optional, available or maintenance
+}
+
+type BulkSlotEditRequest struct {
+	Selector SlotSelector `json:"selector"`
+	Changes  SlotChanges  `json:"changes"`
 }
@@ -1,6 +1,9 @@
 package dtos
 
-import "time"
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
 
 type SlotResponse struct {
 	ID          string    `json:"id"`
@@ -14,3 +17,40 @@ type SlotResponse struct {
 	EndTime     time.Time `json:"end_time"`
 	Description string    `json:"description,omitempty"`
 }
+
+type SlotDeleteResponse struct {
+	DeletedSlotIDs []string            `json:"deleted_slot_ids"`
+	Moved          []BookingRelocation `json:"moved"`
+	Refunded       []string            `json:"refunded"` // Booking IDs that were cancelled and refunded
+}
+
+type SlotState struct {
+	Price    float64             `json:"price"`
+	Category entities.Category   `json:"category"`
+	Status   entities.SlotStatus `json:"status"`
+}
+
+type BulkSlotChange struct {
+	SlotID string    `json:"slot_id"`
+	Name   string    `json:"name"`
+	Zone   string    `json:"zone"`
+	Date   string    `json:"date"`
+	Before SlotState `json:"before"`
+	After  SlotState `json:"after"`
+}
+
+type BulkSlotSkip struct {
+	SlotID  string `json:"slot_id"`
+	Name    string `json:"name"`
+	Zone    string `json:"zone"`
+	Date    string `json:"date"`
+	Reason  string `json:"reason"`            // "booked", "unchanged" or "zone_rules"
+	Message string `json:"message,omitempty"` // Which zone rule refused the change
+}
+
+type BulkSlotEditResponse struct {
+	Applied bool             `json:"applied"` // False for a dry run
+	Matched int              `json:"matched"`
+	Changed []BulkSlotChange `json:"changed"`
+	Skipped []BulkSlotSkip   `json:"skipped"`
+}
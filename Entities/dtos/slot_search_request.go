@@ -0,0 +1,37 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// SlotSearchQuery is a public slot search as it arrives in the query string. Every field is optional.
+type SlotSearchQuery struct {
+	From      string // First market date, YYYY-MM-DD, defaults to today
+	To        string // Last market date, YYYY-MM-DD, defaults to four weeks after From
+	Category  string
+	Zone      string // Zone name, matched in every market
+	MinPrice  string
+	MaxPrice  string
+	MinWidth  string
+	MinHeight string
+	Amenities string // Comma-separated amenity names the slot must all have
+	Lat       string // Lat, Lng and RadiusKm go together to search around a point
+	Lng       string
+	RadiusKm  string
+	Page      string
+	PageSize  string
+}
+
+// SlotSearchFilter is a validated SlotSearchQuery.
+type SlotSearchFilter struct {
+	From      string
+	To        string
+	Category  entities.Category
+	Zone      string
+	MinPrice  float64 // Checked against the quoted price, not the one stored on the slot
+	MaxPrice  float64 // 0 for no upper limit
+	MinWidth  int
+	MinHeight int
+	Amenities []string
+	Area      *GeoArea // Set when searching around a point
+	Page      int
+	PageSize  int
+}
@@ -0,0 +1,36 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type SearchSlot struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Zone     string            `json:"zone"`
+	Category entities.Category `json:"category"`
+	Date     string            `json:"date"`
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+	Price    float64           `json:"price"`
+}
+
+// MarketSlots is one market's share of a search: its free slots, cheapest first.
+type MarketSlots struct {
+	MarketID    string       `json:"market_id"`
+	Name        string       `json:"name"`
+	Address     string       `json:"address"`
+	Image       string       `json:"image"`
+	DistanceKm  *float64     `json:"distance_km,omitempty"` // Only for searches around a point
+	LowestPrice float64      `json:"lowest_price"`
+	Available   int          `json:"available"`
+	Slots       []SearchSlot `json:"slots"`
+}
+
+// SlotSearchResponse is one page of a slot search. The totals count every slot matching the search apart from
+// its price range, since prices are only quoted for the markets on the page.
+type SlotSearchResponse struct {
+	Page         int           `json:"page"`
+	PageSize     int           `json:"page_size"`
+	TotalMarkets int           `json:"total_markets"`
+	TotalSlots   int           `json:"total_slots"`
+	Markets      []MarketSlots `json:"markets"`
+}
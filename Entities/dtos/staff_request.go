@@ -0,0 +1,29 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type StaffInviteRequest struct {
+	Name        string                   `json:"name" validate:"required"`        // Required, at most 100 characters
+	Email       string                   `json:"email" validate:"required,email"` // Required, one staff login per email
+	Assignments []StaffAssignmentRequest `json:"assignments" validate:"required"` // Required, at least one market
+}
+
+type StaffAssignmentRequest struct {
+	MarketID string             `json:"market_id" validate:"required,uuid"` // Required, one of the provider's markets
+	Role     entities.StaffRole `json:"role" validate:"required"`           // Required, manager, cashier or checker
+}
+
+type StaffAssignmentsRequest struct {
+	Assignments []StaffAssignmentRequest `json:"assignments" validate:"required"` // Required, replaces every current assignment
+}
+
+type StaffAcceptRequest struct {
+	InviteToken string `json:"invite_token" validate:"required"`   // Required, from the invite
+	Username    string `json:"username" validate:"required"`       // Required, used to log in
+	Password    string `json:"password" validate:"required,min=8"` // Required, at least 8 characters
+}
+
+type StaffLoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type StaffInviteResponse struct {
+	Staff       *entities.StaffMember `json:"staff"`
+	InviteToken string                `json:"invite_token"` // Shown once; the staff member needs it to accept the invite
+	InviteURL   string                `json:"invite_url,omitempty"`
+}
+
+type StaffLoginResponse struct {
+	AccessToken string `json:"access_token"`
+	StaffID     string `json:"staff_id"`
+	ProviderID  string `json:"provider_id"`
+}
+
+// StaffAccessResponse is what a staff member may do, market by market.
+type StaffAccessResponse struct {
+	Staff   *entities.StaffMember `json:"staff"`
+	Markets []StaffMarketAccess   `json:"markets"`
+}
+
+type StaffMarketAccess struct {
+	MarketID    string                `json:"market_id"`
+	MarketName  string                `json:"market_name"`
+	Role        entities.StaffRole    `json:"role"`
+	Permissions []entities.Permission `json:"permissions"`
+}
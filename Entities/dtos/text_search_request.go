@@ -0,0 +1,36 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// TextSearchQuery is a full-text search as it arrives in the query string. Only Q is required.
+type TextSearchQuery struct {
+	Q        string
+	Type     string // market, vendor or all, the default
+	Category string
+	Date     string // YYYY-MM-DD, markets with a free slot or vendors trading that day
+	Lat      string // Lat, Lng and RadiusKm go together, for markets or vendors trading within range
+	Lng      string
+	RadiusKm string
+	Page     string
+	PageSize string
+}
+
+// TextSearchFilter is a validated TextSearchQuery.
+type TextSearchFilter struct {
+	Terms      []string
+	MinMatched int // Fewest query terms a document must contain
+	Kinds      []entities.SearchKind
+	Category   entities.Category
+	Date       string
+	Area       *GeoArea // Set when searching around a point
+	Page       int
+	PageSize   int
+}
+
+// SearchMatch is a document that matched, with how many query terms it contains and their summed weight.
+type SearchMatch struct {
+	Kind    entities.SearchKind
+	RefID   string
+	Matched int
+	Weight  float64
+}
@@ -0,0 +1,29 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+// TextSearchHit is a market or vendor stall that matched, with its matching fields highlighted in <mark>.
+type TextSearchHit struct {
+	Kind       entities.SearchKind `json:"kind"`
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Image      string              `json:"image"`
+	Category   entities.Category   `json:"category,omitempty"` // Vendors only
+	DistanceKm *float64            `json:"distance_km,omitempty"`
+	Score      float64             `json:"score"`
+	Highlights map[string]string   `json:"highlights"`
+}
+
+type TextSearchResponse struct {
+	Query    string          `json:"query"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+	Total    int             `json:"total"`
+	Results  []TextSearchHit `json:"results"`
+}
+
+// ReindexResponse counts the documents a full rebuild of the search index wrote.
+type ReindexResponse struct {
+	Markets int `json:"markets"`
+	Vendors int `json:"vendors"`
+}
@@ -0,0 +1,22 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type VendorApplicationRequest struct {
+	StallName   string            `json:"stall_name" validate:"required"` // Required, at most 100 characters
+	Description string            `json:"description,omitempty"`          // Optional, what the stall is about
+	Category    entities.Category `json:"category,omitempty"`             // Optional, what the stall mostly sells
+	Products    []string          `json:"products,omitempty"`             // Optional, at most 20 products
+	PhotoIDs    []string          `json:"photo_ids,omitempty"`            // Optional, application_photo uploads, at most 10
+	DocumentIDs []string          `json:"document_ids,omitempty"`         // Optional, application_document uploads such as licences, at most 10
+}
+
+type ApplicationReviewRequest struct {
+	Status entities.ApplicationStatus `json:"status" validate:"required"` // Required, approved, rejected, changes_requested, revoked or blacklisted
+	Note   string                     `json:"note,omitempty"`             // Required unless approving, shown to the vendor
+}
+
+type BlacklistRequest struct {
+	VendorID string `json:"vendor_id" validate:"required,uuid"` // Required, the vendor to bar from the market
+	Reason   string `json:"reason" validate:"required"`         // Required, shown to the vendor
+}
@@ -0,0 +1,12 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type VendorProfileRequest struct {
+	StallName   string            `json:"stall_name" validate:"required"` // Required, at most 100 characters
+	Description string            `json:"description,omitempty"`          // Optional
+	Category    entities.Category `json:"category,omitempty"`             // Optional, what the stall mostly sells
+	Products    []string          `json:"products,omitempty"`             // Optional, at most 20 products
+	Image       string            `json:"image,omitempty"`                // Optional, URL or path to the stall image
+	Public      *bool             `json:"public,omitempty"`               // Optional, true by default
+}
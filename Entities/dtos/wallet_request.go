@@ -0,0 +1,14 @@
+package dtos
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type IssueCreditRequest struct {
+	VendorID  string                      `json:"vendor_id" validate:"required,uuid"`                  // Required, vendor receiving the credit
+	Amount    float64                     `json:"amount" validate:"required,gt=0"`                     // Required, credit amount in baht
+	Source    entities.WalletCreditSource `json:"source" validate:"required,oneof=goodwill promotion"` // Required, why the credit is issued
+	Note      string                      `json:"note,omitempty"`                                      // Optional, shown in the vendor's history
+	ExpiresAt *time.Time                  `json:"expires_at,omitempty"`                                // Optional, credit is unusable after this time
+}
@@ -0,0 +1,9 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type WalletBalanceResponse struct {
+	VendorID string                  `json:"vendor_id"`
+	Balance  float64                 `json:"balance"`
+	Credits  []entities.WalletCredit `json:"credits"`
+}
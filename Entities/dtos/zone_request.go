@@ -0,0 +1,14 @@
+package dtos
+
+import entities "tln-backend/Entities"
+
+type ZoneRequest struct {
+	Name              string                    `json:"name" validate:"required"`     // Required, unique within the market
+	Colour            string                    `json:"colour,omitempty"`             // Optional, #rrggbb
+	Description       string                    `json:"description,omitempty"`        // Optional
+	DefaultPrice      float64                   `json:"default_price,omitempty"`      // Optional, for stalls laid out without a price
+	DefaultWidth      int                       `json:"default_width,omitempty"`      // Optional, for stalls laid out without a size
+	DefaultHeight     int                       `json:"default_height,omitempty"`     // Optional, for stalls laid out without a size
+	AllowedCategories []entities.Category       `json:"allowed_categories,omitempty"` // Optional, empty allows every category
+	CategoryCaps      map[entities.Category]int `json:"category_caps,omitempty"`      // Optional, most stalls of a category per day
+}
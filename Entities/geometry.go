@@ -0,0 +1,57 @@
+package entities
+
+import "math"
+
+// Point is a position on a market floor plan, in the same units as a slot's width and height.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// SlotGeometry places a stall on the market floor plan. X and Y are the top-left corner of the stall's
+// width by height box, Rotation turns it clockwise in degrees about its centre, and an optional Shape
+// replaces the box with a polygon whose points are relative to X and Y.
+type SlotGeometry struct {
+	X        float64 `gorm:"type:decimal(10,2);not null;default:0" json:"x"`
+	Y        float64 `gorm:"type:decimal(10,2);not null;default:0" json:"y"`
+	Rotation float64 `gorm:"type:decimal(6,2);not null;default:0" json:"rotation"`
+	Shape    []Point `gorm:"type:text;serializer:json" json:"shape,omitempty"`
+}
+
+// Placed reports whether the stall has been given a position at all; older layouts only have a size.
+func (g SlotGeometry) Placed() bool {
+	return g.X != 0 || g.Y != 0 || g.Rotation != 0 || len(g.Shape) > 0
+}
+
+// Bounds is the axis-aligned box around a width by height stall once its shape and rotation are applied.
+func (g SlotGeometry) Bounds(width, height int) (float64, float64, float64, float64) {
+	w, h := float64(width), float64(height)
+	points := []Point{{X: 0, Y: 0}, {X: w, Y: 0}, {X: w, Y: h}, {X: 0, Y: h}}
+	if len(g.Shape) > 0 {
+		points = g.Shape
+	}
+
+	radians := g.Rotation * math.Pi / 180
+	sin, cos := math.Sin(radians), math.Cos(radians)
+	x1, y1, x2, y2 := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		dx, dy := p.X-w/2, p.Y-h/2
+		x := g.X + w/2 + dx*cos - dy*sin
+		y := g.Y + h/2 + dx*sin + dy*cos
+		x1, y1, x2, y2 = math.Min(x1, x), math.Min(y1, y), math.Max(x2, x), math.Max(y2, y)
+	}
+	return x1, y1, x2, y2
+}
+
+// SameGeometry compares two placements, treating a missing shape and an empty one alike.
+func (g SlotGeometry) SameGeometry(other SlotGeometry) bool {
+	if g.X != other.X || g.Y != other.Y || g.Rotation != other.Rotation || len(g.Shape) != len(other.Shape) {
+		return false
+	}
+	for i := range g.Shape {
+		if g.Shape[i] != other.Shape[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// LayoutTemplate is a named, reusable market layout that can be stamped onto many dates.
+type LayoutTemplate struct {
+	ID          string          `gorm:"primaryKey;column:id" json:"id"`
+	MarketID    string          `gorm:"type:varchar(36);not null;uniqueIndex:idx_layout_template_name" json:"market_id"`
+	Name        string          `gorm:"type:varchar(100);not null;uniqueIndex:idx_layout_template_name" json:"name"`
+	Description string          `gorm:"type:text" json:"description,omitempty"`
+	Stalls      []TemplateStall `gorm:"foreignKey:TemplateID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"stalls"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TemplateStall is one stall of a template; applying the template turns it into a Slot per date.
+type TemplateStall struct {
+	ID         string    `gorm:"primaryKey;column:id" json:"id"`
+	TemplateID string    `gorm:"type:varchar(36);not null;index" json:"template_id"`
+	Zone       string    `gorm:"type:varchar(50);not null" json:"zone"`
+	ZoneID     string    `gorm:"type:varchar(36)" json:"zone_id,omitempty"`
+	Name       string    `gorm:"type:varchar(100);not null" json:"name"`
+	Width      int       `gorm:"type:int;not null" json:"width"`
+	Height     int       `gorm:"type:int;not null" json:"height"`
+	Price      float64   `gorm:"type:decimal(10,2);not null" json:"price"`
+	Category   Category  `gorm:"type:varchar(50);not null" json:"category"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	SlotGeometry
+}
@@ -0,0 +1,48 @@
+package entities
+
+import "time"
+
+// LayoutVersion is a snapshot of the zones a layout change touched, as they looked once it was applied.
+type LayoutVersion struct {
+	ID         string               `gorm:"primaryKey;column:id" json:"id"`
+	MarketID   string               `gorm:"type:varchar(36);not null;uniqueIndex:idx_layout_version" json:"market_id"`
+	Version    int                  `gorm:"type:int;not null;uniqueIndex:idx_layout_version" json:"version"`
+	Source     LayoutVersionSource  `gorm:"type:varchar(20);not null" json:"source"`
+	RestoredOf int                  `gorm:"type:int" json:"restored_of,omitempty"`
+	Scopes     []string             `gorm:"type:text;serializer:json" json:"scopes"`
+	Note       string               `gorm:"type:text" json:"note,omitempty"`
+	CreatedBy  string               `gorm:"type:varchar(36)" json:"created_by,omitempty"`
+	Stalls     []LayoutVersionStall `gorm:"foreignKey:VersionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"stalls,omitempty"`
+	CreatedAt  time.Time            `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// LayoutVersionStall is one stall of a version's snapshot.
+type LayoutVersionStall struct {
+	ID        string   `gorm:"primaryKey;column:id" json:"id"`
+	VersionID string   `gorm:"type:varchar(36);not null;index" json:"version_id"`
+	Date      string   `gorm:"type:varchar(10);not null" json:"date"`
+	Zone      string   `gorm:"type:varchar(50);not null" json:"zone"`
+	ZoneID    string   `gorm:"type:varchar(36)" json:"zone_id,omitempty"`
+	Name      string   `gorm:"type:varchar(100);not null" json:"name"`
+	Width     int      `gorm:"type:int;not null" json:"width"`
+	Height    int      `gorm:"type:int;not null" json:"height"`
+	Price     float64  `gorm:"type:decimal(10,2);not null" json:"price"`
+	Category  Category `gorm:"type:varchar(50);not null" json:"category"`
+	SlotGeometry
+}
+
+type LayoutVersionSource string
+
+const (
+	// VersionBaseline records how a zone looked before its first versioned change, so that change can be rolled back.
+	VersionBaseline LayoutVersionSource = "baseline"
+	VersionApply    LayoutVersionSource = "apply"
+	VersionRollback LayoutVersionSource = "rollback"
+	// VersionEdit records slots reshaped by a template, a bulk edit or a single slot edit.
+	VersionEdit LayoutVersionSource = "edit"
+)
+
+// LayoutScope names one zone on one date, the unit a layout change replaces.
+func LayoutScope(date, zone string) string {
+	return date + "/" + zone
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// LedgerAccount is a money bucket owned by a vendor, a provider, the platform or the payment gateway.
+type LedgerAccount struct {
+	ID        string            `gorm:"primaryKey;column:id" json:"id"`
+	Type      LedgerAccountType `gorm:"type:varchar(30);not null;uniqueIndex:idx_ledger_account_owner" json:"type"`
+	OwnerID   string            `gorm:"type:varchar(36);not null;uniqueIndex:idx_ledger_account_owner" json:"owner_id"`
+	Name      string            `gorm:"type:varchar(100)" json:"name"`
+	CreatedAt time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// JournalEntry groups the balanced lines of a single money movement. Entries are append-only.
+type JournalEntry struct {
+	ID          string        `gorm:"primaryKey;column:id" json:"id"`
+	Kind        JournalKind   `gorm:"type:varchar(20);not null;uniqueIndex:idx_journal_kind_reference" json:"kind"`
+	ReferenceID string        `gorm:"type:varchar(36);not null;uniqueIndex:idx_journal_kind_reference" json:"reference_id"`
+	Description string        `gorm:"type:varchar(255)" json:"description"`
+	Lines       []JournalLine `gorm:"foreignKey:EntryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"lines"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type JournalLine struct {
+	ID        string         `gorm:"primaryKey;column:id" json:"id"`
+	EntryID   string         `gorm:"type:varchar(36);not null;index" json:"entry_id"`
+	AccountID string         `gorm:"type:varchar(36);not null;index" json:"account_id"`
+	Account   *LedgerAccount `gorm:"foreignKey:AccountID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"account,omitempty"`
+	Debit     float64        `gorm:"type:decimal(10,2);not null;default:0" json:"debit"`
+	Credit    float64        `gorm:"type:decimal(10,2);not null;default:0" json:"credit"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type LedgerAccountType string
+
+const (
+	AccountVendor          LedgerAccountType = "vendor"
+	AccountProvider        LedgerAccountType = "provider"
+	AccountPlatform        LedgerAccountType = "platform"
+	AccountGatewayClearing LedgerAccountType = "gateway_clearing"
+)
+
+// Owner IDs of the accounts that are not tied to a vendor or provider row.
+const (
+	PlatformOwnerID = "platform"
+	GatewayOwnerID  = "promptpay"
+)
+
+type JournalKind string
+
+const (
+	JournalCharge     JournalKind = "charge"
+	JournalRefund     JournalKind = "refund"
+	JournalFee        JournalKind = "fee"
+	JournalSettlement JournalKind = "settlement"
+	JournalAdjustment JournalKind = "adjustment"
+	JournalCredit     JournalKind = "credit"
+	JournalExpiry     JournalKind = "expiry"
+)
+
+// IsDebitNormal reports whether the account balance grows with debits (assets) rather than credits (liabilities).
+func (t LedgerAccountType) IsDebitNormal() bool {
+	return t == AccountGatewayClearing
+}
+
+// RoundMoney rounds an amount to satang so float sums can be compared safely.
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Market struct {
 	ID          string         `gorm:"primaryKey;column:id" json:"id"`
@@ -12,12 +16,65 @@ type Market struct {
 	Description string         `gorm:"type:text" json:"description"`
 	Image       string         `gorm:"type:text" json:"image"`
 	LayoutImage string         `gorm:"type:text" json:"layout_image"`
+	FloorWidth  float64        `gorm:"type:decimal(10,2);not null;default:0" json:"floor_width"`  // Floor plan size in slot units, 0 to fit the slots
+	FloorHeight float64        `gorm:"type:decimal(10,2);not null;default:0" json:"floor_height"` // Floor plan size in slot units, 0 to fit the slots
 	OpenTime    string         `gorm:"type:varchar(10)" json:"open_time"`
 	CloseTime   string         `gorm:"type:varchar(10)" json:"close_time"`
-	Latitude    string         `gorm:"type:varchar(20)" json:"latitude"`
-	Longitude   string         `gorm:"type:varchar(20)" json:"longitude"`
+	Timezone    string         `gorm:"type:varchar(50);not null;default:'Asia/Bangkok'" json:"timezone"`
+	Status      MarketStatus   `gorm:"type:varchar(20);not null;default:'published';index" json:"status"`
+	RosterOnly  bool           `gorm:"not null;default:false" json:"roster_only"`                    // Only vendors on the market's roster may book
+	Latitude    *float64       `gorm:"type:decimal(9,6);index:idx_market_location" json:"latitude"`  // Degrees, nil when the market has no location
+	Longitude   *float64       `gorm:"type:decimal(9,6);index:idx_market_location" json:"longitude"` // Degrees, nil when the market has no location
 	Slots       []Slot         `gorm:"foreignKey:MarketID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"slots"`
 	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   *time.Time     `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Uploaded images, see Media. Image and LayoutImage above are older free-form URLs.
+	ImageID            string `gorm:"type:varchar(36)" json:"image_id,omitempty"`
+	ImageURL           string `gorm:"-" json:"image_url,omitempty"`
+	ImageThumbnailURL  string `gorm:"-" json:"image_thumbnail_url,omitempty"`
+	LayoutImageID      string `gorm:"type:varchar(36)" json:"layout_image_id,omitempty"`
+	LayoutImageURL     string `gorm:"-" json:"layout_image_url,omitempty"`
+	LayoutThumbnailURL string `gorm:"-" json:"layout_thumbnail_url,omitempty"`
+
+	// Average of the market's visible reviews, filled on its profile.
+	Rating *RatingSummary `gorm:"-" json:"rating,omitempty"`
+}
+
+// MarketStatus is where a market is in its lifecycle. Only published markets are listed and take bookings.
+type MarketStatus string
+
+const (
+	MarketDraft     MarketStatus = "draft"     // Being set up, only its provider sees it
+	MarketPublished MarketStatus = "published" // Public and open for bookings
+	MarketSuspended MarketStatus = "suspended" // Taken down for now by its provider or an admin
+	MarketArchived  MarketStatus = "archived"  // Closed; kept read-only with its bookings and stats until restored as a draft
+)
+
+// MarketStatusChange records one move of a market through its lifecycle.
+type MarketStatusChange struct {
+	ID         string       `gorm:"primaryKey;column:id" json:"id"`
+	MarketID   string       `gorm:"type:varchar(36);not null;index" json:"market_id"`
+	FromStatus MarketStatus `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus   MarketStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Reason     string       `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	ChangedBy  string       `gorm:"type:varchar(36);not null" json:"changed_by"`
+	Role       string       `gorm:"type:varchar(20);not null" json:"role"` // provider or admin
+	CreatedAt  time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// AfterFind signs the URLs of the market's uploaded images.
+func (m *Market) AfterFind(tx *gorm.DB) error {
+	m.ImageURL, m.ImageThumbnailURL = MediaURLs(m.ImageID)
+	m.LayoutImageURL, m.LayoutThumbnailURL = MediaURLs(m.LayoutImageID)
+	return nil
+}
+
+// ListImage is the picture to show for the market in a list: the uploaded thumbnail, or the older link.
+func (m *Market) ListImage() string {
+	if m.ImageThumbnailURL != "" {
+		return m.ImageThumbnailURL
+	}
+	return m.Image
 }
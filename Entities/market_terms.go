@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// MarketTerms is one version of a market's rules for vendors, such as opening times, waste disposal, noise and
+// prohibited goods. Versions are never edited; publishing a change adds the next version, and the highest
+// version is the one in force.
+type MarketTerms struct {
+	ID          string    `gorm:"primaryKey;column:id" json:"id"`
+	MarketID    string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_market_terms_version" json:"market_id"`
+	Version     int       `gorm:"not null;uniqueIndex:idx_market_terms_version" json:"version"`
+	Title       string    `gorm:"type:varchar(200)" json:"title,omitempty"`
+	Body        string    `gorm:"type:text;not null" json:"body"`
+	PublishedBy string    `gorm:"type:varchar(36);not null" json:"published_by"` // The provider or staff member
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TermsAcceptance records a vendor agreeing to one version of a market's terms, kept as evidence for disputes.
+type TermsAcceptance struct {
+	ID         string       `gorm:"primaryKey;column:id" json:"id"`
+	TermsID    string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_terms_acceptance_vendor" json:"terms_id"`
+	Terms      *MarketTerms `gorm:"foreignKey:TermsID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"terms,omitempty"`
+	MarketID   string       `gorm:"type:varchar(36);not null;index" json:"market_id"`
+	Version    int          `gorm:"not null" json:"version"`
+	VendorID   string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_terms_acceptance_vendor;index" json:"vendor_id"`
+	Vendor     *Vendor      `gorm:"foreignKey:VendorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"vendor,omitempty"`
+	IPAddress  string       `gorm:"type:varchar(45);not null" json:"ip_address"`
+	UserAgent  string       `gorm:"type:varchar(255)" json:"user_agent,omitempty"`
+	AcceptedAt time.Time    `gorm:"not null" json:"accepted_at"`
+}
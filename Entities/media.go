@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Media is an uploaded image. The row only describes it; the file and a JPEG thumbnail are kept in media
+// storage, and the entity it belongs to holds its ID in a column such as Market.ImageID.
+type Media struct {
+	ID           string     `gorm:"primaryKey;column:id" json:"id"`
+	Kind         MediaKind  `gorm:"type:varchar(30);not null;index:idx_media_target" json:"kind"`
+	TargetID     string     `gorm:"type:varchar(36);not null;index:idx_media_target" json:"target_id"` // The market, slot, vendor, transaction or slip it belongs to
+	OwnerID      string     `gorm:"type:varchar(36);not null;index" json:"owner_id"`                   // Who uploaded it, empty when the server made it
+	ContentType  string     `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size         int64      `gorm:"not null" json:"size"` // Bytes
+	Width        int        `gorm:"not null" json:"width"`
+	Height       int        `gorm:"not null" json:"height"`
+	URL          string     `gorm:"-" json:"url,omitempty"`
+	ThumbnailURL string     `gorm:"-" json:"thumbnail_url,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	DeletedAt    *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+type MediaKind string
+
+const (
+	MediaMarketImage  MediaKind = "market_image"
+	MediaMarketLayout MediaKind = "market_layout"
+	MediaSlotImage    MediaKind = "slot_image"
+	MediaVendorImage  MediaKind = "vendor_image"
+	MediaStallImage   MediaKind = "stall_image"
+	MediaPaymentQR    MediaKind = "payment_qr"
+
+	// Files a vendor sends with market applications. They are kept under the vendor and listed by the
+	// applications that use them rather than held in a column.
+	MediaApplicationPhoto    MediaKind = "application_photo"
+	MediaApplicationDocument MediaKind = "application_document"
+
+	// A transfer slip a vendor paid with. It is held by its BankSlip and only shown with the booking's slips.
+	MediaBankSlip MediaKind = "bank_slip"
+)
+
+// Attached reports whether media of this kind replaces the one image its target holds in a column.
+func (k MediaKind) Attached() bool {
+	return k != MediaApplicationPhoto && k != MediaApplicationDocument
+}
+
+// Private reports whether media of this kind is kept from public lookup and only shown with what it belongs to.
+func (k MediaKind) Private() bool {
+	return !k.Attached() || k == MediaBankSlip
+}
+
+// MediaURLs signs the URLs of an uploaded file and of its thumbnail, or returns two empty strings when there
+// is no media ID. App replaces it once media storage is set up.
+var MediaURLs = func(mediaID string) (string, string) {
+	return "", ""
+}
+
+func (m *Media) AfterFind(tx *gorm.DB) error {
+	m.URL, m.ThumbnailURL = MediaURLs(m.ID)
+	return nil
+}
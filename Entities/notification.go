@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Notification is a message in a user's in-app inbox.
+type Notification struct {
+	ID        string           `gorm:"primaryKey;column:id" json:"id"`
+	UserID    string           `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Kind      NotificationKind `gorm:"type:varchar(30);not null" json:"kind"`
+	Title     string           `gorm:"type:varchar(200);not null" json:"title"`
+	Message   string           `gorm:"type:text;not null" json:"message"`
+	RefID     string           `gorm:"type:varchar(36)" json:"ref_id,omitempty"` // What the message is about, e.g. a booking
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type NotificationKind string
+
+const (
+	NotificationBookingMoved    NotificationKind = "booking_moved"
+	NotificationBookingRefunded NotificationKind = "booking_refunded"
+	NotificationApplication     NotificationKind = "application_reviewed"
+	NotificationAnnouncement    NotificationKind = "announcement"
+)
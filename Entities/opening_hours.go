@@ -0,0 +1,129 @@
+package entities
+
+import "time"
+
+// OpeningSession is one stretch of a market's usual week, e.g. Saturday 17:00-23:00. A day may have several.
+type OpeningSession struct {
+	ID        string    `gorm:"primaryKey;column:id" json:"id"`
+	MarketID  string    `gorm:"type:varchar(36);not null;index:idx_opening_session_day" json:"market_id"`
+	Weekday   int       `gorm:"type:int;not null;index:idx_opening_session_day" json:"weekday"` // 0 = Sunday ... 6 = Saturday
+	Opens     string    `gorm:"type:varchar(5);not null" json:"opens"`                          // HH:MM in the market's timezone
+	Closes    string    `gorm:"type:varchar(5);not null" json:"closes"`                         // HH:MM, before Opens when the session runs past midnight
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OpeningException overrides a market's usual hours on one date: closed shuts the market for the day, open
+// opens it with its own sessions, e.g. a special night market.
+type OpeningException struct {
+	ID        string        `gorm:"primaryKey;column:id" json:"id"`
+	MarketID  string        `gorm:"type:varchar(36);not null;uniqueIndex:idx_opening_exception_date" json:"market_id"`
+	Date      string        `gorm:"type:varchar(10);not null;uniqueIndex:idx_opening_exception_date" json:"date"`
+	Kind      ExceptionKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Reason    string        `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	Sessions  []TimeRange   `gorm:"type:text;serializer:json" json:"sessions,omitempty"` // Open only
+	CreatedAt time.Time     `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type TimeRange struct {
+	Opens  string `json:"opens"`  // HH:MM
+	Closes string `json:"closes"` // HH:MM, before Opens when the range runs past midnight
+}
+
+// OpeningHours is everything needed to tell when a market opens. A market that never set weekly sessions
+// opens every day for its Default range.
+type OpeningHours struct {
+	Location   *time.Location
+	Default    TimeRange
+	Sessions   []OpeningSession
+	Exceptions []OpeningException
+}
+
+// SessionsOn returns the market's sessions on a date, none when it is closed, and the exception that decided
+// it if there is one.
+func (h *OpeningHours) SessionsOn(day time.Time) ([]TimeRange, *OpeningException) {
+	date := day.Format("2006-01-02")
+	for i := range h.Exceptions {
+		if exception := &h.Exceptions[i]; exception.Date == date {
+			if exception.Kind == ExceptionClosed {
+				return nil, exception
+			}
+			return exception.Sessions, exception
+		}
+	}
+
+	if len(h.Sessions) == 0 {
+		return []TimeRange{h.Default}, nil
+	}
+	sessions := make([]TimeRange, 0)
+	for _, session := range h.Sessions {
+		if session.Weekday == int(day.Weekday()) {
+			sessions = append(sessions, TimeRange{Opens: session.Opens, Closes: session.Closes})
+		}
+	}
+	return sessions, nil
+}
+
+// WithSchedule layers a market's slot schedule onto its hours, so bookings are taken on the same dates the
+// schedule generates slots for. The market's own exceptions still decide their dates. A schedule exception
+// opens a date for that weekday's sessions, or the Default range when it has none, or closes it; a holiday
+// closes the date when the schedule skips holidays.
+func (h *OpeningHours) WithSchedule(schedule *MarketSchedule, holidays []Holiday) {
+	if schedule == nil || !schedule.Active {
+		return
+	}
+	decided := make(map[string]bool, len(h.Exceptions))
+	for _, exception := range h.Exceptions {
+		decided[exception.Date] = true
+	}
+
+	for _, exception := range schedule.Exceptions {
+		if decided[exception.Date] {
+			continue
+		}
+		decided[exception.Date] = true
+		override := OpeningException{MarketID: schedule.MarketID, Date: exception.Date, Kind: exception.Kind, Reason: exception.Reason}
+		if exception.Kind == ExceptionOpen {
+			day, err := time.Parse("2006-01-02", exception.Date)
+			if err != nil {
+				continue
+			}
+			usual, _ := h.SessionsOn(day)
+			if len(usual) == 0 {
+				usual = []TimeRange{h.Default}
+			}
+			override.Sessions = usual
+		}
+		h.Exceptions = append(h.Exceptions, override)
+	}
+
+	if !schedule.SkipHolidays {
+		return
+	}
+	for _, holiday := range holidays {
+		if decided[holiday.Date] {
+			continue
+		}
+		decided[holiday.Date] = true
+		h.Exceptions = append(h.Exceptions, OpeningException{MarketID: schedule.MarketID, Date: holiday.Date, Kind: ExceptionClosed, Reason: holiday.Name})
+	}
+}
+
+// Span places a time range on a date in a location. A range that closes at or before it opens ends the
+// next day.
+func (r TimeRange) Span(day time.Time, location *time.Location) (time.Time, time.Time, error) {
+	opens, err := time.Parse("15:04", r.Opens)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	closes, err := time.Parse("15:04", r.Closes)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), opens.Hour(), opens.Minute(), 0, 0, location)
+	end := time.Date(day.Year(), day.Month(), day.Day(), closes.Hour(), closes.Minute(), 0, 0, location)
+	if !end.After(start) {
+		end = time.Date(day.Year(), day.Month(), day.Day()+1, closes.Hour(), closes.Minute(), 0, 0, location)
+	}
+	return start, end, nil
+}
@@ -9,6 +9,8 @@ type Payment struct {
 	BookingID   string        `gorm:"type:varchar(36);not null;uniqueIndex" json:"booking_id"`
 	Booking     *Booking      `gorm:"foreignKey:BookingID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"booking"`
 	Price       float64       `gorm:"type:decimal(10,2);not null" json:"price"`
+	Discount    float64       `gorm:"type:decimal(10,2);not null;default:0" json:"discount"`    // Promotion discount already taken off Price
+	CreditUsed  float64       `gorm:"type:decimal(10,2);not null;default:0" json:"credit_used"` // Portion of Price paid from the vendor wallet
 	Method      Method        `gorm:"type:varchar(50);not null" json:"method"`
 	Status      PaymentStatus `gorm:"type:varchar(20);not null" json:"status"`
 	PaymentDate time.Time     `gorm:"type:timestamptz;not null" json:"payment_date"`
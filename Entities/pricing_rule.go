@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// PricingRule is one step of a market's price calculation. Empty Zone and Category match every slot.
+// Base rules set the starting price; every other kind adjusts it as price*Multiplier + Amount.
+type PricingRule struct {
+	ID                 string          `gorm:"primaryKey;column:id" json:"id"`
+	MarketID           string          `gorm:"type:varchar(36);not null;index" json:"market_id"`
+	Name               string          `gorm:"type:varchar(100);not null" json:"name"`
+	Kind               PricingRuleKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Zone               string          `gorm:"type:varchar(50)" json:"zone,omitempty"`
+	Category           Category        `gorm:"type:varchar(50)" json:"category,omitempty"`
+	Weekdays           []int           `gorm:"type:text;serializer:json" json:"weekdays,omitempty"` // Weekday rules, 0 (Sunday) to 6 (Saturday)
+	Price              float64         `gorm:"type:decimal(10,2);not null;default:0" json:"price"`  // Base rules only
+	Multiplier         float64         `gorm:"type:decimal(6,3);not null;default:1" json:"multiplier"`
+	Amount             float64         `gorm:"type:decimal(10,2);not null;default:0" json:"amount"`
+	DaysBefore         int             `gorm:"type:int;not null;default:0" json:"days_before"`                  // Early bird: at least this far ahead; last minute: at most
+	OccupancyThreshold float64         `gorm:"type:decimal(4,3);not null;default:0" json:"occupancy_threshold"` // Demand: share of the day's slots already taken, 0-1
+	Priority           int             `gorm:"type:int;not null;default:0" json:"priority"`                     // Order among rules of the same kind, lowest first
+	Active             bool            `gorm:"not null;default:true" json:"active"`
+	CreatedAt          time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type PricingRuleKind string
+
+// Rule kinds, in the order the engine applies them.
+const (
+	RuleBase       PricingRuleKind = "base"
+	RuleWeekday    PricingRuleKind = "weekday"
+	RuleHoliday    PricingRuleKind = "holiday"
+	RuleEarlyBird  PricingRuleKind = "early_bird"
+	RuleLastMinute PricingRuleKind = "last_minute"
+	RuleDemand     PricingRuleKind = "demand"
+)
+
+// PricingRuleKinds lists every kind in evaluation order.
+var PricingRuleKinds = []PricingRuleKind{RuleBase, RuleWeekday, RuleHoliday, RuleEarlyBird, RuleLastMinute, RuleDemand}
+
+// ComboPrice is what a provider charges for booking a number of adjacent stalls together, in place of the sum
+// of their prices. An empty Zone matches groups anywhere in the market.
+type ComboPrice struct {
+	ID        string    `gorm:"primaryKey;column:id" json:"id"`
+	MarketID  string    `gorm:"type:varchar(36);not null;index" json:"market_id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	Zone      string    `gorm:"type:varchar(50)" json:"zone,omitempty"`
+	Stalls    int       `gorm:"type:int;not null" json:"stalls"`
+	Price     float64   `gorm:"type:decimal(10,2);not null" json:"price"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+// Promotion is a provider's discount code. Empty scope fields match everything in the provider's markets.
+type Promotion struct {
+	ID               string       `gorm:"primaryKey;column:id" json:"id"`
+	ProviderID       string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_promotion_code" json:"provider_id"`
+	Code             string       `gorm:"type:varchar(50);not null;uniqueIndex:idx_promotion_code" json:"code"`
+	Description      string       `gorm:"type:varchar(255)" json:"description,omitempty"`
+	DiscountType     DiscountType `gorm:"type:varchar(20);not null" json:"discount_type"`
+	DiscountValue    float64      `gorm:"type:decimal(10,2);not null" json:"discount_value"`
+	MaxDiscount      float64      `gorm:"type:decimal(10,2);not null;default:0" json:"max_discount,omitempty"` // Caps percentage discounts, 0 means no cap
+	MarketID         string       `gorm:"type:varchar(36)" json:"market_id,omitempty"`
+	ZoneID           string       `gorm:"type:varchar(36)" json:"zone_id,omitempty"`
+	Zone             string       `gorm:"type:varchar(50)" json:"zone,omitempty"` // Copy of the zone's name; promotions from before zones had IDs only have this
+	Category         Category     `gorm:"type:varchar(50)" json:"category,omitempty"`
+	StartDate        string       `gorm:"type:varchar(10)" json:"start_date,omitempty"` // First booking date the code applies to
+	EndDate          string       `gorm:"type:varchar(10)" json:"end_date,omitempty"`   // Last booking date the code applies to
+	ValidFrom        *time.Time   `gorm:"type:timestamptz" json:"valid_from,omitempty"`
+	ValidUntil       *time.Time   `gorm:"type:timestamptz" json:"valid_until,omitempty"`
+	UsageLimit       int          `gorm:"type:int;not null;default:0" json:"usage_limit"`      // 0 means unlimited
+	PerVendorLimit   int          `gorm:"type:int;not null;default:0" json:"per_vendor_limit"` // 0 means unlimited
+	FirstBookingOnly bool         `gorm:"not null;default:false" json:"first_booking_only"`
+	UsedCount        int          `gorm:"type:int;not null;default:0" json:"used_count"`
+	Status           PromoStatus  `gorm:"type:varchar(20);not null" json:"status"`
+	Redemptions      []Redemption `gorm:"foreignKey:PromotionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"redemptions,omitempty"`
+	CreatedAt        time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Redemption records one use of a promotion against a booking's payment.
+type Redemption struct {
+	ID          string           `gorm:"primaryKey;column:id" json:"id"`
+	PromotionID string           `gorm:"type:varchar(36);not null;index" json:"promotion_id"`
+	PaymentID   string           `gorm:"type:varchar(36);not null;index" json:"payment_id"`
+	BookingID   string           `gorm:"type:varchar(36);not null;uniqueIndex" json:"booking_id"`
+	VendorID    string           `gorm:"type:varchar(36);not null;index" json:"vendor_id"`
+	ListPrice   float64          `gorm:"type:decimal(10,2);not null" json:"list_price"`
+	Discount    float64          `gorm:"type:decimal(10,2);not null" json:"discount"`
+	Status      RedemptionStatus `gorm:"type:varchar(20);not null" json:"status"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent"
+	DiscountFixed   DiscountType = "fixed"
+)
+
+type PromoStatus string
+
+const (
+	PromoActive   PromoStatus = "active"
+	PromoDisabled PromoStatus = "disabled"
+)
+
+type RedemptionStatus string
+
+const (
+	RedemptionApplied  RedemptionStatus = "applied"
+	RedemptionReleased RedemptionStatus = "released"
+)
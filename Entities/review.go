@@ -0,0 +1,81 @@
+package entities
+
+import "time"
+
+// Review is feedback on one market day, either from the vendor about the market or from the market about the
+// vendor. There is at most one of each per vendor, market and day.
+type Review struct {
+	ID         string       `gorm:"primaryKey;column:id" json:"id"`
+	Kind       ReviewKind   `gorm:"type:varchar(10);not null;uniqueIndex:idx_review_market_day" json:"kind"`
+	MarketID   string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_review_market_day;index" json:"market_id"`
+	VendorID   string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_review_market_day;index" json:"vendor_id"`
+	MarketDate time.Time    `gorm:"type:date;not null;uniqueIndex:idx_review_market_day" json:"market_date"`
+	BookingID  string       `gorm:"type:varchar(36);not null" json:"booking_id"`
+	AuthorID   string       `gorm:"type:varchar(36);not null" json:"author_id"` // The vendor, or the provider or staff member
+	Role       string       `gorm:"type:varchar(20);not null" json:"role"`      // vendor, provider or staff
+	Rating     int          `gorm:"not null" json:"rating"`                     // Overall, 1 to 5
+	Comment    string       `gorm:"type:text" json:"comment,omitempty"`
+	Status     ReviewStatus `gorm:"type:varchar(20);not null;default:'visible';index" json:"status"`
+	CreatedAt  time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Scores of 1 to 5 for what matters to each side, 0 when not given. Vendors rate a market's foot traffic;
+	// markets rate a vendor's punctuality and cleanliness.
+	FootTraffic int `gorm:"not null;default:0" json:"foot_traffic,omitempty"`
+	Punctuality int `gorm:"not null;default:0" json:"punctuality,omitempty"`
+	Cleanliness int `gorm:"not null;default:0" json:"cleanliness,omitempty"`
+
+	// The provider's public answer to a review of its market.
+	Reply     string     `gorm:"type:text" json:"reply,omitempty"`
+	RepliedBy string     `gorm:"type:varchar(36)" json:"replied_by,omitempty"`
+	RepliedAt *time.Time `json:"replied_at,omitempty"`
+
+	// Why an admin hid the review, see ReviewReport.
+	ModerationNote string `gorm:"type:varchar(500)" json:"moderation_note,omitempty"`
+}
+
+// ReviewKind is what a review is about.
+type ReviewKind string
+
+const (
+	ReviewOfMarket ReviewKind = "market" // Written by the vendor
+	ReviewOfVendor ReviewKind = "vendor" // Written by the market
+)
+
+type ReviewStatus string
+
+const (
+	ReviewVisible ReviewStatus = "visible"
+	ReviewHidden  ReviewStatus = "hidden" // Taken down by an admin; left out of listings and scores
+)
+
+// RatingSummary is the average of the visible reviews of a market or a vendor.
+type RatingSummary struct {
+	Count       int     `json:"count"`
+	Average     float64 `json:"average"`
+	FootTraffic float64 `json:"foot_traffic,omitempty"`
+	Punctuality float64 `json:"punctuality,omitempty"`
+	Cleanliness float64 `json:"cleanliness,omitempty"`
+}
+
+// ReviewReport is a user's complaint that a review is abusive, for an admin to act on.
+type ReviewReport struct {
+	ID         string       `gorm:"primaryKey;column:id" json:"id"`
+	ReviewID   string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_review_report_reporter" json:"review_id"`
+	Review     *Review      `gorm:"foreignKey:ReviewID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"review,omitempty"`
+	ReporterID string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_review_report_reporter" json:"reporter_id"`
+	Role       string       `gorm:"type:varchar(20);not null" json:"role"`
+	Reason     string       `gorm:"type:varchar(500);not null" json:"reason"`
+	Status     ReportStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	ResolvedBy string       `gorm:"type:varchar(36)" json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportUpheld    ReportStatus = "upheld"    // The review was hidden
+	ReportDismissed ReportStatus = "dismissed" // The review stays up
+)
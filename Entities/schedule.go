@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// MarketSchedule says on which dates a market opens. Slots for those dates are generated from TemplateID.
+type MarketSchedule struct {
+	ID              string              `gorm:"primaryKey;column:id" json:"id"`
+	MarketID        string              `gorm:"type:varchar(36);not null;uniqueIndex" json:"market_id"`
+	TemplateID      string              `gorm:"type:varchar(36);not null" json:"template_id"`
+	Frequency       ScheduleFrequency   `gorm:"type:varchar(20);not null" json:"frequency"`
+	Interval        int                 `gorm:"type:int;not null;default:1" json:"interval"`    // Weekly only: every N weeks counted from StartDate
+	Weekdays        []int               `gorm:"type:text;serializer:json" json:"weekdays"`      // 0 = Sunday ... 6 = Saturday
+	MonthWeeks      []int               `gorm:"type:text;serializer:json" json:"month_weeks"`   // Monthly only: 1-5 for the nth such weekday, -1 for the last
+	StartDate       string              `gorm:"type:varchar(10);not null" json:"start_date"`    // First date the rule applies to
+	EndDate         string              `gorm:"type:varchar(10)" json:"end_date,omitempty"`     // Optional last date
+	WeeksAhead      int                 `gorm:"type:int;not null;default:0" json:"weeks_ahead"` // 0 uses SLOT_GENERATION_WEEKS
+	SkipHolidays    bool                `gorm:"not null;default:true" json:"skip_holidays"`
+	Active          bool                `gorm:"not null;default:true" json:"active"`
+	LastGeneratedAt *time.Time          `gorm:"type:timestamptz" json:"last_generated_at,omitempty"`
+	Exceptions      []ScheduleException `gorm:"foreignKey:ScheduleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"exceptions,omitempty"`
+	CreatedAt       time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ScheduleException closes a date the rule would open, or opens one it would not.
+type ScheduleException struct {
+	ID         string        `gorm:"primaryKey;column:id" json:"id"`
+	ScheduleID string        `gorm:"type:varchar(36);not null;uniqueIndex:idx_schedule_exception_date" json:"schedule_id"`
+	Date       string        `gorm:"type:varchar(10);not null;uniqueIndex:idx_schedule_exception_date" json:"date"`
+	Kind       ExceptionKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Reason     string        `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Holiday is a platform-wide closed date, skipped by every schedule with SkipHolidays set.
+type Holiday struct {
+	Date      string    `gorm:"primaryKey;type:varchar(10)" json:"date"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type ScheduleFrequency string
+
+const (
+	FrequencyWeekly  ScheduleFrequency = "weekly"
+	FrequencyMonthly ScheduleFrequency = "monthly"
+)
+
+type ExceptionKind string
+
+const (
+	ExceptionClosed ExceptionKind = "closed"
+	ExceptionOpen   ExceptionKind = "open"
+)
+
+// OccursOn applies the recurrence rule alone; exceptions and holidays are layered on by the caller.
+func (s *MarketSchedule) OccursOn(day time.Time) bool {
+	date := day.Format("2006-01-02")
+	if date < s.StartDate || (s.EndDate != "" && date > s.EndDate) {
+		return false
+	}
+	if !containsInt(s.Weekdays, int(day.Weekday())) {
+		return false
+	}
+
+	switch s.Frequency {
+	case FrequencyWeekly:
+		if s.Interval <= 1 {
+			return true
+		}
+		start, err := time.Parse("2006-01-02", s.StartDate)
+		if err != nil {
+			return false
+		}
+		// Count whole weeks between the Sundays that start each week
+		startWeek := start.AddDate(0, 0, -int(start.Weekday()))
+		dayWeek := day.AddDate(0, 0, -int(day.Weekday()))
+		weeks := int(math.Round(dayWeek.Sub(startWeek).Hours() / 24 / 7))
+		return weeks%s.Interval == 0
+	case FrequencyMonthly:
+		nth := (day.Day()-1)/7 + 1
+		last := day.AddDate(0, 0, 7).Month() != day.Month()
+		return containsInt(s.MonthWeeks, nth) || (last && containsInt(s.MonthWeeks, -1))
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
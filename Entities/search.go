@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+type SearchKind string
+
+const (
+	SearchMarket SearchKind = "market"
+	SearchVendor SearchKind = "vendor"
+)
+
+// SearchDocument is a market or vendor stall profile in the full-text index.
+type SearchDocument struct {
+	ID        string       `gorm:"primaryKey;column:id" json:"id"`
+	Kind      SearchKind   `gorm:"type:varchar(20);not null;uniqueIndex:idx_search_document_ref" json:"kind"`
+	RefID     string       `gorm:"type:varchar(36);not null;uniqueIndex:idx_search_document_ref" json:"ref_id"`
+	Terms     []SearchTerm `gorm:"foreignKey:DocumentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UpdatedAt time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SearchTerm says a document contains a term and how much the fields it appears in weigh.
+type SearchTerm struct {
+	DocumentID string  `gorm:"primaryKey;type:varchar(36)" json:"document_id"`
+	Term       string  `gorm:"primaryKey;type:varchar(64);index:idx_search_term" json:"term"`
+	Weight     float64 `gorm:"not null;default:0" json:"weight"`
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BankSlip is a transfer slip a vendor uploaded to pay a pending booking.
+type BankSlip struct {
+	ID            string           `gorm:"primaryKey;column:id" json:"id"`
+	BookingID     string           `gorm:"type:varchar(36);not null;index" json:"booking_id"`
+	Booking       *Booking         `gorm:"foreignKey:BookingID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"booking,omitempty"`
+	PaymentID     string           `gorm:"type:varchar(36);not null" json:"payment_id"`
+	TransactionID string           `gorm:"type:varchar(36);not null" json:"transaction_id"`
+	VendorID      string           `gorm:"type:varchar(36);not null;index" json:"vendor_id"`
+	TransRef      string           `gorm:"type:varchar(50);not null;uniqueIndex" json:"trans_ref"`
+	BankCode      string           `gorm:"type:varchar(10)" json:"bank_code"`
+	BankName      string           `gorm:"type:varchar(100)" json:"bank_name,omitempty"`
+	Amount        float64          `gorm:"type:decimal(10,2);not null" json:"amount"`
+	AmountSource  SlipAmountSource `gorm:"type:varchar(20);not null" json:"amount_source"`
+	ImageID       string           `gorm:"type:varchar(36)" json:"image_id,omitempty"` // The slip picture, see Media
+	ImageURL      string           `gorm:"-" json:"image_url,omitempty"`
+	ThumbnailURL  string           `gorm:"-" json:"thumbnail_url,omitempty"`
+	Status        SlipStatus       `gorm:"type:varchar(20);not null;index" json:"status"`
+	ReviewBy      *time.Time       `gorm:"type:timestamptz" json:"review_by,omitempty"` // The booking is cancelled if nobody has reviewed the slip by then
+	ReviewedBy    string           `gorm:"type:varchar(36)" json:"reviewed_by,omitempty"`
+	ReviewNote    string           `gorm:"type:varchar(255)" json:"review_note,omitempty"`
+	ReviewedAt    *time.Time       `gorm:"type:timestamptz" json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AfterFind signs the URLs of the slip picture.
+func (s *BankSlip) AfterFind(tx *gorm.DB) error {
+	s.ImageURL, s.ThumbnailURL = MediaURLs(s.ImageID)
+	return nil
+}
+
+type SlipStatus string
+
+const (
+	SlipPendingReview SlipStatus = "pending_review"
+	SlipApproved      SlipStatus = "approved"
+	SlipRejected      SlipStatus = "rejected"
+	SlipExpired       SlipStatus = "expired" // Not reviewed before its booking was cancelled
+)
+
+// SlipAmountSource tells whether the amount was read from the slip QR or typed in by the vendor.
+type SlipAmountSource string
+
+const (
+	SlipAmountQR       SlipAmountSource = "qr"
+	SlipAmountDeclared SlipAmountSource = "declared"
+)
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Slot struct {
@@ -9,6 +11,7 @@ type Slot struct {
 	MarketID  string     `gorm:"type:varchar(36);not null" json:"market_id"`
 	Name      string     `gorm:"type:varchar(100);not null" json:"name"`
 	Zone      string     `gorm:"type:varchar(50);not null" json:"zone"`
+	ZoneID    string     `gorm:"type:varchar(36);index" json:"zone_id,omitempty"`
 	Width     int        `gorm:"type:int;not null" json:"width"`
 	Height    int        `gorm:"type:int;not null" json:"height"`
 	Price     float64    `gorm:"type:decimal(10,2);not null" json:"price"`
@@ -20,7 +23,22 @@ type Slot struct {
 	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt *time.Time `gorm:"index" json:"deleted_at,omitempty"`
+	Adjacent  []string   `gorm:"type:text;serializer:json" json:"adjacent,omitempty"`  // Slots declared next to this one, besides those touching it on the map
+	Amenities []string   `gorm:"type:text;serializer:json" json:"amenities,omitempty"` // IDs of the market's feature amenities this stall has
+	SlotGeometry
+
+	// Uploaded photo of the stall, see Media.
+	ImageID           string `gorm:"type:varchar(36)" json:"image_id,omitempty"`
+	ImageURL          string `gorm:"-" json:"image_url,omitempty"`
+	ImageThumbnailURL string `gorm:"-" json:"image_thumbnail_url,omitempty"`
 }
+
+// AfterFind signs the URLs of the slot's uploaded photo.
+func (s *Slot) AfterFind(tx *gorm.DB) error {
+	s.ImageURL, s.ImageThumbnailURL = MediaURLs(s.ImageID)
+	return nil
+}
+
 type SlotStatus string
 type Category string
 
@@ -0,0 +1,87 @@
+package entities
+
+import "time"
+
+// StaffMember is a login invited by a provider to help run some of its markets. What it may do in each market
+// depends on its role there, see StaffAssignment.
+type StaffMember struct {
+	ID              string            `gorm:"primaryKey;column:id" json:"id"`
+	ProviderID      string            `gorm:"type:varchar(36);not null;index" json:"provider_id"`
+	Name            string            `gorm:"type:varchar(100);not null" json:"name"`
+	Email           string            `gorm:"type:varchar(100);not null;uniqueIndex" json:"email"`
+	Username        *string           `gorm:"type:varchar(100);uniqueIndex" json:"username,omitempty"` // Chosen when the invite is accepted
+	Password        string            `gorm:"type:varchar(100)" json:"-"`
+	Status          StaffStatus       `gorm:"type:varchar(20);not null;index" json:"status"`
+	InviteTokenHash string            `gorm:"type:varchar(64);index" json:"-"` // SHA-256 of the token sent with the invite
+	InviteExpiresAt *time.Time        `json:"invite_expires_at,omitempty"`
+	Assignments     []StaffAssignment `gorm:"foreignKey:StaffID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"assignments"`
+	CreatedAt       time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type StaffStatus string
+
+const (
+	StaffInvited  StaffStatus = "invited"
+	StaffActive   StaffStatus = "active"
+	StaffDisabled StaffStatus = "disabled"
+)
+
+// StaffAssignment gives a staff member one role in one market.
+type StaffAssignment struct {
+	ID        string    `gorm:"primaryKey;column:id" json:"id"`
+	StaffID   string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_staff_assignment_market" json:"staff_id"`
+	MarketID  string    `gorm:"type:varchar(36);not null;uniqueIndex:idx_staff_assignment_market;index" json:"market_id"`
+	Role      StaffRole `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type StaffRole string
+
+const (
+	StaffManager StaffRole = "manager" // Runs the market: details, hours, layouts, pricing and its vendor roster
+	StaffCashier StaffRole = "cashier" // Takes payments: bank slips, refunds and cancelled market days
+	StaffChecker StaffRole = "checker" // Checks vendors in at the gate
+)
+
+// Permission is something a provider route lets its caller do. A provider may do everything in its own
+// markets; staff may do what their role in the market allows.
+type Permission string
+
+const (
+	PermManageProvider Permission = "manage_provider" // Account, staff, new markets, promotions and finance; providers only
+	PermManageMarket   Permission = "manage_market"   // Market details, hours, zones, amenities and schedules
+	PermManageLayout   Permission = "manage_layout"   // Slots, layout versions and templates
+	PermManagePricing  Permission = "manage_pricing"  // Pricing rules and combo prices
+	PermReviewVendors  Permission = "review_vendors"  // Vendor applications, the roster and the blacklist
+	PermViewBookings   Permission = "view_bookings"   // Day sheets
+	PermTakePayments   Permission = "take_payments"   // Reviewing bank slips and refunding by cancelling a market day
+	PermCheckIn        Permission = "check_in"        // Checking vendors in on the day
+)
+
+var rolePermissions = map[StaffRole][]Permission{
+	StaffManager: {PermManageMarket, PermManageLayout, PermManagePricing, PermReviewVendors, PermViewBookings, PermCheckIn},
+	StaffCashier: {PermTakePayments, PermViewBookings, PermCheckIn},
+	StaffChecker: {PermViewBookings, PermCheckIn},
+}
+
+// Valid reports whether the role is one staff can be given.
+func (r StaffRole) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions lists what the role allows.
+func (r StaffRole) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// Allows reports whether the role grants the permission.
+func (r StaffRole) Allows(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
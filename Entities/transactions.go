@@ -2,6 +2,8 @@ package entities
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Transaction struct {
@@ -21,6 +23,16 @@ type Transaction struct {
 	CreatedAt       time.Time         `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt       time.Time         `gorm:"type:timestamp;default:CURRENT_TIMESTAMP" json:"updated_at"`
 	DeletedAt       *time.Time        `gorm:"index" json:"deleted_at,omitempty"`
+
+	// The payment QR kept in media storage, see Media. Image above holds it inline on older transactions.
+	ImageID  string `gorm:"type:varchar(36)" json:"image_id,omitempty"`
+	ImageURL string `gorm:"-" json:"image_url,omitempty"`
+}
+
+// AfterFind signs the URL of the transaction's payment QR.
+func (t *Transaction) AfterFind(tx *gorm.DB) error {
+	t.ImageURL, _ = MediaURLs(t.ImageID)
+	return nil
 }
 
 type TransactionStatus string
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Vendor struct {
@@ -17,4 +19,15 @@ type Vendor struct {
 	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt *time.Time `gorm:"index" json:"deleted_at,omitempty"`
 	Bookings  []Booking  `gorm:"foreignKey:VendorID" json:"bookings"`
+
+	// Uploaded profile picture, see Media. Image above is an older free-form URL.
+	ImageID           string `gorm:"type:varchar(36)" json:"image_id,omitempty"`
+	ImageURL          string `gorm:"-" json:"image_url,omitempty"`
+	ImageThumbnailURL string `gorm:"-" json:"image_thumbnail_url,omitempty"`
+}
+
+// AfterFind signs the URLs of the vendor's uploaded picture.
+func (v *Vendor) AfterFind(tx *gorm.DB) error {
+	v.ImageURL, v.ImageThumbnailURL = MediaURLs(v.ImageID)
+	return nil
 }
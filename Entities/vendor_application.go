@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VendorApplication is a vendor's request to join a market's roster, and the market's decision on it. A vendor
+// has at most one per market; applying again updates it.
+type VendorApplication struct {
+	ID          string            `gorm:"primaryKey;column:id" json:"id"`
+	MarketID    string            `gorm:"type:varchar(36);not null;uniqueIndex:idx_vendor_application_market" json:"market_id"`
+	VendorID    string            `gorm:"type:varchar(36);not null;uniqueIndex:idx_vendor_application_market;index" json:"vendor_id"`
+	Vendor      *Vendor           `gorm:"foreignKey:VendorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"vendor,omitempty"`
+	Status      ApplicationStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	StallName   string            `gorm:"type:varchar(100)" json:"stall_name"`
+	Description string            `gorm:"type:text" json:"description"`
+	Category    Category          `gorm:"type:varchar(50)" json:"category,omitempty"`
+	Products    []string          `gorm:"type:text;serializer:json" json:"products"`
+	PhotoIDs    []string          `gorm:"type:text;serializer:json" json:"photo_ids"`    // Uploaded application_photo media
+	DocumentIDs []string          `gorm:"type:text;serializer:json" json:"document_ids"` // Uploaded application_document media, e.g. licences
+	Photos      []MediaFile       `gorm:"-" json:"photos"`
+	Documents   []MediaFile       `gorm:"-" json:"documents"`
+	ReviewNote  string            `gorm:"type:varchar(500)" json:"review_note,omitempty"` // The market's reason for its last decision
+	ReviewedBy  string            `gorm:"type:varchar(36)" json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"`
+	SubmittedAt *time.Time        `json:"submitted_at,omitempty"` // Nil for vendors blacklisted without applying
+	CreatedAt   time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// MediaFile is an uploaded file listed by what uses it, with its signed URLs.
+type MediaFile struct {
+	ID           string `json:"id"`
+	URL          string `json:"url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// AfterFind signs the URLs of the application's photos and documents.
+func (a *VendorApplication) AfterFind(tx *gorm.DB) error {
+	a.Photos = MediaFiles(a.PhotoIDs)
+	a.Documents = MediaFiles(a.DocumentIDs)
+	return nil
+}
+
+func MediaFiles(ids []string) []MediaFile {
+	files := make([]MediaFile, 0, len(ids))
+	for _, id := range ids {
+		url, thumbnail := MediaURLs(id)
+		files = append(files, MediaFile{ID: id, URL: url, ThumbnailURL: thumbnail})
+	}
+	return files
+}
+
+type ApplicationStatus string
+
+const (
+	ApplicationPending          ApplicationStatus = "pending"           // Waiting for the market to review it
+	ApplicationChangesRequested ApplicationStatus = "changes_requested" // Sent back to the vendor to fix and submit again
+	ApplicationApproved         ApplicationStatus = "approved"          // On the market's roster
+	ApplicationRejected         ApplicationStatus = "rejected"          // Turned down; the vendor may apply again
+	ApplicationRevoked          ApplicationStatus = "revoked"           // Taken off the roster; the vendor may apply again
+	ApplicationBlacklisted      ApplicationStatus = "blacklisted"       // Barred from applying and booking until lifted
+)
+
+// VendorApplicationEvent records one change of an application's status, by its vendor or the market.
+type VendorApplicationEvent struct {
+	ID            string            `gorm:"primaryKey;column:id" json:"id"`
+	ApplicationID string            `gorm:"type:varchar(36);not null;index" json:"application_id"`
+	FromStatus    ApplicationStatus `gorm:"type:varchar(20)" json:"from_status,omitempty"` // Empty for the first submission
+	ToStatus      ApplicationStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Note          string            `gorm:"type:varchar(500)" json:"note,omitempty"`
+	ActorID       string            `gorm:"type:varchar(36);not null" json:"actor_id"`
+	Role          string            `gorm:"type:varchar(20);not null" json:"role"` // vendor, provider, staff or admin
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VendorProfile is a vendor's public stall: what it is called and what it sells.
+type VendorProfile struct {
+	VendorID    string    `gorm:"primaryKey;type:varchar(36)" json:"vendor_id"`
+	StallName   string    `gorm:"type:varchar(100);not null" json:"stall_name"`
+	Description string    `gorm:"type:text" json:"description"`
+	Category    Category  `gorm:"type:varchar(50)" json:"category,omitempty"`
+	Products    []string  `gorm:"type:text;serializer:json" json:"products"`
+	Image       string    `gorm:"type:text" json:"image"`
+	Public      bool      `gorm:"not null;default:true" json:"public"` // Hidden profiles are left out of search
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Uploaded stall photo, see Media. Image above is an older free-form URL.
+	ImageID           string `gorm:"type:varchar(36)" json:"image_id,omitempty"`
+	ImageURL          string `gorm:"-" json:"image_url,omitempty"`
+	ImageThumbnailURL string `gorm:"-" json:"image_thumbnail_url,omitempty"`
+
+	// Average of what markets said about the vendor in its visible reviews.
+	Rating *RatingSummary `gorm:"-" json:"rating,omitempty"`
+}
+
+// AfterFind signs the URLs of the stall's uploaded photo.
+func (p *VendorProfile) AfterFind(tx *gorm.DB) error {
+	p.ImageURL, p.ImageThumbnailURL = MediaURLs(p.ImageID)
+	return nil
+}
+
+// ListImage is the picture to show for the stall in a list: the uploaded thumbnail, or the older link.
+func (p *VendorProfile) ListImage() string {
+	if p.ImageThumbnailURL != "" {
+		return p.ImageThumbnailURL
+	}
+	return p.Image
+}
@@ -0,0 +1,140 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// WalletCredit is one lot of credit held by a vendor. Lots are spent earliest-expiry first.
+type WalletCredit struct {
+	ID         string             `gorm:"primaryKey;column:id" json:"id"`
+	VendorID   string             `gorm:"type:varchar(36);not null;index" json:"vendor_id"`
+	Vendor     *Vendor            `gorm:"foreignKey:VendorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"vendor,omitempty"`
+	Source     WalletCreditSource `gorm:"type:varchar(20);not null" json:"source"`
+	IssuerType LedgerAccountType  `gorm:"type:varchar(30);not null" json:"issuer_type"`
+	IssuerID   string             `gorm:"type:varchar(36);not null;index" json:"issuer_id"`
+	BookingID  string             `gorm:"type:varchar(36)" json:"booking_id,omitempty"`
+	Amount     float64            `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Remaining  float64            `gorm:"type:decimal(10,2);not null" json:"remaining"`
+	Note       string             `gorm:"type:varchar(255)" json:"note,omitempty"`
+	ExpiresAt  *time.Time         `gorm:"type:timestamptz;index" json:"expires_at,omitempty"`
+	CreatedAt  time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WalletTransaction is the history line for every change to a credit lot.
+type WalletTransaction struct {
+	ID        string                `gorm:"primaryKey;column:id" json:"id"`
+	VendorID  string                `gorm:"type:varchar(36);not null;index" json:"vendor_id"`
+	CreditID  string                `gorm:"type:varchar(36);not null;index" json:"credit_id"`
+	IssuerID  string                `gorm:"type:varchar(36);not null;index" json:"issuer_id"`
+	BookingID string                `gorm:"type:varchar(36);index" json:"booking_id,omitempty"`
+	Type      WalletTransactionType `gorm:"type:varchar(20);not null" json:"type"`
+	Amount    float64               `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Note      string                `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedAt time.Time             `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type WalletCreditSource string
+
+const (
+	CreditSourceRefund    WalletCreditSource = "refund"
+	CreditSourceGoodwill  WalletCreditSource = "goodwill"
+	CreditSourcePromotion WalletCreditSource = "promotion"
+)
+
+type WalletTransactionType string
+
+const (
+	WalletIssue   WalletTransactionType = "issue"
+	WalletRedeem  WalletTransactionType = "redeem"
+	WalletRestore WalletTransactionType = "restore"
+	WalletExpire  WalletTransactionType = "expire"
+)
+
+// WalletTake is what one redemption spends from one lot.
+type WalletTake struct {
+	Credit WalletCredit
+	Amount float64
+}
+
+// SpendableAt reports whether the lot can pay for a booking in one of providerID's markets at now. Platform credit
+// pays anywhere; provider credit only at the provider that funded it.
+func (c *WalletCredit) SpendableAt(providerID string, now time.Time) bool {
+	if c.Remaining <= 0 || (c.ExpiresAt != nil && !c.ExpiresAt.After(now)) {
+		return false
+	}
+	return c.IssuerType == AccountPlatform || (c.IssuerType == AccountProvider && c.IssuerID == providerID)
+}
+
+// PlanRedemption spends up to amount from the lots usable at providerID's markets, earliest expiry first and lots
+// that never expire last, oldest first within the same expiry.
+func PlanRedemption(lots []WalletCredit, providerID string, amount float64, now time.Time) []WalletTake {
+	usable := make([]WalletCredit, 0, len(lots))
+	for _, lot := range lots {
+		if lot.SpendableAt(providerID, now) {
+			usable = append(usable, lot)
+		}
+	}
+	sort.SliceStable(usable, func(i, j int) bool {
+		a, b := usable[i].ExpiresAt, usable[j].ExpiresAt
+		switch {
+		case a == nil && b == nil:
+			return usable[i].CreatedAt.Before(usable[j].CreatedAt)
+		case a == nil || b == nil:
+			return b == nil
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return usable[i].CreatedAt.Before(usable[j].CreatedAt)
+	})
+
+	takes := make([]WalletTake, 0)
+	left := RoundMoney(amount)
+	for _, lot := range usable {
+		if left <= 0 {
+			break
+		}
+		take := RoundMoney(math.Min(lot.Remaining, left))
+		takes = append(takes, WalletTake{Credit: lot, Amount: take})
+		left = RoundMoney(left - take)
+	}
+	return takes
+}
+
+// PendingRestores works out, from a booking's redeem and restore lines, what each lot is still owed back. Lots
+// come out in the order the booking first spent them.
+func PendingRestores(history []WalletTransaction) []WalletTake {
+	owed := make(map[string]float64)
+	first := make(map[string]WalletTransaction)
+	order := make([]string, 0)
+	for _, line := range history {
+		switch line.Type {
+		case WalletRedeem:
+			owed[line.CreditID] += line.Amount
+		case WalletRestore:
+			owed[line.CreditID] -= line.Amount
+		default:
+			continue
+		}
+		if _, seen := first[line.CreditID]; !seen {
+			first[line.CreditID] = line
+			order = append(order, line.CreditID)
+		}
+	}
+
+	restores := make([]WalletTake, 0)
+	for _, creditID := range order {
+		amount := RoundMoney(owed[creditID])
+		if amount <= 0 {
+			continue
+		}
+		line := first[creditID]
+		restores = append(restores, WalletTake{
+			Credit: WalletCredit{ID: creditID, VendorID: line.VendorID, IssuerID: line.IssuerID},
+			Amount: amount,
+		})
+	}
+	return restores
+}
@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanRedemption(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		when := now.AddDate(0, 0, days)
+		return &when
+	}
+	lot := func(id string, issuerType LedgerAccountType, issuerID string, remaining float64, expiresAt *time.Time, age int) WalletCredit {
+		return WalletCredit{
+			ID:         id,
+			IssuerType: issuerType,
+			IssuerID:   issuerID,
+			Remaining:  remaining,
+			ExpiresAt:  expiresAt,
+			CreatedAt:  now.AddDate(0, 0, -age),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		lots       []WalletCredit
+		providerID string
+		amount     float64
+		want       map[string]float64
+		wantOrder  []string
+	}{
+		{
+			name:      "no lots",
+			amount:    100,
+			wantOrder: []string{},
+		},
+		{
+			name:      "one lot covers the price",
+			lots:      []WalletCredit{lot("a", AccountProvider, "p1", 500, nil, 1)},
+			amount:    120.5,
+			wantOrder: []string{"a"},
+			want:      map[string]float64{"a": 120.5},
+		},
+		{
+			name:      "lot smaller than the price is emptied",
+			lots:      []WalletCredit{lot("a", AccountProvider, "p1", 80, nil, 1)},
+			amount:    120,
+			wantOrder: []string{"a"},
+			want:      map[string]float64{"a": 80},
+		},
+		{
+			name: "earliest expiry first, never-expiring last",
+			lots: []WalletCredit{
+				lot("forever", AccountPlatform, PlatformOwnerID, 100, nil, 9),
+				lot("month", AccountProvider, "p1", 50, at(30), 1),
+				lot("week", AccountProvider, "p1", 50, at(7), 1),
+			},
+			amount:    180,
+			wantOrder: []string{"week", "month", "forever"},
+			want:      map[string]float64{"week": 50, "month": 50, "forever": 80},
+		},
+		{
+			name: "oldest first for the same expiry",
+			lots: []WalletCredit{
+				lot("new", AccountProvider, "p1", 50, nil, 1),
+				lot("old", AccountProvider, "p1", 50, nil, 5),
+			},
+			amount:    70,
+			wantOrder: []string{"old", "new"},
+			want:      map[string]float64{"old": 50, "new": 20},
+		},
+		{
+			name: "another provider's credit is not spent",
+			lots: []WalletCredit{
+				lot("mine", AccountProvider, "p1", 30, nil, 1),
+				lot("theirs", AccountProvider, "p2", 500, at(1), 1),
+				lot("platform", AccountPlatform, PlatformOwnerID, 30, nil, 2),
+			},
+			amount:    100,
+			wantOrder: []string{"platform", "mine"},
+			want:      map[string]float64{"platform": 30, "mine": 30},
+		},
+		{
+			name: "expired and empty lots are skipped",
+			lots: []WalletCredit{
+				lot("expired", AccountPlatform, PlatformOwnerID, 100, at(0), 1),
+				lot("empty", AccountPlatform, PlatformOwnerID, 0, nil, 1),
+				lot("good", AccountPlatform, PlatformOwnerID, 100, nil, 1),
+			},
+			amount:    40,
+			wantOrder: []string{"good"},
+			want:      map[string]float64{"good": 40},
+		},
+		{
+			name:      "amounts are rounded to satang",
+			lots:      []WalletCredit{lot("a", AccountPlatform, PlatformOwnerID, 0.1, nil, 2), lot("b", AccountPlatform, PlatformOwnerID, 0.2, nil, 1)},
+			amount:    0.3,
+			wantOrder: []string{"a", "b"},
+			want:      map[string]float64{"a": 0.1, "b": 0.2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providerID := tt.providerID
+			if providerID == "" {
+				providerID = "p1"
+			}
+			takes := PlanRedemption(tt.lots, providerID, tt.amount, now)
+
+			order := make([]string, 0, len(takes))
+			got := make(map[string]float64, len(takes))
+			for _, take := range takes {
+				order = append(order, take.Credit.ID)
+				got[take.Credit.ID] = take.Amount
+			}
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("lots spent in order %v, want %v", order, tt.wantOrder)
+			}
+			if len(tt.want) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("spent %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPendingRestores(t *testing.T) {
+	line := func(creditID string, kind WalletTransactionType, amount float64) WalletTransaction {
+		return WalletTransaction{CreditID: creditID, VendorID: "v1", IssuerID: "issuer-" + creditID, BookingID: "b1", Type: kind, Amount: amount}
+	}
+
+	tests := []struct {
+		name    string
+		history []WalletTransaction
+		want    []WalletTake
+	}{
+		{
+			name: "nothing spent",
+			want: []WalletTake{},
+		},
+		{
+			name:    "everything spent is owed back",
+			history: []WalletTransaction{line("a", WalletRedeem, 50), line("b", WalletRedeem, 25.5)},
+			want: []WalletTake{
+				{Credit: WalletCredit{ID: "a", VendorID: "v1", IssuerID: "issuer-a"}, Amount: 50},
+				{Credit: WalletCredit{ID: "b", VendorID: "v1", IssuerID: "issuer-b"}, Amount: 25.5},
+			},
+		},
+		{
+			name:    "restoring twice puts nothing back the second time",
+			history: []WalletTransaction{line("a", WalletRedeem, 50), line("a", WalletRestore, 50)},
+			want:    []WalletTake{},
+		},
+		{
+			name:    "a partly restored lot gets the rest",
+			history: []WalletTransaction{line("a", WalletRedeem, 50), line("a", WalletRestore, 20), line("b", WalletRedeem, 10)},
+			want: []WalletTake{
+				{Credit: WalletCredit{ID: "a", VendorID: "v1", IssuerID: "issuer-a"}, Amount: 30},
+				{Credit: WalletCredit{ID: "b", VendorID: "v1", IssuerID: "issuer-b"}, Amount: 10},
+			},
+		},
+		{
+			name:    "issues and expiries are not redemptions",
+			history: []WalletTransaction{line("a", WalletIssue, 500), line("a", WalletExpire, 100)},
+			want:    []WalletTake{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PendingRestores(tt.history); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PendingRestores() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrZoneFull is returned when a booking would take a zone past its cap for a category.
+var ErrZoneFull = errors.New("zone is full")
+
+// Zone is a named area of a market floor. Slots point at it by ZoneID and keep a copy of its name in Slot.Zone.
+type Zone struct {
+	ID          string `gorm:"primaryKey;column:id" json:"id"`
+	MarketID    string `gorm:"type:varchar(36);not null;uniqueIndex:idx_market_zone_name" json:"market_id"`
+	Name        string `gorm:"type:varchar(50);not null;uniqueIndex:idx_market_zone_name" json:"name"`
+	Colour      string `gorm:"type:varchar(7)" json:"colour,omitempty"`
+	Description string `gorm:"type:text" json:"description,omitempty"`
+	// Stalls laid out without a size or price take these
+	DefaultPrice  float64 `gorm:"type:decimal(10,2);not null;default:0" json:"default_price"`
+	DefaultWidth  int     `gorm:"type:int;not null;default:0" json:"default_width"`
+	DefaultHeight int     `gorm:"type:int;not null;default:0" json:"default_height"`
+	// Empty means every category is welcome
+	AllowedCategories []Category `gorm:"type:text;serializer:json" json:"allowed_categories"`
+	// At most this many stalls of a category per market day, e.g. {"food": 5}
+	CategoryCaps map[Category]int `gorm:"type:text;serializer:json" json:"category_caps,omitempty"`
+	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Allows reports whether stalls of the category may be laid out or booked in the zone.
+func (z *Zone) Allows(category Category) bool {
+	if len(z.AllowedCategories) == 0 {
+		return true
+	}
+	for _, allowed := range z.AllowedCategories {
+		if allowed == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Cap is how many stalls of the category the zone takes per day, or 0 for no limit.
+func (z *Zone) Cap(category Category) int {
+	return z.CategoryCaps[category]
+}
+
+// Fits reports whether a zone with booked stalls of the category already taken has room for adding more.
+func (z *Zone) Fits(category Category, booked, adding int) bool {
+	limit := z.Cap(category)
+	return limit == 0 || booked+adding <= limit
+}
+
+// ZoneLoad is the stalls of one category a booking takes in one zone on one day.
+type ZoneLoad struct {
+	ZoneID   string
+	Category Category
+	Date     string
+	SlotIDs  []string
+}
+
+// ZoneLoads groups the zoned slots of a booking by zone, category and day, in the order they first appear.
+// Slots outside any zone have no cap and are left out.
+func ZoneLoads(slots []*Slot) []ZoneLoad {
+	loads := []ZoneLoad{}
+	type loadKey struct {
+		zoneID   string
+		category Category
+		date     string
+	}
+	index := make(map[loadKey]int)
+	for _, slot := range slots {
+		if slot.ZoneID == "" {
+			continue
+		}
+		date := slot.Date
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		key := loadKey{slot.ZoneID, slot.Category, date}
+		i, ok := index[key]
+		if !ok {
+			i = len(loads)
+			index[key] = i
+			loads = append(loads, ZoneLoad{ZoneID: slot.ZoneID, Category: slot.Category, Date: date})
+		}
+		loads[i].SlotIDs = append(loads[i].SlotIDs, slot.ID)
+	}
+	return loads
+}
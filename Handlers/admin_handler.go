@@ -0,0 +1,46 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type AdminHandler struct {
+	useCase *Usecase.AdminUseCase
+}
+
+func NewAdminHandler(useCase *Usecase.AdminUseCase) *AdminHandler {
+	return &AdminHandler{useCase: useCase}
+}
+
+// AdminLogin godoc
+// @Summary Admin Login
+// @Description Sign in as a platform admin
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param login body dtos.AdminLoginRequest true "Admin login data"
+// @Success 200 {object} dtos.AdminLoginResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /auth/admin/login [post]
+func (h *AdminHandler) AdminLogin(c *fiber.Ctx) error {
+	var req entitiesDtos.AdminLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	login, errRes := h.useCase.Login(req.Username, req.Password)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Admin login successful",
+		"data":    login,
+	})
+}
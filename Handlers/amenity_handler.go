@@ -0,0 +1,124 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type AmenityHandler struct {
+	useCase *Usecase.AmenityUseCase
+}
+
+func NewAmenityHandler(useCase *Usecase.AmenityUseCase) *AmenityHandler {
+	return &AmenityHandler{useCase: useCase}
+}
+
+// CreateAmenity godoc
+// @Summary Create a market amenity
+// @Description Add a feature stalls can have, such as electricity or shade, or an add-on vendors can buy when booking
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param amenity body dtos.AmenityRequest true "Amenity data"
+// @Success 201 {object} entities.Amenity
+// @Router /markets/{id}/amenities [post]
+// @Security BearerAuth
+func (h *AmenityHandler) CreateAmenity(c *fiber.Ctx) error {
+	var req entitiesDtos.AmenityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	amenity, errRes := h.useCase.CreateAmenity(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Amenity created successfully",
+		"data":    amenity,
+	})
+}
+
+// GetAmenities godoc
+// @Summary Get a market's amenities
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} []entities.Amenity
+// @Router /markets/{id}/amenities [get]
+func (h *AmenityHandler) GetAmenities(c *fiber.Ctx) error {
+	amenities, errRes := h.useCase.GetAmenities(c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Amenities retrieved successfully",
+		"data":    amenities,
+	})
+}
+
+// UpdateAmenity godoc
+// @Summary Update a market amenity
+// @Description Replace an amenity's settings; a feature assigned to slots stays a feature
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Amenity ID"
+// @Param amenity body dtos.AmenityRequest true "Amenity data"
+// @Success 200 {object} entities.Amenity
+// @Router /amenities/{id} [put]
+// @Security BearerAuth
+func (h *AmenityHandler) UpdateAmenity(c *fiber.Ctx) error {
+	var req entitiesDtos.AmenityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	amenity, errRes := h.useCase.UpdateAmenity(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Amenity updated successfully",
+		"data":    amenity,
+	})
+}
+
+// DeleteAmenity godoc
+// @Summary Delete a market amenity
+// @Description Delete an amenity no live slot has; bookings keep the add-ons they paid for
+// @Tags amenities
+// @Accept json
+// @Produce json
+// @Param id path string true "Amenity ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /amenities/{id} [delete]
+// @Security BearerAuth
+func (h *AmenityHandler) DeleteAmenity(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.DeleteAmenity(providerID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Amenity deleted successfully",
+	})
+}
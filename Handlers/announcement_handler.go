@@ -0,0 +1,155 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type AnnouncementHandler struct {
+	useCase *Usecase.AnnouncementUseCase
+}
+
+func NewAnnouncementHandler(useCase *Usecase.AnnouncementUseCase) *AnnouncementHandler {
+	return &AnnouncementHandler{useCase: useCase}
+}
+
+// SendAnnouncement godoc
+// @Summary Send an announcement to a market's vendors
+// @Description Notify every vendor booked on a market day, in one zone or stall category on that day, or on the market's approved roster
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param announcement body dtos.AnnouncementRequest true "Announcement"
+// @Success 201 {object} entities.Announcement
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /markets/{id}/announcements [post]
+// @Security BearerAuth
+func (h *AnnouncementHandler) SendAnnouncement(c *fiber.Ctx) error {
+	var req entitiesDtos.AnnouncementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	announcement, errRes := h.useCase.SendAnnouncement(providerID, actorID(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Announcement sent successfully",
+		"data":    announcement,
+	})
+}
+
+// GetMarketAnnouncements godoc
+// @Summary List a market's announcements
+// @Description Newest first, with how many vendors each reached and how many read it
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {array} entities.Announcement
+// @Router /markets/{id}/announcements [get]
+// @Security BearerAuth
+func (h *AnnouncementHandler) GetMarketAnnouncements(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	announcements, errRes := h.useCase.GetMarketAnnouncements(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Announcements retrieved successfully",
+		"data":    announcements,
+	})
+}
+
+// GetReceipts godoc
+// @Summary List an announcement's read receipts
+// @Description Every vendor the announcement reached, unread first
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {array} entities.AnnouncementReceipt
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /announcements/{id}/receipts [get]
+// @Security BearerAuth
+func (h *AnnouncementHandler) GetReceipts(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	receipts, errRes := h.useCase.GetReceipts(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Receipts retrieved successfully",
+		"data":    receipts,
+	})
+}
+
+// GetMyAnnouncements godoc
+// @Summary List announcements for my upcoming bookings
+// @Description Announcements about the signed-in vendor's upcoming market days, including those sent before it booked, and recent roster announcements, newest first
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Success 200 {array} entities.Announcement
+// @Router /announcements/mine [get]
+// @Security BearerAuth
+func (h *AnnouncementHandler) GetMyAnnouncements(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	announcements, errRes := h.useCase.GetVendorAnnouncements(vendorID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Announcements retrieved successfully",
+		"data":    announcements,
+	})
+}
+
+// MarkAnnouncementRead godoc
+// @Summary Mark an announcement as read
+// @Tags announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /announcements/{id}/read [patch]
+// @Security BearerAuth
+func (h *AnnouncementHandler) MarkAnnouncementRead(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.MarkRead(vendorID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Announcement marked as read",
+	})
+}
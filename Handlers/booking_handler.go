@@ -27,7 +27,14 @@ func NewBookingHandler(useCase *Usecase.BookingUseCase) *BookingHandler {
 // @Failure 409 {object} string "Booking already exists"
 // @Failure 500 {object} string "Internal server error"
 // @Router /bookings/create [post]
+// @Security BearerAuth
 func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
 	var req entitiesDtos.BookingRequest
 	if err := c.BodyParser(&req); err != nil {
 		log.Printf("Failed to parse request body: %v", err) // Log the detailed error
@@ -36,6 +43,8 @@ func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
 			"details": err.Error(),
 		})
 	}
+	// Vendors only ever book, and spend wallet credit, as themselves
+	req.VendorID, _ = c.Locals("userID").(string)
 
 	booking, errResponse := h.useCase.CreateBooking(&req)
 	if errResponse != nil {
@@ -53,6 +62,98 @@ func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
 	})
 }
 
+// CreateGroupBooking godoc
+// @Summary Book adjacent slots together
+// @Description Reserve two or more neighbouring slots of one market day as a single booking, all or nothing
+// @Tags bookings
+// @Accept  json
+// @Produce  json
+// @Param booking body dtos.GroupBookingRequest true "Combined booking data"
+// @Success 200 {object} dtos.BookingResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /bookings/group/create [post]
+// @Security BearerAuth
+func (h *BookingHandler) CreateGroupBooking(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	var req entitiesDtos.GroupBookingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+	req.VendorID, _ = c.Locals("userID").(string)
+
+	booking, errRes := h.useCase.CreateGroupBooking(&req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Combined booking created successfully",
+		"data":    booking,
+	})
+}
+
+// GetDaySheet godoc
+// @Summary Get a market day sheet
+// @Description List every stall of a market day with its features, vendor and the add-ons booked with it
+// @Tags bookings
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Market ID"
+// @Param date path string true "Date, YYYY-MM-DD"
+// @Success 200 {object} dtos.DaySheet
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /bookings/market/{id}/day-sheet/{date} [get]
+// @Security BearerAuth
+func (h *BookingHandler) GetDaySheet(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	sheet, errRes := h.useCase.GetDaySheet(providerID, c.Params("id"), c.Params("date"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Day sheet retrieved successfully",
+		"data":    sheet,
+	})
+}
+
+// CheckIn godoc
+// @Summary Check a vendor in
+// @Description Record that a paid booking's vendor arrived, on the market day. Group bookings are checked in through the lead booking.
+// @Tags bookings
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} entities.Booking
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /bookings/{id}/check-in [patch]
+// @Security BearerAuth
+func (h *BookingHandler) CheckIn(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	booking, errRes := h.useCase.CheckIn(providerID, actorID(c), c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Booking checked in successfully",
+		"data":    booking,
+	})
+}
+
 // GetBookingsByMarket godoc
 // @Summary Get bookings by market
 // @Description Get bookings by market with the provided ID
@@ -94,7 +195,14 @@ func (h *BookingHandler) GetBookingsByMarket(c *fiber.Ctx) error {
 // @Failure 409 {object} string "Booking already exists"
 // @Failure 500 {object} string "Internal server error"
 // @Router /bookings/cancel [patch]
+// @Security BearerAuth
 func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
 	var req entitiesDtos.CancelBookingRequest
 	if err := c.BodyParser(&req); err != nil {
 		log.Printf("Failed to parse request body: %v", err) // Log the detailed error
@@ -103,6 +211,7 @@ func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
 			"details": err.Error(),
 		})
 	}
+	req.VendorID, _ = c.Locals("userID").(string)
 
 	booking, errResponse := h.useCase.CancelBooking(&req)
 	if errResponse != nil {
@@ -120,6 +229,45 @@ func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
 	})
 }
 
+// CancelMarketDay godoc
+// @Summary Cancel a market day
+// @Description Cancel every active booking of a market on one date, refunding paid bookings to the bank or as wallet credit
+// @Tags bookings
+// @Accept  json
+// @Produce  json
+// @Param request body dtos.CancelMarketDayRequest true "Market day data"
+// @Success 200 {object} []dtos.BookingResponse
+// @Failure 400 {object} string "Invalid input"
+// @Failure 403 {object} string "You are not authorized to cancel this market's bookings"
+// @Failure 500 {object} string "Internal server error"
+// @Router /bookings/market-day/cancel [patch]
+func (h *BookingHandler) CancelMarketDay(c *fiber.Ctx) error {
+	var req entitiesDtos.CancelMarketDayRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("Failed to parse request body: %v", err) // Log the detailed error
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	bookings, errResponse := h.useCase.CancelMarketDay(providerID, &req)
+	if errResponse != nil {
+		log.Printf("Failed to cancel market day: %v", errResponse) // Log the error details
+		return c.Status(errResponse.Code).JSON(fiber.Map{
+			"error":   "Failed to cancel market day",
+			"details": errResponse,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Market day cancelled successfully",
+		"data":    bookings,
+	})
+}
+
 // GetBookingsByUser godoc
 // @Summary Get bookings by user
 // @Description Get bookings by user with the provided ID
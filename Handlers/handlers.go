@@ -1,12 +1,35 @@
 package Handlers
 
 type AllHandlers struct {
-	UserHandler      *UserHandler
-	AuthHandler      *AuthHandler
-	PaymentHandler   *PaymentHandler
-	MarketProvider   *MarketProvider
-	MarketHandler    *MarketHandler
-	BookingHandler   *BookingHandler
-	SlotHandler      *SlotHandler
-	DashboardHandler *DashboardHandler
+	UserHandler          *UserHandler
+	AuthHandler          *AuthHandler
+	PaymentHandler       *PaymentHandler
+	MarketProvider       *MarketProvider
+	MarketHandler        *MarketHandler
+	MarketMapHandler     *MarketMapHandler
+	ZoneHandler          *ZoneHandler
+	AmenityHandler       *AmenityHandler
+	OpeningHoursHandler  *OpeningHoursHandler
+	BookingHandler       *BookingHandler
+	SlotHandler          *SlotHandler
+	LayoutVersionHandler *LayoutVersionHandler
+	DashboardHandler     *DashboardHandler
+	LedgerHandler        *LedgerHandler
+	WalletHandler        *WalletHandler
+	PromotionHandler     *PromotionHandler
+	PricingHandler       *PricingHandler
+	SlipHandler          *SlipHandler
+	TemplateHandler      *LayoutTemplateHandler
+	ScheduleHandler      *ScheduleHandler
+	NotificationHandler  *NotificationHandler
+	SlotSearchHandler    *SlotSearchHandler
+	TextSearchHandler    *TextSearchHandler
+	VendorProfileHandler *VendorProfileHandler
+	MediaHandler         *MediaHandler
+	StaffHandler         *StaffHandler
+	ApplicationHandler   *VendorApplicationHandler
+	TermsHandler         *MarketTermsHandler
+	ReviewHandler        *ReviewHandler
+	AnnouncementHandler  *AnnouncementHandler
+	AdminHandler         *AdminHandler
 }
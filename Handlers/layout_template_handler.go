@@ -0,0 +1,182 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type LayoutTemplateHandler struct {
+	useCase *Usecase.LayoutTemplateUseCase
+}
+
+func NewLayoutTemplateHandler(useCase *Usecase.LayoutTemplateUseCase) *LayoutTemplateHandler {
+	return &LayoutTemplateHandler{useCase: useCase}
+}
+
+// CreateTemplate godoc
+// @Summary Create a layout template
+// @Description Save a named zone and stall layout for a market so it can be stamped onto many dates
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param template body dtos.LayoutTemplateRequest true "Template data"
+// @Success 201 {object} entities.LayoutTemplate
+// @Router /templates/market/{marketId} [post]
+// @Security BearerAuth
+func (h *LayoutTemplateHandler) CreateTemplate(c *fiber.Ctx) error {
+	var req entitiesDtos.LayoutTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	template, errRes := h.useCase.CreateTemplate(providerID, c.Params("marketId"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template created successfully",
+		"data":    template,
+	})
+}
+
+// GetTemplates godoc
+// @Summary Get a market's layout templates
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Success 200 {object} []entities.LayoutTemplate
+// @Router /templates/market/{marketId} [get]
+// @Security BearerAuth
+func (h *LayoutTemplateHandler) GetTemplates(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	templates, errRes := h.useCase.GetTemplates(providerID, c.Params("marketId"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Templates retrieved successfully",
+		"data":    templates,
+	})
+}
+
+// GetTemplate godoc
+// @Summary Get a layout template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} entities.LayoutTemplate
+// @Router /templates/{id} [get]
+// @Security BearerAuth
+func (h *LayoutTemplateHandler) GetTemplate(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	template, errRes := h.useCase.GetTemplate(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template retrieved successfully",
+		"data":    template,
+	})
+}
+
+// UpdateTemplate godoc
+// @Summary Replace a layout template
+// @Description Replace the template's name, description and full stall list. Slots already stamped are not changed until the template is applied again.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param template body dtos.LayoutTemplateRequest true "Template data"
+// @Success 200 {object} entities.LayoutTemplate
+// @Router /templates/{id} [put]
+// @Security BearerAuth
+func (h *LayoutTemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
+	var req entitiesDtos.LayoutTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	template, errRes := h.useCase.UpdateTemplate(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template updated successfully",
+		"data":    template,
+	})
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a layout template
+// @Description Slots already stamped from the template are kept
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} string
+// @Router /templates/{id} [delete]
+// @Security BearerAuth
+func (h *LayoutTemplateHandler) DeleteTemplate(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.DeleteTemplate(providerID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template deleted successfully",
+	})
+}
+
+// ApplyTemplate godoc
+// @Summary Apply a layout template to dates
+// @Description Stamp the template onto every date in the range, optionally only on some weekdays. Safe to repeat.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param apply body dtos.ApplyTemplateRequest true "Dates to stamp"
+// @Success 200 {object} dtos.TemplateApplyResponse
+// @Router /templates/{id}/apply [post]
+// @Security BearerAuth
+func (h *LayoutTemplateHandler) ApplyTemplate(c *fiber.Ctx) error {
+	var req entitiesDtos.ApplyTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	result, errRes := h.useCase.ApplyTemplate(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template applied successfully",
+		"data":    result,
+	})
+}
@@ -0,0 +1,148 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type LayoutVersionHandler struct {
+	useCase *Usecase.LayoutVersionUseCase
+}
+
+func NewLayoutVersionHandler(useCase *Usecase.LayoutVersionUseCase) *LayoutVersionHandler {
+	return &LayoutVersionHandler{useCase: useCase}
+}
+
+// ApplyLayout godoc
+// @Summary Create or update layout
+// @Description Replace the stalls of every zone and date in the request. With dry_run=true only the diff of added, removed and modified stalls and the bookings they affect is returned; otherwise the change is applied and saved as a new layout version. Booked slots keep their status.
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param dry_run query bool false "Preview the change without applying it"
+// @Param layout body dtos.LayoutRequest true "Layout data"
+// @Success 200 {object} dtos.LayoutApplyResponse
+// @Router /slots/{marketId}/create [post]
+// @Security BearerAuth
+func (h *LayoutVersionHandler) ApplyLayout(c *fiber.Ctx) error {
+	var req entitiesDtos.LayoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	dryRun := c.QueryBool("dry_run")
+	result, errRes := h.useCase.ApplyLayout(providerID, c.Params("marketId"), &req, dryRun)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	message := "Layout applied successfully"
+	if dryRun {
+		message = "Layout preview generated successfully"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    result,
+	})
+}
+
+// GetVersions godoc
+// @Summary Get a market's layout versions
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Success 200 {object} []entities.LayoutVersion
+// @Router /slots/{marketId}/versions [get]
+// @Security BearerAuth
+func (h *LayoutVersionHandler) GetVersions(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	versions, errRes := h.useCase.GetVersions(providerID, c.Params("marketId"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Layout versions retrieved successfully",
+		"data":    versions,
+	})
+}
+
+// GetVersion godoc
+// @Summary Get a layout version with its stalls
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} entities.LayoutVersion
+// @Router /slots/{marketId}/versions/{version} [get]
+// @Security BearerAuth
+func (h *LayoutVersionHandler) GetVersion(c *fiber.Ctx) error {
+	version, err := c.ParamsInt("version")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid version number",
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	layoutVersion, errRes := h.useCase.GetVersion(providerID, c.Params("marketId"), version)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Layout version retrieved successfully",
+		"data":    layoutVersion,
+	})
+}
+
+// RollbackLayout godoc
+// @Summary Roll a layout back to an earlier version
+// @Description Restores the zones the version covered as they were in that version, recorded as a new version. Supports dry_run like layout creation.
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param version path int true "Version number to restore"
+// @Param dry_run query bool false "Preview the rollback without applying it"
+// @Success 200 {object} dtos.LayoutApplyResponse
+// @Router /slots/{marketId}/versions/{version}/rollback [post]
+// @Security BearerAuth
+func (h *LayoutVersionHandler) RollbackLayout(c *fiber.Ctx) error {
+	version, err := c.ParamsInt("version")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid version number",
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	dryRun := c.QueryBool("dry_run")
+	result, errRes := h.useCase.RollbackLayout(providerID, c.Params("marketId"), version, dryRun)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	message := "Layout rolled back successfully"
+	if dryRun {
+		message = "Rollback preview generated successfully"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    result,
+	})
+}
@@ -0,0 +1,173 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type LedgerHandler struct {
+	useCase *Usecase.LedgerUseCase
+}
+
+func NewLedgerHandler(useCase *Usecase.LedgerUseCase) *LedgerHandler {
+	return &LedgerHandler{useCase: useCase}
+}
+
+// GetAccountBalance godoc
+// @Summary Get ledger account balance
+// @Description Get the debit, credit and net balance of one of your ledger accounts; admins may read any account
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} dtos.AccountBalanceResponse
+// @Router /ledger/accounts/{id}/balance [get]
+// @Security BearerAuth
+func (h *LedgerHandler) GetAccountBalance(c *fiber.Ctx) error {
+	accountID := c.Params("id")
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	balance, errRes := h.useCase.GetAccountBalance(userID, role, accountID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Balance retrieved successfully",
+		"data":    balance,
+	})
+}
+
+// GetAccountEntries godoc
+// @Summary Get ledger account entries
+// @Description Get every journal entry that touches one of your ledger accounts, newest first; admins may read any account
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} []entities.JournalEntry
+// @Router /ledger/accounts/{id}/entries [get]
+// @Security BearerAuth
+func (h *LedgerHandler) GetAccountEntries(c *fiber.Ctx) error {
+	accountID := c.Params("id")
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	entries, errRes := h.useCase.GetAccountEntries(userID, role, accountID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Entries retrieved successfully",
+		"data":    entries,
+	})
+}
+
+// GetOwnerBalances godoc
+// @Summary Get balances by owner
+// @Description Get the balances of your own ledger accounts; admins may read those of any vendor, provider or system owner
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param id path string true "Owner ID"
+// @Success 200 {object} []dtos.AccountBalanceResponse
+// @Router /ledger/owners/{id}/balances [get]
+// @Security BearerAuth
+func (h *LedgerHandler) GetOwnerBalances(c *fiber.Ctx) error {
+	ownerID := c.Params("id")
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	balances, errRes := h.useCase.GetOwnerBalances(userID, role, ownerID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Balances retrieved successfully",
+		"data":    balances,
+	})
+}
+
+// RecordSettlement godoc
+// @Summary Record a provider settlement
+// @Description Record a payout from gateway clearing to a provider
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param settlement body dtos.SettlementRequest true "Settlement data"
+// @Success 201 {object} entities.JournalEntry
+// @Router /ledger/settlements [post]
+func (h *LedgerHandler) RecordSettlement(c *fiber.Ctx) error {
+	var req entitiesDtos.SettlementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	entry, errRes := h.useCase.RecordSettlement(&req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Settlement recorded successfully",
+		"data":    entry,
+	})
+}
+
+// RecordAdjustment godoc
+// @Summary Record a ledger adjustment
+// @Description Post a balanced manual correction to the ledger
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Param adjustment body dtos.AdjustmentRequest true "Adjustment data"
+// @Success 201 {object} entities.JournalEntry
+// @Router /ledger/adjustments [post]
+func (h *LedgerHandler) RecordAdjustment(c *fiber.Ctx) error {
+	var req entitiesDtos.AdjustmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	entry, errRes := h.useCase.RecordAdjustment(&req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Adjustment recorded successfully",
+		"data":    entry,
+	})
+}
+
+// CheckInvariants godoc
+// @Summary Check ledger invariants
+// @Description Verify that every journal entry balances and total debits equal total credits
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.LedgerInvariantReport
+// @Router /ledger/invariants [get]
+func (h *LedgerHandler) CheckInvariants(c *fiber.Ctx) error {
+	report, errRes := h.useCase.CheckInvariants()
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": "success",
+		"data":   report,
+	})
+}
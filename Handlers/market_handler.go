@@ -75,6 +75,47 @@ func (h *MarketHandler) GetMarket(c *fiber.Ctx) error {
 	})
 }
 
+// GetNearbyMarkets godoc
+// @Summary Find markets near a point
+// @Description List the markets within radius_km of lat/lng, nearest first, optionally only those open on a date, with a free slot or with slots of a category
+// @Tags Market
+// @Accept json
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius_km query number false "Distance from lat/lng in kilometres, 10 by default and at most 500"
+// @Param date query string false "Only markets open on this date, YYYY-MM-DD"
+// @Param available query bool false "Only markets with a free slot"
+// @Param category query string false "Only markets with slots of this category"
+// @Param page query int false "Page of markets, from 1"
+// @Param page_size query int false "Markets per page, at most 100"
+// @Success 200 {object} dtos.NearbyMarketResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /markets/nearby [get]
+func (h *MarketHandler) GetNearbyMarkets(c *fiber.Ctx) error {
+	query := &entitiesDtos.NearbyMarketQuery{
+		Lat:       c.Query("lat"),
+		Lng:       c.Query("lng"),
+		RadiusKm:  c.Query("radius_km"),
+		Date:      c.Query("date"),
+		Available: c.Query("available"),
+		Category:  c.Query("category"),
+		Page:      c.Query("page"),
+		PageSize:  c.Query("page_size"),
+	}
+
+	markets, errRes := h.useCase.GetNearbyMarkets(query)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Nearby markets fetched successfully",
+		"data":    markets,
+	})
+}
+
 // EditMarket godoc
 // @Summary Edit a market
 // @Description Edit a market
@@ -114,7 +155,7 @@ func (h *MarketHandler) EditMarket(c *fiber.Ctx) error {
 
 // GetMarketByID godoc
 // @Summary Get a market by ID
-// @Description Get a market by ID
+// @Description Get a market by ID. Markets that are not published are only shown to their provider and admins, who must send their token.
 // @Tags Market
 // @Accept json
 // @Produce json
@@ -123,9 +164,11 @@ func (h *MarketHandler) EditMarket(c *fiber.Ctx) error {
 // @Router /markets/get/{id} [get]
 func (h *MarketHandler) GetMarketByID(c *fiber.Ctx) error {
 	marketID := c.Params("id")
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
 
 	// Call the useCase to get the market by ID
-	market, errRes := h.useCase.GetMarketByID(marketID)
+	market, errRes := h.useCase.GetMarketByID(marketID, userID, role)
 	if errRes != nil {
 		return c.Status(errRes.Code).JSON(fiber.Map{
 			"status":  "error",
@@ -169,3 +212,66 @@ func (h *MarketHandler) GetMarketByProviderID(c *fiber.Ctx) error {
 		"data":    market,
 	})
 }
+
+// ChangeMarketStatus godoc
+// @Summary Change a market's status
+// @Description Publish, suspend, archive or restore a market. Publishing needs a complete profile (address, description, hours, location and image) and a layout with at least one stall. Suspending needs a reason. Archiving needs the market to have no upcoming bookings. Archived markets come back as drafts.
+// @Tags Market
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param status body dtos.MarketStatusRequest true "New status"
+// @Success 200 {object} entities.Market
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse "Transition not allowed"
+// @Failure 422 {object} dtos.ErrorResponse "Market not ready to publish"
+// @Router /markets/{id}/status [patch]
+// @Security BearerAuth
+func (h *MarketHandler) ChangeMarketStatus(c *fiber.Ctx) error {
+	var req entitiesDtos.MarketStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	market, errRes := h.useCase.ChangeStatus(userID, role, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Market is now " + string(market.Status),
+		"data":    market,
+	})
+}
+
+// GetMarketStatusHistory godoc
+// @Summary Get a market's status history
+// @Description Every status change of a market, newest first
+// @Tags Market
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {array} entities.MarketStatusChange
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /markets/{id}/status-history [get]
+// @Security BearerAuth
+func (h *MarketHandler) GetMarketStatusHistory(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	changes, errRes := h.useCase.GetStatusHistory(userID, role, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Market status history retrieved successfully",
+		"data":    changes,
+	})
+}
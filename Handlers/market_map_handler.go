@@ -0,0 +1,56 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"tln-backend/Usecase"
+)
+
+type MarketMapHandler struct {
+	useCase *Usecase.MarketMapUseCase
+}
+
+func NewMarketMapHandler(useCase *Usecase.MarketMapUseCase) *MarketMapHandler {
+	return &MarketMapHandler{useCase: useCase}
+}
+
+// GetMarketMap godoc
+// @Summary Get a market's stall map as JSON
+// @Description Geometry feed for one market day: floor plan size and each stall's position, rotation, shape, category and availability (available, held, booked, unavailable). Stalls without a position are laid out in rows per zone and marked placed=false.
+// @Tags markets
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param date query string true "Market date (YYYY-MM-DD)"
+// @Success 200 {object} dtos.MarketMap
+// @Router /markets/{id}/map [get]
+func (h *MarketMapHandler) GetMarketMap(c *fiber.Ctx) error {
+	marketMap, errRes := h.useCase.GetMarketMap(c.Params("id"), c.Query("date"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Market map retrieved successfully",
+		"data":    marketMap,
+	})
+}
+
+// GetMarketMapSVG godoc
+// @Summary Get a market's stall map as SVG
+// @Description Renders the market day with stalls filled by category and styled by availability. Each stall group carries data-slot-id for picking.
+// @Tags markets
+// @Produce image/svg+xml
+// @Param id path string true "Market ID"
+// @Param date query string true "Market date (YYYY-MM-DD)"
+// @Success 200 {string} string "SVG document"
+// @Router /markets/{id}/map/svg [get]
+func (h *MarketMapHandler) GetMarketMapSVG(c *fiber.Ctx) error {
+	svg, errRes := h.useCase.RenderMarketMap(c.Params("id"), c.Query("date"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/svg+xml; charset=utf-8")
+	return c.Status(fiber.StatusOK).Send(svg)
+}
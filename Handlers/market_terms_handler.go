@@ -0,0 +1,215 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type MarketTermsHandler struct {
+	useCase *Usecase.MarketTermsUseCase
+}
+
+func NewMarketTermsHandler(useCase *Usecase.MarketTermsUseCase) *MarketTermsHandler {
+	return &MarketTermsHandler{useCase: useCase}
+}
+
+// PublishTerms godoc
+// @Summary Publish a new version of a market's terms
+// @Description Rules for vendors such as opening times, waste disposal, noise and prohibited goods. Every vendor has to accept the new version before their next booking.
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param terms body dtos.MarketTermsRequest true "Terms"
+// @Success 201 {object} entities.MarketTerms
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /markets/{id}/terms [post]
+// @Security BearerAuth
+func (h *MarketTermsHandler) PublishTerms(c *fiber.Ctx) error {
+	var req entitiesDtos.MarketTermsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	terms, errRes := h.useCase.PublishTerms(providerID, actorID(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terms published successfully",
+		"data":    terms,
+	})
+}
+
+// GetTerms godoc
+// @Summary Get a market's terms
+// @Description The version in force, and for a signed-in vendor whether they accepted it
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} dtos.MarketTermsResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /markets/{id}/terms [get]
+func (h *MarketTermsHandler) GetTerms(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	terms, errRes := h.useCase.GetTerms(c.Params("id"), userID, role)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terms retrieved successfully",
+		"data":    terms,
+	})
+}
+
+// GetTermsVersions godoc
+// @Summary List every version of a market's terms
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {array} entities.MarketTerms
+// @Router /markets/{id}/terms/versions [get]
+// @Security BearerAuth
+func (h *MarketTermsHandler) GetTermsVersions(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	versions, errRes := h.useCase.GetTermsVersions(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terms versions retrieved successfully",
+		"data":    versions,
+	})
+}
+
+// AcceptTerms godoc
+// @Summary Accept a market's terms
+// @Description Record that the signed-in vendor accepts the version in force, with the time and the IP address it was accepted from
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param accept body dtos.TermsAcceptRequest true "Version read"
+// @Success 200 {object} entities.TermsAcceptance
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /markets/{id}/terms/accept [post]
+// @Security BearerAuth
+func (h *MarketTermsHandler) AcceptTerms(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	var req entitiesDtos.TermsAcceptRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	acceptance, errRes := h.useCase.AcceptTerms(vendorID, c.Params("id"), req.Version, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terms accepted successfully",
+		"data":    acceptance,
+	})
+}
+
+// GetMarketAcceptances godoc
+// @Summary List who accepted a market's terms
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param version query int false "Only this version"
+// @Success 200 {array} entities.TermsAcceptance
+// @Router /markets/{id}/terms/acceptances [get]
+// @Security BearerAuth
+func (h *MarketTermsHandler) GetMarketAcceptances(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	acceptances, errRes := h.useCase.GetMarketAcceptances(providerID, c.Params("id"), c.QueryInt("version"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terms acceptances retrieved successfully",
+		"data":    acceptances,
+	})
+}
+
+// GetMyAcceptances godoc
+// @Summary List the market terms I accepted
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Success 200 {array} entities.TermsAcceptance
+// @Router /vendors/terms [get]
+// @Security BearerAuth
+func (h *MarketTermsHandler) GetMyAcceptances(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	acceptances, errRes := h.useCase.GetVendorAcceptances(vendorID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Terms acceptances retrieved successfully",
+		"data":    acceptances,
+	})
+}
+
+// GetBookingTerms godoc
+// @Summary Get the terms a booking was made under
+// @Description The vendor's acceptance, with its time and IP address, and the version of the terms it accepted
+// @Tags terms
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} entities.TermsAcceptance
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /bookings/{id}/terms [get]
+// @Security BearerAuth
+func (h *MarketTermsHandler) GetBookingTerms(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	acceptance, errRes := h.useCase.GetBookingTerms(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Booking terms retrieved successfully",
+		"data":    acceptance,
+	})
+}
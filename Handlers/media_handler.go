@@ -0,0 +1,146 @@
+package Handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type MediaHandler struct {
+	useCase *Usecase.MediaUseCase
+}
+
+func NewMediaHandler(useCase *Usecase.MediaUseCase) *MediaHandler {
+	return &MediaHandler{useCase: useCase}
+}
+
+// UploadMedia godoc
+// @Summary Upload an image
+// @Description Upload a JPEG, PNG or GIF and attach it to a market, slot or vendor, replacing its previous image. A thumbnail is made alongside, and both are served through signed URLs.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Image file"
+// @Param kind formData string true "market_image, market_layout, slot_image, vendor_image, stall_image, application_photo or application_document"
+// @Param target_id formData string false "Market or slot ID; vendors leave it out for their own pictures"
+// @Success 201 {object} entities.Media
+// @Failure 413 {object} dtos.ErrorResponse "File too large"
+// @Failure 415 {object} dtos.ErrorResponse "Not a supported image"
+// @Router /media [post]
+// @Security BearerAuth
+func (h *MediaHandler) UploadMedia(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Image file is required",
+		})
+	}
+	if file.Size > h.useCase.MaxBytes() {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("Image is larger than %d MB", h.useCase.MaxBytes()>>20),
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Failed to open image: " + err.Error(),
+		})
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Failed to read image: " + err.Error(),
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	media, errRes := h.useCase.Upload(userID, role, c.FormValue("kind"), c.FormValue("target_id"), data)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Image uploaded successfully",
+		"data":    media,
+	})
+}
+
+// GetMedia godoc
+// @Summary Get an image
+// @Description Details of an uploaded image with fresh signed URLs for it and its thumbnail
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} entities.Media
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /media/{id} [get]
+func (h *MediaHandler) GetMedia(c *fiber.Ctx) error {
+	media, errRes := h.useCase.GetPublicMedia(c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Media retrieved successfully",
+		"data":    media,
+	})
+}
+
+// DeleteMedia godoc
+// @Summary Delete an image
+// @Description Take an image off the market, slot or vendor it belongs to and remove its files
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /media/{id} [delete]
+// @Security BearerAuth
+func (h *MediaHandler) DeleteMedia(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	if errRes := h.useCase.DeleteMedia(userID, role, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Image deleted successfully",
+	})
+}
+
+// GetMediaFile godoc
+// @Summary Download an image file
+// @Description Serve a file kept in local media storage. Only signed URLs handed out by the API work.
+// @Tags media
+// @Produce image/jpeg,image/png,image/gif
+// @Param key path string true "File key"
+// @Param expires query int true "Expiry as a Unix time"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} dtos.ErrorResponse "Bad signature or expired link"
+// @Router /media/files/{key} [get]
+func (h *MediaHandler) GetMediaFile(c *fiber.Ctx) error {
+	data, contentType, errRes := h.useCase.OpenFile(c.Params("*"), c.Query("expires"), c.Query("signature"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=3600")
+	return c.Status(fiber.StatusOK).Send(data)
+}
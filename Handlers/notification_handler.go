@@ -0,0 +1,60 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"tln-backend/Usecase"
+)
+
+type NotificationHandler struct {
+	useCase *Usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(useCase *Usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{useCase: useCase}
+}
+
+// GetNotifications godoc
+// @Summary Get my notifications
+// @Description Get the signed-in user's inbox, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} []entities.Notification
+// @Router /notifications [get]
+// @Security BearerAuth
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	notifications, errRes := h.useCase.GetNotifications(userID, c.QueryBool("unread"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Notifications retrieved successfully",
+		"data":    notifications,
+	})
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /notifications/{id}/read [patch]
+// @Security BearerAuth
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.MarkRead(userID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Notification marked as read",
+	})
+}
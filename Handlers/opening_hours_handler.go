@@ -0,0 +1,154 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type OpeningHoursHandler struct {
+	useCase *Usecase.OpeningHoursUseCase
+}
+
+func NewOpeningHoursHandler(useCase *Usecase.OpeningHoursUseCase) *OpeningHoursHandler {
+	return &OpeningHoursHandler{useCase: useCase}
+}
+
+// GetOpeningHours godoc
+// @Summary Get a market's opening hours
+// @Description The market's timezone, weekly sessions and its closures and special openings from today on
+// @Tags opening-hours
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} dtos.OpeningHoursResponse
+// @Router /markets/{id}/hours [get]
+func (h *OpeningHoursHandler) GetOpeningHours(c *fiber.Ctx) error {
+	hours, errRes := h.useCase.GetOpeningHours(c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Opening hours retrieved successfully",
+		"data":    hours,
+	})
+}
+
+// SaveWeeklyHours godoc
+// @Summary Set a market's weekly opening hours
+// @Description Replace the market's usual week with the given sessions, several per day if needed, and optionally change its timezone
+// @Tags opening-hours
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param hours body dtos.OpeningHoursRequest true "Weekly sessions"
+// @Success 200 {object} dtos.OpeningHoursResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /markets/{id}/hours [put]
+// @Security BearerAuth
+func (h *OpeningHoursHandler) SaveWeeklyHours(c *fiber.Ctx) error {
+	var req entitiesDtos.OpeningHoursRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	hours, errRes := h.useCase.SaveWeeklyHours(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Opening hours saved successfully",
+		"data":    hours,
+	})
+}
+
+// SaveException godoc
+// @Summary Close or specially open a market on a date
+// @Description Set a holiday closure or a special opening with its own sessions, replacing any exception already on that date
+// @Tags opening-hours
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param exception body dtos.OpeningExceptionRequest true "Exception data"
+// @Success 201 {object} entities.OpeningException
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /markets/{id}/hours/exceptions [post]
+// @Security BearerAuth
+func (h *OpeningHoursHandler) SaveException(c *fiber.Ctx) error {
+	var req entitiesDtos.OpeningExceptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	exception, errRes := h.useCase.SaveException(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Opening exception saved successfully",
+		"data":    exception,
+	})
+}
+
+// DeleteException godoc
+// @Summary Remove a market's opening exception
+// @Description The market goes back to its usual hours on that date
+// @Tags opening-hours
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param date path string true "Date, YYYY-MM-DD"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /markets/{id}/hours/exceptions/{date} [delete]
+// @Security BearerAuth
+func (h *OpeningHoursHandler) DeleteException(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.DeleteException(providerID, c.Params("id"), c.Params("date")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Opening exception deleted successfully",
+	})
+}
+
+// GetOpenDays godoc
+// @Summary Get a market's upcoming open days
+// @Description The dates the market opens with each session in local time and as an instant
+// @Tags opening-hours
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param from query string false "First date, YYYY-MM-DD, defaults to today in the market's timezone"
+// @Param days query int false "Number of days to look at, 14 by default and at most 92"
+// @Success 200 {object} dtos.OpenDaysResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /markets/{id}/open-days [get]
+func (h *OpeningHoursHandler) GetOpenDays(c *fiber.Ctx) error {
+	days, errRes := h.useCase.GetOpenDays(c.Params("id"), c.Query("from"), c.QueryInt("days"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Open days retrieved successfully",
+		"data":    days,
+	})
+}
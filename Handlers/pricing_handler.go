@@ -0,0 +1,276 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type PricingHandler struct {
+	useCase *Usecase.PricingUseCase
+}
+
+func NewPricingHandler(useCase *Usecase.PricingUseCase) *PricingHandler {
+	return &PricingHandler{useCase: useCase}
+}
+
+// CreateRule godoc
+// @Summary Create a pricing rule
+// @Description Add a base price, weekday, holiday, early bird, last minute or demand rule to a market
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param rule body dtos.PricingRuleRequest true "Pricing rule"
+// @Success 201 {object} entities.PricingRule
+// @Router /pricing/market/{marketId}/rules [post]
+// @Security BearerAuth
+func (h *PricingHandler) CreateRule(c *fiber.Ctx) error {
+	var req entitiesDtos.PricingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	rule, errRes := h.useCase.CreateRule(providerID, c.Params("marketId"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pricing rule created successfully",
+		"data":    rule,
+	})
+}
+
+// GetRules godoc
+// @Summary Get a market's pricing rules
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Success 200 {object} []entities.PricingRule
+// @Router /pricing/market/{marketId}/rules [get]
+// @Security BearerAuth
+func (h *PricingHandler) GetRules(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	rules, errRes := h.useCase.GetRules(providerID, c.Params("marketId"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pricing rules retrieved successfully",
+		"data":    rules,
+	})
+}
+
+// UpdateRule godoc
+// @Summary Update a pricing rule
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Pricing rule ID"
+// @Param rule body dtos.PricingRuleRequest true "Pricing rule"
+// @Success 200 {object} entities.PricingRule
+// @Router /pricing/rules/{id} [put]
+// @Security BearerAuth
+func (h *PricingHandler) UpdateRule(c *fiber.Ctx) error {
+	var req entitiesDtos.PricingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	rule, errRes := h.useCase.UpdateRule(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pricing rule updated successfully",
+		"data":    rule,
+	})
+}
+
+// DeleteRule godoc
+// @Summary Delete a pricing rule
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Pricing rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /pricing/rules/{id} [delete]
+// @Security BearerAuth
+func (h *PricingHandler) DeleteRule(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.DeleteRule(providerID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Pricing rule deleted successfully",
+	})
+}
+
+// QuoteSlot godoc
+// @Summary Quote a slot's price
+// @Description Evaluate the market's pricing rules for a slot right now and return the price with a trace of every rule
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param slotId path string true "Slot ID"
+// @Param add_ons query string false "Add-ons as amenityId or amenityId:quantity, comma-separated"
+// @Success 200 {object} dtos.PriceQuote
+// @Router /pricing/quote/{slotId} [get]
+// @Security BearerAuth
+func (h *PricingHandler) QuoteSlot(c *fiber.Ctx) error {
+	addOns := make([]entitiesDtos.AddOnChoice, 0)
+	for _, item := range strings.Split(c.Query("add_ons"), ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		choice := entitiesDtos.AddOnChoice{AmenityID: item}
+		if id, quantity, found := strings.Cut(item, ":"); found {
+			parsed, err := strconv.Atoi(quantity)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+					Code:    fiber.StatusBadRequest,
+					Message: "Invalid add-on quantity: " + item,
+				})
+			}
+			choice = entitiesDtos.AddOnChoice{AmenityID: id, Quantity: parsed}
+		}
+		addOns = append(addOns, choice)
+	}
+
+	quote, errRes := h.useCase.QuoteSlot(c.Params("slotId"), addOns)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Price quoted successfully",
+		"data":    quote,
+	})
+}
+
+// CreateCombo godoc
+// @Summary Create a combo price
+// @Description Set what a group of adjacent stalls booked together costs instead of the sum of their prices
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param combo body dtos.ComboPriceRequest true "Combo price"
+// @Success 201 {object} entities.ComboPrice
+// @Router /pricing/market/{marketId}/combos [post]
+// @Security BearerAuth
+func (h *PricingHandler) CreateCombo(c *fiber.Ctx) error {
+	var req entitiesDtos.ComboPriceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	combo, errRes := h.useCase.CreateCombo(providerID, c.Params("marketId"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Combo price created successfully",
+		"data":    combo,
+	})
+}
+
+// GetCombos godoc
+// @Summary Get a market's combo prices
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Success 200 {object} []entities.ComboPrice
+// @Router /pricing/market/{marketId}/combos [get]
+// @Security BearerAuth
+func (h *PricingHandler) GetCombos(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	combos, errRes := h.useCase.GetCombos(providerID, c.Params("marketId"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Combo prices retrieved successfully",
+		"data":    combos,
+	})
+}
+
+// DeleteCombo godoc
+// @Summary Delete a combo price
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Combo price ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /pricing/combos/{id} [delete]
+// @Security BearerAuth
+func (h *PricingHandler) DeleteCombo(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.DeleteCombo(providerID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Combo price deleted successfully",
+	})
+}
+
+// QuoteGroup godoc
+// @Summary Quote adjacent slots booked together
+// @Description Price each slot through the market's rules and apply a combo price when one fits the group
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param slot_ids query string true "Comma-separated slot IDs"
+// @Success 200 {object} dtos.GroupQuote
+// @Router /pricing/quote-group [get]
+// @Security BearerAuth
+func (h *PricingHandler) QuoteGroup(c *fiber.Ctx) error {
+	slotIDs := make([]string, 0)
+	for _, id := range strings.Split(c.Query("slot_ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			slotIDs = append(slotIDs, id)
+		}
+	}
+
+	quote, errRes := h.useCase.QuoteGroup(slotIDs)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Group price quoted successfully",
+		"data":    quote,
+	})
+}
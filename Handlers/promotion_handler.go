@@ -0,0 +1,164 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type PromotionHandler struct {
+	useCase *Usecase.PromotionUseCase
+}
+
+func NewPromotionHandler(useCase *Usecase.PromotionUseCase) *PromotionHandler {
+	return &PromotionHandler{useCase: useCase}
+}
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Description Create a discount code for the logged-in provider's markets
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param promotion body dtos.PromotionRequest true "Promotion data"
+// @Success 201 {object} entities.Promotion
+// @Router /promotions/create [post]
+// @Security BearerAuth
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	var req entitiesDtos.PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	promotion, errRes := h.useCase.CreatePromotion(providerID, &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Promotion created successfully",
+		"data":    promotion,
+	})
+}
+
+// GetPromotions godoc
+// @Summary Get provider promotions
+// @Description Get every promotion of the logged-in provider
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Success 200 {object} []entities.Promotion
+// @Router /promotions/provider [get]
+// @Security BearerAuth
+func (h *PromotionHandler) GetPromotions(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	promotions, errRes := h.useCase.GetPromotions(providerID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Promotions retrieved successfully",
+		"data":    promotions,
+	})
+}
+
+// UpdatePromotionStatus godoc
+// @Summary Enable or disable a promotion
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param status body dtos.UpdatePromotionStatusRequest true "New status"
+// @Success 200 {object} entities.Promotion
+// @Router /promotions/{id}/status [patch]
+// @Security BearerAuth
+func (h *PromotionHandler) UpdatePromotionStatus(c *fiber.Ctx) error {
+	var req entitiesDtos.UpdatePromotionStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	promotion, errRes := h.useCase.UpdatePromotionStatus(providerID, c.Params("id"), req.Status)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Promotion updated successfully",
+		"data":    promotion,
+	})
+}
+
+// GetRedemptions godoc
+// @Summary Get promotion redemptions
+// @Description Get every booking payment a promotion was applied to
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} []entities.Redemption
+// @Router /promotions/{id}/redemptions [get]
+// @Security BearerAuth
+func (h *PromotionHandler) GetRedemptions(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	redemptions, errRes := h.useCase.GetRedemptions(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Redemptions retrieved successfully",
+		"data":    redemptions,
+	})
+}
+
+// Quote godoc
+// @Summary Preview a promotion code
+// @Description Show the discounted price of a slot for a code without using it
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param quote body dtos.PromotionQuoteRequest true "Code and slot"
+// @Success 200 {object} dtos.PromotionQuoteResponse
+// @Router /promotions/quote [post]
+// @Security BearerAuth
+func (h *PromotionHandler) Quote(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	var req entitiesDtos.PromotionQuoteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+	req.VendorID, _ = c.Locals("userID").(string)
+
+	quote, errRes := h.useCase.Quote(&req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Promotion quote retrieved successfully",
+		"data":    quote,
+	})
+}
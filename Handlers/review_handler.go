@@ -0,0 +1,292 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type ReviewHandler struct {
+	useCase *Usecase.ReviewUseCase
+}
+
+func NewReviewHandler(useCase *Usecase.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{useCase: useCase}
+}
+
+// ReviewMarket godoc
+// @Summary Review the market of a booking
+// @Description Rate the market and its foot traffic once the booked market day is over. One review per market day.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param review body dtos.MarketReviewRequest true "Review"
+// @Success 201 {object} entities.Review
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /bookings/{id}/review [post]
+// @Security BearerAuth
+func (h *ReviewHandler) ReviewMarket(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	var req entitiesDtos.MarketReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	review, errRes := h.useCase.ReviewMarket(vendorID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Review saved successfully",
+		"data":    review,
+	})
+}
+
+// ReviewVendor godoc
+// @Summary Rate the vendor of a booking
+// @Description Rate the vendor's punctuality and cleanliness once the booked market day is over. One rating per market day.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param review body dtos.VendorReviewRequest true "Rating"
+// @Success 201 {object} entities.Review
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /bookings/{id}/vendor-review [post]
+// @Security BearerAuth
+func (h *ReviewHandler) ReviewVendor(c *fiber.Ctx) error {
+	var req entitiesDtos.VendorReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	review, errRes := h.useCase.ReviewVendor(providerID, actorID(c), actorRole(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Review saved successfully",
+		"data":    review,
+	})
+}
+
+// GetMarketReviews godoc
+// @Summary List a market's reviews
+// @Description What vendors said about the market, newest first, with its average scores
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} dtos.ReviewListResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /markets/{id}/reviews [get]
+func (h *ReviewHandler) GetMarketReviews(c *fiber.Ctx) error {
+	reviews, errRes := h.useCase.GetMarketReviews(c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reviews retrieved successfully",
+		"data":    reviews,
+	})
+}
+
+// GetVendorReviews godoc
+// @Summary List a vendor's reviews
+// @Description What markets said about the vendor, newest first, with its average scores
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Success 200 {object} dtos.ReviewListResponse
+// @Router /vendors/{id}/reviews [get]
+func (h *ReviewHandler) GetVendorReviews(c *fiber.Ctx) error {
+	reviews, errRes := h.useCase.GetVendorReviews(c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reviews retrieved successfully",
+		"data":    reviews,
+	})
+}
+
+// ReplyToReview godoc
+// @Summary Reply to a review of my market
+// @Description Set the market's public answer to a vendor's review, replacing any earlier reply
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param reply body dtos.ReviewReplyRequest true "Reply"
+// @Success 200 {object} entities.Review
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /reviews/{id}/reply [put]
+// @Security BearerAuth
+func (h *ReviewHandler) ReplyToReview(c *fiber.Ctx) error {
+	var req entitiesDtos.ReviewReplyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	review, errRes := h.useCase.Reply(providerID, actorID(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reply saved successfully",
+		"data":    review,
+	})
+}
+
+// ReportReview godoc
+// @Summary Report an abusive review
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param report body dtos.ReviewReportRequest true "Reason"
+// @Success 201 {object} entities.ReviewReport
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /reviews/{id}/report [post]
+// @Security BearerAuth
+func (h *ReviewHandler) ReportReview(c *fiber.Ctx) error {
+	var req entitiesDtos.ReviewReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	report, errRes := h.useCase.ReportReview(actorID(c), actorRole(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Review reported successfully",
+		"data":    report,
+	})
+}
+
+// GetReports godoc
+// @Summary List review reports
+// @Description Reports with the reviews they are about, oldest first. Admin only.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param status query string false "open (default), upheld, dismissed or all"
+// @Success 200 {array} entities.ReviewReport
+// @Router /reviews/reports [get]
+// @Security BearerAuth
+func (h *ReviewHandler) GetReports(c *fiber.Ctx) error {
+	reports, errRes := h.useCase.GetReports(c.Query("status"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Reports retrieved successfully",
+		"data":    reports,
+	})
+}
+
+// ResolveReport godoc
+// @Summary Resolve a review report
+// @Description Hide the review or dismiss the report; other open reports of the review are resolved the same way. Admin only.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Report ID"
+// @Param resolve body dtos.ReportResolveRequest true "Decision"
+// @Success 200 {object} entities.Review
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /reviews/reports/{id} [patch]
+// @Security BearerAuth
+func (h *ReviewHandler) ResolveReport(c *fiber.Ctx) error {
+	var req entitiesDtos.ReportResolveRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	adminID, _ := c.Locals("userID").(string)
+	review, errRes := h.useCase.ResolveReport(adminID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Report resolved successfully",
+		"data":    review,
+	})
+}
+
+// ModerateReview godoc
+// @Summary Hide or restore a review
+// @Description Admin only. Open reports of the review are upheld when hiding and dismissed when restoring.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param moderate body dtos.ReviewModerateRequest true "Status"
+// @Success 200 {object} entities.Review
+// @Router /reviews/{id}/moderate [patch]
+// @Security BearerAuth
+func (h *ReviewHandler) ModerateReview(c *fiber.Ctx) error {
+	var req entitiesDtos.ReviewModerateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	adminID, _ := c.Locals("userID").(string)
+	review, errRes := h.useCase.ModerateReview(adminID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Review moderated successfully",
+		"data":    review,
+	})
+}
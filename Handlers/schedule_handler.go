@@ -0,0 +1,225 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type ScheduleHandler struct {
+	useCase *Usecase.ScheduleUseCase
+}
+
+func NewScheduleHandler(useCase *Usecase.ScheduleUseCase) *ScheduleHandler {
+	return &ScheduleHandler{useCase: useCase}
+}
+
+// SaveSchedule godoc
+// @Summary Set a market's recurring schedule
+// @Description Create or replace the operating rule for a market (e.g. every Saturday, or the first Sunday of each month) and generate its slots from the default template
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param schedule body dtos.ScheduleRequest true "Schedule data"
+// @Success 200 {object} dtos.ScheduleResponse
+// @Router /schedules/market/{marketId} [put]
+// @Security BearerAuth
+func (h *ScheduleHandler) SaveSchedule(c *fiber.Ctx) error {
+	var req entitiesDtos.ScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	schedule, errRes := h.useCase.SaveSchedule(providerID, c.Params("marketId"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Schedule saved successfully",
+		"data":    schedule,
+	})
+}
+
+// GetSchedule godoc
+// @Summary Get a market's recurring schedule
+// @Description Returns the schedule with its exceptions and the market dates inside the generation horizon
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Success 200 {object} dtos.ScheduleResponse
+// @Router /schedules/market/{marketId} [get]
+// @Security BearerAuth
+func (h *ScheduleHandler) GetSchedule(c *fiber.Ctx) error {
+	schedule, errRes := h.useCase.GetSchedule(c.Params("marketId"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Schedule retrieved successfully",
+		"data":    schedule,
+	})
+}
+
+// AddException godoc
+// @Summary Close or open a single date
+// @Description Adds a one-off closure or extra market day; slots for the date are generated or cleared straight away
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param exception body dtos.ScheduleExceptionRequest true "Exception data"
+// @Success 200 {object} dtos.ScheduleResponse
+// @Router /schedules/market/{marketId}/exceptions [post]
+// @Security BearerAuth
+func (h *ScheduleHandler) AddException(c *fiber.Ctx) error {
+	var req entitiesDtos.ScheduleExceptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	schedule, errRes := h.useCase.AddException(providerID, c.Params("marketId"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Exception saved successfully",
+		"data":    schedule,
+	})
+}
+
+// RemoveException godoc
+// @Summary Remove a schedule exception
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Exception ID"
+// @Success 200 {object} dtos.ScheduleResponse
+// @Router /schedules/exceptions/{id} [delete]
+// @Security BearerAuth
+func (h *ScheduleHandler) RemoveException(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	schedule, errRes := h.useCase.RemoveException(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Exception removed successfully",
+		"data":    schedule,
+	})
+}
+
+// Generate godoc
+// @Summary Generate a market's slots now
+// @Description Runs the nightly slot generation for one market immediately
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Success 200 {object} dtos.TemplateApplyResponse
+// @Router /schedules/market/{marketId}/generate [post]
+// @Security BearerAuth
+func (h *ScheduleHandler) Generate(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	result, errRes := h.useCase.Generate(providerID, c.Params("marketId"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Slots generated successfully",
+		"data":    result,
+	})
+}
+
+// SaveHoliday godoc
+// @Summary Add a public holiday
+// @Description Markets whose schedule skips holidays do not open on this date
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param holiday body dtos.HolidayRequest true "Holiday data"
+// @Success 200 {object} entities.Holiday
+// @Router /schedules/holidays [post]
+// @Security BearerAuth
+func (h *ScheduleHandler) SaveHoliday(c *fiber.Ctx) error {
+	var req entitiesDtos.HolidayRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	holiday, errRes := h.useCase.SaveHoliday(&req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Holiday saved successfully",
+		"data":    holiday,
+	})
+}
+
+// GetHolidays godoc
+// @Summary List public holidays
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to today"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} []entities.Holiday
+// @Router /schedules/holidays [get]
+// @Security BearerAuth
+func (h *ScheduleHandler) GetHolidays(c *fiber.Ctx) error {
+	holidays, errRes := h.useCase.GetHolidays(c.Query("from"), c.Query("to"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Holidays retrieved successfully",
+		"data":    holidays,
+	})
+}
+
+// DeleteHoliday godoc
+// @Summary Remove a public holiday
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param date path string true "Holiday date (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Router /schedules/holidays/{date} [delete]
+// @Security BearerAuth
+func (h *ScheduleHandler) DeleteHoliday(c *fiber.Ctx) error {
+	if errRes := h.useCase.DeleteHoliday(c.Params("date")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Holiday deleted successfully",
+	})
+}
@@ -0,0 +1,172 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"strconv"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type SlipHandler struct {
+	useCase *Usecase.SlipUseCase
+}
+
+func NewSlipHandler(useCase *Usecase.SlipUseCase) *SlipHandler {
+	return &SlipHandler{useCase: useCase}
+}
+
+// UploadSlip godoc
+// @Summary Upload a bank transfer slip
+// @Description Pay a pending booking by bank transfer. The slip's mini-QR is decoded to check the reference, bank and amount. The booking is held until the slip's review_by time; if nobody reviews it by then the booking expires.
+// @Tags slips
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param slip formData file true "Slip image (PNG or JPEG)"
+// @Param amount formData number false "Amount shown on the slip"
+// @Success 201 {object} entities.BankSlip
+// @Failure 409 {object} dtos.ErrorResponse "Duplicate slip or booking not pending"
+// @Failure 422 {object} dtos.ErrorResponse "Unreadable slip or wrong amount"
+// @Router /slips/booking/{id} [post]
+// @Security BearerAuth
+func (h *SlipHandler) UploadSlip(c *fiber.Ctx) error {
+	file, err := c.FormFile("slip")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Slip image is required",
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Failed to open slip image: " + err.Error(),
+		})
+	}
+	defer src.Close()
+
+	image, err := io.ReadAll(src)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Failed to read slip image: " + err.Error(),
+		})
+	}
+
+	var amount float64
+	if raw := c.FormValue("amount"); raw != "" {
+		amount, err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+				Code:    fiber.StatusBadRequest,
+				Message: "Invalid amount",
+			})
+		}
+	}
+
+	// Vendors may only pay for themselves; the market's provider can upload on a vendor's behalf
+	vendorID, providerID := slipCaller(c)
+	slip, errRes := h.useCase.UploadSlip(c.Params("id"), vendorID, providerID, image, amount)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Slip uploaded successfully",
+		"data":    slip,
+	})
+}
+
+// GetBookingSlips godoc
+// @Summary Get slips of a booking
+// @Description For the booking's vendor or the market's provider
+// @Tags slips
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} []entities.BankSlip
+// @Router /slips/booking/{id} [get]
+// @Security BearerAuth
+func (h *SlipHandler) GetBookingSlips(c *fiber.Ctx) error {
+	vendorID, providerID := slipCaller(c)
+	slips, errRes := h.useCase.GetBookingSlips(c.Params("id"), vendorID, providerID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Slips retrieved successfully",
+		"data":    slips,
+	})
+}
+
+// GetReviewQueue godoc
+// @Summary Get slips awaiting review
+// @Description Get the slips waiting for the logged-in provider to approve or reject, oldest first. Staff must name the market.
+// @Tags slips
+// @Accept json
+// @Produce json
+// @Param market_id query string false "Only slips for this market"
+// @Success 200 {object} []entities.BankSlip
+// @Router /slips/provider/pending [get]
+// @Security BearerAuth
+func (h *SlipHandler) GetReviewQueue(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	slips, errRes := h.useCase.GetReviewQueue(providerID, c.Query("market_id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Slips retrieved successfully",
+		"data":    slips,
+	})
+}
+
+// ReviewSlip godoc
+// @Summary Approve or reject a slip
+// @Description Approving confirms the booking; rejecting gives the vendor a new payment window
+// @Tags slips
+// @Accept json
+// @Produce json
+// @Param id path string true "Slip ID"
+// @Param review body dtos.ReviewSlipRequest true "Review decision"
+// @Success 200 {object} entities.BankSlip
+// @Router /slips/{id}/review [patch]
+// @Security BearerAuth
+func (h *SlipHandler) ReviewSlip(c *fiber.Ctx) error {
+	var req entitiesDtos.ReviewSlipRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	slip, errRes := h.useCase.ReviewSlip(providerID, actorID(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Slip reviewed successfully",
+		"data":    slip,
+	})
+}
+
+// slipCaller splits the caller into a vendor or a provider; staff have already been mapped to their provider.
+func slipCaller(c *fiber.Ctx) (vendorID, providerID string) {
+	userID, _ := c.Locals("userID").(string)
+	if c.Locals("role") == "vendor" {
+		return userID, ""
+	}
+	return "", userID
+}
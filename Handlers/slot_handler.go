@@ -1,7 +1,6 @@
 package Handlers
 
 import (
-	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"net/url"
 	entitiesDtos "tln-backend/Entities/dtos"
@@ -16,40 +15,6 @@ func NewSlotHandler(useCase *Usecase.SlotUseCase) *SlotHandler {
 	return &SlotHandler{useCase: useCase}
 }
 
-// CreateOrUpdateLayout godoc
-// @Summary Create or update layout
-// @Description Create or update layout
-// @Tags slots
-// @Accept json
-// @Produce json
-// @Param marketId path string true "Market ID"
-// @Param layout body dtos.LayoutRequest true "Layout data"
-// @Success 200 {object} string
-// @Router /slots/{marketId}/create [post]
-func (h *SlotHandler) CreateOrUpdateLayout(c *fiber.Ctx) error {
-	marketID := c.Params("marketId")
-	fmt.Println("Market ID: ", marketID)
-	var layoutRequest entitiesDtos.LayoutRequest
-	if err := c.BodyParser(&layoutRequest); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Cannot parse JSON: " + err.Error(),
-		})
-	}
-
-	updatedSlots, errResponse := h.useCase.CreateOrUpdateLayout(marketID, layoutRequest.Layout)
-	if errResponse != nil {
-		return c.Status(errResponse.Code).JSON(fiber.Map{
-			"error": errResponse.Message,
-		})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message":      "Layout processed successfully",
-		"marketId":     marketID,
-		"updatedSlots": updatedSlots,
-	})
-}
-
 // GetSlot godoc
 // @Summary Get all slots
 // @Description Get all slots
@@ -92,7 +57,8 @@ func (h *SlotHandler) EditSlot(c *fiber.Ctx) error {
 	}
 
 	// Call the usecase layer with both slotID and updateDTO
-	updatedSlot, errRes := h.useCase.EditSlot(slotID, &updateDTO)
+	userID, _ := c.Locals("userID").(string)
+	updatedSlot, errRes := h.useCase.EditSlot(userID, slotID, &updateDTO)
 	if errRes != nil {
 		return c.Status(errRes.Code).JSON(&entitiesDtos.ErrorResponse{
 			Code:    errRes.Code,
@@ -105,16 +71,25 @@ func (h *SlotHandler) EditSlot(c *fiber.Ctx) error {
 
 // DeleteSlot godoc
 // @Summary Delete slot
-// @Description Delete slot
+// @Description Soft-delete a slot. Pending or confirmed bookings must each be moved to a free slot of the same market day or refunded; their vendors are notified
 // @Tags slots
 // @Accept json
 // @Produce json
 // @Param id path string true "Slot ID"
-// @Success 200 {object} string
+// @Param plan body dtos.SlotDeleteRequest false "Relocation plan for the slot's bookings"
+// @Success 200 {object} dtos.SlotDeleteResponse
+// @Failure 409 {object} dtos.ErrorResponse
 // @Router /slots/delete/{id} [delete]
+// @Security BearerAuth
 func (h *SlotHandler) DeleteSlot(c *fiber.Ctx) error {
 	slotID := c.Params("id")
-	errRes := h.useCase.DeleteSlot(slotID)
+	req, errRes := slotDeleteRequest(c)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	deleted, errRes := h.useCase.DeleteSlot(providerID, slotID, req)
 	if errRes != nil {
 		return c.Status(errRes.Code).JSON(&entitiesDtos.ErrorResponse{
 			Code:    errRes.Code,
@@ -124,22 +99,26 @@ func (h *SlotHandler) DeleteSlot(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Slot deleted successfully",
+		"data":    deleted,
 	})
 }
 
 // DeleteSlotByDateAndZone godoc
 // @Summary Delete slot by date and zone
-// @Description Delete slot by date and zone
+// @Description Soft-delete every slot of a zone on one market day, with the same relocation plan as a single slot
 // @Tags slots
 // @Accept json
 // @Produce json
-// @Param id path string true "Slot ID"
+// @Param id path string true "Market ID"
 // @Param zoneID path string true "Zone ID"
 // @Param date path string true "Date"
-// @Success 200 {object} string
+// @Param plan body dtos.SlotDeleteRequest false "Relocation plan for the slots' bookings"
+// @Success 200 {object} dtos.SlotDeleteResponse
+// @Failure 409 {object} dtos.ErrorResponse
 // @Router /slots/delete/{id}/zone/{zoneID}/date/{date} [delete]
+// @Security BearerAuth
 func (h *SlotHandler) DeleteSlotByDateAndZone(c *fiber.Ctx) error {
-	slotID := c.Params("id")
+	marketID := c.Params("id")
 	zoneID := c.Params("zoneID")
 	date, err := url.QueryUnescape(c.Params("date"))
 	if err != nil {
@@ -148,8 +127,13 @@ func (h *SlotHandler) DeleteSlotByDateAndZone(c *fiber.Ctx) error {
 			Message: "Invalid date format in URL",
 		})
 	}
+	req, errRes := slotDeleteRequest(c)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
 
-	errRes := h.useCase.DeleteSlotByDateAndZone(slotID, zoneID, date)
+	providerID, _ := c.Locals("userID").(string)
+	deleted, errRes := h.useCase.DeleteSlotByDateAndZone(providerID, marketID, zoneID, date, req)
 	if errRes != nil {
 		return c.Status(errRes.Code).JSON(&entitiesDtos.ErrorResponse{
 			Code:    errRes.Code,
@@ -159,9 +143,65 @@ func (h *SlotHandler) DeleteSlotByDateAndZone(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "Slot deleted successfully",
+		"data":    deleted,
 	})
 }
 
+// BulkEditSlots godoc
+// @Summary Bulk edit slots
+// @Description Set or adjust the price, category or status of every slot matching a selector, e.g. raise a zone's prices by 10% for a month. Booked slots, and category changes their zone does not allow or has no room for, are skipped. With dry_run=true the affected slots are only previewed; otherwise all changes are applied together.
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param marketId path string true "Market ID"
+// @Param dry_run query bool false "Preview the edit without applying it"
+// @Param edit body dtos.BulkSlotEditRequest true "Selector and changes"
+// @Success 200 {object} dtos.BulkSlotEditResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /slots/{marketId}/bulk-edit [post]
+// @Security BearerAuth
+func (h *SlotHandler) BulkEditSlots(c *fiber.Ctx) error {
+	var req entitiesDtos.BulkSlotEditRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	dryRun := c.QueryBool("dry_run")
+	result, errRes := h.useCase.BulkEditSlots(providerID, c.Params("marketId"), &req, dryRun)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	message := "Slots updated successfully"
+	if dryRun {
+		message = "Bulk edit preview generated successfully"
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": message,
+		"data":    result,
+	})
+}
+
+// slotDeleteRequest reads the optional relocation plan; a delete without a body has no plan.
+func slotDeleteRequest(c *fiber.Ctx) (*entitiesDtos.SlotDeleteRequest, *entitiesDtos.ErrorResponse) {
+	var req entitiesDtos.SlotDeleteRequest
+	if len(c.Body()) == 0 {
+		return &req, nil
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		}
+	}
+	return &req, nil
+}
+
 // GetSlotByDate godoc
 // @Summary Get slots by date
 // @Description Get slots by date
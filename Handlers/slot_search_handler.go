@@ -0,0 +1,68 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type SlotSearchHandler struct {
+	useCase *Usecase.SlotSearchUseCase
+}
+
+func NewSlotSearchHandler(useCase *Usecase.SlotSearchUseCase) *SlotSearchHandler {
+	return &SlotSearchHandler{useCase: useCase}
+}
+
+// SearchSlots godoc
+// @Summary Search available slots
+// @Description Find free slots across every market, grouped by market with each market's lowest quoted price, a page of markets at a time. Totals leave out the price range, which is only checked on the page
+// @Tags slots
+// @Accept json
+// @Produce json
+// @Param from query string false "First date, YYYY-MM-DD, defaults to today"
+// @Param to query string false "Last date, YYYY-MM-DD, defaults to four weeks after from"
+// @Param category query string false "Slot category"
+// @Param zone query string false "Zone name"
+// @Param min_price query number false "Lowest quoted price"
+// @Param max_price query number false "Highest quoted price"
+// @Param min_width query int false "Smallest width"
+// @Param min_height query int false "Smallest height"
+// @Param amenities query string false "Comma-separated amenity names the slot must all have"
+// @Param lat query number false "Latitude to search around"
+// @Param lng query number false "Longitude to search around"
+// @Param radius_km query number false "Distance from lat/lng in kilometres"
+// @Param page query int false "Page of markets, from 1"
+// @Param page_size query int false "Markets per page, at most 100"
+// @Success 200 {object} dtos.SlotSearchResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /slots/search [get]
+func (h *SlotSearchHandler) SearchSlots(c *fiber.Ctx) error {
+	query := &entitiesDtos.SlotSearchQuery{
+		From:      c.Query("from"),
+		To:        c.Query("to"),
+		Category:  c.Query("category"),
+		Zone:      c.Query("zone"),
+		MinPrice:  c.Query("min_price"),
+		MaxPrice:  c.Query("max_price"),
+		MinWidth:  c.Query("min_width"),
+		MinHeight: c.Query("min_height"),
+		Amenities: c.Query("amenities"),
+		Lat:       c.Query("lat"),
+		Lng:       c.Query("lng"),
+		RadiusKm:  c.Query("radius_km"),
+		Page:      c.Query("page"),
+		PageSize:  c.Query("page_size"),
+	}
+
+	results, errRes := h.useCase.SearchSlots(query)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Slots found successfully",
+		"data":    results,
+	})
+}
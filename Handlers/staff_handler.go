@@ -0,0 +1,258 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type StaffHandler struct {
+	useCase *Usecase.StaffUseCase
+}
+
+func NewStaffHandler(useCase *Usecase.StaffUseCase) *StaffHandler {
+	return &StaffHandler{useCase: useCase}
+}
+
+// actorID is who made the request: the staff member acting for a provider, or the signed-in user.
+func actorID(c *fiber.Ctx) string {
+	if staffID, ok := c.Locals("staffID").(string); ok {
+		return staffID
+	}
+	userID, _ := c.Locals("userID").(string)
+	return userID
+}
+
+// InviteStaff godoc
+// @Summary Invite a staff member
+// @Description Invite a staff member to help run some of the provider's markets, with a role in each. The invite token is only returned here.
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param invite body dtos.StaffInviteRequest true "Staff member and market roles"
+// @Success 201 {object} dtos.StaffInviteResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /staff/invite [post]
+// @Security BearerAuth
+func (h *StaffHandler) InviteStaff(c *fiber.Ctx) error {
+	var req entitiesDtos.StaffInviteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	invite, errRes := h.useCase.Invite(providerID, &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Staff member invited successfully",
+		"data":    invite,
+	})
+}
+
+// ReinviteStaff godoc
+// @Summary Send a staff invite again
+// @Description Issue a new invite token to a staff member who has not accepted yet; the old token stops working
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID"
+// @Success 200 {object} dtos.StaffInviteResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /staff/{id}/invite [post]
+// @Security BearerAuth
+func (h *StaffHandler) ReinviteStaff(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	invite, errRes := h.useCase.Reinvite(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Invite renewed successfully",
+		"data":    invite,
+	})
+}
+
+// GetStaff godoc
+// @Summary List my staff
+// @Description The signed-in provider's staff members with their market roles
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Success 200 {object} []entities.StaffMember
+// @Router /staff [get]
+// @Security BearerAuth
+func (h *StaffHandler) GetStaff(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	staff, errRes := h.useCase.GetStaff(providerID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Staff retrieved successfully",
+		"data":    staff,
+	})
+}
+
+// SetStaffAssignments godoc
+// @Summary Set a staff member's markets
+// @Description Replace the markets a staff member works in and its role in each
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID"
+// @Param assignments body dtos.StaffAssignmentsRequest true "Market roles"
+// @Success 200 {object} entities.StaffMember
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /staff/{id}/assignments [put]
+// @Security BearerAuth
+func (h *StaffHandler) SetStaffAssignments(c *fiber.Ctx) error {
+	var req entitiesDtos.StaffAssignmentsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	staff, errRes := h.useCase.SetAssignments(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Staff assignments saved successfully",
+		"data":    staff,
+	})
+}
+
+// DisableStaff godoc
+// @Summary Disable a staff member
+// @Description Stop a staff member from signing in; its current sessions end at once
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID"
+// @Success 200 {object} entities.StaffMember
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /staff/{id} [delete]
+// @Security BearerAuth
+func (h *StaffHandler) DisableStaff(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	staff, errRes := h.useCase.DisableStaff(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Staff member disabled successfully",
+		"data":    staff,
+	})
+}
+
+// GetMyAccess godoc
+// @Summary Get my markets as staff
+// @Description The markets the signed-in staff member works in, with its role and permissions in each
+// @Tags staff
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.StaffAccessResponse
+// @Router /staff/me [get]
+// @Security BearerAuth
+func (h *StaffHandler) GetMyAccess(c *fiber.Ctx) error {
+	if c.Locals("role") != "staff" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Staff role required.",
+		})
+	}
+
+	staffID, _ := c.Locals("userID").(string)
+	access, errRes := h.useCase.GetAccess(staffID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Staff access retrieved successfully",
+		"data":    access,
+	})
+}
+
+// AcceptInvite godoc
+// @Summary Accept a staff invite
+// @Description Choose a username and password with the invite token, and sign in as staff
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param accept body dtos.StaffAcceptRequest true "Invite token and login"
+// @Success 200 {object} dtos.StaffLoginResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /auth/staff/accept [post]
+func (h *StaffHandler) AcceptInvite(c *fiber.Ctx) error {
+	var req entitiesDtos.StaffAcceptRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	login, errRes := h.useCase.AcceptInvite(&req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Invite accepted successfully",
+		"data":    login,
+	})
+}
+
+// StaffLogin godoc
+// @Summary Staff Login
+// @Description Sign in as a provider's staff member
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param login body dtos.StaffLoginRequest true "Staff login data"
+// @Success 200 {object} dtos.StaffLoginResponse
+// @Failure 401 {object} dtos.ErrorResponse
+// @Router /auth/staff/login [post]
+func (h *StaffHandler) StaffLogin(c *fiber.Ctx) error {
+	var req entitiesDtos.StaffLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	login, errRes := h.useCase.Login(req.Username, req.Password)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Staff login successful",
+		"data":    login,
+	})
+}
@@ -0,0 +1,80 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type TextSearchHandler struct {
+	useCase *Usecase.TextSearchUseCase
+}
+
+func NewTextSearchHandler(useCase *Usecase.TextSearchUseCase) *TextSearchHandler {
+	return &TextSearchHandler{useCase: useCase}
+}
+
+// Search godoc
+// @Summary Search markets and vendor stalls
+// @Description Full-text search over market names, descriptions and addresses and vendor stall profiles. Thai matches without spaces between words. Results are ranked and their matching text is wrapped in <mark>.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param type query string false "market, vendor or all"
+// @Param category query string false "Markets with slots of this category or vendors selling it"
+// @Param date query string false "Markets with a free slot or vendors trading on this date, YYYY-MM-DD"
+// @Param lat query number false "Latitude to search around"
+// @Param lng query number false "Longitude to search around"
+// @Param radius_km query number false "Distance from lat/lng in kilometres"
+// @Param page query int false "Page of results, from 1"
+// @Param page_size query int false "Results per page, at most 100"
+// @Success 200 {object} dtos.TextSearchResponse
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /search [get]
+func (h *TextSearchHandler) Search(c *fiber.Ctx) error {
+	query := &entitiesDtos.TextSearchQuery{
+		Q:        c.Query("q"),
+		Type:     c.Query("type"),
+		Category: c.Query("category"),
+		Date:     c.Query("date"),
+		Lat:      c.Query("lat"),
+		Lng:      c.Query("lng"),
+		RadiusKm: c.Query("radius_km"),
+		Page:     c.Query("page"),
+		PageSize: c.Query("page_size"),
+	}
+
+	results, errRes := h.useCase.Search(query)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Search completed successfully",
+		"data":    results,
+	})
+}
+
+// Reindex godoc
+// @Summary Rebuild the search index
+// @Description Index every live market and vendor profile again
+// @Tags search
+// @Accept json
+// @Produce json
+// @Success 200 {object} dtos.ReindexResponse
+// @Router /search/reindex [post]
+// @Security BearerAuth
+func (h *TextSearchHandler) Reindex(c *fiber.Ctx) error {
+	counts, errRes := h.useCase.Reindex()
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Search index rebuilt successfully",
+		"data":    counts,
+	})
+}
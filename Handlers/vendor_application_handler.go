@@ -0,0 +1,241 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type VendorApplicationHandler struct {
+	useCase *Usecase.VendorApplicationUseCase
+}
+
+func NewVendorApplicationHandler(useCase *Usecase.VendorApplicationUseCase) *VendorApplicationHandler {
+	return &VendorApplicationHandler{useCase: useCase}
+}
+
+// actorRole is the role recorded against a market decision: staff act as their provider, but are recorded
+// as staff.
+func actorRole(c *fiber.Ctx) string {
+	if _, ok := c.Locals("staffID").(string); ok {
+		return "staff"
+	}
+	role, _ := c.Locals("role").(string)
+	return role
+}
+
+// Apply godoc
+// @Summary Apply to a market's roster
+// @Description Submit stall details, product photos and documents to a market, or resubmit them after the market asked for changes, declined or revoked. Photos and documents are uploaded first as application_photo and application_document media.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param application body dtos.VendorApplicationRequest true "Application"
+// @Success 200 {object} entities.VendorApplication
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 403 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /markets/{id}/applications [post]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) Apply(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	var req entitiesDtos.VendorApplicationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	application, errRes := h.useCase.Apply(vendorID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Application submitted successfully",
+		"data":    application,
+	})
+}
+
+// GetMyApplications godoc
+// @Summary List my market applications
+// @Description The signed-in vendor's applications to every market, most recently changed first
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Success 200 {array} entities.VendorApplication
+// @Router /vendors/applications [get]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) GetMyApplications(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	applications, errRes := h.useCase.GetVendorApplications(vendorID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Applications retrieved successfully",
+		"data":    applications,
+	})
+}
+
+// GetMarketApplications godoc
+// @Summary List a market's applications
+// @Description Applications to the market, oldest submission first. Filter on approved for the market's roster, or blacklisted for its blacklist.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param status query string false "pending, changes_requested, approved, rejected, revoked or blacklisted"
+// @Success 200 {array} entities.VendorApplication
+// @Failure 403 {object} dtos.ErrorResponse
+// @Router /markets/{id}/applications [get]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) GetMarketApplications(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	applications, errRes := h.useCase.GetMarketApplications(providerID, c.Params("id"), c.Query("status"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Applications retrieved successfully",
+		"data":    applications,
+	})
+}
+
+// GetApplication godoc
+// @Summary Get an application
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} entities.VendorApplication
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /applications/{id} [get]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) GetApplication(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	application, errRes := h.useCase.GetApplication(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Application retrieved successfully",
+		"data":    application,
+	})
+}
+
+// GetApplicationHistory godoc
+// @Summary Get an application's history
+// @Description Every status change of the application, oldest first, with who made it and why
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {array} entities.VendorApplicationEvent
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /applications/{id}/history [get]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) GetApplicationHistory(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	events, errRes := h.useCase.GetApplicationHistory(providerID, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Application history retrieved successfully",
+		"data":    events,
+	})
+}
+
+// ReviewApplication godoc
+// @Summary Decide on an application
+// @Description Approve, reject or ask for changes to a pending application, revoke an approval, blacklist the vendor or lift a blacklist (revoked). A note for the vendor is required unless approving.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param review body dtos.ApplicationReviewRequest true "Decision"
+// @Success 200 {object} entities.VendorApplication
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 409 {object} dtos.ErrorResponse
+// @Router /applications/{id}/review [patch]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) ReviewApplication(c *fiber.Ctx) error {
+	var req entitiesDtos.ApplicationReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	application, errRes := h.useCase.Review(providerID, actorID(c), actorRole(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Application reviewed successfully",
+		"data":    application,
+	})
+}
+
+// BlacklistVendor godoc
+// @Summary Blacklist a vendor from a market
+// @Description Bar a vendor from applying to and booking at the market, whether or not it applied. A reason is required.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param blacklist body dtos.BlacklistRequest true "Vendor and reason"
+// @Success 200 {object} entities.VendorApplication
+// @Failure 400 {object} dtos.ErrorResponse
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /markets/{id}/blacklist [post]
+// @Security BearerAuth
+func (h *VendorApplicationHandler) BlacklistVendor(c *fiber.Ctx) error {
+	var req entitiesDtos.BlacklistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	application, errRes := h.useCase.Blacklist(providerID, actorID(c), actorRole(c), c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Vendor blacklisted successfully",
+		"data":    application,
+	})
+}
@@ -0,0 +1,100 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type VendorProfileHandler struct {
+	useCase *Usecase.VendorProfileUseCase
+}
+
+func NewVendorProfileHandler(useCase *Usecase.VendorProfileUseCase) *VendorProfileHandler {
+	return &VendorProfileHandler{useCase: useCase}
+}
+
+// SaveProfile godoc
+// @Summary Set my stall profile
+// @Description Create or replace the signed-in vendor's public stall profile
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Param profile body dtos.VendorProfileRequest true "Stall profile"
+// @Success 200 {object} entities.VendorProfile
+// @Failure 400 {object} dtos.ErrorResponse
+// @Router /vendors/profile [put]
+// @Security BearerAuth
+func (h *VendorProfileHandler) SaveProfile(c *fiber.Ctx) error {
+	if c.Locals("role") != "vendor" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Vendor role required.",
+		})
+	}
+
+	var req entitiesDtos.VendorProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	vendorID, _ := c.Locals("userID").(string)
+	profile, errRes := h.useCase.SaveProfile(vendorID, &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Stall profile saved successfully",
+		"data":    profile,
+	})
+}
+
+// GetMyProfile godoc
+// @Summary Get my stall profile
+// @Description The signed-in vendor's stall profile, hidden or not
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Success 200 {object} entities.VendorProfile
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /vendors/profile [get]
+// @Security BearerAuth
+func (h *VendorProfileHandler) GetMyProfile(c *fiber.Ctx) error {
+	vendorID, _ := c.Locals("userID").(string)
+	profile, errRes := h.useCase.GetProfile(vendorID, vendorID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Stall profile retrieved successfully",
+		"data":    profile,
+	})
+}
+
+// GetProfile godoc
+// @Summary Get a vendor's stall profile
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Success 200 {object} entities.VendorProfile
+// @Failure 404 {object} dtos.ErrorResponse
+// @Router /vendors/{id}/profile [get]
+func (h *VendorProfileHandler) GetProfile(c *fiber.Ctx) error {
+	profile, errRes := h.useCase.GetProfile(c.Params("id"), "")
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Stall profile retrieved successfully",
+		"data":    profile,
+	})
+}
@@ -0,0 +1,137 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type WalletHandler struct {
+	useCase *Usecase.WalletUseCase
+}
+
+func NewWalletHandler(useCase *Usecase.WalletUseCase) *WalletHandler {
+	return &WalletHandler{useCase: useCase}
+}
+
+// GetBalance godoc
+// @Summary Get wallet balance
+// @Description Get a vendor's usable credit and the lots it is made of. For the vendor, admins and providers the vendor has booked with.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Success 200 {object} dtos.WalletBalanceResponse
+// @Failure 403 {object} string "You are not authorized to view this wallet"
+// @Router /wallet/vendor/{id}/balance [get]
+// @Security BearerAuth
+func (h *WalletHandler) GetBalance(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	balance, errRes := h.useCase.GetBalance(userID, role, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Wallet retrieved successfully",
+		"data":    balance,
+	})
+}
+
+// GetVendorTransactions godoc
+// @Summary Get vendor wallet history
+// @Description Get every issue, redemption, restore and expiry on a vendor's wallet. For the vendor, admins and providers the vendor has booked with.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Success 200 {object} []entities.WalletTransaction
+// @Failure 403 {object} string "You are not authorized to view this wallet"
+// @Router /wallet/vendor/{id}/transactions [get]
+// @Security BearerAuth
+func (h *WalletHandler) GetVendorTransactions(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	transactions, errRes := h.useCase.GetVendorTransactions(userID, role, c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Wallet history retrieved successfully",
+		"data":    transactions,
+	})
+}
+
+// GetProviderTransactions godoc
+// @Summary Get provider-issued credit history
+// @Description Get the wallet history of every credit lot funded by the logged-in provider
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Success 200 {object} []entities.WalletTransaction
+// @Router /wallet/provider/transactions [get]
+// @Security BearerAuth
+func (h *WalletHandler) GetProviderTransactions(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+
+	transactions, errRes := h.useCase.GetIssuerTransactions(providerID)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Wallet history retrieved successfully",
+		"data":    transactions,
+	})
+}
+
+// IssueCredit godoc
+// @Summary Issue wallet credit
+// @Description Grant goodwill or promotional credit to a vendor. Providers fund it themselves and only for vendors who have booked with them; it can then only be spent in their markets. Admins fund it from the platform.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param credit body dtos.IssueCreditRequest true "Credit data"
+// @Success 201 {object} entities.WalletCredit
+// @Failure 403 {object} string "You can only issue credit to vendors who have booked in your markets"
+// @Failure 422 {object} string "The vendor would hold more of your unspent credit than the limit"
+// @Router /wallet/credits [post]
+// @Security BearerAuth
+func (h *WalletHandler) IssueCredit(c *fiber.Ctx) error {
+	var req entitiesDtos.IssueCreditRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	issuerType, issuerID := entities.AccountProvider, ""
+	switch c.Locals("role") {
+	case "provider":
+		issuerID, _ = c.Locals("userID").(string)
+	case "admin":
+		issuerType, issuerID = entities.AccountPlatform, entities.PlatformOwnerID
+	default:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Access denied. Provider or admin role required.",
+		})
+	}
+
+	credit, errRes := h.useCase.IssueCredit(issuerType, issuerID, &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Credit issued successfully",
+		"data":    credit,
+	})
+}
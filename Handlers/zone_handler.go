@@ -0,0 +1,124 @@
+package Handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Usecase"
+)
+
+type ZoneHandler struct {
+	useCase *Usecase.ZoneUseCase
+}
+
+func NewZoneHandler(useCase *Usecase.ZoneUseCase) *ZoneHandler {
+	return &ZoneHandler{useCase: useCase}
+}
+
+// CreateZone godoc
+// @Summary Create a market zone
+// @Description Add a zone with its colour, stall defaults, allowed categories and category caps
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Param zone body dtos.ZoneRequest true "Zone data"
+// @Success 201 {object} entities.Zone
+// @Router /markets/{id}/zones [post]
+// @Security BearerAuth
+func (h *ZoneHandler) CreateZone(c *fiber.Ctx) error {
+	var req entitiesDtos.ZoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	zone, errRes := h.useCase.CreateZone(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Zone created successfully",
+		"data":    zone,
+	})
+}
+
+// GetZones godoc
+// @Summary Get a market's zones
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Param id path string true "Market ID"
+// @Success 200 {object} []entities.Zone
+// @Router /markets/{id}/zones [get]
+func (h *ZoneHandler) GetZones(c *fiber.Ctx) error {
+	zones, errRes := h.useCase.GetZones(c.Params("id"))
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Zones retrieved successfully",
+		"data":    zones,
+	})
+}
+
+// UpdateZone godoc
+// @Summary Update a market zone
+// @Description Replace a zone's settings; a zone with stalls laid out keeps its name
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Param id path string true "Zone ID"
+// @Param zone body dtos.ZoneRequest true "Zone data"
+// @Success 200 {object} entities.Zone
+// @Router /zones/{id} [put]
+// @Security BearerAuth
+func (h *ZoneHandler) UpdateZone(c *fiber.Ctx) error {
+	var req entitiesDtos.ZoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(&entitiesDtos.ErrorResponse{
+			Code:    fiber.StatusBadRequest,
+			Message: "Invalid request body: " + err.Error(),
+		})
+	}
+
+	providerID, _ := c.Locals("userID").(string)
+	zone, errRes := h.useCase.UpdateZone(providerID, c.Params("id"), &req)
+	if errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Zone updated successfully",
+		"data":    zone,
+	})
+}
+
+// DeleteZone godoc
+// @Summary Delete a market zone
+// @Description Delete a zone no live slot or template stall uses any more
+// @Tags zones
+// @Accept json
+// @Produce json
+// @Param id path string true "Zone ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /zones/{id} [delete]
+// @Security BearerAuth
+func (h *ZoneHandler) DeleteZone(c *fiber.Ctx) error {
+	providerID, _ := c.Locals("userID").(string)
+	if errRes := h.useCase.DeleteZone(providerID, c.Params("id")); errRes != nil {
+		return c.Status(errRes.Code).JSON(errRes)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Zone deleted successfully",
+	})
+}
@@ -0,0 +1,9 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IAdmin interface {
+	CreateAdmin(admin *entities.Admin) error
+	GetAdminByID(adminID string) (*entities.Admin, error)
+	GetAdminByUsername(username string) (*entities.Admin, error)
+}
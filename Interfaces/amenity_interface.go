@@ -0,0 +1,13 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IAmenity interface {
+	CreateAmenity(amenity *entities.Amenity) error
+	SaveAmenity(amenity *entities.Amenity) error
+	GetAmenity(amenityID string) (*entities.Amenity, error)
+	GetAmenities(marketID string) ([]entities.Amenity, error)
+	DeleteAmenity(amenityID string) error
+	CountAmenitySlots(amenityID string) (int64, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
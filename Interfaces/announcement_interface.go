@@ -0,0 +1,21 @@
+package Interfaces
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type IAnnouncement interface {
+	GetMarket(marketID string) (*entities.Market, error)
+	GetMarketProviderID(marketID string) (string, error)
+	GetZone(zoneID string) (*entities.Zone, error)
+	GetBookedVendors(marketID, date, zoneID string, category entities.Category) ([]string, error)
+	GetRosterVendors(marketID string) ([]string, error)
+	CreateAnnouncement(announcement *entities.Announcement, receipts []entities.AnnouncementReceipt) error
+	GetAnnouncement(announcementID string) (*entities.Announcement, error)
+	GetMarketAnnouncements(marketID string) ([]entities.Announcement, error)
+	GetReceipts(announcementID string) ([]entities.AnnouncementReceipt, error)
+	GetVendorAnnouncements(vendorID string, from, rosterSince time.Time) ([]entities.Announcement, error)
+	IsRecipient(announcementID, vendorID string) (bool, error)
+	MarkRead(announcementID, vendorID string) error
+}
@@ -0,0 +1,17 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type ILayoutTemplate interface {
+	CreateTemplate(template *entities.LayoutTemplate) error
+	ReplaceTemplate(template *entities.LayoutTemplate) error
+	GetTemplate(templateID string) (*entities.LayoutTemplate, error)
+	GetTemplatesByMarket(marketID string) ([]entities.LayoutTemplate, error)
+	DeleteTemplate(templateID string) error
+	GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error)
+	GetActiveBookingSlotIDs(slotIDs []string) ([]string, error)
+	SaveSlots(created, updated []*entities.Slot) error
+	DeleteUnusedSlots(slotIDs []string) ([]string, error)
+	GetZones(marketID string) ([]entities.Zone, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
@@ -0,0 +1,16 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type ILayoutVersion interface {
+	GetSlotsOnDates(marketID string, dates []string) ([]*entities.Slot, error)
+	GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error)
+	GetBookedSlotIDs(slotIDs []string) ([]string, error)
+	ApplyLayout(versions []*entities.LayoutVersion, created, updated, revived []*entities.Slot, deleted, retired []string) error
+	GetVersionScopes(marketID string) ([]string, error)
+	GetVersions(marketID string) ([]entities.LayoutVersion, error)
+	GetVersion(marketID string, version int) (*entities.LayoutVersion, error)
+	GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error)
+	GetZones(marketID string) ([]entities.Zone, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
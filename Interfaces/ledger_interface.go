@@ -0,0 +1,16 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type ILedger interface {
+	GetOrCreateAccount(accountType entities.LedgerAccountType, ownerID, name string) (*entities.LedgerAccount, error)
+	GetAccount(accountID string) (*entities.LedgerAccount, error)
+	GetAccountsByOwner(ownerID string) ([]entities.LedgerAccount, error)
+	GetEntryByReference(kind entities.JournalKind, referenceID string) (*entities.JournalEntry, error)
+	CreateEntry(entry *entities.JournalEntry) error
+	GetEntriesByAccount(accountID string) ([]entities.JournalEntry, error)
+	GetAccountTotals(accountID string) (float64, float64, error)
+	GetLedgerTotals() (float64, float64, int64, error)
+	GetUnbalancedEntryIDs() ([]string, error)
+	GetBookingParties(bookingID string) (*entities.Booking, string, error)
+}
@@ -14,4 +14,11 @@ type IMarket interface {
 	GetMarketByID(marketID string) ([]entities.Market, *entitiesDtos.ErrorResponse)
 	GetMarketByProviderID(providerID string) ([]entities.Market, *entitiesDtos.ErrorResponse)
 	EditMarket(marketID string, marketReq *entitiesDtos.MarketEditRequest) (*entities.Market, *entitiesDtos.ErrorResponse)
+	GetNearbyMarkets(filter *entitiesDtos.NearbyMarketFilter) ([]entitiesDtos.MarketDistance, error)
+	GetMarketsByIDs(marketIDs []string) ([]entities.Market, error)
+	ChangeMarketStatus(change *entities.MarketStatusChange) error
+	GetStatusChanges(marketID string) ([]entities.MarketStatusChange, error)
+	GetLastStatusChange(marketID string, status entities.MarketStatus) (*entities.MarketStatusChange, error)
+	CountLiveSlots(marketID string) (int64, error)
+	CountUpcomingBookings(marketID, from string) (int64, error)
 }
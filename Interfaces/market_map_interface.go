@@ -0,0 +1,9 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IMarketMap interface {
+	GetMarket(marketID string) (*entities.Market, error)
+	GetSlotsByDate(marketID, date string) ([]*entities.Slot, error)
+	GetActiveBookings(slotIDs []string) ([]entities.Booking, error)
+}
@@ -0,0 +1,17 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IMarketTerms interface {
+	GetMarket(marketID string) (*entities.Market, error)
+	GetMarketProviderID(marketID string) (string, error)
+	CreateTerms(terms *entities.MarketTerms) error
+	GetCurrentTerms(marketID string) (*entities.MarketTerms, error)
+	GetTermsVersions(marketID string) ([]entities.MarketTerms, error)
+	CreateAcceptance(acceptance *entities.TermsAcceptance) error
+	GetAcceptance(termsID, vendorID string) (*entities.TermsAcceptance, error)
+	GetAcceptanceByID(acceptanceID string) (*entities.TermsAcceptance, error)
+	GetMarketAcceptances(marketID string, version int) ([]entities.TermsAcceptance, error)
+	GetVendorAcceptances(vendorID string) ([]entities.TermsAcceptance, error)
+	GetBooking(bookingID string) (*entities.Booking, error)
+}
@@ -0,0 +1,12 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IMedia interface {
+	CreateMedia(media *entities.Media) error
+	AttachMedia(media *entities.Media) (string, error)
+	DetachMedia(media *entities.Media) error
+	GetMedia(mediaID string) (*entities.Media, error)
+	GetMarketProviderID(marketID string) (string, error)
+	GetSlotProviderID(slotID string) (string, error)
+}
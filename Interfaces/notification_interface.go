@@ -0,0 +1,9 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type INotification interface {
+	CreateNotification(notification *entities.Notification) error
+	GetNotifications(userID string, unreadOnly bool) ([]entities.Notification, error)
+	MarkRead(userID, notificationID string) error
+}
@@ -0,0 +1,15 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IOpeningHours interface {
+	GetMarket(marketID string) (*entities.Market, error)
+	GetMarketProviderID(marketID string) (string, error)
+	GetSessions(marketID string) ([]entities.OpeningSession, error)
+	SaveWeeklyHours(marketID, timezone string, sessions []entities.OpeningSession) error
+	GetExceptions(marketID, from, to string) ([]entities.OpeningException, error)
+	SaveException(exception *entities.OpeningException) error
+	DeleteException(marketID, date string) error
+	GetSchedule(marketID string) (*entities.MarketSchedule, error)
+	GetHolidays(from, to string) ([]entities.Holiday, error)
+}
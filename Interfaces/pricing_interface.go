@@ -0,0 +1,21 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IPricing interface {
+	CreateRule(rule *entities.PricingRule) error
+	SaveRule(rule *entities.PricingRule) error
+	GetRule(ruleID string) (*entities.PricingRule, error)
+	DeleteRule(ruleID string) error
+	GetRules(marketID string) ([]entities.PricingRule, error)
+	GetActiveRules(marketID string) ([]entities.PricingRule, error)
+	CreateCombo(combo *entities.ComboPrice) error
+	GetCombo(comboID string) (*entities.ComboPrice, error)
+	GetCombos(marketID string) ([]entities.ComboPrice, error)
+	DeleteCombo(comboID string) error
+	GetSlot(slotID string) (*entities.Slot, error)
+	GetSlots(slotIDs []string) ([]*entities.Slot, error)
+	GetHoliday(date string) (*entities.Holiday, error)
+	GetOccupancy(marketID, date string) (int64, int64, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
@@ -0,0 +1,18 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IPromotion interface {
+	CreatePromotion(promotion *entities.Promotion) error
+	UpdatePromotionStatus(promotionID string, status entities.PromoStatus) error
+	GetPromotion(promotionID string) (*entities.Promotion, error)
+	GetPromotionByCode(providerID, code string) (*entities.Promotion, error)
+	GetPromotionsByProvider(providerID string) ([]entities.Promotion, error)
+	ReserveRedemption(redemption *entities.Redemption) error
+	ReleaseRedemption(bookingID string) error
+	GetRedemptionsByPromotion(promotionID string) ([]entities.Redemption, error)
+	HasCompletedBooking(vendorID, providerID string) (bool, error)
+	GetSlot(slotID string) (*entities.Slot, error)
+	GetZone(zoneID string) (*entities.Zone, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
@@ -0,0 +1,23 @@
+package Interfaces
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type IReview interface {
+	GetBooking(bookingID string) (*entities.Booking, error)
+	GetMarket(marketID string) (*entities.Market, error)
+	GetMarketProviderID(marketID string) (string, error)
+	CreateReview(review *entities.Review) error
+	UpdateReview(review *entities.Review) error
+	GetReview(reviewID string) (*entities.Review, error)
+	ReviewExists(kind entities.ReviewKind, marketID, vendorID string, marketDate time.Time) (bool, error)
+	GetReviews(kind entities.ReviewKind, marketID, vendorID string) ([]entities.Review, error)
+	GetRatingSummary(kind entities.ReviewKind, marketID, vendorID string) (*entities.RatingSummary, error)
+	CreateReport(report *entities.ReviewReport) error
+	ReportExists(reviewID, reporterID string) (bool, error)
+	GetReport(reportID string) (*entities.ReviewReport, error)
+	GetReports(status entities.ReportStatus) ([]entities.ReviewReport, error)
+	ModerateReview(review *entities.Review, reports entities.ReportStatus, resolvedBy string) error
+}
@@ -0,0 +1,22 @@
+package Interfaces
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type ISchedule interface {
+	SaveSchedule(schedule *entities.MarketSchedule) error
+	GetScheduleByMarket(marketID string) (*entities.MarketSchedule, error)
+	GetActiveSchedules() ([]entities.MarketSchedule, error)
+	GetScheduleMarketID(scheduleID string) (string, error)
+	MarkGenerated(scheduleID string, at time.Time) error
+	SaveException(exception *entities.ScheduleException) error
+	GetException(exceptionID string) (*entities.ScheduleException, error)
+	DeleteException(exceptionID string) error
+	SaveHoliday(holiday *entities.Holiday) error
+	DeleteHoliday(date string) error
+	GetHolidays(from, to string) ([]entities.Holiday, error)
+	GetTemplateMarketID(templateID string) (string, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
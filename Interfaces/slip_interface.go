@@ -0,0 +1,17 @@
+package Interfaces
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type ISlip interface {
+	CreateSlip(slip *entities.BankSlip) error
+	GetSlip(slipID string) (*entities.BankSlip, error)
+	GetSlipByTransRef(transRef string) (*entities.BankSlip, error)
+	GetSlipsByBooking(bookingID string) ([]entities.BankSlip, error)
+	GetSlipsByProvider(providerID, marketID string, status entities.SlipStatus) ([]entities.BankSlip, error)
+	UpdateSlipReview(slipID string, status entities.SlipStatus, reviewerID, note string, reviewedAt time.Time) error
+	GetPendingTransactionID(paymentID string) (string, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
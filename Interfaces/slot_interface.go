@@ -1,6 +1,9 @@
 package Interfaces
 
-import entities "tln-backend/Entities"
+import (
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
 
 type ISlot interface {
 	CreateSlot(slot []*entities.Slot) error
@@ -13,6 +16,12 @@ type ISlot interface {
 	GetSlotsByMarketID(marketID string) ([]*entities.Slot, error)
 	UpdateSlot(slot *entities.Slot) (*entities.Slot, error)
 	UpsertSlots(slots []*entities.Slot) ([]*entities.Slot, error)
-	DeleteSlot(slotID string) error
-	DeleteSlotByDateAndZone(marketID, zoneID, date string) error
+	GetSlotsByDateAndZone(marketID, zoneID, date string) ([]*entities.Slot, error)
+	GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error)
+	GetGroupLeadBookingID(groupID string) (string, error)
+	DeleteSlots(slotIDs []string, moves map[string]string) error
+	GetMarketProviderID(marketID string) (string, error)
+	SelectSlots(marketID string, selector *entitiesDtos.SlotSelector) ([]*entities.Slot, error)
+	BulkUpdateSlots(slots []*entities.Slot) error
+	GetZones(marketID string) ([]entities.Zone, error)
 }
@@ -0,0 +1,13 @@
+package Interfaces
+
+import (
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+type ISlotSearch interface {
+	SearchMarkets(filter *entitiesDtos.SlotSearchFilter) ([]entitiesDtos.MarketDistance, error)
+	CountAvailableSlots(filter *entitiesDtos.SlotSearchFilter) (int64, int64, error)
+	SearchAvailableSlots(filter *entitiesDtos.SlotSearchFilter, marketIDs []string) ([]*entities.Slot, error)
+	GetMarketsByIDs(marketIDs []string) ([]entities.Market, error)
+}
@@ -0,0 +1,17 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IStaff interface {
+	CreateStaff(staff *entities.StaffMember) error
+	UpdateStaff(staff *entities.StaffMember) error
+	GetStaffByID(staffID string) (*entities.StaffMember, error)
+	GetStaffByEmail(email string) (*entities.StaffMember, error)
+	GetStaffByUsername(username string) (*entities.StaffMember, error)
+	GetStaffByInviteToken(tokenHash string) (*entities.StaffMember, error)
+	GetStaffByProvider(providerID string) ([]entities.StaffMember, error)
+	ReplaceAssignments(staffID string, assignments []entities.StaffAssignment) error
+	GetAssignment(staffID, marketID string) (*entities.StaffAssignment, error)
+	GetMarketsByIDs(marketIDs []string) ([]entities.Market, error)
+	GetResourceMarketID(resource, id string) (string, error)
+}
@@ -0,0 +1,15 @@
+package Interfaces
+
+import (
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+type ITextSearch interface {
+	SaveDocument(kind entities.SearchKind, refID string, weights map[string]float64) error
+	MatchDocuments(filter *entitiesDtos.TextSearchFilter) ([]entitiesDtos.SearchMatch, error)
+	GetMarkets() ([]entities.Market, error)
+	GetMarketsByIDs(marketIDs []string) ([]entities.Market, error)
+	GetVendorProfiles() ([]entities.VendorProfile, error)
+	GetVendorProfilesByIDs(vendorIDs []string) ([]entities.VendorProfile, error)
+}
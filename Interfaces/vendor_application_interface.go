@@ -0,0 +1,17 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IVendorApplication interface {
+	CreateApplication(application *entities.VendorApplication, event *entities.VendorApplicationEvent) error
+	UpdateApplication(application *entities.VendorApplication, from entities.ApplicationStatus, event *entities.VendorApplicationEvent) (bool, error)
+	GetApplication(applicationID string) (*entities.VendorApplication, error)
+	GetVendorApplication(marketID, vendorID string) (*entities.VendorApplication, error)
+	GetMarketApplications(marketID string, status entities.ApplicationStatus) ([]entities.VendorApplication, error)
+	GetVendorApplications(vendorID string) ([]entities.VendorApplication, error)
+	GetApplicationEvents(applicationID string) ([]entities.VendorApplicationEvent, error)
+	GetMarket(marketID string) (*entities.Market, error)
+	GetMarketProviderID(marketID string) (string, error)
+	VendorExists(vendorID string) (bool, error)
+	GetVendorMedia(vendorID string, kind entities.MediaKind, mediaIDs []string) ([]entities.Media, error)
+}
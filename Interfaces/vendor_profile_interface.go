@@ -0,0 +1,8 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IVendorProfile interface {
+	GetProfile(vendorID string) (*entities.VendorProfile, error)
+	SaveProfile(profile *entities.VendorProfile) error
+}
@@ -0,0 +1,21 @@
+package Interfaces
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type IWallet interface {
+	CreateCredit(credit *entities.WalletCredit) error
+	RedeemCredit(vendorID, providerID, bookingID string, amount float64, now time.Time) (float64, error)
+	RestoreCredit(bookingID string) (float64, error)
+	ExpireCredit(creditID string, now time.Time) (*entities.WalletTransaction, error)
+	GetCredit(creditID string) (*entities.WalletCredit, error)
+	GetActiveCredits(vendorID string, now time.Time) ([]entities.WalletCredit, error)
+	GetIssuedBalance(issuerID, vendorID string, now time.Time) (float64, error)
+	GetExpiredCredits(now time.Time) ([]entities.WalletCredit, error)
+	GetTransactionsByVendor(vendorID string) ([]entities.WalletTransaction, error)
+	GetTransactionsByIssuer(issuerID string) ([]entities.WalletTransaction, error)
+	GetMarketProviderID(marketID string) (string, error)
+	HasProviderBooking(providerID, vendorID string) (bool, error)
+}
@@ -0,0 +1,14 @@
+package Interfaces
+
+import entities "tln-backend/Entities"
+
+type IZone interface {
+	CreateZone(zone *entities.Zone) error
+	SaveZone(zone *entities.Zone) error
+	GetZone(zoneID string) (*entities.Zone, error)
+	GetZones(marketID string) ([]entities.Zone, error)
+	DeleteZone(zoneID string) error
+	CountZoneSlots(zoneID string) (int64, error)
+	CountZoneBookings(zoneID, date string, category entities.Category, excludeSlotIDs []string) (int64, error)
+	GetMarketProviderID(marketID string) (string, error)
+}
@@ -1,14 +1,17 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 	"os"
 	"strings"
+	entities "tln-backend/Entities"
 	"tln-backend/Repository"
 )
 
-func JWTAuthMiddleware(userRepo *Repository.UserRepository, providerRepo *Repository.ProviderRepository) fiber.Handler {
+func JWTAuthMiddleware(userRepo *Repository.UserRepository, providerRepo *Repository.ProviderRepository, staffRepo *Repository.StaffRepository, adminRepo *Repository.AdminRepository) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -47,7 +50,8 @@ func JWTAuthMiddleware(userRepo *Repository.UserRepository, providerRepo *Reposi
 				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid role in token"})
 			}
 
-			if role == "provider" {
+			switch role {
+			case "provider":
 				// Check if the provider exists in the database
 				provider, err := providerRepo.GetProviderByID(userID)
 				if err != nil {
@@ -57,7 +61,25 @@ func JWTAuthMiddleware(userRepo *Repository.UserRepository, providerRepo *Reposi
 				if provider.Email != email {
 					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid provider information"})
 				}
-			} else {
+			case "staff":
+				// Staff sign in under a provider; disabled staff lose access at once
+				staff, err := staffRepo.GetStaffByID(userID)
+				if err != nil || staff.Status != entities.StaffActive {
+					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Staff member not found"})
+				}
+				if staff.Email != email {
+					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid staff information"})
+				}
+				c.Locals("providerID", staff.ProviderID)
+			case "admin":
+				admin, err := adminRepo.GetAdminByID(userID)
+				if err != nil || admin.Disabled {
+					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Admin not found"})
+				}
+				if admin.Email != email {
+					return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid admin information"})
+				}
+			default:
 				// Check if the user exists in the database
 				user, err := userRepo.GetUserByID(userID)
 				if err != nil {
@@ -81,15 +103,124 @@ func JWTAuthMiddleware(userRepo *Repository.UserRepository, providerRepo *Reposi
 	}
 }
 
-// ProviderAuthMiddleware remains unchanged
-func ProviderAuthMiddleware() fiber.Handler {
+// OptionalAuthMiddleware identifies the caller when a token is sent and lets anonymous requests through, for
+// public routes that show more to signed-in users.
+func OptionalAuthMiddleware(auth fiber.Handler) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		role := c.Locals("role")
-		if role != "provider" {
+		if c.Get("Authorization") == "" {
+			return c.Next()
+		}
+		return auth(c)
+	}
+}
+
+// VendorOrMiddleware lets vendors through, leaving the handler to hold them to their own rows, and sends everyone
+// else through guard, for routes shared by vendors and the market's provider.
+func VendorOrMiddleware(guard fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals("role") == "vendor" {
+			return c.Next()
+		}
+		return guard(c)
+	}
+}
+
+// MarketResolver finds the market a provider route acts on. It returns "" when the request names none.
+type MarketResolver func(c *fiber.Ctx) (string, error)
+
+// MarketParam reads the market ID from a route parameter.
+func MarketParam(name string) MarketResolver {
+	return func(c *fiber.Ctx) (string, error) {
+		return c.Params(name), nil
+	}
+}
+
+// MarketQuery reads the market ID from a query parameter.
+func MarketQuery(name string) MarketResolver {
+	return func(c *fiber.Ctx) (string, error) {
+		return c.Query(name), nil
+	}
+}
+
+// MarketBody reads the market ID from a field of the JSON body. The body is left for the handler to parse.
+func MarketBody(field string) MarketResolver {
+	return func(c *fiber.Ctx) (string, error) {
+		var body map[string]interface{}
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return "", err
+		}
+		marketID, _ := body[field].(string)
+		return marketID, nil
+	}
+}
+
+// MarketOf looks up the market of the row a route parameter names, e.g. the market of a zone.
+func MarketOf(staffRepo *Repository.StaffRepository, resource, param string) MarketResolver {
+	return func(c *fiber.Ctx) (string, error) {
+		return staffRepo.GetResourceMarketID(resource, c.Params(param))
+	}
+}
+
+// PermissionMiddleware guards provider routes. Providers pass, acting on their own markets as before. Staff
+// pass when their role in the route's market grants the permission; the request then runs as their provider,
+// with the staff member's ID kept in staffID. Routes without a market are for providers only.
+func PermissionMiddleware(staffRepo *Repository.StaffRepository, permission entities.Permission, market MarketResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Locals("role") {
+		case "provider":
+			return c.Next()
+		case "staff":
+		default:
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "Access denied. Provider role required.",
 			})
 		}
+
+		if market == nil || permission == entities.PermManageProvider {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied. Only the provider can do this.",
+			})
+		}
+		marketID, err := market(c)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Not found"})
+		}
+		if marketID == "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied. Staff must name the market.",
+			})
+		}
+
+		staffID, _ := c.Locals("userID").(string)
+		assignment, err := staffRepo.GetAssignment(staffID, marketID)
+		if err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied. You are not assigned to this market.",
+			})
+		}
+		if !assignment.Role.Allows(permission) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": fmt.Sprintf("Access denied. A %s cannot do this.", assignment.Role),
+			})
+		}
+
+		c.Locals("staffID", staffID)
+		c.Locals("userID", c.Locals("providerID"))
+		c.Locals("role", "provider")
+		return c.Next()
+	}
+}
+
+// AdminAuthMiddleware guards what only platform admins may do, such as settling the ledger or moderating
+// reviews. Admins sign in through /Auth/admin/login.
+func AdminAuthMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role := c.Locals("role")
+		if role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied. Admin role required.",
+			})
+		}
 		return c.Next()
 	}
 }
@@ -0,0 +1,39 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type AdminRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminRepository(db *gorm.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+func (repo *AdminRepository) CreateAdmin(admin *entities.Admin) error {
+	return repo.db.Create(admin).Error
+}
+
+func (repo *AdminRepository) GetAdminByID(adminID string) (*entities.Admin, error) {
+	return repo.findAdmin("id = ?", adminID)
+}
+
+func (repo *AdminRepository) GetAdminByUsername(username string) (*entities.Admin, error) {
+	return repo.findAdmin("username = ?", username)
+}
+
+func (repo *AdminRepository) findAdmin(query string, args ...interface{}) (*entities.Admin, error) {
+	var admin entities.Admin
+	if err := repo.db.Where(query, args...).First(&admin).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("admin not found")
+		}
+		return nil, err
+	}
+	return &admin, nil
+}
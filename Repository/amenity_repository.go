@@ -0,0 +1,67 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type AmenityRepository struct {
+	db *gorm.DB
+}
+
+func NewAmenityRepository(db *gorm.DB) *AmenityRepository {
+	return &AmenityRepository{db: db}
+}
+
+func (repo *AmenityRepository) CreateAmenity(amenity *entities.Amenity) error {
+	return repo.db.Create(amenity).Error
+}
+
+func (repo *AmenityRepository) SaveAmenity(amenity *entities.Amenity) error {
+	return repo.db.Save(amenity).Error
+}
+
+func (repo *AmenityRepository) GetAmenity(amenityID string) (*entities.Amenity, error) {
+	var amenity entities.Amenity
+	if err := repo.db.Where("id = ?", amenityID).First(&amenity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("amenity not found")
+		}
+		return nil, err
+	}
+	return &amenity, nil
+}
+
+func (repo *AmenityRepository) GetAmenities(marketID string) ([]entities.Amenity, error) {
+	var amenities []entities.Amenity
+	if err := repo.db.Where("market_id = ?", marketID).Order("kind ASC, name ASC").Find(&amenities).Error; err != nil {
+		return nil, err
+	}
+	return amenities, nil
+}
+
+func (repo *AmenityRepository) DeleteAmenity(amenityID string) error {
+	return repo.db.Where("id = ?", amenityID).Delete(&entities.Amenity{}).Error
+}
+
+// CountAmenitySlots counts the live slots the amenity is assigned to.
+func (repo *AmenityRepository) CountAmenitySlots(amenityID string) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entities.Slot{}).
+		Where("deleted_at IS NULL AND amenities LIKE ?", fmt.Sprintf("%%%q%%", amenityID)).
+		Count(&count).Error
+	return count, err
+}
+
+func (repo *AmenityRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
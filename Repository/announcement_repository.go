@@ -0,0 +1,220 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+// bookedAnnouncements selects the announcements a vendor's paid bookings put it in the audience of, including
+// those sent before it booked.
+const bookedAnnouncements = `SELECT a.id FROM bookings b
+	JOIN slots s ON s.id = b.slot_id
+	JOIN announcements a ON a.market_id = s.market_id AND a.market_date = b.booking_date
+	WHERE b.vendor_id = ? AND b.status = 'completed'
+	AND (a.audience = 'date' OR (a.audience = 'zone' AND a.zone_id = s.zone_id) OR (a.audience = 'category' AND a.category = s.category))`
+
+type AnnouncementRepository struct {
+	db *gorm.DB
+}
+
+func NewAnnouncementRepository(db *gorm.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+func (repo *AnnouncementRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+func (repo *AnnouncementRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+func (repo *AnnouncementRepository) GetZone(zoneID string) (*entities.Zone, error) {
+	var zone entities.Zone
+	if err := repo.db.Where("id = ?", zoneID).First(&zone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("zone not found")
+		}
+		return nil, err
+	}
+	return &zone, nil
+}
+
+// GetBookedVendors lists the vendors with a paid booking at the market on the date, only in one zone or
+// stall category when those are set.
+func (repo *AnnouncementRepository) GetBookedVendors(marketID, date, zoneID string, category entities.Category) ([]string, error) {
+	query := repo.db.Table("bookings b").Joins("JOIN slots s ON s.id = b.slot_id").
+		Where("s.market_id = ? AND b.booking_date = ? AND b.status = ?", marketID, date, entities.StatusCompleted)
+	if zoneID != "" {
+		query = query.Where("s.zone_id = ?", zoneID)
+	}
+	if category != "" {
+		query = query.Where("s.category = ?", category)
+	}
+	var vendorIDs []string
+	if err := query.Distinct().Pluck("b.vendor_id", &vendorIDs).Error; err != nil {
+		return nil, err
+	}
+	return vendorIDs, nil
+}
+
+func (repo *AnnouncementRepository) GetRosterVendors(marketID string) ([]string, error) {
+	var vendorIDs []string
+	if err := repo.db.Model(&entities.VendorApplication{}).
+		Where("market_id = ? AND status = ?", marketID, entities.ApplicationApproved).
+		Pluck("vendor_id", &vendorIDs).Error; err != nil {
+		return nil, err
+	}
+	return vendorIDs, nil
+}
+
+// CreateAnnouncement saves an announcement along with a receipt for every vendor it was sent to.
+func (repo *AnnouncementRepository) CreateAnnouncement(announcement *entities.Announcement, receipts []entities.AnnouncementReceipt) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(announcement).Error; err != nil {
+			return err
+		}
+		if len(receipts) == 0 {
+			return nil
+		}
+		return tx.Omit("Vendor").CreateInBatches(receipts, 500).Error
+	})
+}
+
+func (repo *AnnouncementRepository) GetAnnouncement(announcementID string) (*entities.Announcement, error) {
+	var announcement entities.Announcement
+	if err := repo.db.Where("id = ?", announcementID).First(&announcement).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("announcement not found")
+		}
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// GetMarketAnnouncements lists a market's announcements, newest first, with how many vendors read each.
+func (repo *AnnouncementRepository) GetMarketAnnouncements(marketID string) ([]entities.Announcement, error) {
+	var announcements []entities.Announcement
+	if err := repo.db.Where("market_id = ?", marketID).Order("created_at DESC").Find(&announcements).Error; err != nil {
+		return nil, err
+	}
+	if len(announcements) == 0 {
+		return announcements, nil
+	}
+
+	ids := make([]string, 0, len(announcements))
+	for _, announcement := range announcements {
+		ids = append(ids, announcement.ID)
+	}
+	var counts []struct {
+		AnnouncementID string
+		Count          int
+	}
+	if err := repo.db.Model(&entities.AnnouncementReceipt{}).
+		Select("announcement_id, COUNT(*) AS count").
+		Where("announcement_id IN ? AND read_at IS NOT NULL", ids).
+		Group("announcement_id").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	read := make(map[string]int, len(counts))
+	for _, count := range counts {
+		read[count.AnnouncementID] = count.Count
+	}
+	for i := range announcements {
+		announcements[i].ReadCount = read[announcements[i].ID]
+	}
+	return announcements, nil
+}
+
+// GetReceipts lists who an announcement reached, unread first.
+func (repo *AnnouncementRepository) GetReceipts(announcementID string) ([]entities.AnnouncementReceipt, error) {
+	var receipts []entities.AnnouncementReceipt
+	if err := repo.db.Preload("Vendor").Where("announcement_id = ?", announcementID).
+		Order("read_at ASC NULLS FIRST").Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// GetVendorAnnouncements lists, newest first, the announcements about a vendor's market days from the given
+// date on, and those without a day sent to it since rosterSince, each with when the vendor read it.
+func (repo *AnnouncementRepository) GetVendorAnnouncements(vendorID string, from, rosterSince time.Time) ([]entities.Announcement, error) {
+	day := from.Format("2006-01-02")
+	var announcements []entities.Announcement
+	if err := repo.db.
+		Where("(market_date >= ? AND id IN ("+bookedAnnouncements+")) OR "+
+			"(id IN (SELECT announcement_id FROM announcement_receipts WHERE vendor_id = ?) AND (market_date >= ? OR (market_date IS NULL AND created_at >= ?)))",
+			day, vendorID, vendorID, day, rosterSince).
+		Order("created_at DESC").Find(&announcements).Error; err != nil {
+		return nil, err
+	}
+	if len(announcements) == 0 {
+		return announcements, nil
+	}
+
+	ids := make([]string, 0, len(announcements))
+	for _, announcement := range announcements {
+		ids = append(ids, announcement.ID)
+	}
+	var receipts []entities.AnnouncementReceipt
+	if err := repo.db.Where("announcement_id IN ? AND vendor_id = ?", ids, vendorID).Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	readAt := make(map[string]*time.Time, len(receipts))
+	for _, receipt := range receipts {
+		readAt[receipt.AnnouncementID] = receipt.ReadAt
+	}
+	for i := range announcements {
+		announcements[i].ReadAt = readAt[announcements[i].ID]
+	}
+	return announcements, nil
+}
+
+// IsRecipient reports whether the vendor was sent the announcement, or is in its audience through a booking
+// made since.
+func (repo *AnnouncementRepository) IsRecipient(announcementID, vendorID string) (bool, error) {
+	var count int64
+	if err := repo.db.Model(&entities.Announcement{}).
+		Where("id = ? AND (id IN (SELECT announcement_id FROM announcement_receipts WHERE vendor_id = ?) OR id IN ("+bookedAnnouncements+"))",
+			announcementID, vendorID, vendorID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// MarkRead sets when the vendor read the announcement, giving it a receipt if it booked after it was sent.
+// Reading it again keeps the first time.
+func (repo *AnnouncementRepository) MarkRead(announcementID, vendorID string) error {
+	now := time.Now()
+	result := repo.db.Model(&entities.AnnouncementReceipt{}).
+		Where("announcement_id = ? AND vendor_id = ?", announcementID, vendorID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", now))
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	return repo.db.Omit("Vendor").Create(&entities.AnnouncementReceipt{
+		ID:             uuid.New().String(),
+		AnnouncementID: announcementID,
+		VendorID:       vendorID,
+		ReadAt:         &now,
+	}).Error
+}
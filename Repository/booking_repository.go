@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 	entities "tln-backend/Entities"
 	entitiesDtos "tln-backend/Entities/dtos"
 )
@@ -16,8 +18,31 @@ func NewBookingRepository(db *gorm.DB) *BookingRepository {
 	return &BookingRepository{db: db}
 }
 
+// CreateBooking saves a booking after re-checking, under lock, that its slot is free and its zone has room.
 func (repo *BookingRepository) CreateBooking(booking *entities.Booking) error {
-	return repo.db.Create(booking).Error
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var locked []*entities.Slot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", booking.SlotID).Find(&locked).Error; err != nil {
+			return err
+		}
+
+		var taken int64
+		err := tx.Model(&entities.Booking{}).
+			Where("slot_id = ? AND DATE(booking_date) = ? AND status IN ?", booking.SlotID, booking.BookingDate.Format("2006-01-02"),
+				[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+			Count(&taken).Error
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return fmt.Errorf("slot %s already has a pending or confirmed booking", booking.SlotID)
+		}
+
+		if err := checkZoneCaps(tx, locked); err != nil {
+			return err
+		}
+		return tx.Create(booking).Error
+	})
 }
 
 func (repo *BookingRepository) GetBooking(bookingID string) (*entities.Booking, error) {
@@ -62,17 +87,39 @@ func (repo *BookingRepository) GetBookingsByUser(userID string) ([]entities.Book
 	return bookings, nil
 }
 
-// GetBookingsByMarket
+// GetBookingsByMarket lists a combined booking once, as its lead booking with the group attached.
 func (repo *BookingRepository) GetBookingsByMarket(marketID string) ([]entities.Booking, error) {
 	var bookings []entities.Booking
 
-	result := repo.db.Where("market_id = ?", marketID).Find(&bookings)
+	leads := repo.db.Model(&entities.BookingGroup{}).Select("lead_booking_id").Where("market_id = ?", marketID)
+	result := repo.db.Preload("Vendor").
+		Where("market_id = ?", marketID).
+		Where("group_id IS NULL OR group_id = '' OR id IN (?)", leads).
+		Find(&bookings)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
-	//reload vendor
-	result = repo.db.Preload("Vendor").Where("market_id = ?", marketID).Find(&bookings)
+	var groups []entities.BookingGroup
+	if err := repo.db.Where("market_id = ?", marketID).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	byLead := make(map[string]*entities.BookingGroup, len(groups))
+	for i := range groups {
+		byLead[groups[i].LeadBookingID] = &groups[i]
+	}
+	for i := range bookings {
+		bookings[i].Group = byLead[bookings[i].ID]
+	}
+
+	return bookings, nil
+}
+
+func (repo *BookingRepository) GetActiveBookingsByMarketAndDate(marketID, date string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+
+	result := repo.db.Where("market_id = ? AND DATE(booking_date) = ? AND status IN ?", marketID, date,
+		[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).Find(&bookings)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -80,9 +127,11 @@ func (repo *BookingRepository) GetBookingsByMarket(marketID string) ([]entities.
 	return bookings, nil
 }
 
+// UpdateBookingStatus moves the rest of a combined booking along with its lead booking.
 func (repo *BookingRepository) UpdateBookingStatus(bookingID string, status entities.BookingStatus) (*entities.Booking, error) {
 	var booking entities.Booking
-	result := repo.db.Model(&booking).Where("ID = ?", bookingID).Update("status", status)
+	groups := repo.db.Model(&entities.BookingGroup{}).Select("id").Where("lead_booking_id = ?", bookingID)
+	result := repo.db.Model(&booking).Where("ID = ?", bookingID).Or("group_id IN (?)", groups).Update("status", status)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -91,14 +140,24 @@ func (repo *BookingRepository) UpdateBookingStatus(bookingID string, status enti
 	return &booking, nil
 }
 
-func (repo *BookingRepository) IsSlotAvailable(bookingReq *entitiesDtos.BookingRequest) error {
+// ExpireSlips closes the slips of a booking that were still waiting for review.
+func (repo *BookingRepository) ExpireSlips(bookingID string) error {
+	return repo.db.Model(&entities.BankSlip{}).
+		Where("booking_id = ? AND status = ?", bookingID, entities.SlipPendingReview).
+		Update("status", entities.SlipExpired).Error
+}
+
+func (repo *BookingRepository) IsSlotAvailable(bookingReq *entitiesDtos.BookingRequest) (*entities.Slot, error) {
 	// First, check if the slot exists
 	var slot entities.Slot
-	if err := repo.db.Where("ID = ? AND market_id = ?", bookingReq.SlotID, bookingReq.MarketID).First(&slot).Error; err != nil {
+	if err := repo.db.Where("ID = ? AND market_id = ? AND deleted_at IS NULL", bookingReq.SlotID, bookingReq.MarketID).First(&slot).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("slot not found")
+			return nil, fmt.Errorf("slot not found")
 		}
-		return fmt.Errorf("error checking slot existence: %w", err)
+		return nil, fmt.Errorf("error checking slot existence: %w", err)
+	}
+	if slot.Status == entities.StatusMaintenance {
+		return nil, fmt.Errorf("slot is not open for booking")
 	}
 
 	// Check for existing bookings on the requested date
@@ -109,12 +168,137 @@ func (repo *BookingRepository) IsSlotAvailable(bookingReq *entitiesDtos.BookingR
 		Count(&count).Error
 
 	if err != nil {
-		return fmt.Errorf("error checking existing bookings: %w", err)
+		return nil, fmt.Errorf("error checking existing bookings: %w", err)
 	}
 
 	if count > 0 {
-		return fmt.Errorf("you already have a pending or confirmed booking for this slot")
+		return nil, fmt.Errorf("you already have a pending or confirmed booking for this slot")
+	}
+
+	return &slot, nil
+}
+
+func (repo *BookingRepository) GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	if len(slotIDs) == 0 {
+		return slots, nil
+	}
+	if err := repo.db.Where("id IN ?", slotIDs).Find(&slots).Error; err != nil {
+		return nil, err
 	}
+	return slots, nil
+}
+
+// CreateBookingGroup books every slot of a group or none of them. The slots are locked while their bookings
+// are checked so two groups cannot share a stall.
+func (repo *BookingRepository) CreateBookingGroup(group *entities.BookingGroup, bookings []*entities.Booking) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var locked []*entities.Slot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", group.SlotIDs).Find(&locked).Error; err != nil {
+			return err
+		}
+
+		var taken []string
+		err := tx.Model(&entities.Booking{}).
+			Where("slot_id IN ? AND DATE(booking_date) = ? AND status IN ?", group.SlotIDs, group.Date,
+				[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+			Pluck("slot_id", &taken).Error
+		if err != nil {
+			return err
+		}
+		if len(taken) > 0 {
+			return fmt.Errorf("slot %s already has a pending or confirmed booking", taken[0])
+		}
+		if err := checkZoneCaps(tx, locked); err != nil {
+			return err
+		}
+
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		return tx.Create(bookings).Error
+	})
+}
 
-	return nil
+func (repo *BookingRepository) GetBookingGroup(groupID string) (*entities.BookingGroup, error) {
+	var group entities.BookingGroup
+	if err := repo.db.Where("id = ?", groupID).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("booking group not found")
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetGroupMemberSlotIDs returns the slots a lead booking holds besides its own, or none for a single booking.
+func (repo *BookingRepository) GetGroupMemberSlotIDs(leadBookingID string) ([]string, error) {
+	var slotIDs []string
+	groups := repo.db.Model(&entities.BookingGroup{}).Select("id").Where("lead_booking_id = ?", leadBookingID)
+	err := repo.db.Model(&entities.Booking{}).
+		Where("group_id IN (?) AND id <> ?", groups, leadBookingID).
+		Pluck("slot_id", &slotIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return slotIDs, nil
+}
+
+// GetDaySlots lists a market day's live slots in sheet order.
+func (repo *BookingRepository) GetDaySlots(marketID, date string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	err := repo.db.Where("market_id = ? AND date = ? AND deleted_at IS NULL", marketID, date).
+		Order("zone ASC, name ASC").Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// GetDayBookings lists a market day's pending and confirmed bookings with their vendors.
+func (repo *BookingRepository) GetDayBookings(marketID, date string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	err := repo.db.Preload("Vendor").
+		Where("market_id = ? AND DATE(booking_date) = ? AND status IN ?", marketID, date,
+			[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (repo *BookingRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+func (repo *BookingRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+// CheckInBooking marks a booking, and the rest of the group it leads, as checked in. It reports false when
+// the booking was already checked in.
+func (repo *BookingRepository) CheckInBooking(bookingID, checkedInBy string, at time.Time) (bool, error) {
+	groups := repo.db.Model(&entities.BookingGroup{}).Select("id").Where("lead_booking_id = ?", bookingID)
+	result := repo.db.Model(&entities.Booking{}).
+		Where("checked_in_at IS NULL AND (id = ? OR group_id IN (?))", bookingID, groups).
+		Updates(map[string]interface{}{"checked_in_at": at, "checked_in_by": checkedInBy})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
@@ -1,6 +1,7 @@
 package Repository
 
 import (
+	"errors"
 	"fmt"
 	"gorm.io/gorm"
 	"log"
@@ -70,7 +71,7 @@ func (repo *DashboardRepository) UpdateDashboardStats(marketID string) error {
             SELECT 
                 b.market_id,
                 DATE(b.booking_date) as booking_date,
-                s.zone as zone,
+                COALESCE(z.name, s.zone) as zone,
                 COUNT(*) as zone_bookings,
                 COUNT(CASE WHEN b.status = 'completed' THEN 1 END) as completed_in_zone,
                 COUNT(CASE WHEN b.status = 'cancelled' THEN 1 END) as cancelled_in_zone,
@@ -82,11 +83,12 @@ func (repo *DashboardRepository) UpdateDashboardStats(marketID string) error {
                 ) as zone_occupancy
             FROM bookings b
             JOIN slots s ON b.slot_id = s.id
+            LEFT JOIN zones z ON z.id = s.zone_id
             WHERE b.market_id = $1
             GROUP BY 
                 b.market_id, 
                 DATE(b.booking_date), 
-                s.zone
+                COALESCE(z.name, s.zone)
         ),
         ranked_zones AS (
             SELECT 
@@ -181,6 +183,18 @@ func (repo *DashboardRepository) UpdateDashboardStats(marketID string) error {
 	return nil
 }
 
+// GetMarketStatus returns where a market is in its lifecycle.
+func (repo *DashboardRepository) GetMarketStatus(marketID string) (entities.MarketStatus, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "status").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.Status, nil
+}
+
 const mondayOffsetWhenSunday = -6
 
 func (repo *DashboardRepository) GetWeeklyStats(marketID string) ([]entities.MarketDashboardStats, error) {
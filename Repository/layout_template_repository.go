@@ -0,0 +1,160 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type LayoutTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewLayoutTemplateRepository(db *gorm.DB) *LayoutTemplateRepository {
+	return &LayoutTemplateRepository{db: db}
+}
+
+func (repo *LayoutTemplateRepository) CreateTemplate(template *entities.LayoutTemplate) error {
+	return repo.db.Create(template).Error
+}
+
+// ReplaceTemplate saves the template's details and swaps its whole stall list.
+func (repo *LayoutTemplateRepository) ReplaceTemplate(template *entities.LayoutTemplate) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.LayoutTemplate{}).Where("id = ?", template.ID).Updates(map[string]interface{}{
+			"name":        template.Name,
+			"description": template.Description,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("template_id = ?", template.ID).Delete(&entities.TemplateStall{}).Error; err != nil {
+			return err
+		}
+
+		if len(template.Stalls) == 0 {
+			return nil
+		}
+		return tx.Create(&template.Stalls).Error
+	})
+}
+
+func (repo *LayoutTemplateRepository) GetTemplate(templateID string) (*entities.LayoutTemplate, error) {
+	var template entities.LayoutTemplate
+	err := repo.db.Preload("Stalls", func(db *gorm.DB) *gorm.DB {
+		return db.Order("zone ASC, name ASC")
+	}).Where("id = ?", templateID).First(&template).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (repo *LayoutTemplateRepository) GetTemplatesByMarket(marketID string) ([]entities.LayoutTemplate, error) {
+	var templates []entities.LayoutTemplate
+	err := repo.db.Preload("Stalls", func(db *gorm.DB) *gorm.DB {
+		return db.Order("zone ASC, name ASC")
+	}).Where("market_id = ?", marketID).Order("name ASC").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (repo *LayoutTemplateRepository) DeleteTemplate(templateID string) error {
+	return repo.db.Where("id = ?", templateID).Delete(&entities.LayoutTemplate{}).Error
+}
+
+func (repo *LayoutTemplateRepository) GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	if len(slotIDs) == 0 {
+		return slots, nil
+	}
+	if err := repo.db.Where("id IN ?", slotIDs).Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// GetActiveBookingSlotIDs returns the slots that have a pending or confirmed booking.
+func (repo *LayoutTemplateRepository) GetActiveBookingSlotIDs(slotIDs []string) ([]string, error) {
+	var booked []string
+	if len(slotIDs) == 0 {
+		return booked, nil
+	}
+	err := repo.db.Model(&entities.Booking{}).
+		Where("slot_id IN ? AND status IN ?", slotIDs, []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Distinct().Pluck("slot_id", &booked).Error
+	if err != nil {
+		return nil, err
+	}
+	return booked, nil
+}
+
+// SaveSlots writes a template application in one transaction so a failure leaves no half-stamped dates.
+func (repo *LayoutTemplateRepository) SaveSlots(created, updated []*entities.Slot) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, slot := range updated {
+			if err := tx.Model(slot).Select(slotShapeColumns).Updates(slot).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteUnusedSlots removes the given slots that are available and have never been booked, and returns their IDs.
+// Slots with any booking history are kept because deleting them would cascade to the bookings.
+func (repo *LayoutTemplateRepository) DeleteUnusedSlots(slotIDs []string) ([]string, error) {
+	var removed []string
+	if len(slotIDs) == 0 {
+		return removed, nil
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		unused := tx.Model(&entities.Slot{}).
+			Where("id IN ? AND status = ?", slotIDs, entities.StatusAvailable).
+			Where("NOT EXISTS (SELECT 1 FROM bookings WHERE bookings.slot_id = slots.id)")
+		if err := unused.Pluck("id", &removed).Error; err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", removed).Delete(&entities.Slot{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (repo *LayoutTemplateRepository) GetZones(marketID string) ([]entities.Zone, error) {
+	var zones []entities.Zone
+	if err := repo.db.Where("market_id = ?", marketID).Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (repo *LayoutTemplateRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
@@ -0,0 +1,180 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+// slotShapeColumns are the slot columns a layout controls; status and booker belong to bookings.
+var slotShapeColumns = []string{"zone_id", "width", "height", "price", "category", "x", "y", "rotation", "shape"}
+
+type LayoutVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewLayoutVersionRepository(db *gorm.DB) *LayoutVersionRepository {
+	return &LayoutVersionRepository{db: db}
+}
+
+// GetSlotsOnDates includes retired slots so a layout that brings a stall back can revive it.
+func (repo *LayoutVersionRepository) GetSlotsOnDates(marketID string, dates []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	if len(dates) == 0 {
+		return slots, nil
+	}
+	if err := repo.db.Where("market_id = ? AND date IN ?", marketID, dates).Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (repo *LayoutVersionRepository) GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	if len(slotIDs) == 0 {
+		return bookings, nil
+	}
+	err := repo.db.Where("slot_id IN ? AND status IN ?", slotIDs, []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Order("created_at ASC").Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// GetBookedSlotIDs returns the slots that have any booking at all, including cancelled and refunded ones.
+func (repo *LayoutVersionRepository) GetBookedSlotIDs(slotIDs []string) ([]string, error) {
+	var booked []string
+	if len(slotIDs) == 0 {
+		return booked, nil
+	}
+	if err := repo.db.Model(&entities.Booking{}).Where("slot_id IN ?", slotIDs).Distinct().Pluck("slot_id", &booked).Error; err != nil {
+		return nil, err
+	}
+	return booked, nil
+}
+
+// ApplyLayout writes the slot changes and the new versions together. Versions are numbered here, inside the
+// transaction, and the unique index on (market_id, version) stops two concurrent changes taking the same number.
+// Retired slots are hidden rather than deleted because deleting a slot cascades to its booking history.
+func (repo *LayoutVersionRepository) ApplyLayout(versions []*entities.LayoutVersion, created, updated, revived []*entities.Slot, deleted, retired []string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, version := range versions {
+			var latest int
+			if err := tx.Model(&entities.LayoutVersion{}).Where("market_id = ?", version.MarketID).
+				Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+				return err
+			}
+			version.Version = latest + 1
+			if err := tx.Create(version).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(created) > 0 {
+			if err := tx.CreateInBatches(created, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		// Only the shape is written so booked and maintenance slots keep their status and booker
+		for _, slot := range updated {
+			if err := tx.Model(slot).Select(slotShapeColumns).Updates(slot).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, slot := range revived {
+			slot.Status, slot.Booker, slot.DeletedAt = entities.StatusAvailable, "", nil
+			columns := append([]string{"status", "booker", "deleted_at"}, slotShapeColumns...)
+			if err := tx.Model(slot).Select(columns).Updates(slot).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(deleted) > 0 {
+			if err := tx.Where("id IN ?", deleted).Delete(&entities.Slot{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(retired) > 0 {
+			if err := tx.Model(&entities.Slot{}).Where("id IN ?", retired).Updates(map[string]interface{}{
+				"status":     entities.StatusMaintenance,
+				"deleted_at": time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetVersionScopes lists every date/zone pair any version of the market has recorded.
+func (repo *LayoutVersionRepository) GetVersionScopes(marketID string) ([]string, error) {
+	var versions []entities.LayoutVersion
+	if err := repo.db.Select("id", "scopes").Where("market_id = ?", marketID).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	scopes := make([]string, 0)
+	for _, version := range versions {
+		scopes = append(scopes, version.Scopes...)
+	}
+	return scopes, nil
+}
+
+func (repo *LayoutVersionRepository) GetVersions(marketID string) ([]entities.LayoutVersion, error) {
+	var versions []entities.LayoutVersion
+	if err := repo.db.Where("market_id = ?", marketID).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (repo *LayoutVersionRepository) GetVersion(marketID string, version int) (*entities.LayoutVersion, error) {
+	var layoutVersion entities.LayoutVersion
+	err := repo.db.Preload("Stalls", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date ASC, zone ASC, name ASC")
+	}).Where("market_id = ? AND version = ?", marketID, version).First(&layoutVersion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("layout version not found")
+		}
+		return nil, err
+	}
+	return &layoutVersion, nil
+}
+
+func (repo *LayoutVersionRepository) GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	if len(slotIDs) == 0 {
+		return slots, nil
+	}
+	if err := repo.db.Where("id IN ?", slotIDs).Order("date ASC, zone ASC, name ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (repo *LayoutVersionRepository) GetZones(marketID string) ([]entities.Zone, error) {
+	var zones []entities.Zone
+	if err := repo.db.Where("market_id = ?", marketID).Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (repo *LayoutVersionRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
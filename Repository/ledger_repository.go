@@ -0,0 +1,154 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+func (repo *LedgerRepository) GetOrCreateAccount(accountType entities.LedgerAccountType, ownerID, name string) (*entities.LedgerAccount, error) {
+	account := entities.LedgerAccount{
+		ID:      uuid.New().String(),
+		Type:    accountType,
+		OwnerID: ownerID,
+		Name:    name,
+	}
+
+	err := repo.db.Where("type = ? AND owner_id = ?", accountType, ownerID).FirstOrCreate(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (repo *LedgerRepository) GetAccount(accountID string) (*entities.LedgerAccount, error) {
+	var account entities.LedgerAccount
+	if err := repo.db.Where("id = ?", accountID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (repo *LedgerRepository) GetAccountsByOwner(ownerID string) ([]entities.LedgerAccount, error) {
+	var accounts []entities.LedgerAccount
+	if err := repo.db.Where("owner_id = ?", ownerID).Order("type").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// GetEntryByReference returns nil without an error when nothing has been posted for the reference yet.
+func (repo *LedgerRepository) GetEntryByReference(kind entities.JournalKind, referenceID string) (*entities.JournalEntry, error) {
+	var entry entities.JournalEntry
+	err := repo.db.Preload("Lines").Where("kind = ? AND reference_id = ?", kind, referenceID).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateEntry writes the entry and all of its lines in one database transaction.
+func (repo *LedgerRepository) CreateEntry(entry *entities.JournalEntry) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(entry).Error
+	})
+}
+
+func (repo *LedgerRepository) GetEntriesByAccount(accountID string) ([]entities.JournalEntry, error) {
+	var entries []entities.JournalEntry
+	err := repo.db.Preload("Lines").
+		Where("id IN (?)", repo.db.Model(&entities.JournalLine{}).Select("entry_id").Where("account_id = ?", accountID)).
+		Order("created_at DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (repo *LedgerRepository) GetAccountTotals(accountID string) (float64, float64, error) {
+	var totals struct {
+		Debit  float64
+		Credit float64
+	}
+	err := repo.db.Model(&entities.JournalLine{}).
+		Select("COALESCE(SUM(debit), 0) AS debit, COALESCE(SUM(credit), 0) AS credit").
+		Where("account_id = ?", accountID).
+		Scan(&totals).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return totals.Debit, totals.Credit, nil
+}
+
+func (repo *LedgerRepository) GetLedgerTotals() (float64, float64, int64, error) {
+	var totals struct {
+		Debit  float64
+		Credit float64
+	}
+	err := repo.db.Model(&entities.JournalLine{}).
+		Select("COALESCE(SUM(debit), 0) AS debit, COALESCE(SUM(credit), 0) AS credit").
+		Scan(&totals).Error
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var count int64
+	if err := repo.db.Model(&entities.JournalEntry{}).Count(&count).Error; err != nil {
+		return 0, 0, 0, err
+	}
+
+	return totals.Debit, totals.Credit, count, nil
+}
+
+func (repo *LedgerRepository) GetUnbalancedEntryIDs() ([]string, error) {
+	var ids []string
+	err := repo.db.Model(&entities.JournalEntry{}).
+		Select("journal_entries.id").
+		Joins("LEFT JOIN journal_lines ON journal_lines.entry_id = journal_entries.id").
+		Group("journal_entries.id").
+		Having("COUNT(journal_lines.id) < 2 OR COALESCE(SUM(journal_lines.debit), 0) <> COALESCE(SUM(journal_lines.credit), 0)").
+		Pluck("journal_entries.id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetBookingParties loads the booking with its payment and resolves the provider that owns the booked market.
+func (repo *LedgerRepository) GetBookingParties(bookingID string) (*entities.Booking, string, error) {
+	var booking entities.Booking
+	if err := repo.db.Preload("Payment").Where("id = ?", bookingID).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", fmt.Errorf("booking not found")
+		}
+		return nil, "", err
+	}
+
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", booking.MarketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", fmt.Errorf("market not found")
+		}
+		return nil, "", err
+	}
+
+	return &booking, market.ProviderID, nil
+}
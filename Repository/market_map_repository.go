@@ -0,0 +1,52 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type MarketMapRepository struct {
+	db *gorm.DB
+}
+
+func NewMarketMapRepository(db *gorm.DB) *MarketMapRepository {
+	return &MarketMapRepository{db: db}
+}
+
+func (repo *MarketMapRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+// GetSlotsByDate leaves out slots a layout change has retired.
+func (repo *MarketMapRepository) GetSlotsByDate(marketID, date string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	err := repo.db.Where("market_id = ? AND date = ? AND deleted_at IS NULL", marketID, date).
+		Order("zone ASC, name ASC").Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+func (repo *MarketMapRepository) GetActiveBookings(slotIDs []string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	if len(slotIDs) == 0 {
+		return bookings, nil
+	}
+	err := repo.db.Select("id", "slot_id", "status").
+		Where("slot_id IN ? AND status IN ?", slotIDs, []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
@@ -92,11 +92,12 @@ func (repo *MarketRepository) GetMarketWithProviderByID(marketID string) (*entit
 
 	return &market, nil
 }
+
+// GetMarkets lists the published markets.
 func (repo *MarketRepository) GetMarkets() ([]entities.Market, *entitiesDtos.ErrorResponse) {
 	var markets []entities.Market
 
-	// Add Debug logging to see actual SQL query
-	err := repo.db.Debug().Find(&markets).Error
+	err := repo.db.Where("status = ?", entities.MarketPublished).Find(&markets).Error
 	if err != nil {
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
@@ -104,9 +105,84 @@ func (repo *MarketRepository) GetMarkets() ([]entities.Market, *entitiesDtos.Err
 		}
 	}
 
-	// Log the number of markets retrieved for debugging purposes
-	fmt.Printf("Markets found: %d\n", len(markets))
+	return markets, nil
+}
+
+// distanceKmSQL is the haversine distance in kilometres from the point (?, ?) to a market m, taking the
+// latitude twice and then the longitude.
+const distanceKmSQL = `2 * 6371 * ASIN(LEAST(1, SQRT(
+	POWER(SIN(RADIANS(m.latitude::float8 - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(m.latitude::float8)) * POWER(SIN(RADIANS(m.longitude::float8 - ?) / 2), 2))))`
+
+// GetNearbyMarkets measures the distance to every published market with a location within the filter's radius,
+// nearest first. The date, availability and category filters all apply to the same slot.
+func (repo *MarketRepository) GetNearbyMarkets(filter *entitiesDtos.NearbyMarketFilter) ([]entitiesDtos.MarketDistance, error) {
+	area, areaArgs := marketAreaSQL(&filter.GeoArea)
+	inner := repo.db.Table("markets m").
+		Select("m.id, "+distanceKmSQL+" AS distance_km", filter.Lat, filter.Lat, filter.Lng).
+		Where("m.deleted_at IS NULL AND m.status = ?", entities.MarketPublished).
+		Where(area, areaArgs...)
+	if filter.Date != "" || filter.Available || filter.Category != "" {
+		slots, slotArgs := marketSlotsSQL(filter.Date, filter.Category, filter.Available)
+		inner = inner.Where(slots, slotArgs...)
+	}
+
+	var distances []entitiesDtos.MarketDistance
+	err := repo.db.Table("(?) AS nearby", inner).
+		Where("distance_km <= ?", filter.RadiusKm).
+		Order("distance_km ASC, id ASC").
+		Scan(&distances).Error
+	if err != nil {
+		return nil, err
+	}
+	return distances, nil
+}
 
+// marketAreaSQL narrows markets m to the area's bounding box; the exact distance is left to the caller.
+func marketAreaSQL(area *entitiesDtos.GeoArea) (string, []interface{}) {
+	sql := "m.latitude IS NOT NULL AND m.longitude IS NOT NULL AND m.latitude BETWEEN ? AND ?"
+	args := []interface{}{area.MinLat, area.MaxLat}
+	switch {
+	case area.MinLng > area.MaxLng:
+		sql += " AND (m.longitude >= ? OR m.longitude <= ?)"
+		args = append(args, area.MinLng, area.MaxLng)
+	case area.MinLng > -180 || area.MaxLng < 180:
+		sql += " AND m.longitude BETWEEN ? AND ?"
+		args = append(args, area.MinLng, area.MaxLng)
+	}
+	return sql, args
+}
+
+// marketSlotsSQL asks that a market m has a live slot on a date, or from today on when date is empty, of a
+// category if one is given and free if available is set.
+func marketSlotsSQL(date string, category entities.Category, available bool) (string, []interface{}) {
+	sql := "SELECT 1 FROM slots s WHERE s.market_id = m.id AND s.deleted_at IS NULL AND s.status <> ?"
+	args := []interface{}{entities.StatusMaintenance}
+	if date != "" {
+		sql += " AND s.date = ?"
+		args = append(args, date)
+	} else {
+		sql += " AND s.date >= CURRENT_DATE"
+	}
+	if category != "" {
+		sql += " AND s.category = ?"
+		args = append(args, category)
+	}
+	if available {
+		sql += " AND s.status = ? AND NOT EXISTS (SELECT 1 FROM bookings b WHERE b.slot_id = s.id AND b.status IN ?)"
+		args = append(args, entities.StatusAvailable, []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted})
+	}
+	return "EXISTS (" + sql + ")", args
+}
+
+func (repo *MarketRepository) GetMarketsByIDs(marketIDs []string) ([]entities.Market, error) {
+	var markets []entities.Market
+	if len(marketIDs) == 0 {
+		return markets, nil
+	}
+	if err := repo.db.Where("id IN ?", marketIDs).Find(&markets).Error; err != nil {
+		return nil, err
+	}
 	return markets, nil
 }
 
@@ -164,10 +240,15 @@ func (repo *MarketRepository) EditMarket(marketID string, marketReq *entitiesDto
 	market.Phone = marketReq.Phone
 	market.Image = marketReq.Image
 	market.LayoutImage = marketReq.LayoutImage
+	market.FloorWidth = marketReq.FloorWidth
+	market.FloorHeight = marketReq.FloorHeight
 	market.OpenTime = marketReq.OpenTime
 	market.CloseTime = marketReq.CloseTime
 	market.Latitude = marketReq.Latitude
 	market.Longitude = marketReq.Longitude
+	if marketReq.RosterOnly != nil {
+		market.RosterOnly = *marketReq.RosterOnly
+	}
 
 	err = repo.db.Save(&market).Error
 	if err != nil {
@@ -213,3 +294,58 @@ func (repo *MarketRepository) GetMarketByProviderID(providerID string) ([]entiti
 
 	return markets, nil
 }
+
+// ChangeMarketStatus moves a market to the change's status and records the change. It fails when the market
+// has moved on from the change's starting status in the meantime.
+func (repo *MarketRepository) ChangeMarketStatus(change *entities.MarketStatusChange) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Market{}).
+			Where("id = ? AND status = ?", change.MarketID, change.FromStatus).
+			Update("status", change.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("market is no longer %s", change.FromStatus)
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// GetStatusChanges returns a market's lifecycle history, newest first.
+func (repo *MarketRepository) GetStatusChanges(marketID string) ([]entities.MarketStatusChange, error) {
+	var changes []entities.MarketStatusChange
+	if err := repo.db.Where("market_id = ?", marketID).Order("created_at DESC").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetLastStatusChange returns the latest change that moved the market to a status, or nil if none did.
+func (repo *MarketRepository) GetLastStatusChange(marketID string, status entities.MarketStatus) (*entities.MarketStatusChange, error) {
+	var change entities.MarketStatusChange
+	err := repo.db.Where("market_id = ? AND to_status = ?", marketID, status).Order("created_at DESC").First(&change).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (repo *MarketRepository) CountLiveSlots(marketID string) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entities.Slot{}).Where("market_id = ? AND deleted_at IS NULL", marketID).Count(&count).Error
+	return count, err
+}
+
+// CountUpcomingBookings counts the pending and confirmed bookings of a market from a date on.
+func (repo *MarketRepository) CountUpcomingBookings(marketID, from string) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entities.Booking{}).
+		Where("market_id = ? AND booking_date >= ? AND status IN ?", marketID, from,
+			[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Count(&count).Error
+	return count, err
+}
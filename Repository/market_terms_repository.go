@@ -0,0 +1,121 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type MarketTermsRepository struct {
+	db *gorm.DB
+}
+
+func NewMarketTermsRepository(db *gorm.DB) *MarketTermsRepository {
+	return &MarketTermsRepository{db: db}
+}
+
+func (repo *MarketTermsRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+func (repo *MarketTermsRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+// CreateTerms saves a new version of a market's terms. Two versions published at once get the same number,
+// and the unique index turns the second away.
+func (repo *MarketTermsRepository) CreateTerms(terms *entities.MarketTerms) error {
+	return repo.db.Create(terms).Error
+}
+
+// GetCurrentTerms returns the highest version of a market's terms.
+func (repo *MarketTermsRepository) GetCurrentTerms(marketID string) (*entities.MarketTerms, error) {
+	var terms entities.MarketTerms
+	if err := repo.db.Where("market_id = ?", marketID).Order("version DESC").First(&terms).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market terms not found")
+		}
+		return nil, err
+	}
+	return &terms, nil
+}
+
+func (repo *MarketTermsRepository) GetTermsVersions(marketID string) ([]entities.MarketTerms, error) {
+	var versions []entities.MarketTerms
+	if err := repo.db.Where("market_id = ?", marketID).Order("version DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (repo *MarketTermsRepository) CreateAcceptance(acceptance *entities.TermsAcceptance) error {
+	return repo.db.Omit("Terms", "Vendor").Create(acceptance).Error
+}
+
+func (repo *MarketTermsRepository) GetAcceptance(termsID, vendorID string) (*entities.TermsAcceptance, error) {
+	return repo.findAcceptance("terms_id = ? AND vendor_id = ?", termsID, vendorID)
+}
+
+func (repo *MarketTermsRepository) GetAcceptanceByID(acceptanceID string) (*entities.TermsAcceptance, error) {
+	return repo.findAcceptance("id = ?", acceptanceID)
+}
+
+func (repo *MarketTermsRepository) findAcceptance(query string, args ...interface{}) (*entities.TermsAcceptance, error) {
+	var acceptance entities.TermsAcceptance
+	if err := repo.db.Preload("Terms").Where(query, args...).First(&acceptance).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("terms acceptance not found")
+		}
+		return nil, err
+	}
+	return &acceptance, nil
+}
+
+// GetMarketAcceptances lists who accepted a market's terms, newest first, only of one version when version
+// is set.
+func (repo *MarketTermsRepository) GetMarketAcceptances(marketID string, version int) ([]entities.TermsAcceptance, error) {
+	var acceptances []entities.TermsAcceptance
+	query := repo.db.Preload("Vendor").Where("market_id = ?", marketID)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+	if err := query.Order("accepted_at DESC").Find(&acceptances).Error; err != nil {
+		return nil, err
+	}
+	return acceptances, nil
+}
+
+func (repo *MarketTermsRepository) GetVendorAcceptances(vendorID string) ([]entities.TermsAcceptance, error) {
+	var acceptances []entities.TermsAcceptance
+	if err := repo.db.Preload("Terms").Where("vendor_id = ?", vendorID).
+		Order("accepted_at DESC").Find(&acceptances).Error; err != nil {
+		return nil, err
+	}
+	return acceptances, nil
+}
+
+func (repo *MarketTermsRepository) GetBooking(bookingID string) (*entities.Booking, error) {
+	var booking entities.Booking
+	if err := repo.db.Where("id = ?", bookingID).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("booking not found")
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
@@ -0,0 +1,133 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+// mediaTarget is the column that holds the ID of each kind of media.
+type mediaTarget struct {
+	table  string
+	key    string
+	column string
+}
+
+var mediaTargets = map[entities.MediaKind]mediaTarget{
+	entities.MediaMarketImage:  {table: "markets", key: "id", column: "image_id"},
+	entities.MediaMarketLayout: {table: "markets", key: "id", column: "layout_image_id"},
+	entities.MediaSlotImage:    {table: "slots", key: "id", column: "image_id"},
+	entities.MediaVendorImage:  {table: "vendors", key: "id", column: "image_id"},
+	entities.MediaStallImage:   {table: "vendor_profiles", key: "vendor_id", column: "image_id"},
+	entities.MediaPaymentQR:    {table: "transactions", key: "id", column: "image_id"},
+	entities.MediaBankSlip:     {table: "bank_slips", key: "id", column: "image_id"},
+}
+
+type MediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// CreateMedia records media whose target sets the ID itself, such as a transaction saved afterwards.
+func (repo *MediaRepository) CreateMedia(media *entities.Media) error {
+	return repo.db.Create(media).Error
+}
+
+// AttachMedia records media and points its target at it, retiring whatever media the target had before.
+// It returns the ID of that earlier media, or "" when there was none.
+func (repo *MediaRepository) AttachMedia(media *entities.Media) (string, error) {
+	target, ok := mediaTargets[media.Kind]
+	if !ok {
+		return "", fmt.Errorf("unknown media kind %q", media.Kind)
+	}
+
+	previous := ""
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var current []*string
+		if err := tx.Table(target.table).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(target.key+" = ?", media.TargetID).Pluck(target.column, &current).Error; err != nil {
+			return err
+		}
+		if len(current) == 0 {
+			return fmt.Errorf("%s target not found", media.Kind)
+		}
+		if current[0] != nil {
+			previous = *current[0]
+		}
+
+		if err := tx.Create(media).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(target.table).Where(target.key+" = ?", media.TargetID).
+			Update(target.column, media.ID).Error; err != nil {
+			return err
+		}
+		if previous != "" {
+			return tx.Model(&entities.Media{}).Where("id = ?", previous).Update("deleted_at", time.Now()).Error
+		}
+		return nil
+	})
+	return previous, err
+}
+
+// DetachMedia clears the media from its target and marks it deleted.
+func (repo *MediaRepository) DetachMedia(media *entities.Media) error {
+	if !media.Kind.Attached() {
+		return repo.db.Model(&entities.Media{}).Where("id = ?", media.ID).Update("deleted_at", time.Now()).Error
+	}
+	target, ok := mediaTargets[media.Kind]
+	if !ok {
+		return fmt.Errorf("unknown media kind %q", media.Kind)
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(target.table).Where(target.column+" = ?", media.ID).
+			Update(target.column, "").Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.Media{}).Where("id = ?", media.ID).Update("deleted_at", time.Now()).Error
+	})
+}
+
+func (repo *MediaRepository) GetMedia(mediaID string) (*entities.Media, error) {
+	var media entities.Media
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", mediaID).First(&media).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("media not found")
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (repo *MediaRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ? AND deleted_at IS NULL", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+func (repo *MediaRepository) GetSlotProviderID(slotID string) (string, error) {
+	var providerIDs []string
+	err := repo.db.Table("slots").
+		Joins("JOIN markets ON markets.id = slots.market_id").
+		Where("slots.id = ? AND slots.deleted_at IS NULL AND markets.deleted_at IS NULL", slotID).
+		Pluck("markets.provider_id", &providerIDs).Error
+	if err != nil {
+		return "", err
+	}
+	if len(providerIDs) == 0 {
+		return "", fmt.Errorf("slot not found")
+	}
+	return providerIDs[0], nil
+}
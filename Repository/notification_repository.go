@@ -0,0 +1,52 @@
+package Repository
+
+import (
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (repo *NotificationRepository) CreateNotification(notification *entities.Notification) error {
+	return repo.db.Create(notification).Error
+}
+
+func (repo *NotificationRepository) GetNotifications(userID string, unreadOnly bool) ([]entities.Notification, error) {
+	var notifications []entities.Notification
+	query := repo.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Order("created_at DESC").Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkRead only touches the user's own notifications, so another user's ID reads as not found.
+func (repo *NotificationRepository) MarkRead(userID, notificationID string) error {
+	result := repo.db.Model(&entities.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := repo.db.Model(&entities.Notification{}).Where("id = ? AND user_id = ?", notificationID, userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("notification not found")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type OpeningHoursRepository struct {
+	db *gorm.DB
+}
+
+func NewOpeningHoursRepository(db *gorm.DB) *OpeningHoursRepository {
+	return &OpeningHoursRepository{db: db}
+}
+
+func (repo *OpeningHoursRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+func (repo *OpeningHoursRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+func (repo *OpeningHoursRepository) GetSessions(marketID string) ([]entities.OpeningSession, error) {
+	var sessions []entities.OpeningSession
+	err := repo.db.Where("market_id = ?", marketID).Order("weekday ASC, opens ASC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// SaveWeeklyHours replaces the market's weekly sessions and sets its timezone together.
+func (repo *OpeningHoursRepository) SaveWeeklyHours(marketID, timezone string, sessions []entities.OpeningSession) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Market{}).Where("id = ?", marketID).Update("timezone", timezone).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("market_id = ?", marketID).Delete(&entities.OpeningSession{}).Error; err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			return nil
+		}
+		return tx.Create(&sessions).Error
+	})
+}
+
+// GetExceptions returns the market's exceptions between two dates, both included. An empty to has no end.
+func (repo *OpeningHoursRepository) GetExceptions(marketID, from, to string) ([]entities.OpeningException, error) {
+	var exceptions []entities.OpeningException
+	query := repo.db.Where("market_id = ? AND date >= ?", marketID, from)
+	if to != "" {
+		query = query.Where("date <= ?", to)
+	}
+	if err := query.Order("date ASC").Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+// SaveException stores an exception in place of any the market already has on that date.
+func (repo *OpeningHoursRepository) SaveException(exception *entities.OpeningException) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("market_id = ? AND date = ?", exception.MarketID, exception.Date).Delete(&entities.OpeningException{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(exception).Error
+	})
+}
+
+func (repo *OpeningHoursRepository) DeleteException(marketID, date string) error {
+	result := repo.db.Where("market_id = ? AND date = ?", marketID, date).Delete(&entities.OpeningException{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("opening exception not found")
+	}
+	return nil
+}
+
+// GetSchedule returns the market's slot schedule with its exceptions.
+func (repo *OpeningHoursRepository) GetSchedule(marketID string) (*entities.MarketSchedule, error) {
+	var schedule entities.MarketSchedule
+	if err := repo.db.Preload("Exceptions").Where("market_id = ?", marketID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("schedule not found")
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (repo *OpeningHoursRepository) GetHolidays(from, to string) ([]entities.Holiday, error) {
+	var holidays []entities.Holiday
+	if err := repo.db.Where("date >= ? AND date <= ?", from, to).Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
@@ -68,6 +68,7 @@ func (r *PaymentRepository) GetPayment(paymentID string) (*entitiesDtos.BookingR
 		Status:        entities.BookingStatus(payment.Status),
 		Method:        payment.Method,
 		Image:         payment.Transactions[0].Image,
+		ImageURL:      payment.Transactions[0].ImageURL,
 		ExpiresAt:     payment.ExpiresAt,
 	}
 
@@ -0,0 +1,153 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type PricingRepository struct {
+	db *gorm.DB
+}
+
+func NewPricingRepository(db *gorm.DB) *PricingRepository {
+	return &PricingRepository{db: db}
+}
+
+func (repo *PricingRepository) CreateRule(rule *entities.PricingRule) error {
+	return repo.db.Create(rule).Error
+}
+
+// SaveRule writes every column so zero values such as an inactive flag are kept.
+func (repo *PricingRepository) SaveRule(rule *entities.PricingRule) error {
+	return repo.db.Save(rule).Error
+}
+
+func (repo *PricingRepository) GetRule(ruleID string) (*entities.PricingRule, error) {
+	var rule entities.PricingRule
+	if err := repo.db.Where("id = ?", ruleID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("pricing rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (repo *PricingRepository) DeleteRule(ruleID string) error {
+	return repo.db.Where("id = ?", ruleID).Delete(&entities.PricingRule{}).Error
+}
+
+func (repo *PricingRepository) GetRules(marketID string) ([]entities.PricingRule, error) {
+	var rules []entities.PricingRule
+	if err := repo.db.Where("market_id = ?", marketID).Order("kind ASC, priority ASC, created_at ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (repo *PricingRepository) GetActiveRules(marketID string) ([]entities.PricingRule, error) {
+	var rules []entities.PricingRule
+	err := repo.db.Where("market_id = ? AND active = ?", marketID, true).
+		Order("priority ASC, created_at ASC").Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (repo *PricingRepository) CreateCombo(combo *entities.ComboPrice) error {
+	return repo.db.Create(combo).Error
+}
+
+func (repo *PricingRepository) GetCombo(comboID string) (*entities.ComboPrice, error) {
+	var combo entities.ComboPrice
+	if err := repo.db.Where("id = ?", comboID).First(&combo).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("combo price not found")
+		}
+		return nil, err
+	}
+	return &combo, nil
+}
+
+func (repo *PricingRepository) GetCombos(marketID string) ([]entities.ComboPrice, error) {
+	var combos []entities.ComboPrice
+	if err := repo.db.Where("market_id = ?", marketID).Order("stalls ASC, created_at ASC").Find(&combos).Error; err != nil {
+		return nil, err
+	}
+	return combos, nil
+}
+
+func (repo *PricingRepository) DeleteCombo(comboID string) error {
+	return repo.db.Where("id = ?", comboID).Delete(&entities.ComboPrice{}).Error
+}
+
+func (repo *PricingRepository) GetSlot(slotID string) (*entities.Slot, error) {
+	var slot entities.Slot
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", slotID).First(&slot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("slot not found")
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (repo *PricingRepository) GetSlots(slotIDs []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	if len(slotIDs) == 0 {
+		return slots, nil
+	}
+	if err := repo.db.Where("id IN ? AND deleted_at IS NULL", slotIDs).Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// GetHoliday returns nil without an error when the date is not a holiday.
+func (repo *PricingRepository) GetHoliday(date string) (*entities.Holiday, error) {
+	var holidays []entities.Holiday
+	if err := repo.db.Where("date = ?", date).Limit(1).Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	if len(holidays) == 0 {
+		return nil, nil
+	}
+	return &holidays[0], nil
+}
+
+// GetOccupancy counts the day's bookable slots and how many of them are booked or held by a pending booking.
+func (repo *PricingRepository) GetOccupancy(marketID, date string) (int64, int64, error) {
+	var total int64
+	err := repo.db.Model(&entities.Slot{}).
+		Where("market_id = ? AND date = ? AND deleted_at IS NULL AND status <> ?", marketID, date, entities.StatusMaintenance).
+		Count(&total).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var taken int64
+	err = repo.db.Model(&entities.Slot{}).
+		Where("market_id = ? AND date = ? AND deleted_at IS NULL AND status <> ?", marketID, date, entities.StatusMaintenance).
+		Where("status = ? OR EXISTS (SELECT 1 FROM bookings WHERE bookings.slot_id = slots.id AND bookings.status IN ?)",
+			entities.StatusBooked, []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Count(&taken).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return taken, total, nil
+}
+
+func (repo *PricingRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
@@ -0,0 +1,173 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	entities "tln-backend/Entities"
+)
+
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+func (repo *PromotionRepository) CreatePromotion(promotion *entities.Promotion) error {
+	return repo.db.Create(promotion).Error
+}
+
+func (repo *PromotionRepository) UpdatePromotionStatus(promotionID string, status entities.PromoStatus) error {
+	return repo.db.Model(&entities.Promotion{}).Where("id = ?", promotionID).Update("status", status).Error
+}
+
+func (repo *PromotionRepository) GetPromotion(promotionID string) (*entities.Promotion, error) {
+	var promotion entities.Promotion
+	if err := repo.db.Where("id = ?", promotionID).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (repo *PromotionRepository) GetPromotionByCode(providerID, code string) (*entities.Promotion, error) {
+	var promotion entities.Promotion
+	if err := repo.db.Where("provider_id = ? AND code = ?", providerID, code).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (repo *PromotionRepository) GetPromotionsByProvider(providerID string) ([]entities.Promotion, error) {
+	var promotions []entities.Promotion
+	if err := repo.db.Where("provider_id = ?", providerID).Order("created_at DESC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// ReserveRedemption re-checks the usage limits under a row lock so two vendors cannot take the last use of a code.
+func (repo *PromotionRepository) ReserveRedemption(redemption *entities.Redemption) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var promotion entities.Promotion
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", redemption.PromotionID).
+			First(&promotion).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("promotion not found")
+			}
+			return err
+		}
+
+		if promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit {
+			return fmt.Errorf("promotion has been fully redeemed")
+		}
+
+		if promotion.PerVendorLimit > 0 {
+			var used int64
+			err := tx.Model(&entities.Redemption{}).
+				Where("promotion_id = ? AND vendor_id = ? AND status = ?", promotion.ID, redemption.VendorID, entities.RedemptionApplied).
+				Count(&used).Error
+			if err != nil {
+				return err
+			}
+			if int(used) >= promotion.PerVendorLimit {
+				return fmt.Errorf("you have already used this promotion the maximum number of times")
+			}
+		}
+
+		if err := tx.Create(redemption).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.Promotion{}).Where("id = ?", promotion.ID).
+			Update("used_count", gorm.Expr("used_count + 1")).Error
+	})
+}
+
+// ReleaseRedemption hands a booking's code use back. It is safe to call for bookings without a promotion.
+func (repo *PromotionRepository) ReleaseRedemption(bookingID string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var redemption entities.Redemption
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("booking_id = ? AND status = ?", bookingID, entities.RedemptionApplied).
+			First(&redemption).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&entities.Redemption{}).Where("id = ?", redemption.ID).
+			Update("status", entities.RedemptionReleased).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.Promotion{}).Where("id = ? AND used_count > 0", redemption.PromotionID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error
+	})
+}
+
+func (repo *PromotionRepository) GetRedemptionsByPromotion(promotionID string) ([]entities.Redemption, error) {
+	var redemptions []entities.Redemption
+	if err := repo.db.Where("promotion_id = ?", promotionID).Order("created_at DESC").Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+	return redemptions, nil
+}
+
+// HasCompletedBooking reports whether the vendor has ever had a paid booking in one of the provider's markets.
+func (repo *PromotionRepository) HasCompletedBooking(vendorID, providerID string) (bool, error) {
+	var count int64
+	err := repo.db.Model(&entities.Booking{}).
+		Joins("JOIN markets ON markets.id = bookings.market_id").
+		Where("bookings.vendor_id = ? AND markets.provider_id = ? AND bookings.status = ?", vendorID, providerID, entities.StatusCompleted).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repo *PromotionRepository) GetSlot(slotID string) (*entities.Slot, error) {
+	var slot entities.Slot
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", slotID).First(&slot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("slot not found")
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (repo *PromotionRepository) GetZone(zoneID string) (*entities.Zone, error) {
+	var zone entities.Zone
+	if err := repo.db.Where("id = ?", zoneID).First(&zone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("zone not found")
+		}
+		return nil, err
+	}
+	return &zone, nil
+}
+
+func (repo *PromotionRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
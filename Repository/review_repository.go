@@ -0,0 +1,168 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func (repo *ReviewRepository) GetBooking(bookingID string) (*entities.Booking, error) {
+	var booking entities.Booking
+	if err := repo.db.Where("id = ?", bookingID).First(&booking).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("booking not found")
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+func (repo *ReviewRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+func (repo *ReviewRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+func (repo *ReviewRepository) CreateReview(review *entities.Review) error {
+	return repo.db.Create(review).Error
+}
+
+func (repo *ReviewRepository) UpdateReview(review *entities.Review) error {
+	return repo.db.Save(review).Error
+}
+
+func (repo *ReviewRepository) GetReview(reviewID string) (*entities.Review, error) {
+	var review entities.Review
+	if err := repo.db.Where("id = ?", reviewID).First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("review not found")
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (repo *ReviewRepository) ReviewExists(kind entities.ReviewKind, marketID, vendorID string, marketDate time.Time) (bool, error) {
+	var count int64
+	if err := repo.db.Model(&entities.Review{}).
+		Where("kind = ? AND market_id = ? AND vendor_id = ? AND market_date = ?", kind, marketID, vendorID, marketDate.Format("2006-01-02")).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetReviews lists the visible reviews of one kind, newest first, of a market when marketID is set and of a
+// vendor when vendorID is set.
+func (repo *ReviewRepository) GetReviews(kind entities.ReviewKind, marketID, vendorID string) ([]entities.Review, error) {
+	var reviews []entities.Review
+	query := repo.reviewsOf(kind, marketID, vendorID)
+	if err := query.Order("created_at DESC").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// GetRatingSummary averages the same reviews GetReviews lists. Scores left at 0 are not counted.
+func (repo *ReviewRepository) GetRatingSummary(kind entities.ReviewKind, marketID, vendorID string) (*entities.RatingSummary, error) {
+	var summary entities.RatingSummary
+	if err := repo.reviewsOf(kind, marketID, vendorID).
+		Select("COUNT(*) AS count, COALESCE(AVG(rating), 0) AS average, " +
+			"COALESCE(AVG(NULLIF(foot_traffic, 0)), 0) AS foot_traffic, " +
+			"COALESCE(AVG(NULLIF(punctuality, 0)), 0) AS punctuality, " +
+			"COALESCE(AVG(NULLIF(cleanliness, 0)), 0) AS cleanliness").
+		Scan(&summary).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (repo *ReviewRepository) reviewsOf(kind entities.ReviewKind, marketID, vendorID string) *gorm.DB {
+	query := repo.db.Model(&entities.Review{}).Where("kind = ? AND status = ?", kind, entities.ReviewVisible)
+	if marketID != "" {
+		query = query.Where("market_id = ?", marketID)
+	}
+	if vendorID != "" {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+	return query
+}
+
+func (repo *ReviewRepository) CreateReport(report *entities.ReviewReport) error {
+	return repo.db.Omit("Review").Create(report).Error
+}
+
+func (repo *ReviewRepository) ReportExists(reviewID, reporterID string) (bool, error) {
+	var count int64
+	if err := repo.db.Model(&entities.ReviewReport{}).Where("review_id = ? AND reporter_id = ?", reviewID, reporterID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repo *ReviewRepository) GetReport(reportID string) (*entities.ReviewReport, error) {
+	var report entities.ReviewReport
+	if err := repo.db.Preload("Review").Where("id = ?", reportID).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("review report not found")
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetReports lists reports with the reviews they are about, oldest first, in one status when status is set.
+func (repo *ReviewRepository) GetReports(status entities.ReportStatus) ([]entities.ReviewReport, error) {
+	var reports []entities.ReviewReport
+	query := repo.db.Preload("Review")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at ASC").Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ModerateReview saves an admin's decision on a review, and closes every open report of it with the given
+// outcome when one is set.
+func (repo *ReviewRepository) ModerateReview(review *entities.Review, reports entities.ReportStatus, resolvedBy string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(review).Select("Status", "ModerationNote").Updates(review).Error; err != nil {
+			return err
+		}
+		if reports == "" {
+			return nil
+		}
+		return tx.Model(&entities.ReviewReport{}).
+			Where("review_id = ? AND status = ?", review.ID, entities.ReportOpen).
+			Updates(map[string]interface{}{"status": reports, "resolved_by": resolvedBy, "resolved_at": time.Now()}).Error
+	})
+}
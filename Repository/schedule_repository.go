@@ -0,0 +1,120 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+func (repo *ScheduleRepository) SaveSchedule(schedule *entities.MarketSchedule) error {
+	return repo.db.Omit("Exceptions").Save(schedule).Error
+}
+
+func (repo *ScheduleRepository) GetScheduleByMarket(marketID string) (*entities.MarketSchedule, error) {
+	var schedule entities.MarketSchedule
+	err := repo.db.Preload("Exceptions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date ASC")
+	}).Where("market_id = ?", marketID).First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("schedule not found")
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetActiveSchedules returns the schedules to generate slots for, leaving out those of archived markets.
+func (repo *ScheduleRepository) GetActiveSchedules() ([]entities.MarketSchedule, error) {
+	var schedules []entities.MarketSchedule
+	err := repo.db.Preload("Exceptions").
+		Where("active = ? AND NOT EXISTS (SELECT 1 FROM markets m WHERE m.id = market_schedules.market_id AND m.status = ?)",
+			true, entities.MarketArchived).
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (repo *ScheduleRepository) GetScheduleMarketID(scheduleID string) (string, error) {
+	var schedule entities.MarketSchedule
+	if err := repo.db.Select("id", "market_id").Where("id = ?", scheduleID).First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("schedule not found")
+		}
+		return "", err
+	}
+	return schedule.MarketID, nil
+}
+
+func (repo *ScheduleRepository) MarkGenerated(scheduleID string, at time.Time) error {
+	return repo.db.Model(&entities.MarketSchedule{}).Where("id = ?", scheduleID).Update("last_generated_at", at).Error
+}
+
+func (repo *ScheduleRepository) SaveException(exception *entities.ScheduleException) error {
+	return repo.db.Save(exception).Error
+}
+
+func (repo *ScheduleRepository) GetException(exceptionID string) (*entities.ScheduleException, error) {
+	var exception entities.ScheduleException
+	if err := repo.db.Where("id = ?", exceptionID).First(&exception).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("exception not found")
+		}
+		return nil, err
+	}
+	return &exception, nil
+}
+
+func (repo *ScheduleRepository) DeleteException(exceptionID string) error {
+	return repo.db.Where("id = ?", exceptionID).Delete(&entities.ScheduleException{}).Error
+}
+
+func (repo *ScheduleRepository) SaveHoliday(holiday *entities.Holiday) error {
+	return repo.db.Save(holiday).Error
+}
+
+func (repo *ScheduleRepository) DeleteHoliday(date string) error {
+	return repo.db.Where("date = ?", date).Delete(&entities.Holiday{}).Error
+}
+
+func (repo *ScheduleRepository) GetHolidays(from, to string) ([]entities.Holiday, error) {
+	var holidays []entities.Holiday
+	if err := repo.db.Where("date >= ? AND date <= ?", from, to).Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (repo *ScheduleRepository) GetTemplateMarketID(templateID string) (string, error) {
+	var template entities.LayoutTemplate
+	if err := repo.db.Select("id", "market_id").Where("id = ?", templateID).First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("template not found")
+		}
+		return "", err
+	}
+	return template.MarketID, nil
+}
+
+func (repo *ScheduleRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
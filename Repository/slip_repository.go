@@ -0,0 +1,102 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type SlipRepository struct {
+	db *gorm.DB
+}
+
+func NewSlipRepository(db *gorm.DB) *SlipRepository {
+	return &SlipRepository{db: db}
+}
+
+func (repo *SlipRepository) CreateSlip(slip *entities.BankSlip) error {
+	return repo.db.Create(slip).Error
+}
+
+func (repo *SlipRepository) GetSlip(slipID string) (*entities.BankSlip, error) {
+	var slip entities.BankSlip
+	if err := repo.db.Preload("Booking").Where("id = ?", slipID).First(&slip).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("slip not found")
+		}
+		return nil, err
+	}
+	return &slip, nil
+}
+
+// GetSlipByTransRef returns nil when the reference has never been submitted.
+func (repo *SlipRepository) GetSlipByTransRef(transRef string) (*entities.BankSlip, error) {
+	var slip entities.BankSlip
+	if err := repo.db.Where("trans_ref = ?", transRef).First(&slip).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &slip, nil
+}
+
+func (repo *SlipRepository) GetSlipsByBooking(bookingID string) ([]entities.BankSlip, error) {
+	var slips []entities.BankSlip
+	if err := repo.db.Where("booking_id = ?", bookingID).Order("created_at DESC").Find(&slips).Error; err != nil {
+		return nil, err
+	}
+	return slips, nil
+}
+
+func (repo *SlipRepository) GetSlipsByProvider(providerID, marketID string, status entities.SlipStatus) ([]entities.BankSlip, error) {
+	var slips []entities.BankSlip
+	query := repo.db.Preload("Booking").
+		Joins("JOIN bookings ON bookings.id = bank_slips.booking_id").
+		Joins("JOIN markets ON markets.id = bookings.market_id").
+		Where("markets.provider_id = ? AND bank_slips.status = ?", providerID, status)
+	if marketID != "" {
+		query = query.Where("bookings.market_id = ?", marketID)
+	}
+	err := query.Order("bank_slips.created_at ASC").Find(&slips).Error
+	if err != nil {
+		return nil, err
+	}
+	return slips, nil
+}
+
+func (repo *SlipRepository) UpdateSlipReview(slipID string, status entities.SlipStatus, reviewerID, note string, reviewedAt time.Time) error {
+	return repo.db.Model(&entities.BankSlip{}).Where("id = ?", slipID).Updates(map[string]interface{}{
+		"status":      status,
+		"reviewed_by": reviewerID,
+		"review_note": note,
+		"reviewed_at": reviewedAt,
+	}).Error
+}
+
+func (repo *SlipRepository) GetPendingTransactionID(paymentID string) (string, error) {
+	var transaction entities.Transaction
+	err := repo.db.Where("payment_id = ? AND status = ?", paymentID, entities.TransactionPending).
+		Order("created_at DESC").
+		First(&transaction).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("no pending transaction for payment")
+		}
+		return "", err
+	}
+	return transaction.ID, nil
+}
+
+func (repo *SlipRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
 )
 
 type SlotRepository struct {
@@ -63,9 +66,10 @@ func (repo *SlotRepository) CheckSlotName(name string) bool {
 	return false
 }
 
+// GetProviderSlots leaves out deleted slots, as every slot read here does; only layout versions see them.
 func (repo *SlotRepository) GetProviderSlots(marketID string) ([]*entities.Slot, error) {
 	var slots []*entities.Slot
-	err := repo.db.Where("market_id = ?", marketID).Find(&slots).Error
+	err := repo.db.Where("market_id = ? AND deleted_at IS NULL", marketID).Find(&slots).Error
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +80,7 @@ func (repo *SlotRepository) GetProviderSlots(marketID string) ([]*entities.Slot,
 func (repo *SlotRepository) GetSlots(slotID string) (*entities.Slot, error) {
 	var slot entities.Slot
 
-	result := repo.db.Where("ID = ?", slotID).First(&slot)
+	result := repo.db.Where("ID = ? AND deleted_at IS NULL", slotID).First(&slot)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("slot not found")
@@ -87,15 +91,6 @@ func (repo *SlotRepository) GetSlots(slotID string) (*entities.Slot, error) {
 	return &slot, nil
 }
 
-func (repo *SlotRepository) DeleteSlot(slotID string) error {
-	var slot entities.Slot
-	result := repo.db.Where("ID = ?", slotID).Delete(&slot)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
-}
-
 // CheckMarketExists
 func (repo *SlotRepository) CheckMarketExists(marketID string) (bool, error) {
 	var market entities.Market
@@ -108,7 +103,7 @@ func (repo *SlotRepository) CheckMarketExists(marketID string) (bool, error) {
 
 func (repo *SlotRepository) GetSlotsByMarketID(marketID string) ([]*entities.Slot, error) {
 	var slots []*entities.Slot
-	err := repo.db.Where("market_id = ?", marketID).Find(&slots).Error
+	err := repo.db.Where("market_id = ? AND deleted_at IS NULL", marketID).Find(&slots).Error
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +125,7 @@ func (repo *SlotRepository) UpdateSlots(slots []*entities.Slot) error {
 func (repo *SlotRepository) GetSlotsByDate(marketID, date string) ([]*entities.Slot, error) {
 	var slots []*entities.Slot
 
-	err := repo.db.Where("market_id = ? AND date = ?", marketID, date).Find(&slots).Error
+	err := repo.db.Where("market_id = ? AND date = ? AND deleted_at IS NULL", marketID, date).Find(&slots).Error
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +133,95 @@ func (repo *SlotRepository) GetSlotsByDate(marketID, date string) ([]*entities.S
 	return slots, nil
 }
 
-func (repo *SlotRepository) DeleteSlotByDateAndZone(markeID, zoneID, date string) error {
-	var slot entities.Slot
-	result := repo.db.Where("market_id = ? AND zone = ? AND date = ?", markeID, zoneID, date).Delete(&slot)
-	if result.Error != nil {
-		return result.Error
+// GetSlotsByDateAndZone matches the zone by ID, or by name for slots laid out before the market had zones.
+func (repo *SlotRepository) GetSlotsByDateAndZone(marketID, zoneID, date string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	err := repo.db.Where("market_id = ? AND date = ? AND deleted_at IS NULL", marketID, date).
+		Where("zone_id = ? OR ((zone_id IS NULL OR zone_id = '') AND zone = ?)", zoneID, zoneID).
+		Find(&slots).Error
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return slots, nil
+}
+
+func (repo *SlotRepository) GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	if len(slotIDs) == 0 {
+		return bookings, nil
+	}
+	err := repo.db.Where("slot_id IN ? AND status IN ?", slotIDs,
+		[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).Find(&bookings).Error
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (repo *SlotRepository) GetGroupLeadBookingID(groupID string) (string, error) {
+	var group entities.BookingGroup
+	if err := repo.db.Select("lead_booking_id").Where("id = ?", groupID).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("booking group not found")
+		}
+		return "", err
+	}
+	return group.LeadBookingID, nil
+}
+
+// DeleteSlots soft-deletes slots so their bookings and payments are kept. Each moved booking (booking ID to
+// replacement slot ID) takes its old slot's status and booker with it; the replacement is locked and checked
+// to still be free first.
+func (repo *SlotRepository) DeleteSlots(slotIDs []string, moves map[string]string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for bookingID, slotID := range moves {
+			var booking entities.Booking
+			if err := tx.Where("id = ?", bookingID).First(&booking).Error; err != nil {
+				return err
+			}
+			var from, to entities.Slot
+			if err := tx.Where("id = ?", booking.SlotID).First(&from).Error; err != nil {
+				return err
+			}
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND deleted_at IS NULL", slotID).First(&to).Error; err != nil {
+				return err
+			}
+
+			var taken int64
+			err := tx.Model(&entities.Booking{}).Where("slot_id = ? AND status IN ?", slotID,
+				[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).Count(&taken).Error
+			if err != nil {
+				return err
+			}
+			if taken > 0 {
+				return fmt.Errorf("slot %s already has a pending or confirmed booking", slotID)
+			}
+
+			if err := tx.Model(&booking).Update("slot_id", slotID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&to).Select("status", "booker").Updates(entities.Slot{Status: from.Status, Booker: from.Booker}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entities.Slot{}).Where("id IN ?", slotIDs).Updates(map[string]interface{}{
+			"status":     entities.StatusMaintenance,
+			"booker":     "",
+			"deleted_at": time.Now(),
+		}).Error
+	})
+}
+
+func (repo *SlotRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
 }
 
 func (repo *SlotRepository) UpdateSlotStatus(slotID, vendorID string, status entities.SlotStatus) error {
@@ -163,8 +240,9 @@ func (repo *SlotRepository) UpdateSlotStatus(slotID, vendorID string, status ent
 		updates.Booker = ""
 	}
 
+	// A deleted slot stays retired even when a booking it held is cancelled
 	var slot entities.Slot
-	result := repo.db.Model(&slot).Where("ID = ?", slotID).Updates(updates)
+	result := repo.db.Model(&slot).Where("ID = ? AND deleted_at IS NULL", slotID).Updates(updates)
 
 	if result.Error != nil {
 		return result.Error
@@ -172,3 +250,69 @@ func (repo *SlotRepository) UpdateSlotStatus(slotID, vendorID string, status ent
 
 	return nil
 }
+
+// SelectSlots finds a market's live slots matching a bulk edit selector, in date, zone and name order.
+func (repo *SlotRepository) SelectSlots(marketID string, selector *entitiesDtos.SlotSelector) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+
+	query := repo.db.Where("market_id = ? AND deleted_at IS NULL AND date BETWEEN ? AND ?", marketID, selector.DateFrom, selector.DateTo)
+	if selector.ZoneID != "" {
+		query = query.Where("zone_id = ? OR ((zone_id IS NULL OR zone_id = '') AND zone = ?)", selector.ZoneID, selector.ZoneID)
+	}
+	if selector.Category != "" {
+		query = query.Where("category = ?", selector.Category)
+	}
+	if selector.Status != "" {
+		query = query.Where("status = ?", selector.Status)
+	}
+	if len(selector.SlotIDs) > 0 {
+		query = query.Where("id IN ?", selector.SlotIDs)
+	}
+
+	if err := query.Order("date ASC, zone ASC, name ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// BulkUpdateSlots writes the price, category and status of every slot or none of them. The slots are locked and
+// checked to still have no active booking, since one may have been made after the edit was previewed.
+func (repo *SlotRepository) BulkUpdateSlots(slots []*entities.Slot) error {
+	slotIDs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		slotIDs = append(slotIDs, slot.ID)
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var locked []entities.Slot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", slotIDs).Find(&locked).Error; err != nil {
+			return err
+		}
+
+		var taken []string
+		err := tx.Model(&entities.Booking{}).
+			Where("slot_id IN ? AND status IN ?", slotIDs, []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+			Pluck("slot_id", &taken).Error
+		if err != nil {
+			return err
+		}
+		if len(taken) > 0 {
+			return fmt.Errorf("slot %s was booked after the edit was prepared", taken[0])
+		}
+
+		for _, slot := range slots {
+			if err := tx.Model(slot).Select("price", "category", "status").Updates(slot).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *SlotRepository) GetZones(marketID string) ([]entities.Zone, error) {
+	var zones []entities.Zone
+	if err := repo.db.Where("market_id = ?", marketID).Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
@@ -0,0 +1,108 @@
+package Repository
+
+import (
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+type SlotSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSlotSearchRepository(db *gorm.DB) *SlotSearchRepository {
+	return &SlotSearchRepository{db: db}
+}
+
+// SearchMarkets returns one page of the markets with a free slot matching the filter, nearest first for a
+// search around a point and by name otherwise.
+func (repo *SlotSearchRepository) SearchMarkets(filter *entitiesDtos.SlotSearchFilter) ([]entitiesDtos.MarketDistance, error) {
+	query := repo.availableSlots(filter).Group("m.id")
+	if filter.Area != nil {
+		query = query.Select("m.id, "+distanceKmSQL+" AS distance_km", filter.Area.Lat, filter.Area.Lat, filter.Area.Lng).
+			Order("distance_km ASC, m.name ASC, m.id ASC")
+	} else {
+		query = query.Select("m.id, 0 AS distance_km").Order("m.name ASC, m.id ASC")
+	}
+
+	var markets []entitiesDtos.MarketDistance
+	err := query.Limit(filter.PageSize).Offset((filter.Page - 1) * filter.PageSize).Scan(&markets).Error
+	if err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
+
+// CountAvailableSlots counts the free slots matching the filter and the markets they are in, across every page.
+func (repo *SlotSearchRepository) CountAvailableSlots(filter *entitiesDtos.SlotSearchFilter) (int64, int64, error) {
+	var counts struct {
+		Markets int64
+		Slots   int64
+	}
+	err := repo.availableSlots(filter).
+		Select("COUNT(DISTINCT slots.market_id) AS markets, COUNT(*) AS slots").
+		Scan(&counts).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return counts.Markets, counts.Slots, nil
+}
+
+// SearchAvailableSlots loads the free slots matching the filter in the given markets, by date.
+func (repo *SlotSearchRepository) SearchAvailableSlots(filter *entitiesDtos.SlotSearchFilter, marketIDs []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	err := repo.availableSlots(filter).
+		Where("slots.market_id IN ?", marketIDs).
+		Select("slots.*").
+		Order("slots.date ASC, slots.name ASC").
+		Find(&slots).Error
+	if err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// availableSlots selects the live, available slots of published markets that nobody holds a pending or
+// confirmed booking for. Every filter applies except the price range, which needs the quoted price.
+func (repo *SlotSearchRepository) availableSlots(filter *entitiesDtos.SlotSearchFilter) *gorm.DB {
+	query := repo.db.Model(&entities.Slot{}).
+		Joins("JOIN markets m ON m.id = slots.market_id AND m.deleted_at IS NULL AND m.status = ?", entities.MarketPublished).
+		Where("slots.deleted_at IS NULL AND slots.status = ?", entities.StatusAvailable).
+		Where("slots.date BETWEEN ? AND ?", filter.From, filter.To).
+		Where("NOT EXISTS (SELECT 1 FROM bookings b WHERE b.slot_id = slots.id AND b.status IN ?)",
+			[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted})
+
+	if filter.Category != "" {
+		query = query.Where("slots.category = ?", filter.Category)
+	}
+	if filter.Zone != "" {
+		query = query.Where("LOWER(slots.zone) = LOWER(?)", filter.Zone)
+	}
+	if filter.MinWidth > 0 {
+		query = query.Where("slots.width >= ?", filter.MinWidth)
+	}
+	if filter.MinHeight > 0 {
+		query = query.Where("slots.height >= ?", filter.MinHeight)
+	}
+	for _, amenity := range filter.Amenities {
+		query = query.Where(`EXISTS (SELECT 1 FROM amenities a WHERE a.market_id = slots.market_id AND a.kind = ?
+			AND LOWER(a.name) = LOWER(?) AND slots.amenities LIKE '%"' || a.id || '"%')`, entities.AmenityFeature, amenity)
+	}
+	if filter.Area != nil {
+		area, areaArgs := marketAreaSQL(filter.Area)
+		query = query.Where(area, areaArgs...).
+			Where(distanceKmSQL+" <= ?", filter.Area.Lat, filter.Area.Lat, filter.Area.Lng, filter.Area.RadiusKm)
+	}
+	return query
+}
+
+func (repo *SlotSearchRepository) GetMarketsByIDs(marketIDs []string) ([]entities.Market, error) {
+	var markets []entities.Market
+	if len(marketIDs) == 0 {
+		return markets, nil
+	}
+	if err := repo.db.Where("id IN ?", marketIDs).Find(&markets).Error; err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
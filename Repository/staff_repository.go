@@ -0,0 +1,127 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+// staffResources finds the market a provider route's resource belongs to, so staff permissions can be
+// checked on routes that only name the resource.
+var staffResources = map[string]string{
+	"slots":               "SELECT market_id FROM slots WHERE id = ?",
+	"zones":               "SELECT market_id FROM zones WHERE id = ?",
+	"amenities":           "SELECT market_id FROM amenities WHERE id = ?",
+	"pricing_rules":       "SELECT market_id FROM pricing_rules WHERE id = ?",
+	"combo_prices":        "SELECT market_id FROM combo_prices WHERE id = ?",
+	"layout_templates":    "SELECT market_id FROM layout_templates WHERE id = ?",
+	"bookings":            "SELECT market_id FROM bookings WHERE id = ?",
+	"vendor_applications": "SELECT market_id FROM vendor_applications WHERE id = ?",
+	"reviews":             "SELECT market_id FROM reviews WHERE id = ?",
+	"announcements":       "SELECT market_id FROM announcements WHERE id = ?",
+	"schedule_exceptions": "SELECT s.market_id FROM schedule_exceptions e JOIN market_schedules s ON s.id = e.schedule_id WHERE e.id = ?",
+	"bank_slips":          "SELECT b.market_id FROM bank_slips s JOIN bookings b ON b.id = s.booking_id WHERE s.id = ?",
+}
+
+type StaffRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffRepository(db *gorm.DB) *StaffRepository {
+	return &StaffRepository{db: db}
+}
+
+// CreateStaff saves a staff member along with its assignments.
+func (repo *StaffRepository) CreateStaff(staff *entities.StaffMember) error {
+	return repo.db.Create(staff).Error
+}
+
+// UpdateStaff saves a staff member's own fields; assignments change through ReplaceAssignments.
+func (repo *StaffRepository) UpdateStaff(staff *entities.StaffMember) error {
+	return repo.db.Omit("Assignments").Save(staff).Error
+}
+
+func (repo *StaffRepository) GetStaffByID(staffID string) (*entities.StaffMember, error) {
+	return repo.findStaff("id = ?", staffID)
+}
+
+func (repo *StaffRepository) GetStaffByEmail(email string) (*entities.StaffMember, error) {
+	return repo.findStaff("LOWER(email) = LOWER(?)", email)
+}
+
+func (repo *StaffRepository) GetStaffByUsername(username string) (*entities.StaffMember, error) {
+	return repo.findStaff("username = ?", username)
+}
+
+func (repo *StaffRepository) GetStaffByInviteToken(tokenHash string) (*entities.StaffMember, error) {
+	return repo.findStaff("invite_token_hash = ? AND status = ?", tokenHash, entities.StaffInvited)
+}
+
+func (repo *StaffRepository) findStaff(query string, args ...interface{}) (*entities.StaffMember, error) {
+	var staff entities.StaffMember
+	if err := repo.db.Preload("Assignments").Where(query, args...).First(&staff).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("staff member not found")
+		}
+		return nil, err
+	}
+	return &staff, nil
+}
+
+func (repo *StaffRepository) GetStaffByProvider(providerID string) ([]entities.StaffMember, error) {
+	var staff []entities.StaffMember
+	if err := repo.db.Preload("Assignments").Where("provider_id = ?", providerID).
+		Order("name").Find(&staff).Error; err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// ReplaceAssignments swaps every assignment of a staff member for the given ones.
+func (repo *StaffRepository) ReplaceAssignments(staffID string, assignments []entities.StaffAssignment) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("staff_id = ?", staffID).Delete(&entities.StaffAssignment{}).Error; err != nil {
+			return err
+		}
+		if len(assignments) == 0 {
+			return nil
+		}
+		return tx.Create(&assignments).Error
+	})
+}
+
+func (repo *StaffRepository) GetAssignment(staffID, marketID string) (*entities.StaffAssignment, error) {
+	var assignment entities.StaffAssignment
+	if err := repo.db.Where("staff_id = ? AND market_id = ?", staffID, marketID).First(&assignment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("staff assignment not found")
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (repo *StaffRepository) GetMarketsByIDs(marketIDs []string) ([]entities.Market, error) {
+	var markets []entities.Market
+	if err := repo.db.Where("id IN ? AND deleted_at IS NULL", marketIDs).Find(&markets).Error; err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
+
+// GetResourceMarketID returns the market of a row in one of the staffResources tables.
+func (repo *StaffRepository) GetResourceMarketID(resource, id string) (string, error) {
+	query, ok := staffResources[resource]
+	if !ok {
+		return "", fmt.Errorf("unknown staff resource %q", resource)
+	}
+	var marketIDs []string
+	if err := repo.db.Raw(query, id).Scan(&marketIDs).Error; err != nil {
+		return "", err
+	}
+	if len(marketIDs) == 0 {
+		return "", fmt.Errorf("%s not found", resource)
+	}
+	return marketIDs[0], nil
+}
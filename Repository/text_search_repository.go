@@ -0,0 +1,167 @@
+package Repository
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+type TextSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewTextSearchRepository(db *gorm.DB) *TextSearchRepository {
+	return &TextSearchRepository{db: db}
+}
+
+// SaveDocument replaces the indexed terms of a market or vendor.
+func (repo *TextSearchRepository) SaveDocument(kind entities.SearchKind, refID string, weights map[string]float64) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var document entities.SearchDocument
+		err := tx.Where("kind = ? AND ref_id = ?", kind, refID).First(&document).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			document = entities.SearchDocument{ID: uuid.New().String(), Kind: kind, RefID: refID}
+			if err := tx.Create(&document).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			if err := tx.Where("document_id = ?", document.ID).Delete(&entities.SearchTerm{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&document).Update("updated_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(weights) == 0 {
+			return nil
+		}
+		terms := make([]entities.SearchTerm, 0, len(weights))
+		for term, weight := range weights {
+			terms = append(terms, entities.SearchTerm{DocumentID: document.ID, Term: term, Weight: weight})
+		}
+		return tx.CreateInBatches(&terms, 500).Error
+	})
+}
+
+// MatchDocuments finds live markets and public vendor profiles holding enough of the query terms, best
+// matches first. Markets are filtered on their own slots and location, vendors on the markets they hold
+// pending or confirmed bookings at.
+func (repo *TextSearchRepository) MatchDocuments(filter *entitiesDtos.TextSearchFilter) ([]entitiesDtos.SearchMatch, error) {
+	kinds := make([]string, 0, len(filter.Kinds))
+	args := make([]interface{}, 0)
+	for _, kind := range filter.Kinds {
+		var sql string
+		var kindArgs []interface{}
+		switch kind {
+		case entities.SearchMarket:
+			sql, kindArgs = marketSearchSQL(filter)
+		case entities.SearchVendor:
+			sql, kindArgs = vendorSearchSQL(filter)
+		}
+		kinds = append(kinds, "(d.kind = ? AND "+sql+")")
+		args = append(append(args, kind), kindArgs...)
+	}
+
+	var matches []entitiesDtos.SearchMatch
+	err := repo.db.Table("search_terms t").
+		Select("d.kind, d.ref_id, COUNT(*) AS matched, SUM(t.weight) AS weight").
+		Joins("JOIN search_documents d ON d.id = t.document_id").
+		Where("t.term IN ?", filter.Terms).
+		Where(strings.Join(kinds, " OR "), args...).
+		Group("d.kind, d.ref_id").
+		Having("COUNT(*) >= ?", filter.MinMatched).
+		Order("matched DESC, weight DESC, d.ref_id ASC").
+		Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func marketSearchSQL(filter *entitiesDtos.TextSearchFilter) (string, []interface{}) {
+	sql := "SELECT 1 FROM markets m WHERE m.id = d.ref_id AND m.deleted_at IS NULL AND m.status = ?"
+	args := []interface{}{entities.MarketPublished}
+	if filter.Area != nil {
+		area, areaArgs := marketAreaSQL(filter.Area)
+		sql += " AND " + area + " AND " + distanceKmSQL + " <= ?"
+		args = append(append(args, areaArgs...), filter.Area.Lat, filter.Area.Lat, filter.Area.Lng, filter.Area.RadiusKm)
+	}
+	if filter.Date != "" || filter.Category != "" {
+		slots, slotArgs := marketSlotsSQL(filter.Date, filter.Category, filter.Date != "")
+		sql += " AND " + slots
+		args = append(args, slotArgs...)
+	}
+	return "EXISTS (" + sql + ")", args
+}
+
+func vendorSearchSQL(filter *entitiesDtos.TextSearchFilter) (string, []interface{}) {
+	sql := "SELECT 1 FROM vendor_profiles p JOIN vendors v ON v.id = p.vendor_id WHERE p.vendor_id = d.ref_id AND p.public AND v.deleted_at IS NULL"
+	args := make([]interface{}, 0)
+	if filter.Category != "" {
+		sql += " AND p.category = ?"
+		args = append(args, filter.Category)
+	}
+	if filter.Date != "" || filter.Area != nil {
+		trading := "SELECT 1 FROM bookings b JOIN markets m ON m.id = b.market_id WHERE b.vendor_id = p.vendor_id AND b.status IN ?"
+		tradingArgs := []interface{}{[]entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}}
+		if filter.Date != "" {
+			trading += " AND DATE(b.booking_date) = ?"
+			tradingArgs = append(tradingArgs, filter.Date)
+		} else {
+			trading += " AND DATE(b.booking_date) >= CURRENT_DATE"
+		}
+		if filter.Area != nil {
+			area, areaArgs := marketAreaSQL(filter.Area)
+			trading += " AND " + area + " AND " + distanceKmSQL + " <= ?"
+			tradingArgs = append(append(tradingArgs, areaArgs...), filter.Area.Lat, filter.Area.Lat, filter.Area.Lng, filter.Area.RadiusKm)
+		}
+		sql += " AND EXISTS (" + trading + ")"
+		args = append(args, tradingArgs...)
+	}
+	return "EXISTS (" + sql + ")", args
+}
+
+func (repo *TextSearchRepository) GetMarkets() ([]entities.Market, error) {
+	var markets []entities.Market
+	if err := repo.db.Where("deleted_at IS NULL").Find(&markets).Error; err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
+
+func (repo *TextSearchRepository) GetMarketsByIDs(marketIDs []string) ([]entities.Market, error) {
+	var markets []entities.Market
+	if len(marketIDs) == 0 {
+		return markets, nil
+	}
+	if err := repo.db.Where("id IN ?", marketIDs).Find(&markets).Error; err != nil {
+		return nil, err
+	}
+	return markets, nil
+}
+
+func (repo *TextSearchRepository) GetVendorProfiles() ([]entities.VendorProfile, error) {
+	var profiles []entities.VendorProfile
+	if err := repo.db.Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (repo *TextSearchRepository) GetVendorProfilesByIDs(vendorIDs []string) ([]entities.VendorProfile, error) {
+	var profiles []entities.VendorProfile
+	if len(vendorIDs) == 0 {
+		return profiles, nil
+	}
+	if err := repo.db.Where("vendor_id IN ?", vendorIDs).Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
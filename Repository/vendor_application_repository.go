@@ -0,0 +1,139 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type VendorApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewVendorApplicationRepository(db *gorm.DB) *VendorApplicationRepository {
+	return &VendorApplicationRepository{db: db}
+}
+
+// CreateApplication saves a new application along with the event that started it.
+func (repo *VendorApplicationRepository) CreateApplication(application *entities.VendorApplication, event *entities.VendorApplicationEvent) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Vendor").Create(application).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
+
+// UpdateApplication saves an application that is still in the status it was read in, and records the
+// event. It reports false when someone else changed the application first.
+func (repo *VendorApplicationRepository) UpdateApplication(application *entities.VendorApplication, from entities.ApplicationStatus, event *entities.VendorApplicationEvent) (bool, error) {
+	updated := false
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.VendorApplication{}).Where("id = ? AND status = ?", application.ID, from).
+			Select("Status", "StallName", "Description", "Category", "Products", "PhotoIDs", "DocumentIDs",
+				"ReviewNote", "ReviewedBy", "ReviewedAt", "SubmittedAt").
+			Updates(application)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+		return tx.Create(event).Error
+	})
+	return updated, err
+}
+
+func (repo *VendorApplicationRepository) GetApplication(applicationID string) (*entities.VendorApplication, error) {
+	var application entities.VendorApplication
+	if err := repo.db.Preload("Vendor").Where("id = ?", applicationID).First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (repo *VendorApplicationRepository) GetVendorApplication(marketID, vendorID string) (*entities.VendorApplication, error) {
+	var application entities.VendorApplication
+	if err := repo.db.Where("market_id = ? AND vendor_id = ?", marketID, vendorID).First(&application).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("application not found")
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// GetMarketApplications lists a market's applications, oldest submission first, in one status when status
+// is set.
+func (repo *VendorApplicationRepository) GetMarketApplications(marketID string, status entities.ApplicationStatus) ([]entities.VendorApplication, error) {
+	var applications []entities.VendorApplication
+	query := repo.db.Preload("Vendor").Where("market_id = ?", marketID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("submitted_at ASC NULLS LAST, created_at ASC").Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+func (repo *VendorApplicationRepository) GetVendorApplications(vendorID string) ([]entities.VendorApplication, error) {
+	var applications []entities.VendorApplication
+	if err := repo.db.Where("vendor_id = ?", vendorID).Order("updated_at DESC").Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+func (repo *VendorApplicationRepository) GetApplicationEvents(applicationID string) ([]entities.VendorApplicationEvent, error) {
+	var events []entities.VendorApplicationEvent
+	if err := repo.db.Where("application_id = ?", applicationID).Order("created_at ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (repo *VendorApplicationRepository) GetMarket(marketID string) (*entities.Market, error) {
+	var market entities.Market
+	if err := repo.db.Where("id = ? AND deleted_at IS NULL", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("market not found")
+		}
+		return nil, err
+	}
+	return &market, nil
+}
+
+func (repo *VendorApplicationRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+func (repo *VendorApplicationRepository) VendorExists(vendorID string) (bool, error) {
+	var count int64
+	if err := repo.db.Model(&entities.Vendor{}).Where("id = ? AND deleted_at IS NULL", vendorID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetVendorMedia returns those of the media that the vendor uploaded as the given kind and still has.
+func (repo *VendorApplicationRepository) GetVendorMedia(vendorID string, kind entities.MediaKind, mediaIDs []string) ([]entities.Media, error) {
+	var media []entities.Media
+	if err := repo.db.Where("id IN ? AND owner_id = ? AND kind = ? AND deleted_at IS NULL", mediaIDs, vendorID, kind).
+		Find(&media).Error; err != nil {
+		return nil, err
+	}
+	return media, nil
+}
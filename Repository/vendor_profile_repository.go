@@ -0,0 +1,32 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	entities "tln-backend/Entities"
+)
+
+type VendorProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewVendorProfileRepository(db *gorm.DB) *VendorProfileRepository {
+	return &VendorProfileRepository{db: db}
+}
+
+func (repo *VendorProfileRepository) GetProfile(vendorID string) (*entities.VendorProfile, error) {
+	var profile entities.VendorProfile
+	if err := repo.db.Where("vendor_id = ?", vendorID).First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("vendor profile not found")
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveProfile creates the vendor's profile or replaces it.
+func (repo *VendorProfileRepository) SaveProfile(profile *entities.VendorProfile) error {
+	return repo.db.Save(profile).Error
+}
@@ -0,0 +1,249 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+	entities "tln-backend/Entities"
+)
+
+type WalletRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) *WalletRepository {
+	return &WalletRepository{db: db}
+}
+
+// CreateCredit stores a new credit lot together with its issue history line.
+func (repo *WalletRepository) CreateCredit(credit *entities.WalletCredit) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(credit).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entities.WalletTransaction{
+			ID:        uuid.New().String(),
+			VendorID:  credit.VendorID,
+			CreditID:  credit.ID,
+			IssuerID:  credit.IssuerID,
+			BookingID: credit.BookingID,
+			Type:      entities.WalletIssue,
+			Amount:    credit.Amount,
+			Note:      credit.Note,
+		}).Error
+	})
+}
+
+// RedeemCredit spends up to amount from the vendor's unexpired lots, earliest expiry first, and returns what was spent.
+// Only lots funded by the platform or by providerID, the provider of the market being booked, can be spent.
+func (repo *WalletRepository) RedeemCredit(vendorID, providerID, bookingID string, amount float64, now time.Time) (float64, error) {
+	var redeemed float64
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var credits []entities.WalletCredit
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("vendor_id = ? AND remaining > 0 AND (expires_at IS NULL OR expires_at > ?)", vendorID, now).
+			Where("issuer_type = ? OR (issuer_type = ? AND issuer_id = ?)", entities.AccountPlatform, entities.AccountProvider, providerID).
+			Find(&credits).Error
+		if err != nil {
+			return err
+		}
+
+		for _, take := range entities.PlanRedemption(credits, providerID, amount, now) {
+			if err := tx.Model(&entities.WalletCredit{}).Where("id = ?", take.Credit.ID).
+				Update("remaining", entities.RoundMoney(take.Credit.Remaining-take.Amount)).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&entities.WalletTransaction{
+				ID:        uuid.New().String(),
+				VendorID:  vendorID,
+				CreditID:  take.Credit.ID,
+				IssuerID:  take.Credit.IssuerID,
+				BookingID: bookingID,
+				Type:      entities.WalletRedeem,
+				Amount:    take.Amount,
+			}).Error; err != nil {
+				return err
+			}
+
+			redeemed = entities.RoundMoney(redeemed + take.Amount)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return redeemed, nil
+}
+
+// RestoreCredit puts back whatever a booking spent and has not already been restored. It is safe to call repeatedly.
+func (repo *WalletRepository) RestoreCredit(bookingID string) (float64, error) {
+	var restored float64
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var history []entities.WalletTransaction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("booking_id = ? AND type IN ?", bookingID, []entities.WalletTransactionType{entities.WalletRedeem, entities.WalletRestore}).
+			Order("created_at ASC").
+			Find(&history).Error
+		if err != nil {
+			return err
+		}
+
+		for _, restore := range entities.PendingRestores(history) {
+			if err := tx.Model(&entities.WalletCredit{}).Where("id = ?", restore.Credit.ID).
+				Update("remaining", gorm.Expr("remaining + ?", restore.Amount)).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Create(&entities.WalletTransaction{
+				ID:        uuid.New().String(),
+				VendorID:  restore.Credit.VendorID,
+				CreditID:  restore.Credit.ID,
+				IssuerID:  restore.Credit.IssuerID,
+				BookingID: bookingID,
+				Type:      entities.WalletRestore,
+				Amount:    restore.Amount,
+			}).Error; err != nil {
+				return err
+			}
+
+			restored = entities.RoundMoney(restored + restore.Amount)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return restored, nil
+}
+
+// ExpireCredit zeroes an expired lot. It returns nil when there was nothing left to expire.
+func (repo *WalletRepository) ExpireCredit(creditID string, now time.Time) (*entities.WalletTransaction, error) {
+	var expired *entities.WalletTransaction
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var credit entities.WalletCredit
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND remaining > 0 AND expires_at <= ?", creditID, now).
+			First(&credit).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&entities.WalletCredit{}).Where("id = ?", credit.ID).Update("remaining", 0).Error; err != nil {
+			return err
+		}
+
+		expired = &entities.WalletTransaction{
+			ID:       uuid.New().String(),
+			VendorID: credit.VendorID,
+			CreditID: credit.ID,
+			IssuerID: credit.IssuerID,
+			Type:     entities.WalletExpire,
+			Amount:   credit.Remaining,
+		}
+		return tx.Create(expired).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+func (repo *WalletRepository) GetCredit(creditID string) (*entities.WalletCredit, error) {
+	var credit entities.WalletCredit
+	if err := repo.db.Where("id = ?", creditID).First(&credit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("credit not found")
+		}
+		return nil, err
+	}
+	return &credit, nil
+}
+
+func (repo *WalletRepository) GetActiveCredits(vendorID string, now time.Time) ([]entities.WalletCredit, error) {
+	var credits []entities.WalletCredit
+	err := repo.db.Where("vendor_id = ? AND remaining > 0 AND (expires_at IS NULL OR expires_at > ?)", vendorID, now).
+		Order("expires_at ASC NULLS LAST, created_at ASC").
+		Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+// GetIssuedBalance sums what is left of the goodwill and promotion credit an issuer has given a vendor.
+func (repo *WalletRepository) GetIssuedBalance(issuerID, vendorID string, now time.Time) (float64, error) {
+	var balance float64
+	err := repo.db.Model(&entities.WalletCredit{}).
+		Select("COALESCE(SUM(remaining), 0)").
+		Where("issuer_id = ? AND vendor_id = ? AND source IN ? AND (expires_at IS NULL OR expires_at > ?)", issuerID, vendorID,
+			[]entities.WalletCreditSource{entities.CreditSourceGoodwill, entities.CreditSourcePromotion}, now).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, err
+	}
+	return entities.RoundMoney(balance), nil
+}
+
+func (repo *WalletRepository) GetExpiredCredits(now time.Time) ([]entities.WalletCredit, error) {
+	var credits []entities.WalletCredit
+	if err := repo.db.Where("remaining > 0 AND expires_at <= ?", now).Find(&credits).Error; err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+func (repo *WalletRepository) GetTransactionsByVendor(vendorID string) ([]entities.WalletTransaction, error) {
+	var transactions []entities.WalletTransaction
+	if err := repo.db.Where("vendor_id = ?", vendorID).Order("created_at DESC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (repo *WalletRepository) GetTransactionsByIssuer(issuerID string) ([]entities.WalletTransaction, error) {
+	var transactions []entities.WalletTransaction
+	if err := repo.db.Where("issuer_id = ?", issuerID).Order("created_at DESC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (repo *WalletRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
+
+// HasProviderBooking reports whether the vendor has ever booked a slot in one of the provider's markets.
+func (repo *WalletRepository) HasProviderBooking(providerID, vendorID string) (bool, error) {
+	var count int64
+	err := repo.db.Model(&entities.Booking{}).
+		Joins("JOIN markets ON markets.id = bookings.market_id").
+		Where("markets.provider_id = ? AND bookings.vendor_id = ?", providerID, vendorID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,149 @@
+package Repository
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	entities "tln-backend/Entities"
+)
+
+type ZoneRepository struct {
+	db *gorm.DB
+}
+
+func NewZoneRepository(db *gorm.DB) *ZoneRepository {
+	return &ZoneRepository{db: db}
+}
+
+// CreateZone also claims the market's slots and template stalls that were laid out under the zone's name
+// before zones existed.
+func (repo *ZoneRepository) CreateZone(zone *entities.Zone) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(zone).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&entities.Slot{}).
+			Where("market_id = ? AND zone = ? AND (zone_id IS NULL OR zone_id = '')", zone.MarketID, zone.Name).
+			Update("zone_id", zone.ID).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.TemplateStall{}).
+			Where("zone = ? AND (zone_id IS NULL OR zone_id = '')", zone.Name).
+			Where("template_id IN (?)", tx.Model(&entities.LayoutTemplate{}).Select("id").Where("market_id = ?", zone.MarketID)).
+			Update("zone_id", zone.ID).Error
+	})
+}
+
+// SaveZone writes every column so cleared restrictions are kept.
+func (repo *ZoneRepository) SaveZone(zone *entities.Zone) error {
+	return repo.db.Save(zone).Error
+}
+
+func (repo *ZoneRepository) GetZone(zoneID string) (*entities.Zone, error) {
+	var zone entities.Zone
+	if err := repo.db.Where("id = ?", zoneID).First(&zone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("zone not found")
+		}
+		return nil, err
+	}
+	return &zone, nil
+}
+
+func (repo *ZoneRepository) GetZones(marketID string) ([]entities.Zone, error) {
+	var zones []entities.Zone
+	if err := repo.db.Where("market_id = ?", marketID).Order("name ASC").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (repo *ZoneRepository) DeleteZone(zoneID string) error {
+	return repo.db.Where("id = ?", zoneID).Delete(&entities.Zone{}).Error
+}
+
+// CountZoneSlots counts the live slots and template stalls that still point at the zone.
+func (repo *ZoneRepository) CountZoneSlots(zoneID string) (int64, error) {
+	var slots int64
+	if err := repo.db.Model(&entities.Slot{}).Where("zone_id = ? AND deleted_at IS NULL", zoneID).Count(&slots).Error; err != nil {
+		return 0, err
+	}
+
+	var stalls int64
+	if err := repo.db.Model(&entities.TemplateStall{}).Where("zone_id = ?", zoneID).Count(&stalls).Error; err != nil {
+		return 0, err
+	}
+
+	return slots + stalls, nil
+}
+
+// CountZoneBookings counts the zone's stalls of a category that are booked or held on a date, leaving out the
+// slots being booked.
+func (repo *ZoneRepository) CountZoneBookings(zoneID, date string, category entities.Category, excludeSlotIDs []string) (int64, error) {
+	return countZoneBookings(repo.db, zoneID, date, category, excludeSlotIDs)
+}
+
+func countZoneBookings(db *gorm.DB, zoneID, date string, category entities.Category, excludeSlotIDs []string) (int64, error) {
+	var count int64
+	err := db.Model(&entities.Booking{}).
+		Joins("JOIN slots ON slots.id = bookings.slot_id").
+		Where("slots.zone_id = ? AND slots.date = ? AND slots.category = ? AND slots.id NOT IN ?", zoneID, date, category, excludeSlotIDs).
+		Where("bookings.status IN ?", []entities.BookingStatus{entities.StatusPending, entities.StatusCompleted}).
+		Distinct("bookings.slot_id").
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// checkZoneCaps locks the zones the slots sit in and re-counts their bookings, so two bookings racing for a
+// zone's last place cannot both take it. It must run inside the transaction that inserts the bookings.
+func checkZoneCaps(tx *gorm.DB, slots []*entities.Slot) error {
+	loads := entities.ZoneLoads(slots)
+	if len(loads) == 0 {
+		return nil
+	}
+	zoneIDs := make([]string, 0, len(loads))
+	for _, load := range loads {
+		zoneIDs = append(zoneIDs, load.ZoneID)
+	}
+	var zones []entities.Zone
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", zoneIDs).Find(&zones).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]*entities.Zone, len(zones))
+	for i := range zones {
+		byID[zones[i].ID] = &zones[i]
+	}
+
+	for _, load := range loads {
+		zone, ok := byID[load.ZoneID]
+		if !ok || zone.Cap(load.Category) == 0 {
+			continue
+		}
+		booked, err := countZoneBookings(tx, load.ZoneID, load.Date, load.Category, load.SlotIDs)
+		if err != nil {
+			return err
+		}
+		if !zone.Fits(load.Category, int(booked), len(load.SlotIDs)) {
+			return fmt.Errorf("%w: %s has no room for %d more %s stalls on %s", entities.ErrZoneFull, zone.Name, len(load.SlotIDs), load.Category, load.Date)
+		}
+	}
+	return nil
+}
+
+func (repo *ZoneRepository) GetMarketProviderID(marketID string) (string, error) {
+	var market entities.Market
+	if err := repo.db.Select("id", "provider_id").Where("id = ?", marketID).First(&market).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("market not found")
+		}
+		return "", err
+	}
+	return market.ProviderID, nil
+}
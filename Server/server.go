@@ -1,6 +1,7 @@
 package Server
 
 import (
+	entities "tln-backend/Entities"
 	"tln-backend/Handlers"
 	middleware "tln-backend/Middlewares"
 	"tln-backend/Repository"
@@ -16,10 +17,14 @@ type Server struct {
 	App          *fiber.App
 	UserRepo     *Repository.UserRepository
 	ProviderRepo *Repository.ProviderRepository
+	StaffRepo    *Repository.StaffRepository
+	AdminRepo    *Repository.AdminRepository
 }
 
-func NewServer(userRepo *Repository.UserRepository, providerRepo *Repository.ProviderRepository) *Server {
-	app := fiber.New()
+func NewServer(userRepo *Repository.UserRepository, providerRepo *Repository.ProviderRepository, staffRepo *Repository.StaffRepository, adminRepo *Repository.AdminRepository) *Server {
+	app := fiber.New(fiber.Config{
+		BodyLimit: 16 * 1024 * 1024, // Room for media uploads, which MEDIA_MAX_UPLOAD_MB limits further
+	})
 	app.Use(logger.New())
 	app.Use(recover.New())
 	app.Use(cors.New())
@@ -27,12 +32,24 @@ func NewServer(userRepo *Repository.UserRepository, providerRepo *Repository.Pro
 		App:          app,
 		UserRepo:     userRepo,
 		ProviderRepo: providerRepo,
+		StaffRepo:    staffRepo,
+		AdminRepo:    adminRepo,
 	}
 }
 
 func (s *Server) MapHandlers(allHandlers *Handlers.AllHandlers) {
-	authMiddleware := middleware.JWTAuthMiddleware(s.UserRepo, s.ProviderRepo)
-	providerMiddleware := middleware.ProviderAuthMiddleware()
+	authMiddleware := middleware.JWTAuthMiddleware(s.UserRepo, s.ProviderRepo, s.StaffRepo, s.AdminRepo)
+	adminMiddleware := middleware.AdminAuthMiddleware()
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(authMiddleware)
+
+	// Provider routes: providers may do everything, staff what their role in the route's market allows
+	permit := func(permission entities.Permission, market middleware.MarketResolver) fiber.Handler {
+		return middleware.PermissionMiddleware(s.StaffRepo, permission, market)
+	}
+	marketOf := func(resource, param string) middleware.MarketResolver {
+		return middleware.MarketOf(s.StaffRepo, resource, param)
+	}
+	providerOnly := permit(entities.PermManageProvider, nil)
 
 	v1 := s.App.Group("/api/v1")
 
@@ -41,38 +58,152 @@ func (s *Server) MapHandlers(allHandlers *Handlers.AllHandlers) {
 	userGroup.Get("/:id", allHandlers.UserHandler.GetUserByID)
 	//userGroup.Patch("/:id", allHandlers.UserHandler.UpdateUser)
 
-	providerGroup := v1.Group("/Providers", authMiddleware, providerMiddleware)
+	providerGroup := v1.Group("/Providers", authMiddleware, providerOnly)
 
 	providerGroup.Put("/update", allHandlers.MarketProvider.UpdateProvider)
 
 	marketGroup := v1.Group("/Markets")
-	marketGroup.Post("/create", allHandlers.MarketHandler.CreateMarket, providerMiddleware)
+	marketGroup.Post("/create", authMiddleware, providerOnly, allHandlers.MarketHandler.CreateMarket)
 	marketGroup.Get("/get", allHandlers.MarketHandler.GetMarket)
-	marketGroup.Patch("/edit/:id", allHandlers.MarketHandler.EditMarket, providerMiddleware)
-	marketGroup.Get("/get/:id", allHandlers.MarketHandler.GetMarketByID)
-	marketGroup.Get("/provider/get/:id", allHandlers.MarketHandler.GetMarketByProviderID, providerMiddleware)
+	marketGroup.Get("/nearby", allHandlers.MarketHandler.GetNearbyMarkets)
+	marketGroup.Patch("/edit/:id", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.MarketHandler.EditMarket)
+	marketGroup.Get("/get/:id", optionalAuthMiddleware, allHandlers.MarketHandler.GetMarketByID)
+	marketGroup.Patch("/:id/status", authMiddleware, allHandlers.MarketHandler.ChangeMarketStatus)
+	marketGroup.Get("/:id/status-history", authMiddleware, allHandlers.MarketHandler.GetMarketStatusHistory)
+	marketGroup.Get("/provider/get/:id", authMiddleware, providerOnly, allHandlers.MarketHandler.GetMarketByProviderID)
+	marketGroup.Get("/:id/map", allHandlers.MarketMapHandler.GetMarketMap)
+	marketGroup.Get("/:id/map/svg", allHandlers.MarketMapHandler.GetMarketMapSVG)
+	marketGroup.Get("/:id/zones", allHandlers.ZoneHandler.GetZones)
+	marketGroup.Post("/:id/zones", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.ZoneHandler.CreateZone)
+
+	zoneGroup := v1.Group("/Zones", authMiddleware)
+	zoneGroup.Put("/:id", permit(entities.PermManageMarket, marketOf("zones", "id")), allHandlers.ZoneHandler.UpdateZone)
+	zoneGroup.Delete("/:id", permit(entities.PermManageMarket, marketOf("zones", "id")), allHandlers.ZoneHandler.DeleteZone)
+
+	marketGroup.Get("/:id/amenities", allHandlers.AmenityHandler.GetAmenities)
+	marketGroup.Post("/:id/amenities", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.AmenityHandler.CreateAmenity)
+	marketGroup.Get("/:id/hours", allHandlers.OpeningHoursHandler.GetOpeningHours)
+	marketGroup.Put("/:id/hours", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.OpeningHoursHandler.SaveWeeklyHours)
+	marketGroup.Post("/:id/hours/exceptions", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.OpeningHoursHandler.SaveException)
+	marketGroup.Delete("/:id/hours/exceptions/:date", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.OpeningHoursHandler.DeleteException)
+	marketGroup.Get("/:id/open-days", allHandlers.OpeningHoursHandler.GetOpenDays)
+	marketGroup.Post("/:id/applications", authMiddleware, allHandlers.ApplicationHandler.Apply)
+	marketGroup.Get("/:id/applications", authMiddleware, permit(entities.PermReviewVendors, middleware.MarketParam("id")), allHandlers.ApplicationHandler.GetMarketApplications)
+	marketGroup.Get("/:id/terms", optionalAuthMiddleware, allHandlers.TermsHandler.GetTerms)
+	marketGroup.Post("/:id/terms", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.TermsHandler.PublishTerms)
+	marketGroup.Get("/:id/terms/versions", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.TermsHandler.GetTermsVersions)
+	marketGroup.Get("/:id/terms/acceptances", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.TermsHandler.GetMarketAcceptances)
+	marketGroup.Post("/:id/terms/accept", authMiddleware, allHandlers.TermsHandler.AcceptTerms)
+	marketGroup.Post("/:id/announcements", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.AnnouncementHandler.SendAnnouncement)
+	marketGroup.Get("/:id/announcements", authMiddleware, permit(entities.PermManageMarket, middleware.MarketParam("id")), allHandlers.AnnouncementHandler.GetMarketAnnouncements)
+	marketGroup.Get("/:id/reviews", allHandlers.ReviewHandler.GetMarketReviews)
+	marketGroup.Post("/:id/blacklist", authMiddleware, permit(entities.PermReviewVendors, middleware.MarketParam("id")), allHandlers.ApplicationHandler.BlacklistVendor)
+
+	applicationGroup := v1.Group("/Applications", authMiddleware)
+	applicationGroup.Get("/:id", permit(entities.PermReviewVendors, marketOf("vendor_applications", "id")), allHandlers.ApplicationHandler.GetApplication)
+	applicationGroup.Get("/:id/history", permit(entities.PermReviewVendors, marketOf("vendor_applications", "id")), allHandlers.ApplicationHandler.GetApplicationHistory)
+	applicationGroup.Patch("/:id/review", permit(entities.PermReviewVendors, marketOf("vendor_applications", "id")), allHandlers.ApplicationHandler.ReviewApplication)
+
+	amenityGroup := v1.Group("/Amenities", authMiddleware)
+	amenityGroup.Put("/:id", permit(entities.PermManageMarket, marketOf("amenities", "id")), allHandlers.AmenityHandler.UpdateAmenity)
+	amenityGroup.Delete("/:id", permit(entities.PermManageMarket, marketOf("amenities", "id")), allHandlers.AmenityHandler.DeleteAmenity)
 
 	authGroup := v1.Group("/Auth")
 	authGroup.Post("/register", allHandlers.AuthHandler.Register)
 	authGroup.Post("/login", allHandlers.AuthHandler.Login)
 	authGroup.Post("/provider/login", allHandlers.AuthHandler.ProviderLogin)
 	authGroup.Post("/provider/register", allHandlers.AuthHandler.RegisterProvider)
+	authGroup.Post("/staff/login", allHandlers.StaffHandler.StaffLogin)
+	authGroup.Post("/staff/accept", allHandlers.StaffHandler.AcceptInvite)
+	authGroup.Post("/admin/login", allHandlers.AdminHandler.AdminLogin)
+
+	staffGroup := v1.Group("/Staff", authMiddleware)
+	staffGroup.Get("/me", allHandlers.StaffHandler.GetMyAccess)
+	staffGroup.Get("/", providerOnly, allHandlers.StaffHandler.GetStaff)
+	staffGroup.Post("/invite", providerOnly, allHandlers.StaffHandler.InviteStaff)
+	staffGroup.Post("/:id/invite", providerOnly, allHandlers.StaffHandler.ReinviteStaff)
+	staffGroup.Put("/:id/assignments", providerOnly, allHandlers.StaffHandler.SetStaffAssignments)
+	staffGroup.Delete("/:id", providerOnly, allHandlers.StaffHandler.DisableStaff)
 
 	bookingGroup := v1.Group("/Bookings")
-	bookingGroup.Post("/create", allHandlers.BookingHandler.CreateBooking)
+	bookingGroup.Post("/create", authMiddleware, allHandlers.BookingHandler.CreateBooking)
+	bookingGroup.Post("/group/create", authMiddleware, allHandlers.BookingHandler.CreateGroupBooking)
 	bookingGroup.Get("/get/:id", allHandlers.BookingHandler.GetBooking)
 	bookingGroup.Get("/user/:id", allHandlers.BookingHandler.GetBookingsByUser)
-	bookingGroup.Patch("/cancel", allHandlers.BookingHandler.CancelBooking)
+	bookingGroup.Patch("/cancel", authMiddleware, allHandlers.BookingHandler.CancelBooking)
 	bookingGroup.Get("/market/:id", allHandlers.BookingHandler.GetBookingsByMarket)
+	bookingGroup.Get("/market/:id/day-sheet/:date", authMiddleware, permit(entities.PermViewBookings, middleware.MarketParam("id")), allHandlers.BookingHandler.GetDaySheet)
+	bookingGroup.Patch("/market-day/cancel", authMiddleware, permit(entities.PermTakePayments, middleware.MarketBody("market_id")), allHandlers.BookingHandler.CancelMarketDay)
+	bookingGroup.Patch("/:id/check-in", authMiddleware, permit(entities.PermCheckIn, marketOf("bookings", "id")), allHandlers.BookingHandler.CheckIn)
+	bookingGroup.Post("/:id/review", authMiddleware, allHandlers.ReviewHandler.ReviewMarket)
+	bookingGroup.Post("/:id/vendor-review", authMiddleware, permit(entities.PermReviewVendors, marketOf("bookings", "id")), allHandlers.ReviewHandler.ReviewVendor)
+	bookingGroup.Get("/:id/terms", authMiddleware, permit(entities.PermViewBookings, marketOf("bookings", "id")), allHandlers.TermsHandler.GetBookingTerms)
 
 	slotGroup := v1.Group("/Slots")
-	slotGroup.Post("/:marketId/create", allHandlers.SlotHandler.CreateOrUpdateLayout, providerMiddleware)
+	slotGroup.Post("/:marketId/create", authMiddleware, permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.LayoutVersionHandler.ApplyLayout)
+	slotGroup.Get("/:marketId/versions", authMiddleware, permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.LayoutVersionHandler.GetVersions)
+	slotGroup.Get("/:marketId/versions/:version", authMiddleware, permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.LayoutVersionHandler.GetVersion)
+	slotGroup.Post("/:marketId/versions/:version/rollback", authMiddleware, permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.LayoutVersionHandler.RollbackLayout)
+	slotGroup.Post("/:marketId/bulk-edit", authMiddleware, permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.SlotHandler.BulkEditSlots)
+	slotGroup.Get("/search", allHandlers.SlotSearchHandler.SearchSlots)
 	slotGroup.Get("/get/:id", allHandlers.SlotHandler.GetSlot)
-	slotGroup.Patch("/edit/:id", allHandlers.SlotHandler.EditSlot, providerMiddleware)
-	slotGroup.Delete("/delete/:id", allHandlers.SlotHandler.DeleteSlot, providerMiddleware)
-	slotGroup.Get("/provider/get/:id", allHandlers.SlotHandler.GetProviderSlots, providerMiddleware)
-	slotGroup.Get("/markets/:marketID/date/:date", allHandlers.SlotHandler.GetSlotByDate, providerMiddleware)
-	slotGroup.Delete("/delete/:id/zone/:zoneID/date/:date", allHandlers.SlotHandler.DeleteSlotByDateAndZone, providerMiddleware)
+	slotGroup.Patch("/edit/:id", authMiddleware, permit(entities.PermManageLayout, marketOf("slots", "id")), allHandlers.SlotHandler.EditSlot)
+	slotGroup.Delete("/delete/:id", authMiddleware, permit(entities.PermManageLayout, marketOf("slots", "id")), allHandlers.SlotHandler.DeleteSlot)
+	slotGroup.Get("/provider/get/:id", authMiddleware, providerOnly, allHandlers.SlotHandler.GetProviderSlots)
+	slotGroup.Get("/markets/:marketID/date/:date", authMiddleware, permit(entities.PermViewBookings, middleware.MarketParam("marketID")), allHandlers.SlotHandler.GetSlotByDate)
+	slotGroup.Delete("/delete/:id/zone/:zoneID/date/:date", authMiddleware, permit(entities.PermManageLayout, middleware.MarketParam("id")), allHandlers.SlotHandler.DeleteSlotByDateAndZone)
+
+	searchGroup := v1.Group("/Search")
+	searchGroup.Get("/", allHandlers.TextSearchHandler.Search)
+	searchGroup.Post("/reindex", authMiddleware, adminMiddleware, allHandlers.TextSearchHandler.Reindex)
+
+	vendorGroup := v1.Group("/Vendors")
+	vendorGroup.Get("/profile", authMiddleware, allHandlers.VendorProfileHandler.GetMyProfile)
+	vendorGroup.Put("/profile", authMiddleware, allHandlers.VendorProfileHandler.SaveProfile)
+	vendorGroup.Get("/:id/profile", allHandlers.VendorProfileHandler.GetProfile)
+	vendorGroup.Get("/applications", authMiddleware, allHandlers.ApplicationHandler.GetMyApplications)
+	vendorGroup.Get("/terms", authMiddleware, allHandlers.TermsHandler.GetMyAcceptances)
+	vendorGroup.Get("/:id/reviews", allHandlers.ReviewHandler.GetVendorReviews)
+
+	reviewGroup := v1.Group("/Reviews", authMiddleware)
+	reviewGroup.Get("/reports", adminMiddleware, allHandlers.ReviewHandler.GetReports)
+	reviewGroup.Patch("/reports/:id", adminMiddleware, allHandlers.ReviewHandler.ResolveReport)
+	reviewGroup.Put("/:id/reply", permit(entities.PermManageMarket, marketOf("reviews", "id")), allHandlers.ReviewHandler.ReplyToReview)
+	reviewGroup.Post("/:id/report", allHandlers.ReviewHandler.ReportReview)
+	reviewGroup.Patch("/:id/moderate", adminMiddleware, allHandlers.ReviewHandler.ModerateReview)
+
+	mediaGroup := v1.Group("/Media")
+	mediaGroup.Post("/", authMiddleware, allHandlers.MediaHandler.UploadMedia)
+	mediaGroup.Get("/files/*", allHandlers.MediaHandler.GetMediaFile)
+	mediaGroup.Get("/:id", allHandlers.MediaHandler.GetMedia)
+	mediaGroup.Delete("/:id", authMiddleware, allHandlers.MediaHandler.DeleteMedia)
+
+	announcementGroup := v1.Group("/Announcements", authMiddleware)
+	announcementGroup.Get("/mine", allHandlers.AnnouncementHandler.GetMyAnnouncements)
+	announcementGroup.Get("/:id/receipts", permit(entities.PermManageMarket, marketOf("announcements", "id")), allHandlers.AnnouncementHandler.GetReceipts)
+	announcementGroup.Patch("/:id/read", allHandlers.AnnouncementHandler.MarkAnnouncementRead)
+
+	notificationGroup := v1.Group("/Notifications", authMiddleware)
+	notificationGroup.Get("/", allHandlers.NotificationHandler.GetNotifications)
+	notificationGroup.Patch("/:id/read", allHandlers.NotificationHandler.MarkRead)
+
+	templateGroup := v1.Group("/Templates", authMiddleware)
+	templateGroup.Post("/market/:marketId", permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.TemplateHandler.CreateTemplate)
+	templateGroup.Get("/market/:marketId", permit(entities.PermManageLayout, middleware.MarketParam("marketId")), allHandlers.TemplateHandler.GetTemplates)
+	templateGroup.Get("/:id", permit(entities.PermManageLayout, marketOf("layout_templates", "id")), allHandlers.TemplateHandler.GetTemplate)
+	templateGroup.Put("/:id", permit(entities.PermManageLayout, marketOf("layout_templates", "id")), allHandlers.TemplateHandler.UpdateTemplate)
+	templateGroup.Delete("/:id", permit(entities.PermManageLayout, marketOf("layout_templates", "id")), allHandlers.TemplateHandler.DeleteTemplate)
+	templateGroup.Post("/:id/apply", permit(entities.PermManageLayout, marketOf("layout_templates", "id")), allHandlers.TemplateHandler.ApplyTemplate)
+
+	scheduleGroup := v1.Group("/Schedules", authMiddleware)
+	scheduleGroup.Put("/market/:marketId", permit(entities.PermManageMarket, middleware.MarketParam("marketId")), allHandlers.ScheduleHandler.SaveSchedule)
+	scheduleGroup.Get("/market/:marketId", allHandlers.ScheduleHandler.GetSchedule)
+	scheduleGroup.Post("/market/:marketId/exceptions", permit(entities.PermManageMarket, middleware.MarketParam("marketId")), allHandlers.ScheduleHandler.AddException)
+	scheduleGroup.Post("/market/:marketId/generate", permit(entities.PermManageMarket, middleware.MarketParam("marketId")), allHandlers.ScheduleHandler.Generate)
+	scheduleGroup.Delete("/exceptions/:id", permit(entities.PermManageMarket, marketOf("schedule_exceptions", "id")), allHandlers.ScheduleHandler.RemoveException)
+	scheduleGroup.Post("/holidays", adminMiddleware, allHandlers.ScheduleHandler.SaveHoliday)
+	scheduleGroup.Get("/holidays", allHandlers.ScheduleHandler.GetHolidays)
+	scheduleGroup.Delete("/holidays/:date", adminMiddleware, allHandlers.ScheduleHandler.DeleteHoliday)
 
 	paymentGroup := v1.Group("/Payments", authMiddleware)
 	paymentGroup.Get("/get/:id", allHandlers.PaymentHandler.GetPayment)
@@ -82,6 +213,44 @@ func (s *Server) MapHandlers(allHandlers *Handlers.AllHandlers) {
 
 	dashboardGroup.Get("/weekly/:id", allHandlers.DashboardHandler.GetWeeklyStats)
 
+	ledgerGroup := v1.Group("/Ledger", authMiddleware)
+	ledgerGroup.Get("/accounts/:id/balance", allHandlers.LedgerHandler.GetAccountBalance)
+	ledgerGroup.Get("/accounts/:id/entries", allHandlers.LedgerHandler.GetAccountEntries)
+	ledgerGroup.Get("/owners/:id/balances", allHandlers.LedgerHandler.GetOwnerBalances)
+	ledgerGroup.Post("/settlements", adminMiddleware, allHandlers.LedgerHandler.RecordSettlement)
+	ledgerGroup.Post("/adjustments", adminMiddleware, allHandlers.LedgerHandler.RecordAdjustment)
+	ledgerGroup.Get("/invariants", adminMiddleware, allHandlers.LedgerHandler.CheckInvariants)
+
+	walletGroup := v1.Group("/Wallet", authMiddleware)
+	walletGroup.Get("/vendor/:id/balance", allHandlers.WalletHandler.GetBalance)
+	walletGroup.Get("/vendor/:id/transactions", allHandlers.WalletHandler.GetVendorTransactions)
+	walletGroup.Get("/provider/transactions", providerOnly, allHandlers.WalletHandler.GetProviderTransactions)
+	walletGroup.Post("/credits", allHandlers.WalletHandler.IssueCredit)
+
+	promotionGroup := v1.Group("/Promotions", authMiddleware)
+	promotionGroup.Post("/create", providerOnly, allHandlers.PromotionHandler.CreatePromotion)
+	promotionGroup.Get("/provider", providerOnly, allHandlers.PromotionHandler.GetPromotions)
+	promotionGroup.Patch("/:id/status", providerOnly, allHandlers.PromotionHandler.UpdatePromotionStatus)
+	promotionGroup.Get("/:id/redemptions", providerOnly, allHandlers.PromotionHandler.GetRedemptions)
+	promotionGroup.Post("/quote", allHandlers.PromotionHandler.Quote)
+
+	pricingGroup := v1.Group("/Pricing", authMiddleware)
+	pricingGroup.Post("/market/:marketId/rules", permit(entities.PermManagePricing, middleware.MarketParam("marketId")), allHandlers.PricingHandler.CreateRule)
+	pricingGroup.Get("/market/:marketId/rules", permit(entities.PermManagePricing, middleware.MarketParam("marketId")), allHandlers.PricingHandler.GetRules)
+	pricingGroup.Put("/rules/:id", permit(entities.PermManagePricing, marketOf("pricing_rules", "id")), allHandlers.PricingHandler.UpdateRule)
+	pricingGroup.Delete("/rules/:id", permit(entities.PermManagePricing, marketOf("pricing_rules", "id")), allHandlers.PricingHandler.DeleteRule)
+	pricingGroup.Get("/quote/:slotId", allHandlers.PricingHandler.QuoteSlot)
+	pricingGroup.Post("/market/:marketId/combos", permit(entities.PermManagePricing, middleware.MarketParam("marketId")), allHandlers.PricingHandler.CreateCombo)
+	pricingGroup.Get("/market/:marketId/combos", permit(entities.PermManagePricing, middleware.MarketParam("marketId")), allHandlers.PricingHandler.GetCombos)
+	pricingGroup.Delete("/combos/:id", permit(entities.PermManagePricing, marketOf("combo_prices", "id")), allHandlers.PricingHandler.DeleteCombo)
+	pricingGroup.Get("/quote-group", allHandlers.PricingHandler.QuoteGroup)
+
+	slipGroup := v1.Group("/Slips", authMiddleware)
+	slipGroup.Post("/booking/:id", middleware.VendorOrMiddleware(permit(entities.PermTakePayments, marketOf("bookings", "id"))), allHandlers.SlipHandler.UploadSlip)
+	slipGroup.Get("/booking/:id", middleware.VendorOrMiddleware(permit(entities.PermTakePayments, marketOf("bookings", "id"))), allHandlers.SlipHandler.GetBookingSlips)
+	slipGroup.Get("/provider/pending", permit(entities.PermTakePayments, middleware.MarketQuery("market_id")), allHandlers.SlipHandler.GetReviewQueue)
+	slipGroup.Patch("/:id/review", permit(entities.PermTakePayments, marketOf("bank_slips", "id")), allHandlers.SlipHandler.ReviewSlip)
+
 	ScbResponseGroup := v1.Group("/Scb")
 	ScbResponseGroup.Post("/confirm", allHandlers.PaymentHandler.ScbConfirmation)
 
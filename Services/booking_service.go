@@ -14,9 +14,12 @@ type BookingService struct {
 	repo        contact.IBooking
 	payment     contact.IPayment
 	slotUseCase contact.ISlotUseCase
+	ledger      contact.ILedgerUseCase
+	wallet      contact.IWalletUseCase
+	promotion   contact.IPromotionUseCase
 }
 
-func NewBookingService(repo contact.IBooking, payment contact.IPayment, slotUseCase contact.ISlotUseCase) *BookingService {
+func NewBookingService(repo contact.IBooking, payment contact.IPayment, slotUseCase contact.ISlotUseCase, ledger contact.ILedgerUseCase, wallet contact.IWalletUseCase, promotion contact.IPromotionUseCase) *BookingService {
 	scheduler := gocron.NewScheduler(time.UTC)
 	scheduler.StartAsync()
 	return &BookingService{
@@ -24,6 +27,9 @@ func NewBookingService(repo contact.IBooking, payment contact.IPayment, slotUseC
 		repo:        repo,
 		payment:     payment,
 		slotUseCase: slotUseCase,
+		ledger:      ledger,
+		wallet:      wallet,
+		promotion:   promotion,
 	}
 }
 
@@ -51,6 +57,11 @@ func (s *BookingService) checkBookingStatus(transactionID, bookingID, slotID, ve
 		return
 	}
 
+	// The booking may have been moved to another slot since the check was scheduled
+	if booking, err := s.repo.GetBooking(bookingID); err == nil {
+		slotID = booking.SlotID
+	}
+
 	switch transaction.Status {
 	case entities.TransactionPending:
 		if time.Now().After(expiresAt) {
@@ -66,7 +77,7 @@ func (s *BookingService) checkBookingStatus(transactionID, bookingID, slotID, ve
 
 	case entities.TransactionCompleted:
 		if time.Now().Before(expiresAt) {
-			err := s.completeBooking(transactionID, transaction.PaymentID, bookingID, slotID, vendorID)
+			err := s.CompleteBooking(transactionID, transaction.PaymentID, bookingID, slotID, vendorID)
 			if err != nil {
 				log.Printf("Error completing booking: %v", err)
 				return
@@ -88,7 +99,7 @@ func (s *BookingService) checkBookingStatus(transactionID, bookingID, slotID, ve
 
 	case entities.TransactionRefunded:
 		if time.Now().Before(expiresAt) {
-			err := s.RefundBooking(transactionID, transaction.PaymentID, bookingID, slotID, "")
+			err := s.RefundBooking(transactionID, bookingID, transaction.PaymentID, slotID, "")
 			if err != nil {
 				log.Printf("Error cancelling refunded booking: %v", err)
 				return
@@ -118,6 +129,19 @@ func (s *BookingService) RefundBooking(transactionID, bookingID, paymentID, slot
 	if _, err := s.slotUseCase.UpdateSlotStatus(slotID, vendorID, entities.StatusAvailable); err != nil {
 		return fmt.Errorf("error updating slot status: %v", err)
 	}
+	s.UpdateGroupSlots(bookingID, vendorID, entities.StatusAvailable)
+
+	if errRes := s.ledger.RecordRefund(bookingID, false); errRes != nil {
+		log.Printf("Warning: failed to record refund in ledger for booking %s: %v", bookingID, errRes.Message)
+	}
+
+	if errRes := s.wallet.RestoreCredit(bookingID); errRes != nil {
+		log.Printf("Warning: failed to restore wallet credit for booking %s: %v", bookingID, errRes.Message)
+	}
+
+	if errRes := s.promotion.ReleasePromotion(bookingID); errRes != nil {
+		log.Printf("Warning: failed to release promotion for booking %s: %v", bookingID, errRes.Message)
+	}
 
 	s.RemoveScheduled(bookingID)
 	return nil
@@ -136,11 +160,24 @@ func (s *BookingService) cancelExpiredBooking(transactionID, paymentID, bookingI
 		return fmt.Errorf("error updating booking status: %v", err)
 	}
 
+	if err := s.repo.ExpireSlips(bookingID); err != nil {
+		log.Printf("Warning: failed to expire slips of booking %s: %v", bookingID, err)
+	}
+
+	if errRes := s.wallet.RestoreCredit(bookingID); errRes != nil {
+		log.Printf("Warning: failed to restore wallet credit for booking %s: %v", bookingID, errRes.Message)
+	}
+
+	if errRes := s.promotion.ReleasePromotion(bookingID); errRes != nil {
+		log.Printf("Warning: failed to release promotion for booking %s: %v", bookingID, errRes.Message)
+	}
+
 	s.RemoveScheduled(bookingID)
 	return nil
 }
 
-func (s *BookingService) completeBooking(transactionID, paymentID, bookingID, slotID, vendorID string) error {
+// CompleteBooking marks a paid booking as confirmed and books its slot.
+func (s *BookingService) CompleteBooking(transactionID, paymentID, bookingID, slotID, vendorID string) error {
 	if _, err := s.payment.UpdateTransaction(transactionID, entities.TransactionCompleted); err != nil {
 		return fmt.Errorf("error updating transaction status: %v", err)
 	}
@@ -156,7 +193,26 @@ func (s *BookingService) completeBooking(transactionID, paymentID, bookingID, sl
 	if _, err := s.slotUseCase.UpdateSlotStatus(slotID, vendorID, entities.StatusBooked); err != nil {
 		return fmt.Errorf("error updating slot status: %v", err)
 	}
+	s.UpdateGroupSlots(bookingID, vendorID, entities.StatusBooked)
+
+	if errRes := s.ledger.RecordCharge(bookingID); errRes != nil {
+		log.Printf("Warning: failed to record charge in ledger for booking %s: %v", bookingID, errRes.Message)
+	}
 	s.RemoveScheduled(bookingID)
 
 	return nil
 }
+
+// UpdateGroupSlots gives the other stalls of a combined booking the status its lead slot just got.
+func (s *BookingService) UpdateGroupSlots(bookingID, vendorID string, status entities.SlotStatus) {
+	slotIDs, err := s.repo.GetGroupMemberSlotIDs(bookingID)
+	if err != nil {
+		log.Printf("Warning: failed to get the grouped slots of booking %s: %v", bookingID, err)
+		return
+	}
+	for _, slotID := range slotIDs {
+		if _, errRes := s.slotUseCase.UpdateSlotStatus(slotID, vendorID, status); errRes != nil {
+			log.Printf("Warning: failed to update grouped slot %s of booking %s: %v", slotID, bookingID, errRes.Message)
+		}
+	}
+}
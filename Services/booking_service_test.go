@@ -0,0 +1,88 @@
+package Services
+
+import (
+	"testing"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/contact"
+)
+
+// fakeBookingRepo holds one booking and whether its slips were expired.
+type fakeBookingRepo struct {
+	contact.IBooking
+	booking      entities.Booking
+	slipsExpired bool
+}
+
+func (f *fakeBookingRepo) GetBooking(bookingID string) (*entities.Booking, error) {
+	booking := f.booking
+	return &booking, nil
+}
+
+func (f *fakeBookingRepo) UpdateBookingStatus(bookingID string, status entities.BookingStatus) (*entities.Booking, error) {
+	f.booking.Status = status
+	return &f.booking, nil
+}
+
+func (f *fakeBookingRepo) ExpireSlips(bookingID string) error {
+	f.slipsExpired = true
+	return nil
+}
+
+type fakePayment struct {
+	contact.IPayment
+	transaction entities.Transaction
+}
+
+func (f *fakePayment) GetTransactionByID(transactionID string) (*entities.Transaction, error) {
+	transaction := f.transaction
+	return &transaction, nil
+}
+
+func (f *fakePayment) UpdateTransaction(transactionID string, status entities.TransactionStatus) (*entities.Transaction, error) {
+	f.transaction.Status = status
+	return &f.transaction, nil
+}
+
+func (f *fakePayment) UpdatePayment(paymentID string, status entities.PaymentStatus) (*entities.Payment, error) {
+	return &entities.Payment{ID: paymentID, Status: status}, nil
+}
+
+type fakeWalletUseCase struct{ contact.IWalletUseCase }
+
+func (f *fakeWalletUseCase) RestoreCredit(bookingID string) *entitiesDtos.ErrorResponse { return nil }
+
+type fakePromotionUseCase struct{ contact.IPromotionUseCase }
+
+func (f *fakePromotionUseCase) ReleasePromotion(bookingID string) *entitiesDtos.ErrorResponse {
+	return nil
+}
+
+func TestCheckBookingStatusSlipDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		expiresAt   time.Time
+		wantStatus  entities.BookingStatus
+		wantExpired bool
+	}{
+		{name: "slip still within its review window", expiresAt: time.Now().Add(time.Hour), wantStatus: entities.StatusPending},
+		{name: "slip nobody reviewed in time", expiresAt: time.Now().Add(-time.Minute), wantStatus: entities.StatusCancelled, wantExpired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeBookingRepo{booking: entities.Booking{ID: "b1", SlotID: "s1", Status: entities.StatusPending}}
+			payment := &fakePayment{transaction: entities.Transaction{ID: "t1", PaymentID: "p1", Status: entities.TransactionPending}}
+			service := NewBookingService(repo, payment, nil, nil, &fakeWalletUseCase{}, &fakePromotionUseCase{})
+
+			service.checkBookingStatus("t1", "b1", "s1", "v1", tt.expiresAt)
+			if repo.booking.Status != tt.wantStatus {
+				t.Errorf("booking is %s, want %s", repo.booking.Status, tt.wantStatus)
+			}
+			if repo.slipsExpired != tt.wantExpired {
+				t.Errorf("slips expired = %v, want %v", repo.slipsExpired, tt.wantExpired)
+			}
+		})
+	}
+}
@@ -43,9 +43,12 @@ func (s *DashboardService) startScheduler() {
 }
 
 func (s *DashboardService) updateAllMarketStats() error {
+	// Only markets that are trading; an archived market's stats stay as they were when it closed
 	var marketIDs []string
 	err := s.repo.GetDB().Model(&entities.Booking{}).
 		Select("DISTINCT market_id").
+		Where("EXISTS (SELECT 1 FROM markets m WHERE m.id = bookings.market_id AND m.status IN ?)",
+			[]entities.MarketStatus{entities.MarketPublished, entities.MarketSuspended}).
 		Pluck("market_id", &marketIDs).Error
 
 	if err != nil {
@@ -0,0 +1,73 @@
+package Services
+
+import (
+	"fmt"
+	"github.com/go-co-op/gocron"
+	"log"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+)
+
+type LedgerService struct {
+	repo      Interfaces.ILedger
+	scheduler *gocron.Scheduler
+}
+
+func NewLedgerService(repo Interfaces.ILedger) *LedgerService {
+	scheduler := gocron.NewScheduler(time.UTC)
+	service := &LedgerService{
+		repo:      repo,
+		scheduler: scheduler,
+	}
+
+	service.startScheduler()
+	return service
+}
+
+func (s *LedgerService) startScheduler() {
+	// Verify the ledger still balances every hour
+	_, err := s.scheduler.Every(1).Hour().Do(func() {
+		report, err := s.CheckInvariants()
+		if err != nil {
+			log.Printf("Ledger invariant check failed: %v", err)
+			return
+		}
+		if !report.Balanced {
+			log.Printf("Ledger out of balance: debit=%.2f credit=%.2f unbalanced entries=%v",
+				report.TotalDebit, report.TotalCredit, report.UnbalancedEntries)
+		}
+	})
+
+	if err != nil {
+		log.Printf("Failed to schedule ledger invariant check: %v", err)
+	}
+
+	s.scheduler.StartAsync()
+}
+
+// CheckInvariants verifies that every journal entry balances and that total debits equal total credits.
+func (s *LedgerService) CheckInvariants() (*entitiesDtos.LedgerInvariantReport, error) {
+	debit, credit, count, err := s.repo.GetLedgerTotals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger totals: %v", err)
+	}
+
+	unbalanced, err := s.repo.GetUnbalancedEntryIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unbalanced entries: %v", err)
+	}
+	if unbalanced == nil {
+		unbalanced = []string{}
+	}
+
+	return &entitiesDtos.LedgerInvariantReport{
+		CheckedAt:         time.Now(),
+		EntryCount:        count,
+		TotalDebit:        entities.RoundMoney(debit),
+		TotalCredit:       entities.RoundMoney(credit),
+		UnbalancedEntries: unbalanced,
+		Balanced:          len(unbalanced) == 0 && entities.RoundMoney(debit) == entities.RoundMoney(credit),
+	}, nil
+}
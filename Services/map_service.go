@@ -0,0 +1,254 @@
+package Services
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+// categoryColours fill available stalls so vendors can see at a glance where each trade sits.
+var categoryColours = map[entities.Category]string{
+	entities.CategoryClothes:     "#8e44ad",
+	entities.CategoryFood:        "#e67e22",
+	entities.CategoryCrafts:      "#16a085",
+	entities.CategoryProduce:     "#27ae60",
+	entities.CategoryElectronics: "#2980b9",
+	entities.CategoryServices:    "#c0392b",
+	entities.CategoryOther:       "#7f8c8d",
+}
+
+const (
+	bookedFill      = "#bdbdbd"
+	unavailableFill = "#eeeeee"
+	// stallGap separates stalls that had no position and were laid out in rows, in floor plan units
+	stallGap = 1.0
+)
+
+type MapService struct{}
+
+func NewMapService() *MapService {
+	return &MapService{}
+}
+
+// Arrange fills in positions for stalls that were never placed, one row block per zone below the placed
+// stalls, sets every stall's fill colour, and sizes the floor plan to fit when the market has no size set.
+func (s *MapService) Arrange(m *entitiesDtos.MarketMap) {
+	var maxX, maxY float64
+	unplaced := make(map[string][]int)
+	zones := make([]string, 0)
+	for i := range m.Slots {
+		slot := &m.Slots[i]
+		slot.Fill = mapFill(slot)
+		if !slot.Placed {
+			if _, seen := unplaced[slot.Zone]; !seen {
+				zones = append(zones, slot.Zone)
+			}
+			unplaced[slot.Zone] = append(unplaced[slot.Zone], i)
+			continue
+		}
+		_, _, x2, y2 := slotBounds(slot)
+		maxX, maxY = math.Max(maxX, x2), math.Max(maxY, y2)
+	}
+
+	rowWidth := math.Max(m.Width, maxX)
+	if rowWidth == 0 {
+		// Aim for a roughly square block when nothing gives a width
+		var area float64
+		for _, indexes := range unplaced {
+			for _, i := range indexes {
+				area += (float64(m.Slots[i].Width) + stallGap) * (float64(m.Slots[i].Height) + stallGap)
+			}
+		}
+		rowWidth = math.Ceil(math.Sqrt(area))
+	}
+
+	sort.Strings(zones)
+	y := maxY
+	if y > 0 {
+		y += stallGap * 2
+	}
+	for _, zone := range zones {
+		x, rowHeight := 0.0, 0.0
+		for _, i := range unplaced[zone] {
+			slot := &m.Slots[i]
+			width, height := float64(slot.Width), float64(slot.Height)
+			if x > 0 && x+width > rowWidth {
+				x, y = 0, y+rowHeight+stallGap
+				rowHeight = 0
+			}
+			slot.X, slot.Y = x, y
+			x += width + stallGap
+			rowHeight = math.Max(rowHeight, height)
+			maxX = math.Max(maxX, slot.X+width)
+		}
+		y += rowHeight + stallGap*2
+	}
+	maxY = math.Max(maxY, y-stallGap*2)
+
+	m.Width = math.Max(m.Width, maxX)
+	m.Height = math.Max(m.Height, maxY)
+}
+
+// RenderSVG draws an arranged map. Each stall carries data-slot-id so a client can turn a click into a booking.
+func (s *MapService) RenderSVG(m *entitiesDtos.MarketMap) []byte {
+	width, height := math.Max(m.Width, 1), math.Max(m.Height, 1)
+	unit := math.Max(width, height) / 60
+	legendHeight := unit * 8
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<title>%s %s</title>`, html.EscapeString(m.MarketName), m.Date)
+	fmt.Fprintf(&b, `<defs><pattern id="booked" width="%s" height="%s" patternUnits="userSpaceOnUse" patternTransform="rotate(45)">`+
+		`<rect width="%[1]s" height="%[2]s" fill="%s"/><line x1="0" y1="0" x2="0" y2="%[2]s" stroke="#9e9e9e" stroke-width="%[4]s"/></pattern></defs>`,
+		num(unit), num(unit), bookedFill, num(unit/3))
+	fmt.Fprintf(&b, `<rect x="0" y="0" width="%s" height="%s" fill="#fafafa" stroke="#424242" stroke-width="%s"/>`,
+		num(width), num(height), num(unit/5))
+
+	for _, zone := range zoneBounds(m.Slots) {
+		fmt.Fprintf(&b, `<g class="zone"><rect x="%s" y="%s" width="%s" height="%s" fill="none" stroke="#9e9e9e" stroke-dasharray="%s" stroke-width="%s"/>`,
+			num(zone.x1-unit/2), num(zone.y1-unit/2), num(zone.x2-zone.x1+unit), num(zone.y2-zone.y1+unit), num(unit/2), num(unit/8))
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-size="%s" fill="#616161">%s</text></g>`,
+			num(zone.x1-unit/2), num(zone.y1-unit), num(unit*1.2), html.EscapeString(zone.name))
+	}
+
+	for i := range m.Slots {
+		writeStall(&b, &m.Slots[i], unit)
+	}
+
+	// The legend can run wider than a small floor plan, so the view box is sized once it is drawn
+	legendWidth := writeLegend(&b, m.Slots, height+unit*3, unit)
+	header := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s" font-family="sans-serif">`,
+		num(-unit), num(-unit*3), num(math.Max(width, legendWidth)+unit*2), num(height+legendHeight+unit*4))
+	return []byte(header + b.String() + `</svg>`)
+}
+
+func writeStall(b *strings.Builder, slot *entitiesDtos.MapSlot, unit float64) {
+	width, height := float64(slot.Width), float64(slot.Height)
+	cx, cy := slot.X+width/2, slot.Y+height/2
+
+	stroke, dash, fill := "#2e7d32", "", slot.Fill
+	switch slot.Availability {
+	case entitiesDtos.MapHeld:
+		stroke, dash = "#f9a825", fmt.Sprintf(` stroke-dasharray="%s" fill-opacity="0.5"`, num(unit/2))
+	case entitiesDtos.MapBooked:
+		stroke, fill = "#616161", "url(#booked)"
+	case entitiesDtos.MapUnavailable:
+		stroke = "#bdbdbd"
+	}
+
+	fmt.Fprintf(b, `<g class="stall %s" data-slot-id="%s" data-availability="%s" data-category="%s"`,
+		slot.Availability, html.EscapeString(slot.ID), slot.Availability, slot.Category)
+	if slot.Rotation != 0 {
+		fmt.Fprintf(b, ` transform="rotate(%s %s %s)"`, num(slot.Rotation), num(cx), num(cy))
+	}
+	fmt.Fprintf(b, `><title>%s / %s · %s · %s฿ · %s</title>`, html.EscapeString(slot.Zone), html.EscapeString(slot.Name),
+		slot.Category, num(slot.Price), slot.Availability)
+
+	if len(slot.Shape) > 0 {
+		points := make([]string, 0, len(slot.Shape))
+		for _, p := range slot.Shape {
+			points = append(points, num(slot.X+p.X)+","+num(slot.Y+p.Y))
+		}
+		fmt.Fprintf(b, `<polygon points="%s"`, strings.Join(points, " "))
+	} else {
+		fmt.Fprintf(b, `<rect x="%s" y="%s" width="%s" height="%s"`, num(slot.X), num(slot.Y), num(width), num(height))
+	}
+	fmt.Fprintf(b, ` fill="%s" stroke="%s" stroke-width="%s"%s/>`, fill, stroke, num(unit/6), dash)
+
+	fontSize := math.Min(math.Min(width, height)*0.4, unit*1.5)
+	fmt.Fprintf(b, `<text x="%s" y="%s" font-size="%s" text-anchor="middle" dominant-baseline="central" fill="#212121">%s</text></g>`,
+		num(cx), num(cy), num(fontSize), html.EscapeString(slot.Name))
+}
+
+// writeLegend draws category and availability keys under the plan and returns how wide they run.
+func writeLegend(b *strings.Builder, slots []entitiesDtos.MapSlot, top, unit float64) float64 {
+	used := make(map[entities.Category]bool)
+	for _, slot := range slots {
+		used[slot.Category] = true
+	}
+	categories := make([]string, 0, len(used))
+	for category := range used {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+
+	swatch := func(x, y float64, fill, stroke, label string) float64 {
+		fmt.Fprintf(b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s" stroke="%s" stroke-width="%s"/>`,
+			num(x), num(y), num(unit*1.5), num(unit*1.5), fill, stroke, num(unit/6))
+		fmt.Fprintf(b, `<text x="%s" y="%s" font-size="%s" dominant-baseline="central">%s</text>`,
+			num(x+unit*2), num(y+unit*0.75), num(unit*1.2), label)
+		return x + unit*(3+float64(len(label))*0.75)
+	}
+
+	b.WriteString(`<g class="legend">`)
+	categoryWidth := 0.0
+	for _, category := range categories {
+		categoryWidth = swatch(categoryWidth, top, mapCategoryColour(entities.Category(category)), "#2e7d32", html.EscapeString(category))
+	}
+	x := swatch(0, top+unit*2.5, "#ffffff", "#2e7d32", string(entitiesDtos.MapAvailable))
+	x = swatch(x, top+unit*2.5, "#ffffff", "#f9a825", string(entitiesDtos.MapHeld))
+	x = swatch(x, top+unit*2.5, "url(#booked)", "#616161", string(entitiesDtos.MapBooked))
+	x = swatch(x, top+unit*2.5, unavailableFill, "#bdbdbd", string(entitiesDtos.MapUnavailable))
+	b.WriteString(`</g>`)
+
+	return math.Max(categoryWidth, x)
+}
+
+type zoneBox struct {
+	name           string
+	x1, y1, x2, y2 float64
+}
+
+func zoneBounds(slots []entitiesDtos.MapSlot) []zoneBox {
+	boxes := make(map[string]*zoneBox)
+	names := make([]string, 0)
+	for i := range slots {
+		x1, y1, x2, y2 := slotBounds(&slots[i])
+		box, ok := boxes[slots[i].Zone]
+		if !ok {
+			box = &zoneBox{name: slots[i].Zone, x1: x1, y1: y1, x2: x2, y2: y2}
+			boxes[slots[i].Zone] = box
+			names = append(names, slots[i].Zone)
+			continue
+		}
+		box.x1, box.y1 = math.Min(box.x1, x1), math.Min(box.y1, y1)
+		box.x2, box.y2 = math.Max(box.x2, x2), math.Max(box.y2, y2)
+	}
+
+	sort.Strings(names)
+	result := make([]zoneBox, 0, len(names))
+	for _, name := range names {
+		result = append(result, *boxes[name])
+	}
+	return result
+}
+
+// slotBounds is the axis-aligned box around a stall once its shape and rotation are applied.
+func slotBounds(slot *entitiesDtos.MapSlot) (float64, float64, float64, float64) {
+	return slot.Bounds(slot.Width, slot.Height)
+}
+
+func mapFill(slot *entitiesDtos.MapSlot) string {
+	switch slot.Availability {
+	case entitiesDtos.MapBooked:
+		return bookedFill
+	case entitiesDtos.MapUnavailable:
+		return unavailableFill
+	}
+	return mapCategoryColour(slot.Category)
+}
+
+func mapCategoryColour(category entities.Category) string {
+	if colour, ok := categoryColours[category]; ok {
+		return colour
+	}
+	return categoryColours[entities.CategoryOther]
+}
+
+// num keeps SVG coordinates short.
+func num(value float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", value), "0"), ".")
+}
@@ -0,0 +1,139 @@
+package Services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// mediaMaxPixels stops an upload that is small on disk but decodes to a huge image.
+const mediaMaxPixels = 40_000_000
+
+var mediaContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// ImageInfo is what an upload turned out to be, whatever the client claimed.
+type ImageInfo struct {
+	ContentType string
+	Width       int
+	Height      int
+}
+
+type MediaService struct {
+	maxBytes      int64
+	thumbnailSize int
+}
+
+// NewMediaService reads MEDIA_MAX_UPLOAD_MB (default 8) and MEDIA_THUMBNAIL_SIZE, the longest side of a
+// thumbnail in pixels (default 320).
+func NewMediaService() *MediaService {
+	s := &MediaService{maxBytes: 8 << 20, thumbnailSize: 320}
+	if mb, err := strconv.Atoi(os.Getenv("MEDIA_MAX_UPLOAD_MB")); err == nil && mb > 0 {
+		s.maxBytes = int64(mb) << 20
+	}
+	if size, err := strconv.Atoi(os.Getenv("MEDIA_THUMBNAIL_SIZE")); err == nil && size > 0 {
+		s.thumbnailSize = size
+	}
+	return s
+}
+
+// MaxBytes is the largest upload accepted.
+func (s *MediaService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Inspect checks an upload is a JPEG, PNG or GIF image of a sensible size. The type is sniffed from the
+// content, not taken from the file name or the client's header.
+func (s *MediaService) Inspect(data []byte) (*ImageInfo, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, fmt.Errorf("file is larger than %d MB", s.maxBytes>>20)
+	}
+	contentType := http.DetectContentType(data)
+	if !mediaContentTypes[contentType] {
+		return nil, fmt.Errorf("%s is not supported, upload a JPEG, PNG or GIF image", contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unreadable image: %v", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("image has no pixels")
+	}
+	if config.Width*config.Height > mediaMaxPixels {
+		return nil, fmt.Errorf("image is %dx%d pixels, at most %d million are allowed", config.Width, config.Height, mediaMaxPixels/1_000_000)
+	}
+
+	return &ImageInfo{ContentType: contentType, Width: config.Width, Height: config.Height}, nil
+}
+
+// Thumbnail shrinks an image so its longest side fits the thumbnail size and encodes it as JPEG.
+// Transparent parts are laid on white. Images already small enough are re-encoded at their own size.
+func (s *MediaService) Thumbnail(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unreadable image: %v", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); longest > s.thumbnailSize {
+		width = max(1, width*s.thumbnailSize/longest)
+		height = max(1, height*s.thumbnailSize/longest)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, shrinkOnWhite(src, width, height), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// shrinkOnWhite scales src down to width x height by averaging every source pixel into the one it lands
+// on, then lays the result on white.
+func shrinkOnWhite(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	sums := make([][4]uint64, width*height)
+	counts := make([]uint64, width*height)
+
+	for y := 0; y < srcHeight; y++ {
+		row := y * height / srcHeight * width
+		for x := 0; x < srcWidth; x++ {
+			i := row + x*width/srcWidth
+			r, g, b, a := src.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			sums[i][0] += uint64(r)
+			sums[i][1] += uint64(g)
+			sums[i][2] += uint64(b)
+			sums[i][3] += uint64(a)
+			counts[i]++
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i, sum := range sums {
+		n := counts[i]
+		if n == 0 {
+			n = 1
+		}
+		// Colours are premultiplied by alpha, so adding the missing alpha as white composes onto white
+		white := 0xffff - sum[3]/n
+		for c := 0; c < 3; c++ {
+			dst.Pix[i*4+c] = uint8((sum[c]/n + white) >> 8)
+		}
+		dst.Pix[i*4+3] = 0xff
+	}
+	return dst
+}
@@ -0,0 +1,172 @@
+package Services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaStorage keeps uploaded files under slash-separated keys. Files are never served straight from the
+// bucket or disk; clients get short-lived signed URLs.
+type MediaStorage interface {
+	Put(key, contentType string, data []byte) error
+	Delete(key string) error
+	SignedURL(key string) (string, error)
+}
+
+// ErrMediaNotFound is returned when a stored file does not exist.
+var ErrMediaNotFound = errors.New("media file not found")
+
+// MediaKey is where an uploaded file is stored, MediaThumbnailKey where its thumbnail is.
+func MediaKey(mediaID string) string {
+	return "media/" + mediaID + "/original"
+}
+
+func MediaThumbnailKey(mediaID string) string {
+	return "media/" + mediaID + "/thumbnail.jpg"
+}
+
+// NewMediaStorage picks the storage named by MEDIA_STORAGE: "local" (the default) keeps files on disk, "s3"
+// in an S3-compatible bucket such as MinIO. MEDIA_URL_TTL_MINUTES sets how long signed URLs last (default
+// 12 hours).
+func NewMediaStorage() (MediaStorage, error) {
+	ttl := 12 * time.Hour
+	if minutes, err := strconv.Atoi(os.Getenv("MEDIA_URL_TTL_MINUTES")); err == nil && minutes > 0 {
+		ttl = time.Duration(minutes) * time.Minute
+	}
+
+	switch backend := os.Getenv("MEDIA_STORAGE"); backend {
+	case "", "local":
+		return NewLocalStorage(ttl)
+	case "s3":
+		return NewS3Storage(ttl)
+	default:
+		return nil, fmt.Errorf("unknown MEDIA_STORAGE %q, use local or s3", backend)
+	}
+}
+
+// signingTime rounds now down to half a TTL, so a file keeps the same signed URL for a while and clients can
+// cache it, yet every URL handed out still has at least half its TTL left.
+func signingTime(now time.Time, ttl time.Duration) time.Time {
+	return now.Truncate(ttl / 2)
+}
+
+// LocalStorage keeps files in a directory and serves them through the API. Its URLs are signed with HMAC so
+// only URLs the API handed out work.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+	secret  []byte
+	ttl     time.Duration
+}
+
+// NewLocalStorage reads MEDIA_LOCAL_DIR (default ./uploads), MEDIA_PUBLIC_URL, the address clients reach the
+// API at (default none, giving URLs relative to the API host), and MEDIA_SIGNING_KEY (default
+// JWT_SECRET_KEY).
+func NewLocalStorage(ttl time.Duration) (*LocalStorage, error) {
+	dir := os.Getenv("MEDIA_LOCAL_DIR")
+	if dir == "" {
+		dir = "./uploads"
+	}
+	secret := os.Getenv("MEDIA_SIGNING_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET_KEY")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("MEDIA_SIGNING_KEY or JWT_SECRET_KEY must be set to sign media URLs")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %v", err)
+	}
+
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimRight(os.Getenv("MEDIA_PUBLIC_URL"), "/"),
+		secret:  []byte(secret),
+		ttl:     ttl,
+	}, nil
+}
+
+func (s *LocalStorage) Put(key, contentType string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write beside the file and rename, so a reader never sees half a file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// Drop the file's directory once it is empty; this fails harmlessly while it is not
+	_ = os.Remove(filepath.Dir(path))
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(key string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(signingTime(time.Now(), s.ttl).Add(s.ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+	return s.baseURL + "/api/v1/Media/files/" + key + "?" + query.Encode(), nil
+}
+
+// Open reads a file for a signed URL, after checking the signature and that the URL has not expired.
+func (s *LocalStorage) Open(key, expires, signature string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(key, expires))) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return nil, fmt.Errorf("link has expired")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMediaNotFound
+	}
+	return data, err
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key into the storage directory, refusing keys that would step outside it.
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("invalid media key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,212 @@
+package Services
+
+import (
+	"fmt"
+	"math"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+type PricingService struct{}
+
+func NewPricingService() *PricingService {
+	return &PricingService{}
+}
+
+// Evaluate runs the market's rules over a slot. The quote must already carry the date, lead time, occupancy and
+// holiday; Evaluate fills in Price and a trace entry for every rule it looked at.
+//
+// Base rules pick the starting price, the most specific zone/category match winning. Weekday and holiday rules
+// all apply. Early bird, last minute and demand rules are tiers, so only the best matching one applies.
+func (s *PricingService) Evaluate(slot *entities.Slot, rules []entities.PricingRule, quote *entitiesDtos.PriceQuote) {
+	byKind := make(map[entities.PricingRuleKind][]entities.PricingRule)
+	for _, rule := range rules {
+		byKind[rule.Kind] = append(byKind[rule.Kind], rule)
+	}
+
+	price := entities.RoundMoney(slot.Price)
+	quote.Trace = make([]entitiesDtos.PriceStep, 0, len(rules)+1)
+	quote.Trace = append(quote.Trace, entitiesDtos.PriceStep{
+		Name:    "Slot price",
+		Kind:    entities.RuleBase,
+		Applied: true,
+		Reason:  "price stored on the slot",
+		Before:  price,
+		After:   price,
+	})
+
+	date, _ := time.Parse("2006-01-02", quote.Date)
+	for _, kind := range entities.PricingRuleKinds {
+		candidates := byKind[kind]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		best := bestRule(kind, candidates, slot, date, quote)
+		for _, rule := range candidates {
+			step := entitiesDtos.PriceStep{
+				RuleID: rule.ID,
+				Name:   rule.Name,
+				Kind:   rule.Kind,
+				Before: price,
+				After:  price,
+			}
+
+			reason := ruleMismatch(rule, slot, date, quote)
+			switch {
+			case reason != "":
+				step.Reason = reason
+			case best != nil && best.ID != rule.ID:
+				step.Reason = fmt.Sprintf("%q applied instead", best.Name)
+			default:
+				price = applyRule(rule, price)
+				step.Applied = true
+				step.Reason = ruleEffect(rule)
+				step.After = price
+			}
+			quote.Trace = append(quote.Trace, step)
+		}
+	}
+
+	quote.Price = price
+}
+
+// bestRule picks the single rule that applies for kinds where only one may, or nil for kinds that stack.
+func bestRule(kind entities.PricingRuleKind, rules []entities.PricingRule, slot *entities.Slot, date time.Time, quote *entitiesDtos.PriceQuote) *entities.PricingRule {
+	var best *entities.PricingRule
+	better := func(rule *entities.PricingRule) bool {
+		switch kind {
+		case entities.RuleBase:
+			return ruleSpecificity(rule) > ruleSpecificity(best)
+		case entities.RuleEarlyBird:
+			return rule.DaysBefore > best.DaysBefore
+		case entities.RuleLastMinute:
+			return rule.DaysBefore < best.DaysBefore
+		case entities.RuleDemand:
+			return rule.OccupancyThreshold > best.OccupancyThreshold
+		}
+		return false
+	}
+
+	switch kind {
+	case entities.RuleWeekday, entities.RuleHoliday:
+		return nil
+	}
+	for i := range rules {
+		if ruleMismatch(rules[i], slot, date, quote) != "" {
+			continue
+		}
+		// Rules arrive in priority order, so on a tie the earlier one stays
+		if best == nil || better(&rules[i]) {
+			best = &rules[i]
+		}
+	}
+	return best
+}
+
+// ruleMismatch explains why a rule does not apply, or returns "" when it does.
+func ruleMismatch(rule entities.PricingRule, slot *entities.Slot, date time.Time, quote *entitiesDtos.PriceQuote) string {
+	if rule.Zone != "" && rule.Zone != slot.Zone {
+		return fmt.Sprintf("zone %s only", rule.Zone)
+	}
+	if rule.Category != "" && rule.Category != slot.Category {
+		return fmt.Sprintf("%s stalls only", rule.Category)
+	}
+
+	switch rule.Kind {
+	case entities.RuleWeekday:
+		for _, day := range rule.Weekdays {
+			if time.Weekday(day) == date.Weekday() {
+				return ""
+			}
+		}
+		return fmt.Sprintf("not on a %s", date.Weekday())
+	case entities.RuleHoliday:
+		if quote.Holiday == "" {
+			return "not a holiday"
+		}
+	case entities.RuleEarlyBird:
+		if quote.LeadDays < rule.DaysBefore {
+			return fmt.Sprintf("booked %d days ahead, needs at least %d", quote.LeadDays, rule.DaysBefore)
+		}
+	case entities.RuleLastMinute:
+		if quote.LeadDays > rule.DaysBefore {
+			return fmt.Sprintf("booked %d days ahead, applies from %d days out", quote.LeadDays, rule.DaysBefore)
+		}
+	case entities.RuleDemand:
+		if quote.Occupancy < rule.OccupancyThreshold {
+			return fmt.Sprintf("occupancy %.0f%% is below %.0f%%", quote.Occupancy*100, rule.OccupancyThreshold*100)
+		}
+	}
+	return ""
+}
+
+func applyRule(rule entities.PricingRule, price float64) float64 {
+	if rule.Kind == entities.RuleBase {
+		return entities.RoundMoney(rule.Price)
+	}
+	return entities.RoundMoney(math.Max(price*rule.Multiplier+rule.Amount, 0))
+}
+
+func ruleEffect(rule entities.PricingRule) string {
+	if rule.Kind == entities.RuleBase {
+		return fmt.Sprintf("base price %.2f", rule.Price)
+	}
+
+	effect := ""
+	if rule.Multiplier != 1 {
+		effect = fmt.Sprintf("x%g", rule.Multiplier)
+	}
+	if rule.Amount != 0 {
+		if effect != "" {
+			effect += " "
+		}
+		effect += fmt.Sprintf("%+.2f", rule.Amount)
+	}
+	if effect == "" {
+		effect = "no change"
+	}
+	return effect
+}
+
+func ruleSpecificity(rule *entities.PricingRule) int {
+	if rule == nil {
+		return -1
+	}
+	specificity := 0
+	if rule.Zone != "" {
+		specificity++
+	}
+	if rule.Category != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// BestCombo finds the combo price for a group of stalls: one made for exactly that many stalls, preferring a
+// combo for the group's zone over a market-wide one. Zone combos only fit groups that stay inside the zone.
+func (s *PricingService) BestCombo(slots []*entities.Slot, combos []entities.ComboPrice) *entities.ComboPrice {
+	zone := ""
+	for i, slot := range slots {
+		if i == 0 {
+			zone = slot.Zone
+		} else if slot.Zone != zone {
+			zone = ""
+			break
+		}
+	}
+
+	var best *entities.ComboPrice
+	for i := range combos {
+		combo := &combos[i]
+		if combo.Stalls != len(slots) || (combo.Zone != "" && combo.Zone != zone) {
+			continue
+		}
+		// Combos arrive oldest first, so on a tie the earlier one stays
+		if best == nil || (best.Zone == "" && combo.Zone != "") {
+			best = combo
+		}
+	}
+	return best
+}
@@ -0,0 +1,267 @@
+package Services
+
+import (
+	"testing"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+// traceStep is the part of a PriceStep the tests check. An empty reason is not compared.
+type traceStep struct {
+	name    string
+	applied bool
+	after   float64
+	reason  string
+}
+
+func adjust(id, name string, kind entities.PricingRuleKind, multiplier, amount float64) entities.PricingRule {
+	return entities.PricingRule{ID: id, Name: name, Kind: kind, Multiplier: multiplier, Amount: amount}
+}
+
+func TestPricingEvaluateTrace(t *testing.T) {
+	slot := &entities.Slot{Price: 100, Zone: "A", Category: entities.CategoryFood}
+	weekend := adjust("weekend", "Weekend", entities.RuleWeekday, 1.2, 0)
+	weekend.Weekdays = []int{0, 6}
+	saturday := adjust("saturday", "Saturday", entities.RuleWeekday, 1, 50)
+	saturday.Weekdays = []int{6}
+	monday := adjust("monday", "Monday", entities.RuleWeekday, 0.5, 0)
+	monday.Weekdays = []int{1}
+	early7 := adjust("early7", "Week ahead", entities.RuleEarlyBird, 0.9, 0)
+	early7.DaysBefore = 7
+	early30 := adjust("early30", "Month ahead", entities.RuleEarlyBird, 0.8, 0)
+	early30.DaysBefore = 30
+	last3 := adjust("last3", "Three days out", entities.RuleLastMinute, 0.7, 0)
+	last3.DaysBefore = 3
+	last1 := adjust("last1", "Day before", entities.RuleLastMinute, 0.5, 0)
+	last1.DaysBefore = 1
+	busy := adjust("busy", "Busy", entities.RuleDemand, 1.1, 0)
+	busy.OccupancyThreshold = 0.5
+	packed := adjust("packed", "Packed", entities.RuleDemand, 1.25, 0)
+	packed.OccupancyThreshold = 0.8
+
+	tests := []struct {
+		name      string
+		rules     []entities.PricingRule
+		quote     entitiesDtos.PriceQuote
+		wantPrice float64
+		wantTrace []traceStep
+	}{
+		{
+			name:      "no rules keeps the slot price",
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01"},
+			wantPrice: 100,
+			wantTrace: []traceStep{{name: "Slot price", applied: true, after: 100}},
+		},
+		{
+			name: "most specific base rule wins",
+			rules: []entities.PricingRule{
+				{ID: "all", Name: "Everywhere", Kind: entities.RuleBase, Price: 300},
+				{ID: "zone", Name: "Zone A", Kind: entities.RuleBase, Zone: "A", Price: 400},
+				{ID: "food", Name: "Zone A food", Kind: entities.RuleBase, Zone: "A", Category: entities.CategoryFood, Price: 450},
+				{ID: "zoneB", Name: "Zone B", Kind: entities.RuleBase, Zone: "B", Price: 999},
+			},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01"},
+			wantPrice: 450,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Everywhere", after: 100, reason: `"Zone A food" applied instead`},
+				{name: "Zone A", after: 100, reason: `"Zone A food" applied instead`},
+				{name: "Zone A food", applied: true, after: 450, reason: "base price 450.00"},
+				{name: "Zone B", after: 450, reason: "zone B only"},
+			},
+		},
+		{
+			name: "equally specific base rules keep priority order",
+			rules: []entities.PricingRule{
+				{ID: "first", Name: "First", Kind: entities.RuleBase, Zone: "A", Price: 200},
+				{ID: "second", Name: "Second", Kind: entities.RuleBase, Category: entities.CategoryFood, Price: 250},
+			},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01"},
+			wantPrice: 200,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "First", applied: true, after: 200},
+				{name: "Second", after: 200, reason: `"First" applied instead`},
+			},
+		},
+		{
+			name:      "weekday rules stack in order",
+			rules:     []entities.PricingRule{weekend, saturday, monday},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01"}, // A Saturday
+			wantPrice: 170,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Weekend", applied: true, after: 120, reason: "x1.2"},
+				{name: "Saturday", applied: true, after: 170, reason: "+50.00"},
+				{name: "Monday", after: 170, reason: "not on a Saturday"},
+			},
+		},
+		{
+			name:      "holiday rule needs a holiday",
+			rules:     []entities.PricingRule{adjust("songkran", "Songkran", entities.RuleHoliday, 1.5, 0)},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-04-15"},
+			wantPrice: 100,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Songkran", after: 100, reason: "not a holiday"},
+			},
+		},
+		{
+			name:      "holiday rule on a holiday",
+			rules:     []entities.PricingRule{adjust("songkran", "Songkran", entities.RuleHoliday, 1.5, 0)},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-04-15", Holiday: "Songkran"},
+			wantPrice: 150,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Songkran", applied: true, after: 150},
+			},
+		},
+		{
+			name:      "furthest early bird tier applies",
+			rules:     []entities.PricingRule{early7, early30},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01", LeadDays: 40},
+			wantPrice: 80,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Week ahead", after: 100, reason: `"Month ahead" applied instead`},
+				{name: "Month ahead", applied: true, after: 80},
+			},
+		},
+		{
+			name:      "early bird tier out of reach",
+			rules:     []entities.PricingRule{early7, early30},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01", LeadDays: 10},
+			wantPrice: 90,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Week ahead", applied: true, after: 90},
+				{name: "Month ahead", after: 90, reason: "booked 10 days ahead, needs at least 30"},
+			},
+		},
+		{
+			name:      "closest last minute tier applies",
+			rules:     []entities.PricingRule{last3, last1},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01", LeadDays: 1},
+			wantPrice: 50,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Three days out", after: 100, reason: `"Day before" applied instead`},
+				{name: "Day before", applied: true, after: 50},
+			},
+		},
+		{
+			name:      "highest demand tier applies",
+			rules:     []entities.PricingRule{busy, packed},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01", Occupancy: 0.9},
+			wantPrice: 125,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Busy", after: 100, reason: `"Packed" applied instead`},
+				{name: "Packed", applied: true, after: 125},
+			},
+		},
+		{
+			name:      "demand below every threshold",
+			rules:     []entities.PricingRule{busy},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01", Occupancy: 0.25},
+			wantPrice: 100,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Busy", after: 100, reason: "occupancy 25% is below 50%"},
+			},
+		},
+		{
+			name: "kinds apply in engine order whatever the rule order",
+			rules: []entities.PricingRule{
+				packed,
+				weekend,
+				{ID: "base", Name: "Base", Kind: entities.RuleBase, Price: 200},
+			},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-02", Occupancy: 0.8}, // A Sunday
+			wantPrice: 300,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Base", applied: true, after: 200},
+				{name: "Weekend", applied: true, after: 240},
+				{name: "Packed", applied: true, after: 300},
+			},
+		},
+		{
+			name:      "price never goes below zero",
+			rules:     []entities.PricingRule{adjust("free", "Giveaway", entities.RuleHoliday, 1, -500)},
+			quote:     entitiesDtos.PriceQuote{Date: "2024-06-01", Holiday: "Open day"},
+			wantPrice: 0,
+			wantTrace: []traceStep{
+				{name: "Slot price", applied: true, after: 100},
+				{name: "Giveaway", applied: true, after: 0, reason: "-500.00"},
+			},
+		},
+	}
+
+	s := NewPricingService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := tt.quote
+			s.Evaluate(slot, tt.rules, &quote)
+
+			if quote.Price != tt.wantPrice {
+				t.Errorf("price = %.2f, want %.2f", quote.Price, tt.wantPrice)
+			}
+			if len(quote.Trace) != len(tt.wantTrace) {
+				t.Fatalf("trace has %d steps, want %d: %+v", len(quote.Trace), len(tt.wantTrace), quote.Trace)
+			}
+			for i, want := range tt.wantTrace {
+				got := quote.Trace[i]
+				if got.Name != want.name || got.Applied != want.applied || got.After != want.after {
+					t.Errorf("step %d = %s applied=%v after=%.2f, want %s applied=%v after=%.2f",
+						i, got.Name, got.Applied, got.After, want.name, want.applied, want.after)
+				}
+				if want.reason != "" && got.Reason != want.reason {
+					t.Errorf("step %d (%s) reason = %q, want %q", i, got.Name, got.Reason, want.reason)
+				}
+				if i > 0 && got.Before != quote.Trace[i-1].After {
+					t.Errorf("step %d (%s) starts at %.2f, previous step ended at %.2f", i, got.Name, got.Before, quote.Trace[i-1].After)
+				}
+			}
+		})
+	}
+}
+
+func TestPricingBestCombo(t *testing.T) {
+	combos := []entities.ComboPrice{
+		{ID: "any2", Stalls: 2, Price: 180},
+		{ID: "a2", Zone: "A", Stalls: 2, Price: 150},
+		{ID: "any3", Stalls: 3, Price: 250},
+		{ID: "any2-later", Stalls: 2, Price: 170},
+	}
+
+	tests := []struct {
+		name  string
+		zones []string
+		want  string
+	}{
+		{name: "zone combo preferred", zones: []string{"A", "A"}, want: "a2"},
+		{name: "mixed zones take the market-wide combo", zones: []string{"A", "B"}, want: "any2"},
+		{name: "other zone takes the market-wide combo", zones: []string{"B", "B"}, want: "any2"},
+		{name: "matched by stall count", zones: []string{"B", "B", "B"}, want: "any3"},
+		{name: "no combo for the size", zones: []string{"A", "A", "A", "A"}, want: ""},
+	}
+
+	s := NewPricingService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := make([]*entities.Slot, len(tt.zones))
+			for i, zone := range tt.zones {
+				slots[i] = &entities.Slot{Zone: zone}
+			}
+
+			got := ""
+			if combo := s.BestCombo(slots, combos); combo != nil {
+				got = combo.ID
+			}
+			if got != tt.want {
+				t.Errorf("BestCombo() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package Services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// s3MaxExpiry is the longest a presigned S3 URL may last.
+const s3MaxExpiry = 7 * 24 * time.Hour
+
+// S3Storage keeps files in an S3-compatible bucket. Requests are signed with AWS Signature Version 4, which
+// AWS S3, MinIO and most other object stores accept.
+type S3Storage struct {
+	endpoint  *url.URL
+	public    *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	ttl       time.Duration
+	client    *http.Client
+}
+
+// NewS3Storage reads MEDIA_S3_ENDPOINT (e.g. http://localhost:9000 for MinIO), MEDIA_S3_REGION (default
+// us-east-1), MEDIA_S3_BUCKET, MEDIA_S3_ACCESS_KEY, MEDIA_S3_SECRET_KEY and MEDIA_S3_PATH_STYLE, which must
+// be true for MinIO (the default) and false for virtual-hosted buckets. MEDIA_S3_PUBLIC_URL is the
+// endpoint as clients reach it, when that differs from the API's, e.g. http://localhost:9000 beside
+// http://minio:9000 inside Docker.
+func NewS3Storage(ttl time.Duration) (*S3Storage, error) {
+	endpoint, err := parseS3Endpoint(os.Getenv("MEDIA_S3_ENDPOINT"))
+	if err != nil {
+		return nil, fmt.Errorf("MEDIA_S3_ENDPOINT %v", err)
+	}
+	public := endpoint
+	if raw := os.Getenv("MEDIA_S3_PUBLIC_URL"); raw != "" {
+		if public, err = parseS3Endpoint(raw); err != nil {
+			return nil, fmt.Errorf("MEDIA_S3_PUBLIC_URL %v", err)
+		}
+	}
+	s := &S3Storage{
+		endpoint:  endpoint,
+		public:    public,
+		region:    os.Getenv("MEDIA_S3_REGION"),
+		bucket:    os.Getenv("MEDIA_S3_BUCKET"),
+		accessKey: os.Getenv("MEDIA_S3_ACCESS_KEY"),
+		secretKey: os.Getenv("MEDIA_S3_SECRET_KEY"),
+		pathStyle: os.Getenv("MEDIA_S3_PATH_STYLE") != "false",
+		ttl:       min(ttl, s3MaxExpiry),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	if s.bucket == "" || s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("MEDIA_S3_BUCKET, MEDIA_S3_ACCESS_KEY and MEDIA_S3_SECRET_KEY must be set")
+	}
+	return s, nil
+}
+
+func (s *S3Storage) Put(key, contentType string, data []byte) error {
+	return s.do(http.MethodPut, key, contentType, data)
+}
+
+// Delete removes a file; S3 reports success for keys that do not exist.
+func (s *S3Storage) Delete(key string) error {
+	return s.do(http.MethodDelete, key, "", nil)
+}
+
+// SignedURL presigns a GET for the file. The query carries the signature, so no headers are needed.
+func (s *S3Storage) SignedURL(key string) (string, error) {
+	return s.presign(key, signingTime(time.Now().UTC(), s.ttl)), nil
+}
+
+func (s *S3Storage) presign(key string, signedAt time.Time) string {
+	u := s.objectURL(s.public, key)
+	amzDate := signedAt.Format("20060102T150405Z")
+	scope := s.scope(signedAt)
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    s.accessKey + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(s.ttl.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	canonicalQuery := canonicalS3Query(query)
+	request := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(signedAt, amzDate, scope, request)
+	return u.String()
+}
+
+// do sends a signed request for one object.
+func (s *S3Storage) do(method, key, contentType string, body []byte) error {
+	u := s.objectURL(s.endpoint, key)
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+	payloadHash := sha256Hex(body)
+
+	headers := map[string]string{
+		"host":                 u.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType != "" {
+		headers["content-type"] = contentType
+	}
+	canonicalHeaders, signedHeaders := canonicalS3Headers(headers)
+
+	request := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	signature := s.signature(now, amzDate, scope, request)
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		if name != "host" {
+			req.Header.Set(name, value)
+		}
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("media storage request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("media storage returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// objectURL is the address of a key, with the bucket in the path or the host name.
+func (s *S3Storage) objectURL(endpoint *url.URL, key string) *url.URL {
+	u := *endpoint
+	path := "/" + key
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + path
+	u.RawPath = escapeS3(u.Path, true)
+	return &u
+}
+
+func parseS3Endpoint(raw string) (*url.URL, error) {
+	endpoint, err := url.Parse(raw)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("must be an http(s) URL")
+	}
+	return endpoint, nil
+}
+
+func (s *S3Storage) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request with a key derived from the secret, the date and the region.
+func (s *S3Storage) signature(t time.Time, amzDate, scope, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalS3Headers lists lower-case headers in name order, one "name:value" per line, and returns them with
+// the matching SignedHeaders value.
+func canonicalS3Headers(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// canonicalS3Query sorts and escapes query parameters the way Signature Version 4 expects.
+func canonicalS3Query(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = escapeS3(name, false) + "=" + escapeS3(params[name], false)
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapeS3 percent-encodes everything except the unreserved characters, and slashes when keepSlash is set.
+func escapeS3(s string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', keepSlash && c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
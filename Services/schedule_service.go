@@ -0,0 +1,173 @@
+package Services
+
+import (
+	"fmt"
+	"github.com/go-co-op/gocron"
+	"log"
+	"os"
+	"strconv"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+// defaultGenerationWeeks is used when neither the schedule nor SLOT_GENERATION_WEEKS says how far ahead to go.
+const defaultGenerationWeeks = 4
+
+type ScheduleService struct {
+	repo      Interfaces.ISchedule
+	templates contact.ILayoutTemplateUseCase
+	scheduler *gocron.Scheduler
+	location  *time.Location
+}
+
+func NewScheduleService(repo Interfaces.ISchedule, templates contact.ILayoutTemplateUseCase) *ScheduleService {
+	location, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		location = time.UTC
+	}
+
+	service := &ScheduleService{
+		repo:      repo,
+		templates: templates,
+		scheduler: gocron.NewScheduler(location),
+		location:  location,
+	}
+
+	service.startScheduler()
+	return service
+}
+
+func (s *ScheduleService) startScheduler() {
+	// Roll every market's slots forward once a night, before vendors start booking
+	_, err := s.scheduler.Every(1).Day().At("02:00").Do(func() {
+		if err := s.GenerateAll(); err != nil {
+			log.Printf("Scheduled slot generation failed: %v", err)
+		}
+	})
+
+	if err != nil {
+		log.Printf("Failed to schedule slot generation: %v", err)
+	}
+
+	s.scheduler.StartAsync()
+}
+
+func (s *ScheduleService) GenerateAll() error {
+	schedules, err := s.repo.GetActiveSchedules()
+	if err != nil {
+		return fmt.Errorf("failed to get schedules: %v", err)
+	}
+
+	for i := range schedules {
+		if _, err := s.Generate(&schedules[i]); err != nil {
+			log.Printf("Failed to generate slots for market %s: %v", schedules[i].MarketID, err)
+		}
+	}
+
+	return nil
+}
+
+// Generate stamps the market's default layout onto every open date in the horizon and clears unused slots
+// from dates that have since been closed. Slots with bookings are left as they are.
+func (s *ScheduleService) Generate(schedule *entities.MarketSchedule) (*entitiesDtos.TemplateApplyResponse, error) {
+	from := s.Today()
+	to := from.AddDate(0, 0, 7*s.WeeksAhead(schedule)-1)
+
+	open, closed, err := s.ScheduleDates(schedule, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entitiesDtos.TemplateApplyResponse{
+		TemplateID: schedule.TemplateID,
+		Dates:      open,
+		Created:    []string{},
+		Updated:    []string{},
+		Skipped:    []string{},
+	}
+
+	if len(open) > 0 {
+		stamped, errRes := s.templates.StampTemplate(schedule.TemplateID, open)
+		if errRes != nil {
+			return nil, fmt.Errorf("%s", errRes.Message)
+		}
+		result = stamped
+	}
+
+	if len(closed) > 0 {
+		removed, errRes := s.templates.UnstampTemplate(schedule.TemplateID, closed)
+		if errRes != nil {
+			return nil, fmt.Errorf("%s", errRes.Message)
+		}
+		result.Removed = removed
+	}
+
+	if err := s.repo.MarkGenerated(schedule.ID, time.Now()); err != nil {
+		log.Printf("Warning: failed to record generation time for schedule %s: %v", schedule.ID, err)
+	}
+
+	return result, nil
+}
+
+// ScheduleDates splits [from, to] into the dates the market opens and the dates the rule would open but an
+// exception or holiday closes.
+func (s *ScheduleService) ScheduleDates(schedule *entities.MarketSchedule, from, to time.Time) ([]string, []string, error) {
+	fromDate, toDate := from.Format("2006-01-02"), to.Format("2006-01-02")
+
+	holidays := make(map[string]bool)
+	if schedule.SkipHolidays {
+		list, err := s.repo.GetHolidays(fromDate, toDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get holidays: %v", err)
+		}
+		for _, holiday := range list {
+			holidays[holiday.Date] = true
+		}
+	}
+
+	exceptions := make(map[string]entities.ExceptionKind, len(schedule.Exceptions))
+	for _, exception := range schedule.Exceptions {
+		exceptions[exception.Date] = exception.Kind
+	}
+
+	open, closed := make([]string, 0), make([]string, 0)
+	start, _ := time.Parse("2006-01-02", fromDate)
+	end, _ := time.Parse("2006-01-02", toDate)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		occurs := schedule.OccursOn(day)
+
+		switch {
+		case exceptions[date] == entities.ExceptionOpen:
+			open = append(open, date)
+		case !occurs:
+			// Not a market day, nothing to open or close
+		case exceptions[date] == entities.ExceptionClosed || holidays[date]:
+			closed = append(closed, date)
+		default:
+			open = append(open, date)
+		}
+	}
+
+	return open, closed, nil
+}
+
+// Today is the current market date as a plain calendar day.
+func (s *ScheduleService) Today() time.Time {
+	now := time.Now().In(s.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WeeksAhead is how many weeks of slots a schedule keeps generated.
+func (s *ScheduleService) WeeksAhead(schedule *entities.MarketSchedule) int {
+	if schedule.WeeksAhead > 0 {
+		return schedule.WeeksAhead
+	}
+	if weeks, err := strconv.Atoi(os.Getenv("SLOT_GENERATION_WEEKS")); err == nil && weeks > 0 {
+		return weeks
+	}
+	return defaultGenerationWeeks
+}
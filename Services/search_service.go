@@ -0,0 +1,100 @@
+package Services
+
+import (
+	"math"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+const earthRadiusKm = 6371.0
+
+type SearchService struct{}
+
+func NewSearchService() *SearchService {
+	return &SearchService{}
+}
+
+// DistanceKm is the great-circle distance between two points given in degrees.
+func (s *SearchService) DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox returns the latitude and longitude ranges that hold every point within radiusKm of a point, for
+// narrowing a distance query before measuring. When the longitude range crosses the antimeridian minLng is
+// above maxLng; near the poles it is the whole circle.
+func (s *SearchService) BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	angle := radiusKm / earthRadiusKm
+	dLat := angle * 180 / math.Pi
+	minLat, maxLat = math.Max(-90, lat-dLat), math.Min(90, lat+dLat)
+
+	sinLng := math.Sin(angle) / math.Cos(lat*math.Pi/180)
+	if minLat == -90 || maxLat == 90 || angle >= math.Pi/2 || sinLng >= 1 {
+		return minLat, maxLat, -180, 180
+	}
+	dLng := math.Asin(sinLng) * 180 / math.Pi
+	wrap := func(deg float64) float64 {
+		switch {
+		case deg < -180:
+			return deg + 360
+		case deg > 180:
+			return deg - 360
+		}
+		return deg
+	}
+	return minLat, maxLat, wrap(lng - dLng), wrap(lng + dLng)
+}
+
+// GroupByMarket puts search hits under their market, keeping the markets in the order given and leaving out any
+// without a hit. Slots arrive cheapest first, so each market's first slot is its lowest price.
+func (s *SearchService) GroupByMarket(marketIDs []string, slots []*entities.Slot, markets map[string]entities.Market, distances map[string]float64) []entitiesDtos.MarketSlots {
+	bySlot := make(map[string][]*entities.Slot)
+	for _, slot := range slots {
+		bySlot[slot.MarketID] = append(bySlot[slot.MarketID], slot)
+	}
+
+	groups := make([]entitiesDtos.MarketSlots, 0, len(marketIDs))
+	for _, marketID := range marketIDs {
+		hits := bySlot[marketID]
+		if len(hits) == 0 {
+			continue
+		}
+		market := markets[marketID]
+		group := entitiesDtos.MarketSlots{
+			MarketID:    marketID,
+			Name:        market.Name,
+			Address:     market.Address,
+			Image:       market.ListImage(),
+			LowestPrice: hits[0].Price,
+			Available:   len(hits),
+			Slots:       make([]entitiesDtos.SearchSlot, 0, len(hits)),
+		}
+		if distance, ok := distances[marketID]; ok {
+			distance = math.Round(distance*100) / 100
+			group.DistanceKm = &distance
+		}
+
+		for _, slot := range hits {
+			date := slot.Date
+			if len(date) > 10 {
+				date = date[:10]
+			}
+			group.Slots = append(group.Slots, entitiesDtos.SearchSlot{
+				ID:       slot.ID,
+				Name:     slot.Name,
+				Zone:     slot.Zone,
+				Category: slot.Category,
+				Date:     date,
+				Width:    slot.Width,
+				Height:   slot.Height,
+				Price:    slot.Price,
+			})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
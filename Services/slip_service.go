@@ -0,0 +1,148 @@
+package Services
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"strconv"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+// thaiBanks maps the bank codes used in slip mini-QRs to display names.
+var thaiBanks = map[string]string{
+	"002": "Bangkok Bank",
+	"004": "Kasikornbank",
+	"006": "Krungthai Bank",
+	"011": "TMBThanachart Bank",
+	"014": "Siam Commercial Bank",
+	"022": "CIMB Thai",
+	"024": "UOB Thailand",
+	"025": "Bank of Ayudhya",
+	"030": "Government Savings Bank",
+	"033": "Government Housing Bank",
+	"034": "BAAC",
+	"066": "Islamic Bank of Thailand",
+	"067": "TISCO Bank",
+	"069": "Kiatnakin Phatra Bank",
+	"073": "LH Bank",
+}
+
+type SlipService struct {
+	reader gozxing.Reader
+}
+
+func NewSlipService() *SlipService {
+	return &SlipService{reader: qrcode.NewQRCodeReader()}
+}
+
+// DecodeSlip finds the verification mini-QR in a slip screenshot and parses it without calling any bank API.
+func (s *SlipService) DecodeSlip(data []byte) (*entitiesDtos.SlipQRData, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %v", err)
+	}
+
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %v", err)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+	result, err := s.reader.Decode(bitmap, hints)
+	if err != nil {
+		return nil, fmt.Errorf("no QR code found on slip: %v", err)
+	}
+
+	return ParseSlipPayload(result.GetText())
+}
+
+// ParseSlipPayload reads the EMV-style TLV text of a slip mini-QR.
+// Tag 00 holds the API ID (00), sending bank (01) and transaction reference (02); tag 54 carries the amount when the bank includes it.
+func ParseSlipPayload(payload string) (*entitiesDtos.SlipQRData, error) {
+	payload = strings.TrimSpace(payload)
+	tags, err := parseTLV(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if crc, ok := tags["91"]; ok {
+		body := payload[:len(payload)-len(crc)]
+		if expected := fmt.Sprintf("%04X", crc16(body)); !strings.EqualFold(crc, expected) {
+			return nil, fmt.Errorf("slip QR checksum mismatch")
+		}
+	}
+
+	container, ok := tags["00"]
+	if !ok {
+		return nil, fmt.Errorf("not a bank slip QR")
+	}
+	fields, err := parseTLV(container)
+	if err != nil {
+		return nil, err
+	}
+
+	slip := &entitiesDtos.SlipQRData{
+		BankCode: fields["01"],
+		TransRef: fields["02"],
+		Country:  tags["51"],
+	}
+	if slip.TransRef == "" {
+		return nil, fmt.Errorf("slip QR has no transaction reference")
+	}
+	slip.BankName = thaiBanks[slip.BankCode]
+
+	if raw, ok := tags["54"]; ok {
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount in slip QR: %v", err)
+		}
+		slip.Amount = entities.RoundMoney(amount)
+		slip.HasAmount = true
+	}
+
+	return slip, nil
+}
+
+func parseTLV(data string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for i := 0; i < len(data); {
+		if i+4 > len(data) {
+			return nil, fmt.Errorf("truncated QR field at position %d", i)
+		}
+		tag := data[i : i+2]
+		length, err := strconv.Atoi(data[i+2 : i+4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid length for QR tag %s", tag)
+		}
+		if i+4+length > len(data) {
+			return nil, fmt.Errorf("QR tag %s overruns payload", tag)
+		}
+		tags[tag] = data[i+4 : i+4+length]
+		i += 4 + length
+	}
+	return tags, nil
+}
+
+// crc16 is CRC-16/CCITT-FALSE, the checksum EMV QR payloads end with.
+func crc16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
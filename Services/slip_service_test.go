@@ -0,0 +1,162 @@
+package Services
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+func tlv(tag, value string) string {
+	return fmt.Sprintf("%s%02d%s", tag, len(value), value)
+}
+
+// withCRC ends a payload with tag 91 holding its checksum, the way bank apps print it.
+func withCRC(body string) string {
+	body += "9104"
+	return body + fmt.Sprintf("%04X", crc16(body))
+}
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint16
+	}{
+		{in: "", want: 0xFFFF},
+		{in: "123456789", want: 0x29B1}, // The CRC-16/CCITT-FALSE check value
+		{in: "A", want: 0xB915},
+	}
+
+	for _, tt := range tests {
+		if got := crc16(tt.in); got != tt.want {
+			t.Errorf("crc16(%q) = %04X, want %04X", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "empty", in: "", want: map[string]string{}},
+		{name: "two tags", in: "0003abc5102TH", want: map[string]string{"00": "abc", "51": "TH"}},
+		{name: "empty value", in: "0000", want: map[string]string{"00": ""}},
+		{name: "truncated header", in: "0003abc51", wantErr: "truncated"},
+		{name: "length not a number", in: "00x3abc", wantErr: "invalid length"},
+		{name: "value overruns payload", in: "0010abc", wantErr: "overruns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTLV(tt.in)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseTLV(%q) error = %v, want one containing %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTLV(%q) error = %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTLV(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSlipPayload(t *testing.T) {
+	container := tlv("00", tlv("00", "000001")+tlv("01", "004")+tlv("02", "015123112233ABC1234"))
+	valid := withCRC(container + tlv("51", "TH"))
+	lowerCRC := valid[:len(valid)-4] + strings.ToLower(valid[len(valid)-4:])
+
+	tests := []struct {
+		name    string
+		payload string
+		want    *entitiesDtos.SlipQRData
+		wantErr string
+	}{
+		{
+			name:    "without amount",
+			payload: valid,
+			want:    &entitiesDtos.SlipQRData{TransRef: "015123112233ABC1234", BankCode: "004", BankName: "Kasikornbank", Country: "TH"},
+		},
+		{
+			name:    "with amount",
+			payload: withCRC(container + tlv("51", "TH") + tlv("54", "1250.50")),
+			want:    &entitiesDtos.SlipQRData{TransRef: "015123112233ABC1234", BankCode: "004", BankName: "Kasikornbank", Country: "TH", Amount: 1250.5, HasAmount: true},
+		},
+		{
+			name:    "lower-case checksum",
+			payload: lowerCRC,
+			want:    &entitiesDtos.SlipQRData{TransRef: "015123112233ABC1234", BankCode: "004", BankName: "Kasikornbank", Country: "TH"},
+		},
+		{
+			name:    "surrounding whitespace",
+			payload: " " + valid + "\n",
+			want:    &entitiesDtos.SlipQRData{TransRef: "015123112233ABC1234", BankCode: "004", BankName: "Kasikornbank", Country: "TH"},
+		},
+		{
+			name:    "no checksum",
+			payload: container,
+			want:    &entitiesDtos.SlipQRData{TransRef: "015123112233ABC1234", BankCode: "004", BankName: "Kasikornbank"},
+		},
+		{
+			name:    "unknown bank",
+			payload: withCRC(tlv("00", tlv("01", "999")+tlv("02", "REF1"))),
+			want:    &entitiesDtos.SlipQRData{TransRef: "REF1", BankCode: "999"},
+		},
+		{
+			name:    "checksum mismatch",
+			payload: valid[:len(valid)-4] + "0000",
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "tampered body",
+			payload: strings.Replace(valid, "ABC1234", "ABC1235", 1),
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "not a slip",
+			payload: withCRC(tlv("51", "TH")),
+			wantErr: "not a bank slip QR",
+		},
+		{
+			name:    "no transaction reference",
+			payload: withCRC(tlv("00", tlv("01", "004"))),
+			wantErr: "no transaction reference",
+		},
+		{
+			name:    "invalid amount",
+			payload: withCRC(container + tlv("54", "12,50")),
+			wantErr: "invalid amount",
+		},
+		{
+			name:    "broken container",
+			payload: withCRC(tlv("00", "0199")),
+			wantErr: "overruns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSlipPayload(tt.payload)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSlipPayload() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSlipPayload() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSlipPayload() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
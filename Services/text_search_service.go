@@ -0,0 +1,194 @@
+package Services
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// maxTermRunes caps how long an indexed term may be, so every term fits its column.
+const maxTermRunes = 16
+
+// TextSearchService turns text into search terms and marks where a query matched. Thai is written without
+// spaces between words, so Thai text is split into overlapping pairs of characters instead of words; other
+// scripts are split into words, indexed with their prefixes so a partly typed word still matches.
+type TextSearchService struct{}
+
+func NewTextSearchService() *TextSearchService {
+	return &TextSearchService{}
+}
+
+// WeightedText is one field of a document and how much a match in it counts.
+type WeightedText struct {
+	Text   string
+	Weight float64
+}
+
+// Terms splits a query into its distinct search terms, in order.
+func (s *TextSearchService) Terms(text string) []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, run := range textRuns(text) {
+		pieces := thaiPairs(run.runes)
+		if !run.thai {
+			pieces = []string{truncateTerm(run.runes)}
+		}
+		for _, piece := range pieces {
+			if !seen[piece] {
+				seen[piece] = true
+				terms = append(terms, piece)
+			}
+		}
+	}
+	return terms
+}
+
+// IndexTerms weighs every term of a document. A term counts once per field, so repeating a word does not
+// push a document up the results.
+func (s *TextSearchService) IndexTerms(fields ...WeightedText) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, field := range fields {
+		inField := make(map[string]bool)
+		for _, run := range textRuns(field.Text) {
+			pieces := thaiPairs(run.runes)
+			if !run.thai {
+				pieces = wordPrefixes(run.runes)
+			}
+			for _, piece := range pieces {
+				inField[piece] = true
+			}
+		}
+		for term := range inField {
+			weights[term] += field.Weight
+		}
+	}
+	return weights
+}
+
+// Highlight HTML-escapes text and wraps the parts that match the query terms in <mark>. Long text is cut
+// down to the part around the first match. It returns "" when nothing matches.
+func (s *TextSearchService) Highlight(text string, terms []string, maxRunes int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		needle := []rune(term)
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if string(lower[i:i+len(needle)]) != term {
+				continue
+			}
+			for j := i; j < i+len(needle); j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		start = first - maxRunes/3
+		if start < 0 {
+			start = 0
+		}
+		end = start + maxRunes
+		if end > len(runes) {
+			end, start = len(runes), len(runes)-maxRunes
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		part := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			part = "<mark>" + part + "</mark>"
+		}
+		b.WriteString(part)
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+type textRun struct {
+	runes []rune
+	thai  bool
+}
+
+// textRuns splits lower-cased text into unbroken runs of Thai and of other letters and digits.
+func textRuns(text string) []textRun {
+	runs := make([]textRun, 0)
+	var current []rune
+	currentThai := false
+	flush := func() {
+		if len(current) > 0 {
+			runs = append(runs, textRun{runes: current, thai: currentThai})
+		}
+		current = nil
+	}
+	for _, r := range strings.ToLower(text) {
+		thai := r >= 0x0E00 && r <= 0x0E7F
+		word := thai || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+		if !word {
+			flush()
+			continue
+		}
+		if len(current) > 0 && thai != currentThai {
+			flush()
+		}
+		currentThai = thai
+		current = append(current, r)
+	}
+	flush()
+	return runs
+}
+
+func thaiPairs(runes []rune) []string {
+	if len(runes) < 2 {
+		return []string{string(runes)}
+	}
+	pairs := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		pairs = append(pairs, string(runes[i:i+2]))
+	}
+	return pairs
+}
+
+func wordPrefixes(runes []rune) []string {
+	if len(runes) < 2 {
+		return []string{string(runes)}
+	}
+	if len(runes) > maxTermRunes {
+		runes = runes[:maxTermRunes]
+	}
+	prefixes := make([]string, 0, len(runes)-1)
+	for n := 2; n <= len(runes); n++ {
+		prefixes = append(prefixes, string(runes[:n]))
+	}
+	return prefixes
+}
+
+func truncateTerm(runes []rune) string {
+	if len(runes) > maxTermRunes {
+		runes = runes[:maxTermRunes]
+	}
+	return string(runes)
+}
@@ -0,0 +1,130 @@
+package Services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTextSearchTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "thai is split into pairs", in: "ตลาด", want: []string{"ตล", "ลา", "าด"}},
+		{name: "tone marks stay in their pair", in: "ผ้า", want: []string{"ผ้", "้า"}},
+		{name: "single thai character", in: "ก", want: []string{"ก"}},
+		{name: "repeated pairs are dropped", in: "ตลาดตลาด", want: []string{"ตล", "ลา", "าด", "ดต"}},
+		{name: "other scripts are split into lower-case words", in: "Night  Market!", want: []string{"night", "market"}},
+		{name: "mixed thai and latin", in: "ตลาดนัดRot-Fai", want: []string{"ตล", "ลา", "าด", "ดน", "นั", "ัด", "rot", "fai"}},
+		{name: "digits are words", in: "Soi 38", want: []string{"soi", "38"}},
+		{name: "long words are cut", in: "Supercalifragilisticexpialidocious", want: []string{"supercalifragili"}},
+		{name: "punctuation only", in: "?!", want: []string{}},
+	}
+
+	s := NewTextSearchService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Terms(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSearchIndexMatchesQueries(t *testing.T) {
+	s := NewTextSearchService()
+	index := s.IndexTerms(
+		WeightedText{Text: "ตลาดนัดรถไฟ Srinakarin", Weight: 3},
+		WeightedText{Text: "ของกินเล่น vintage clothes", Weight: 1},
+	)
+
+	tests := []struct {
+		query string
+		found bool
+	}{
+		{query: "รถไฟ", found: true},
+		{query: "ตลาดนัด", found: true},
+		{query: "ของกิน", found: true},
+		{query: "srinak", found: true}, // A partly typed word matches its prefix
+		{query: "Vintage Clothes", found: true},
+		{query: "ตลาดน้ำ", found: false},
+		{query: "clothing", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			found := true
+			for _, term := range s.Terms(tt.query) {
+				if index[term] == 0 {
+					found = false
+				}
+			}
+			if found != tt.found {
+				t.Errorf("query %q found = %v, want %v", tt.query, found, tt.found)
+			}
+		})
+	}
+}
+
+func TestTextSearchIndexWeights(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []WeightedText
+		want   map[string]float64
+	}{
+		{
+			name:   "prefixes of a word",
+			fields: []WeightedText{{Text: "Soup", Weight: 2}},
+			want:   map[string]float64{"so": 2, "sou": 2, "soup": 2},
+		},
+		{
+			name:   "repeats count once per field",
+			fields: []WeightedText{{Text: "ตลา ตลา", Weight: 1}},
+			want:   map[string]float64{"ตล": 1, "ลา": 1},
+		},
+		{
+			name:   "fields add up",
+			fields: []WeightedText{{Text: "Tea", Weight: 3}, {Text: "tea house", Weight: 1}},
+			want:   map[string]float64{"te": 4, "tea": 4, "ho": 1, "hou": 1, "hous": 1, "house": 1},
+		},
+	}
+
+	s := NewTextSearchService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IndexTerms(tt.fields...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IndexTerms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextSearchHighlight(t *testing.T) {
+	long := strings.Repeat("a ", 50) + "match" + strings.Repeat(" b", 50)
+
+	tests := []struct {
+		name     string
+		text     string
+		query    string
+		maxRunes int
+		want     string
+	}{
+		{name: "latin word", text: "Rot Fai Market", query: "market", want: "Rot Fai <mark>Market</mark>"},
+		{name: "thai pairs join up", text: "ตลาดนัดรถไฟ", query: "รถไฟ", want: "ตลาดนัด<mark>รถไฟ</mark>"},
+		{name: "text is escaped", text: "<b>Fish & Chips</b>", query: "fish", want: "&lt;b&gt;<mark>Fish</mark> &amp; Chips&lt;/b&gt;"},
+		{name: "no match", text: "Rot Fai Market", query: "ตลาด", want: ""},
+		{name: "long text is cut around the match", text: long, query: "match", maxRunes: 30, want: "…a a a a a <mark>match</mark> b b b b b b b …"},
+		{name: "cut stops at the end", text: long, query: "match", maxRunes: 300, want: strings.Repeat("a ", 50) + "<mark>match</mark>" + strings.Repeat(" b", 50)},
+	}
+
+	s := NewTextSearchService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Highlight(tt.text, s.Terms(tt.query), tt.maxRunes); got != tt.want {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package Services
+
+import (
+	"fmt"
+	"github.com/go-co-op/gocron"
+	"log"
+	"time"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+type WalletService struct {
+	repo      Interfaces.IWallet
+	ledger    contact.ILedgerUseCase
+	scheduler *gocron.Scheduler
+}
+
+func NewWalletService(repo Interfaces.IWallet, ledger contact.ILedgerUseCase) *WalletService {
+	scheduler := gocron.NewScheduler(time.UTC)
+	service := &WalletService{
+		repo:      repo,
+		ledger:    ledger,
+		scheduler: scheduler,
+	}
+
+	service.startScheduler()
+	return service
+}
+
+func (s *WalletService) startScheduler() {
+	// Expire unused credit every hour
+	_, err := s.scheduler.Every(1).Hour().Do(func() {
+		if err := s.ExpireCredits(); err != nil {
+			log.Printf("Scheduled wallet expiry failed: %v", err)
+		}
+	})
+
+	if err != nil {
+		log.Printf("Failed to schedule wallet expiry: %v", err)
+	}
+
+	s.scheduler.StartAsync()
+}
+
+// ExpireCredits zeroes every lot past its expiry date and hands the unused amount back to the issuer in the ledger.
+func (s *WalletService) ExpireCredits() error {
+	now := time.Now()
+	credits, err := s.repo.GetExpiredCredits(now)
+	if err != nil {
+		return fmt.Errorf("failed to get expired credits: %v", err)
+	}
+
+	for i := range credits {
+		expiry, err := s.repo.ExpireCredit(credits[i].ID, now)
+		if err != nil {
+			log.Printf("Failed to expire credit %s: %v", credits[i].ID, err)
+			continue
+		}
+		if expiry == nil {
+			continue
+		}
+
+		if errRes := s.ledger.RecordCreditExpired(&credits[i], expiry); errRes != nil {
+			log.Printf("Warning: failed to record credit expiry in ledger for credit %s: %v", credits[i].ID, errRes.Message)
+		}
+	}
+
+	return nil
+}
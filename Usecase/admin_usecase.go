@@ -0,0 +1,94 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"log"
+	"os"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+)
+
+type AdminUseCase struct {
+	repo Interfaces.IAdmin
+	hash Interfaces.IHashService
+}
+
+func NewAdminUseCase(repo Interfaces.IAdmin, hash Interfaces.IHashService) *AdminUseCase {
+	return &AdminUseCase{
+		repo: repo,
+		hash: hash,
+	}
+}
+
+// SeedAdmin creates the admin named by ADMIN_USERNAME, ADMIN_EMAIL and ADMIN_PASSWORD when it does not exist
+// yet. An existing admin is left as it is, so changing ADMIN_PASSWORD later does not reset its password.
+// Nothing is created when ADMIN_USERNAME is not set.
+func (uc *AdminUseCase) SeedAdmin() error {
+	username := strings.TrimSpace(os.Getenv("ADMIN_USERNAME"))
+	if username == "" {
+		return nil
+	}
+	if _, err := uc.repo.GetAdminByUsername(username); err == nil {
+		return nil
+	}
+
+	email := strings.TrimSpace(os.Getenv("ADMIN_EMAIL"))
+	password := os.Getenv("ADMIN_PASSWORD")
+	switch {
+	case email == "":
+		return fmt.Errorf("ADMIN_EMAIL is required to create admin %s", username)
+	case len(password) < 8:
+		return fmt.Errorf("ADMIN_PASSWORD must be at least 8 characters to create admin %s", username)
+	}
+
+	hashedPassword, err := uc.hash.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("error hashing admin password: %w", err)
+	}
+	admin := &entities.Admin{
+		ID:       uuid.NewString(),
+		Username: username,
+		Email:    email,
+		Password: hashedPassword,
+	}
+	if err := uc.repo.CreateAdmin(admin); err != nil {
+		return fmt.Errorf("failed to create admin %s: %w", username, err)
+	}
+	log.Printf("Created admin %s", username)
+	return nil
+}
+
+func (uc *AdminUseCase) Login(username, password string) (*entitiesDtos.AdminLoginResponse, *entitiesDtos.ErrorResponse) {
+	admin, err := uc.repo.GetAdminByUsername(strings.TrimSpace(username))
+	if err != nil || admin.Disabled || uc.hash.CompareHashAndPassword(admin.Password, password) != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    401,
+			Message: "Invalid username or password",
+		}
+	}
+
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["sub"] = admin.ID
+	claims["email"] = admin.Email
+	claims["exp"] = time.Now().Add(time.Hour * 12).Unix()
+	claims["role"] = "admin"
+	claims["iat"] = time.Now().Unix()
+
+	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to sign in: " + err.Error(),
+		}
+	}
+	return &entitiesDtos.AdminLoginResponse{
+		AccessToken: tokenString,
+		AdminID:     admin.ID,
+	}, nil
+}
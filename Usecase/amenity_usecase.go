@@ -0,0 +1,302 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+type AmenityUseCase struct {
+	repo Interfaces.IAmenity
+}
+
+var _ contact.IAmenityUseCase = (*AmenityUseCase)(nil)
+
+func NewAmenityUseCase(repo Interfaces.IAmenity) *AmenityUseCase {
+	return &AmenityUseCase{repo: repo}
+}
+
+func (uc *AmenityUseCase) CreateAmenity(providerID, marketID string, req *entitiesDtos.AmenityRequest) (*entities.Amenity, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "amenities"); errRes != nil {
+		return nil, errRes
+	}
+
+	amenity := &entities.Amenity{
+		ID:       uuid.New().String(),
+		MarketID: marketID,
+	}
+	if errRes := fillAmenity(amenity, req); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.checkNameFree(amenity); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.CreateAmenity(amenity); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create amenity: " + err.Error(),
+		}
+	}
+
+	return amenity, nil
+}
+
+func (uc *AmenityUseCase) UpdateAmenity(providerID, amenityID string, req *entitiesDtos.AmenityRequest) (*entities.Amenity, *entitiesDtos.ErrorResponse) {
+	amenity, errRes := uc.ownAmenity(providerID, amenityID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	kind := amenity.Kind
+	if errRes := fillAmenity(amenity, req); errRes != nil {
+		return nil, errRes
+	}
+	if kind == entities.AmenityFeature && amenity.Kind != kind {
+		if errRes := uc.checkUnassigned(amenity, "turned into an add-on"); errRes != nil {
+			return nil, errRes
+		}
+	}
+	if errRes := uc.checkNameFree(amenity); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.SaveAmenity(amenity); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to update amenity: " + err.Error(),
+		}
+	}
+
+	return amenity, nil
+}
+
+// DeleteAmenity removes an amenity no live slot has. Bookings keep the add-ons they were priced with.
+func (uc *AmenityUseCase) DeleteAmenity(providerID, amenityID string) *entitiesDtos.ErrorResponse {
+	amenity, errRes := uc.ownAmenity(providerID, amenityID)
+	if errRes != nil {
+		return errRes
+	}
+	if errRes := uc.checkUnassigned(amenity, "deleted"); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repo.DeleteAmenity(amenity.ID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete amenity: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (uc *AmenityUseCase) GetAmenities(marketID string) ([]entities.Amenity, *entitiesDtos.ErrorResponse) {
+	amenities, err := uc.repo.GetAmenities(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get amenities: " + err.Error(),
+		}
+	}
+
+	return amenities, nil
+}
+
+// PriceAddOns turns a vendor's add-on choices into priced lines at the market's current prices.
+func (uc *AmenityUseCase) PriceAddOns(marketID string, choices []entitiesDtos.AddOnChoice) ([]entities.AddOn, float64, *entitiesDtos.ErrorResponse) {
+	if len(choices) == 0 {
+		return nil, 0, nil
+	}
+
+	byID, errRes := uc.marketAmenities(marketID)
+	if errRes != nil {
+		return nil, 0, errRes
+	}
+
+	addOns := make([]entities.AddOn, 0, len(choices))
+	seen := make(map[string]bool, len(choices))
+	var total float64
+	for _, choice := range choices {
+		amenity, ok := byID[choice.AmenityID]
+		quantity := choice.Quantity
+		if quantity == 0 {
+			quantity = 1
+		}
+		message := ""
+		switch {
+		case !ok || amenity.Kind != entities.AmenityAddOn:
+			message = fmt.Sprintf("Add-on %s is not offered by this market", choice.AmenityID)
+		case seen[amenity.ID]:
+			message = fmt.Sprintf("Add-on %s is chosen more than once", amenity.Name)
+		case quantity < 0 || quantity > amenity.MaxQuantity:
+			message = fmt.Sprintf("Add-on %s can be taken 1 to %d times", amenity.Name, amenity.MaxQuantity)
+		}
+		if message != "" {
+			return nil, 0, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: message,
+			}
+		}
+		seen[amenity.ID] = true
+
+		line := entities.AddOn{
+			AmenityID: amenity.ID,
+			Name:      amenity.Name,
+			Quantity:  quantity,
+			UnitPrice: amenity.Price,
+			Total:     entities.RoundMoney(amenity.Price * float64(quantity)),
+		}
+		total = entities.RoundMoney(total + line.Total)
+		addOns = append(addOns, line)
+	}
+
+	return addOns, total, nil
+}
+
+// CheckSlotAmenities checks a slot's amenities are feature amenities of its market and drops repeats.
+func (uc *AmenityUseCase) CheckSlotAmenities(marketID string, amenityIDs []string) ([]string, *entitiesDtos.ErrorResponse) {
+	byID, errRes := uc.marketAmenities(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	checked := make([]string, 0, len(amenityIDs))
+	seen := make(map[string]bool, len(amenityIDs))
+	for _, id := range amenityIDs {
+		amenity, ok := byID[id]
+		if !ok || amenity.Kind != entities.AmenityFeature {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Amenity %s is not a feature amenity of this market", id),
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			checked = append(checked, id)
+		}
+	}
+
+	return checked, nil
+}
+
+// AmenityNames maps the market's amenity IDs to their names.
+func (uc *AmenityUseCase) AmenityNames(marketID string) (map[string]string, *entitiesDtos.ErrorResponse) {
+	byID, errRes := uc.marketAmenities(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	names := make(map[string]string, len(byID))
+	for id, amenity := range byID {
+		names[id] = amenity.Name
+	}
+	return names, nil
+}
+
+func (uc *AmenityUseCase) marketAmenities(marketID string) (map[string]entities.Amenity, *entitiesDtos.ErrorResponse) {
+	amenities, err := uc.repo.GetAmenities(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get amenities: " + err.Error(),
+		}
+	}
+
+	byID := make(map[string]entities.Amenity, len(amenities))
+	for _, amenity := range amenities {
+		byID[amenity.ID] = amenity
+	}
+	return byID, nil
+}
+
+func (uc *AmenityUseCase) checkUnassigned(amenity *entities.Amenity, action string) *entitiesDtos.ErrorResponse {
+	inUse, err := uc.repo.CountAmenitySlots(amenity.ID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check amenity slots: " + err.Error(),
+		}
+	}
+	if inUse > 0 {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Amenity %s is assigned to %d slots and cannot be %s; remove it from them first", amenity.Name, inUse, action),
+		}
+	}
+
+	return nil
+}
+
+func (uc *AmenityUseCase) checkNameFree(amenity *entities.Amenity) *entitiesDtos.ErrorResponse {
+	amenities, err := uc.repo.GetAmenities(amenity.MarketID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get amenities: " + err.Error(),
+		}
+	}
+	for _, other := range amenities {
+		if other.ID != amenity.ID && strings.EqualFold(other.Name, amenity.Name) {
+			return &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("The market already has an amenity named %s", other.Name),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *AmenityUseCase) ownAmenity(providerID, amenityID string) (*entities.Amenity, *entitiesDtos.ErrorResponse) {
+	amenity, err := uc.repo.GetAmenity(amenityID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get amenity: " + err.Error(),
+		}
+	}
+
+	if errRes := checkMarketOwner(uc.repo, providerID, amenity.MarketID, "amenities"); errRes != nil {
+		return nil, errRes
+	}
+
+	return amenity, nil
+}
+
+func fillAmenity(amenity *entities.Amenity, req *entitiesDtos.AmenityRequest) *entitiesDtos.ErrorResponse {
+	name := strings.TrimSpace(req.Name)
+	message := ""
+	switch {
+	case name == "":
+		message = "Amenity name is required"
+	case strings.Contains(name, ","):
+		message = "Amenity name cannot contain ','"
+	case req.Kind != entities.AmenityFeature && req.Kind != entities.AmenityAddOn:
+		message = "Amenity kind must be feature or addon"
+	case req.Price < 0 || req.MaxQuantity < 0:
+		message = "Amenity price and quantity cannot be negative"
+	case req.Kind == entities.AmenityFeature && (req.Price != 0 || req.MaxQuantity != 0):
+		message = "Only add-ons have a price and quantity"
+	}
+	if message != "" {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	amenity.Name = name
+	amenity.Kind = req.Kind
+	amenity.Description = req.Description
+	amenity.Price = entities.RoundMoney(req.Price)
+	amenity.MaxQuantity = req.MaxQuantity
+	if amenity.Kind == entities.AmenityAddOn && amenity.MaxQuantity == 0 {
+		amenity.MaxQuantity = 1
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+// rosterAnnouncementDays is how long vendors keep seeing roster announcements that are not about a market day.
+const rosterAnnouncementDays = 30
+
+type AnnouncementUseCase struct {
+	repo          Interfaces.IAnnouncement
+	notifications contact.INotificationUseCase
+}
+
+func NewAnnouncementUseCase(repo Interfaces.IAnnouncement, notifications contact.INotificationUseCase) *AnnouncementUseCase {
+	return &AnnouncementUseCase{
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+// SendAnnouncement stores an announcement and notifies every vendor in its audience: those booked on a market
+// day, in one of its zones or stall categories, or the market's whole approved roster.
+func (uc *AnnouncementUseCase) SendAnnouncement(providerID, sentBy, marketID string, req *entitiesDtos.AnnouncementRequest) (*entities.Announcement, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "announcements"); errRes != nil {
+		return nil, errRes
+	}
+	market, err := uc.repo.GetMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+	bad := func(message string) (*entities.Announcement, *entitiesDtos.ErrorResponse) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	announcement := &entities.Announcement{
+		ID:       uuid.New().String(),
+		MarketID: marketID,
+		Title:    strings.TrimSpace(req.Title),
+		Message:  strings.TrimSpace(req.Message),
+		Audience: entities.AnnouncementAudience(strings.ToLower(strings.TrimSpace(string(req.Audience)))),
+		SentBy:   sentBy,
+	}
+	switch {
+	case announcement.Title == "":
+		return bad("Title is required")
+	case utf8.RuneCountInString(announcement.Title) > 200:
+		return bad("Title can be at most 200 characters")
+	case announcement.Message == "":
+		return bad("Message is required")
+	case utf8.RuneCountInString(announcement.Message) > 2000:
+		return bad("Message can be at most 2000 characters")
+	}
+
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return bad("Invalid date format, use YYYY-MM-DD")
+		}
+		if date.Before(marketToday(marketLocation(market))) {
+			return bad("Announcements can only be about today or a later market day")
+		}
+		announcement.MarketDate = &date
+	}
+
+	switch announcement.Audience {
+	case entities.AudienceRoster:
+	case entities.AudienceDate, entities.AudienceZone, entities.AudienceCategory:
+		if announcement.MarketDate == nil {
+			return bad(fmt.Sprintf("A date is required for the %s audience", announcement.Audience))
+		}
+	default:
+		return bad("Audience must be date, zone, category or roster")
+	}
+
+	switch announcement.Audience {
+	case entities.AudienceZone:
+		if req.ZoneID == "" {
+			return bad("Zone ID is required for the zone audience")
+		}
+		zone, err := uc.repo.GetZone(req.ZoneID)
+		if err != nil || zone.MarketID != marketID {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    404,
+				Message: "Zone not found in this market",
+			}
+		}
+		announcement.ZoneID = zone.ID
+	case entities.AudienceCategory:
+		category, err := parseCategory(string(req.Category))
+		if err != nil {
+			return bad("Invalid category: " + err.Error())
+		}
+		announcement.Category = category
+	}
+
+	var vendorIDs []string
+	if announcement.Audience == entities.AudienceRoster {
+		vendorIDs, err = uc.repo.GetRosterVendors(marketID)
+	} else {
+		vendorIDs, err = uc.repo.GetBookedVendors(marketID, req.Date, announcement.ZoneID, announcement.Category)
+	}
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to find the vendors to notify: " + err.Error(),
+		}
+	}
+
+	announcement.RecipientCount = len(vendorIDs)
+	receipts := make([]entities.AnnouncementReceipt, 0, len(vendorIDs))
+	for _, vendorID := range vendorIDs {
+		receipts = append(receipts, entities.AnnouncementReceipt{
+			ID:             uuid.New().String(),
+			AnnouncementID: announcement.ID,
+			VendorID:       vendorID,
+		})
+	}
+	if err := uc.repo.CreateAnnouncement(announcement, receipts); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to send announcement: " + err.Error(),
+		}
+	}
+
+	// The announcement is saved and listed for its vendors either way, so failed notifications are only logged
+	message := market.Name + ": " + announcement.Message
+	for _, vendorID := range vendorIDs {
+		if errRes := uc.notifications.Notify(vendorID, entities.NotificationAnnouncement, announcement.Title, message, announcement.ID); errRes != nil {
+			log.Printf("Warning: Error notifying vendor %s of announcement %s: %s", vendorID, announcement.ID, errRes.Message)
+		}
+	}
+	return announcement, nil
+}
+
+// GetMarketAnnouncements lists a market's announcements for its provider, newest first, with read counts.
+func (uc *AnnouncementUseCase) GetMarketAnnouncements(providerID, marketID string) ([]entities.Announcement, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "announcements"); errRes != nil {
+		return nil, errRes
+	}
+	announcements, err := uc.repo.GetMarketAnnouncements(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve announcements: " + err.Error(),
+		}
+	}
+	return announcements, nil
+}
+
+// GetReceipts lists which vendors an announcement reached and which of them read it.
+func (uc *AnnouncementUseCase) GetReceipts(providerID, announcementID string) ([]entities.AnnouncementReceipt, *entitiesDtos.ErrorResponse) {
+	announcement, err := uc.repo.GetAnnouncement(announcementID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Announcement not found",
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, announcement.MarketID, "announcements"); errRes != nil {
+		return nil, errRes
+	}
+	receipts, err := uc.repo.GetReceipts(announcementID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve receipts: " + err.Error(),
+		}
+	}
+	return receipts, nil
+}
+
+// GetVendorAnnouncements lists the announcements about a vendor's upcoming bookings, including those sent
+// before it booked, and its recent roster announcements.
+func (uc *AnnouncementUseCase) GetVendorAnnouncements(vendorID string) ([]entities.Announcement, *entitiesDtos.ErrorResponse) {
+	// Today in UTC is never later than today at a market in Thailand, so the market's own day is still listed
+	from := marketToday(time.UTC)
+	rosterSince := time.Now().AddDate(0, 0, -rosterAnnouncementDays)
+	announcements, err := uc.repo.GetVendorAnnouncements(vendorID, from, rosterSince)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve announcements: " + err.Error(),
+		}
+	}
+	return announcements, nil
+}
+
+// MarkRead records that the vendor read an announcement it was sent or is in the audience of.
+func (uc *AnnouncementUseCase) MarkRead(vendorID, announcementID string) *entitiesDtos.ErrorResponse {
+	recipient, err := uc.repo.IsRecipient(announcementID, vendorID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get announcement: " + err.Error(),
+		}
+	}
+	if !recipient {
+		return &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Announcement not found",
+		}
+	}
+	if err := uc.repo.MarkRead(announcementID, vendorID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to mark announcement as read: " + err.Error(),
+		}
+	}
+	return nil
+}
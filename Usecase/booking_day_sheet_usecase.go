@@ -0,0 +1,157 @@
+package Usecase
+
+import (
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+// GetDaySheet lists every stall of a market day with its features, its booking and the add-ons to hand out.
+func (uc *BookingUseCase) GetDaySheet(providerID, marketID, date string) (*entitiesDtos.DaySheet, *entitiesDtos.ErrorResponse) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid date format. Please use YYYY-MM-DD",
+		}
+	}
+
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "bookings"); errRes != nil {
+		return nil, errRes
+	}
+
+	slots, err := uc.repo.GetDaySlots(marketID, date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get slots: " + err.Error(),
+		}
+	}
+	bookings, err := uc.repo.GetDayBookings(marketID, date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get bookings: " + err.Error(),
+		}
+	}
+	names, errRes := uc.amenities.AmenityNames(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	bySlot := make(map[string]entities.Booking, len(bookings))
+	for _, booking := range bookings {
+		bySlot[booking.SlotID] = booking
+	}
+
+	sheet := &entitiesDtos.DaySheet{
+		MarketID:    marketID,
+		Date:        date,
+		AddOnTotals: make(map[string]int),
+		Rows:        make([]entitiesDtos.DaySheetRow, 0, len(slots)),
+	}
+	for _, slot := range slots {
+		row := entitiesDtos.DaySheetRow{
+			SlotID:    slot.ID,
+			SlotName:  slot.Name,
+			Zone:      slot.Zone,
+			Category:  slot.Category,
+			Amenities: make([]string, 0, len(slot.Amenities)),
+		}
+		for _, id := range slot.Amenities {
+			if name, ok := names[id]; ok {
+				row.Amenities = append(row.Amenities, name)
+			}
+		}
+
+		booking, booked := bySlot[slot.ID]
+		if !booked {
+			sheet.Free++
+			sheet.Rows = append(sheet.Rows, row)
+			continue
+		}
+		sheet.Booked++
+		row.BookingID = booking.ID
+		row.GroupID = booking.GroupID
+		row.VendorID = booking.VendorID
+		row.Status = booking.Status
+		row.Price = booking.Price
+		row.AddOns = booking.AddOns
+		row.CheckedIn = booking.CheckedInAt
+		if booking.CheckedInAt != nil {
+			sheet.CheckedIn++
+		}
+		if booking.Vendor != nil {
+			row.VendorName = strings.TrimSpace(booking.Vendor.FirstName + " " + booking.Vendor.LastName)
+			if row.VendorName == "" {
+				row.VendorName = booking.Vendor.Username
+			}
+			row.Phone = booking.Vendor.Phone
+		}
+		for _, addOn := range booking.AddOns {
+			sheet.AddOnTotals[addOn.Name] += addOn.Quantity
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	return sheet, nil
+}
+
+// CheckIn records that a booking's vendor arrived, on the market day itself. Only paid bookings can be
+// checked in; a group booking is checked in through its lead booking.
+func (uc *BookingUseCase) CheckIn(providerID, checkedInBy, bookingID string) (*entities.Booking, *entitiesDtos.ErrorResponse) {
+	booking, err := uc.repo.GetBooking(bookingID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get booking: " + err.Error(),
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, booking.MarketID, "bookings"); errRes != nil {
+		return nil, errRes
+	}
+	market, err := uc.repo.GetMarket(booking.MarketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+
+	today := marketToday(marketLocation(market)).Format("2006-01-02")
+	switch {
+	case booking.Status != entities.StatusCompleted:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Only paid bookings can be checked in; this booking is " + string(booking.Status),
+		}
+	case booking.BookingDate.Format("2006-01-02") != today:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Bookings can only be checked in on their market day, " + booking.BookingDate.Format("2006-01-02"),
+		}
+	case booking.CheckedInAt != nil:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Booking was already checked in at " + booking.CheckedInAt.In(marketLocation(market)).Format("15:04"),
+		}
+	}
+
+	now := time.Now()
+	checkedIn, err := uc.repo.CheckInBooking(booking.ID, checkedInBy, now)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check in booking: " + err.Error(),
+		}
+	}
+	if !checkedIn {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Booking was already checked in",
+		}
+	}
+
+	booking.CheckedInAt, booking.CheckedInBy = &now, checkedInBy
+	return booking, nil
+}
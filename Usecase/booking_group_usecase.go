@@ -0,0 +1,266 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"math"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+const (
+	// maxGroupSlots caps how many stalls one combined booking may hold.
+	maxGroupSlots = 4
+	// adjacencyGap is how far apart, in floor plan units, two placed stalls may be and still count as neighbours.
+	adjacencyGap = 0.5
+)
+
+// CreateGroupBooking reserves adjacent stalls of one market day as a single booking: every slot is booked or
+// none is. The first slot's booking leads the group and carries its price, add-ons and payment.
+func (uc *BookingUseCase) CreateGroupBooking(req *entitiesDtos.GroupBookingRequest) (*entitiesDtos.BookingResponse, *entitiesDtos.ErrorResponse) {
+	bookingDate, errRes := validateGroupBooking(req)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	found, err := uc.repo.GetSlotsByIDs(req.SlotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get slots: " + err.Error(),
+		}
+	}
+	byID := make(map[string]*entities.Slot, len(found))
+	for _, slot := range found {
+		byID[slot.ID] = slot
+	}
+	slots := make([]*entities.Slot, 0, len(req.SlotIDs))
+	for _, slotID := range req.SlotIDs {
+		slot, ok := byID[slotID]
+		switch {
+		case !ok || slot.MarketID != req.MarketID:
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    404,
+				Message: fmt.Sprintf("Slot %s not found in this market", slotID),
+			}
+		case slotDate(slot) != req.BookingDate:
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Slot %s is not on %s", slot.Name, req.BookingDate),
+			}
+		case slot.DeletedAt != nil || slot.Status == entities.StatusMaintenance:
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("Slot %s is not open for booking", slot.Name),
+			}
+		}
+		slots = append(slots, slot)
+	}
+
+	if errRes := uc.markets.CheckBookable(req.MarketID); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.roster.CheckVendor(req.MarketID, req.VendorID); errRes != nil {
+		return nil, errRes
+	}
+	acceptance, errRes := uc.terms.CheckAccepted(req.MarketID, req.VendorID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.hours.CheckOpen(req.MarketID, req.BookingDate); errRes != nil {
+		return nil, errRes
+	}
+	if !contiguous(slots) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "The slots must form one contiguous group of neighbouring stalls",
+		}
+	}
+	if errRes := uc.zones.CheckBooking(slots...); errRes != nil {
+		return nil, errRes
+	}
+
+	quote, errRes := uc.pricing.PriceGroup(slots, time.Now())
+	if errRes != nil {
+		return nil, errRes
+	}
+	addOns, addOnTotal, errRes := uc.amenities.PriceAddOns(req.MarketID, req.AddOns)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	expirationTime := time.Now().Add(30 * time.Minute)
+	thLocation, _ := time.LoadLocation("Asia/Bangkok")
+	groupID := uuid.New().String()
+	bookingID := uuid.New().String()
+	paymentID := uuid.New().String()
+
+	price := quote.Price
+	var discount float64
+	if req.PromoCode != "" {
+		// The code is checked against the lead stall and discounts the whole group's price
+		lead := *slots[0]
+		lead.Price = quote.Price
+		redemption, errRes := uc.promotion.ApplyPromotion(req.PromoCode, &lead, req.VendorID, req.BookingDate, bookingID, paymentID)
+		if errRes != nil {
+			return nil, errRes
+		}
+		discount = redemption.Discount
+		price = entities.RoundMoney(price - discount)
+	}
+	price = entities.RoundMoney(price + addOnTotal)
+
+	var creditUsed float64
+	if req.UseCredit {
+		creditUsed, errRes = uc.wallet.RedeemCredit(req.VendorID, req.MarketID, bookingID, price)
+		if errRes != nil {
+			uc.releaseHolds(bookingID)
+			return nil, errRes
+		}
+	}
+	amountDue := entities.RoundMoney(price - creditUsed)
+
+	method := req.Method
+	if amountDue <= 0 {
+		method = entities.MethodWallet
+	}
+
+	group := &entities.BookingGroup{
+		ID:            groupID,
+		MarketID:      req.MarketID,
+		VendorID:      req.VendorID,
+		LeadBookingID: bookingID,
+		SlotIDs:       req.SlotIDs,
+		Date:          req.BookingDate,
+		ListPrice:     quote.ListPrice,
+		Price:         quote.Price,
+		ComboID:       quote.ComboID,
+	}
+	bookings := make([]*entities.Booking, 0, len(slots))
+	for i, slot := range slots {
+		booking := &entities.Booking{
+			ID:          uuid.New().String(),
+			SlotID:      slot.ID,
+			VendorID:    req.VendorID,
+			MarketID:    req.MarketID,
+			BookingDate: bookingDate,
+			Status:      entities.StatusPending,
+			Method:      method,
+			ExpiresAt:   expirationTime,
+			GroupID:     groupID,
+		}
+		if acceptance != nil {
+			booking.TermsAcceptanceID = acceptance.ID
+		}
+		if i == 0 {
+			booking.ID = bookingID
+			booking.Price = price
+			booking.AddOns = addOns
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := uc.repo.CreateBookingGroup(group, bookings); err != nil {
+		log.Printf("Error creating group booking: %v", err)
+		uc.releaseHolds(bookingID)
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Slots are not available: " + err.Error(),
+		}
+	}
+
+	response, errRes := uc.checkout(bookings[0], paymentID, discount, creditUsed, amountDue, thLocation)
+	if errRes != nil {
+		return nil, errRes
+	}
+	response.GroupID = groupID
+	response.SlotIDs = req.SlotIDs
+	withTerms(response, acceptance)
+	return response, nil
+}
+
+// groupMember reports whether a booking is a non-lead stall of a combined booking, which only moves with its lead.
+func (uc *BookingUseCase) groupMember(booking *entities.Booking) (*entities.BookingGroup, bool) {
+	if booking.GroupID == "" {
+		return nil, false
+	}
+	group, err := uc.repo.GetBookingGroup(booking.GroupID)
+	if err != nil {
+		log.Printf("Warning: Error getting booking group %s: %v", booking.GroupID, err)
+		return nil, false
+	}
+	return group, group.LeadBookingID != booking.ID
+}
+
+func validateGroupBooking(req *entitiesDtos.GroupBookingRequest) (time.Time, *entitiesDtos.ErrorResponse) {
+	message := ""
+	switch {
+	case req.VendorID == "" || req.MarketID == "" || req.Method == "":
+		message = "Vendor ID, market ID and payment method are required"
+	case len(req.SlotIDs) < 2 || len(req.SlotIDs) > maxGroupSlots:
+		message = fmt.Sprintf("A combined booking holds 2 to %d slots", maxGroupSlots)
+	}
+	seen := make(map[string]bool, len(req.SlotIDs))
+	for _, slotID := range req.SlotIDs {
+		if message == "" && (slotID == "" || seen[slotID]) {
+			message = "Each slot may appear only once"
+		}
+		seen[slotID] = true
+	}
+	bookingDate, err := time.Parse("2006-01-02", req.BookingDate)
+	if message == "" && err != nil {
+		message = "Invalid booking date format. Please use YYYY-MM-DD"
+	}
+	if message != "" {
+		return time.Time{}, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid booking request: " + message,
+		}
+	}
+
+	return bookingDate, nil
+}
+
+// contiguous reports whether every slot can be reached from the first by stepping between neighbours.
+func contiguous(slots []*entities.Slot) bool {
+	reached := map[int]bool{0: true}
+	queue := []int{0}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for next := range slots {
+			if !reached[next] && slotsAdjacent(slots[current], slots[next]) {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return len(reached) == len(slots)
+}
+
+// slotsAdjacent reports whether two stalls are neighbours: one lists the other as adjacent, or both are placed
+// on the map and their footprints touch along an edge.
+func slotsAdjacent(a, b *entities.Slot) bool {
+	for _, id := range a.Adjacent {
+		if id == b.ID {
+			return true
+		}
+	}
+	for _, id := range b.Adjacent {
+		if id == a.ID {
+			return true
+		}
+	}
+	if !a.Placed() || !b.Placed() {
+		return false
+	}
+
+	ax1, ay1, ax2, ay2 := a.Bounds(a.Width, a.Height)
+	bx1, by1, bx2, by2 := b.Bounds(b.Width, b.Height)
+	gapX := math.Max(ax1, bx1) - math.Min(ax2, bx2)
+	gapY := math.Max(ay1, by1) - math.Min(ay2, by2)
+	// Side by side: close horizontally and sharing some vertical run, or the other way round
+	return (gapX <= adjacencyGap && gapY < 0) || (gapY <= adjacencyGap && gapX < 0)
+}
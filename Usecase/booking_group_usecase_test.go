@@ -0,0 +1,68 @@
+package Usecase
+
+import (
+	"testing"
+	entities "tln-backend/Entities"
+)
+
+func placedStall(id string, x, y float64) *entities.Slot {
+	return &entities.Slot{ID: id, Width: 2, Height: 2, SlotGeometry: entities.SlotGeometry{X: x, Y: y}}
+}
+
+func TestSlotsAdjacent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *entities.Slot
+		want bool
+	}{
+		{name: "side by side", a: placedStall("a", 1, 1), b: placedStall("b", 3, 1), want: true},
+		{name: "one above the other", a: placedStall("a", 1, 1), b: placedStall("b", 1, 3), want: true},
+		{name: "within the gap", a: placedStall("a", 1, 1), b: placedStall("b", 3.5, 1), want: true},
+		{name: "past the gap", a: placedStall("a", 1, 1), b: placedStall("b", 3.6, 1)},
+		{name: "corners only touch", a: placedStall("a", 1, 1), b: placedStall("b", 3, 3)},
+		{name: "declared neighbours", a: &entities.Slot{ID: "a", Adjacent: []string{"b"}}, b: &entities.Slot{ID: "b"}, want: true},
+		{name: "declared the other way", a: &entities.Slot{ID: "a"}, b: &entities.Slot{ID: "b", Adjacent: []string{"a"}}, want: true},
+		{name: "unplaced and undeclared", a: &entities.Slot{ID: "a", Width: 2, Height: 2}, b: &entities.Slot{ID: "b", Width: 2, Height: 2}},
+		{name: "one unplaced", a: placedStall("a", 1, 1), b: &entities.Slot{ID: "b", Width: 2, Height: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slotsAdjacent(tt.a, tt.b); got != tt.want {
+				t.Errorf("slotsAdjacent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContiguous(t *testing.T) {
+	tests := []struct {
+		name  string
+		slots []*entities.Slot
+		want  bool
+	}{
+		{name: "a row", slots: []*entities.Slot{placedStall("a", 1, 1), placedStall("b", 3, 1), placedStall("c", 5, 1)}, want: true},
+		{name: "a row out of order", slots: []*entities.Slot{placedStall("a", 1, 1), placedStall("c", 5, 1), placedStall("b", 3, 1)}, want: true},
+		{name: "an L", slots: []*entities.Slot{placedStall("a", 1, 1), placedStall("b", 3, 1), placedStall("c", 3, 3)}, want: true},
+		{name: "a hole in the row", slots: []*entities.Slot{placedStall("a", 1, 1), placedStall("c", 5, 1)}},
+		{name: "two pairs apart", slots: []*entities.Slot{placedStall("a", 1, 1), placedStall("b", 3, 1), placedStall("c", 9, 1), placedStall("d", 11, 1)}},
+		{
+			name: "a declared chain",
+			slots: []*entities.Slot{
+				{ID: "a", Adjacent: []string{"b"}},
+				{ID: "b", Adjacent: []string{"c"}},
+				{ID: "c"},
+			},
+			want: true,
+		},
+		{name: "placed and declared together", slots: []*entities.Slot{placedStall("a", 1, 1), {ID: "b", Adjacent: []string{"a"}}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contiguous(tt.slots); got != tt.want {
+				t.Errorf("contiguous() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package Usecase
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"log"
@@ -18,15 +20,37 @@ type BookingUseCase struct {
 	PaymentUseCase *PaymentUseCase
 	bookingService *Services.BookingService
 	slotUseCase    contact.ISlotUseCase
+	ledger         contact.ILedgerUseCase
+	wallet         contact.IWalletUseCase
+	promotion      contact.IPromotionUseCase
+	pricing        contact.IPricingUseCase
+	zones          contact.IZoneUseCase
+	amenities      contact.IAmenityUseCase
+	hours          contact.IOpeningHoursUseCase
+	media          contact.IMediaStore
+	markets        contact.IMarketUseCase
+	roster         contact.IRosterUseCase
+	terms          contact.ITermsUseCase
 }
 
-func NewBookingUseCase(repo contact.IBooking, payment contact.IPayment, paymentUseCase *PaymentUseCase, bookingService *Services.BookingService, slotUseCase contact.ISlotUseCase) *BookingUseCase {
+func NewBookingUseCase(repo contact.IBooking, payment contact.IPayment, paymentUseCase *PaymentUseCase, bookingService *Services.BookingService, slotUseCase contact.ISlotUseCase, ledger contact.ILedgerUseCase, wallet contact.IWalletUseCase, promotion contact.IPromotionUseCase, pricing contact.IPricingUseCase, zones contact.IZoneUseCase, amenities contact.IAmenityUseCase, hours contact.IOpeningHoursUseCase, media contact.IMediaStore, markets contact.IMarketUseCase, roster contact.IRosterUseCase, terms contact.ITermsUseCase) *BookingUseCase {
 	return &BookingUseCase{
 		repo:           repo,
 		payment:        payment,
 		PaymentUseCase: paymentUseCase,
 		bookingService: bookingService,
 		slotUseCase:    slotUseCase,
+		ledger:         ledger,
+		wallet:         wallet,
+		promotion:      promotion,
+		pricing:        pricing,
+		zones:          zones,
+		amenities:      amenities,
+		hours:          hours,
+		media:          media,
+		markets:        markets,
+		roster:         roster,
+		terms:          terms,
 	}
 }
 
@@ -47,7 +71,8 @@ func (uc *BookingUseCase) CreateBooking(bookingReq *entitiesDtos.BookingRequest)
 		}
 	}
 
-	if err := uc.repo.IsSlotAvailable(bookingReq); err != nil {
+	slot, err := uc.repo.IsSlotAvailable(bookingReq)
+	if err != nil {
 		log.Printf("Slot is not available: %v", err)
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    409,
@@ -55,49 +80,152 @@ func (uc *BookingUseCase) CreateBooking(bookingReq *entitiesDtos.BookingRequest)
 		}
 	}
 
+	// A slot is one stall on one market day, so the booking must be for that day
+	if slotDate(slot) != bookingReq.BookingDate {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Slot %s is not on %s", slot.Name, bookingReq.BookingDate),
+		}
+	}
+
+	if errRes := uc.zones.CheckBooking(slot); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.markets.CheckBookable(slot.MarketID); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.roster.CheckVendor(slot.MarketID, bookingReq.VendorID); errRes != nil {
+		return nil, errRes
+	}
+	acceptance, errRes := uc.terms.CheckAccepted(slot.MarketID, bookingReq.VendorID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.hours.CheckOpen(slot.MarketID, bookingReq.BookingDate); errRes != nil {
+		return nil, errRes
+	}
+
 	expirationTime := time.Now().Add(30 * time.Minute)
 	thLocation, _ := time.LoadLocation("Asia/Bangkok")
+	bookingID := uuid.New().String()
+	paymentID := uuid.New().String()
+
+	// Price comes from the market's pricing rules, never from the client. Promotions discount that price.
+	quote, errRes := uc.pricing.PriceSlot(slot, time.Now())
+	if errRes != nil {
+		return nil, errRes
+	}
+	slot.Price = quote.Price
+	addOns, addOnTotal, errRes := uc.amenities.PriceAddOns(bookingReq.MarketID, bookingReq.AddOns)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	price := slot.Price
+	var discount float64
+	if bookingReq.PromoCode != "" {
+		redemption, errRes := uc.promotion.ApplyPromotion(bookingReq.PromoCode, slot, bookingReq.VendorID, bookingReq.BookingDate, bookingID, paymentID)
+		if errRes != nil {
+			return nil, errRes
+		}
+		discount = redemption.Discount
+		price = entities.RoundMoney(price - discount)
+	}
+	// Add-ons are charged in full; promotions only discount the stall
+	price = entities.RoundMoney(price + addOnTotal)
+
+	// Spend wallet credit first; whatever it does not cover is paid through the requested method
+	var creditUsed float64
+	if bookingReq.UseCredit {
+		var errRes *entitiesDtos.ErrorResponse
+		creditUsed, errRes = uc.wallet.RedeemCredit(bookingReq.VendorID, bookingReq.MarketID, bookingID, price)
+		if errRes != nil {
+			uc.releaseHolds(bookingID)
+			return nil, errRes
+		}
+	}
+	amountDue := entities.RoundMoney(price - creditUsed)
+
+	method := bookingReq.Method
+	if amountDue <= 0 {
+		method = entities.MethodWallet
+	}
+
 	bookingEntity := &entities.Booking{
-		ID:          uuid.New().String(),
+		ID:          bookingID,
 		SlotID:      bookingReq.SlotID,
 		VendorID:    bookingReq.VendorID,
 		MarketID:    bookingReq.MarketID,
 		BookingDate: bookingDate,
 		Status:      entities.StatusPending,
-		Method:      bookingReq.Method,
-		Price:       bookingReq.Price,
+		Method:      method,
+		Price:       price,
 		ExpiresAt:   expirationTime,
+		AddOns:      addOns,
+	}
+	if acceptance != nil {
+		bookingEntity.TermsAcceptanceID = acceptance.ID
 	}
 
 	if err := uc.repo.CreateBooking(bookingEntity); err != nil {
 		log.Printf("Error creating booking: %v", err)
+		uc.releaseHolds(bookingID)
+		if errors.Is(err, entities.ErrZoneFull) {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: "Slot is not available: " + err.Error(),
+			}
+		}
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
 			Message: "Failed to create booking: " + err.Error(),
 		}
 	}
 
+	response, errRes := uc.checkout(bookingEntity, paymentID, discount, creditUsed, amountDue, thLocation)
+	if errRes != nil {
+		return nil, errRes
+	}
+	withTerms(response, acceptance)
+	return response, nil
+}
+
+// checkout creates the payment of a saved booking, then settles it from the wallet or issues a PromptPay QR
+// for what is left to pay.
+func (uc *BookingUseCase) checkout(bookingEntity *entities.Booking, paymentID string, discount, creditUsed, amountDue float64, thLocation *time.Location) (*entitiesDtos.BookingResponse, *entitiesDtos.ErrorResponse) {
 	paymentEntity := entities.Payment{
-		ID:          uuid.New().String(),
+		ID:          paymentID,
 		BookingID:   bookingEntity.ID,
-		Price:       bookingReq.Price,
-		Method:      bookingReq.Method,
+		Price:       bookingEntity.Price,
+		Discount:    discount,
+		CreditUsed:  creditUsed,
+		Method:      bookingEntity.Method,
 		Status:      entities.PaymentPending,
 		PaymentDate: time.Now().In(thLocation),
-		ExpiresAt:   expirationTime,
+		ExpiresAt:   bookingEntity.ExpiresAt,
 	}
 
 	if err := uc.payment.CreatePayment(&paymentEntity); err != nil {
+		uc.releaseHolds(bookingEntity.ID)
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
 			Message: "Failed to create payment: " + err.Error(),
 		}
 	}
 
+	if bookingEntity.Method == entities.MethodWallet {
+		return uc.completeWalletBooking(bookingEntity, &paymentEntity, thLocation)
+	}
+
 	var promptPayResult entitiesDtos.PromptPayResult
-	if bookingReq.Method == "PromptPay" {
-		promptPayResult, err = uc.handlePayment(paymentEntity, paymentEntity.ID)
+	if bookingEntity.Method == entities.MethodPromptPay {
+		// The QR only has to collect what the wallet did not cover
+		duePayment := paymentEntity
+		duePayment.Price = amountDue
+		var err error
+		promptPayResult, err = uc.handlePayment(duePayment, paymentEntity.ID)
 		if err != nil {
+			uc.releaseHolds(bookingEntity.ID)
 			return nil, &entitiesDtos.ErrorResponse{
 				Code:    500,
 				Message: "Failed to handle payment: " + err.Error(),
@@ -108,6 +236,7 @@ func (uc *BookingUseCase) CreateBooking(bookingReq *entitiesDtos.BookingRequest)
 	Price, err := strconv.ParseFloat(promptPayResult.PromptPayDetail.Amount, 64)
 	if err != nil {
 		log.Printf("Failed to parse amount: %v", err)
+		uc.releaseHolds(bookingEntity.ID)
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    400,
 			Message: fmt.Sprintf("Failed to parse amount: %v", err),
@@ -126,12 +255,14 @@ func (uc *BookingUseCase) CreateBooking(bookingReq *entitiesDtos.BookingRequest)
 		Image:           promptPayResult.QRResponse.Data.QRImage,
 		Status:          entities.TransactionPending,
 		TransactionDate: time.Now().In(thLocation),
-		ExpiresAt:       expirationTime,
+		ExpiresAt:       bookingEntity.ExpiresAt,
 	}
+	uc.storePaymentQR(transaction)
 
 	err = uc.PaymentUseCase.repo.CreateTransaction(transaction)
 	if err != nil {
 		log.Printf("Failed to create transaction: %v", err)
+		uc.releaseHolds(bookingEntity.ID)
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
 			Message: fmt.Sprintf("Failed to create transaction: %v", err),
@@ -145,18 +276,87 @@ func (uc *BookingUseCase) CreateBooking(bookingReq *entitiesDtos.BookingRequest)
 		TransactionID: transaction.ID,
 		BookingDate:   bookingEntity.BookingDate,
 		Price:         bookingEntity.Price,
+		Discount:      discount,
+		CreditUsed:    creditUsed,
+		AmountDue:     amountDue,
 		Status:        bookingEntity.Status,
 		Method:        bookingEntity.Method,
-		Image:         transaction.Image,
+		Image:         promptPayResult.QRResponse.Data.QRImage,
+		ImageURL:      transaction.ImageURL,
 		ExpiresAt:     transaction.ExpiresAt,
+		AddOns:        bookingEntity.AddOns,
 	}
 
 	// Schedule booking cancellation using BookingService
-	uc.bookingService.ScheduleBookingCancellation(transaction.ID, bookingEntity.ID, bookingEntity.SlotID, bookingEntity.VendorID, expirationTime)
+	uc.bookingService.ScheduleBookingCancellation(transaction.ID, bookingEntity.ID, bookingEntity.SlotID, bookingEntity.VendorID, bookingEntity.ExpiresAt)
 
 	return &bookingResponse, nil
 }
 
+// completeWalletBooking confirms a booking paid entirely from wallet credit. No QR code is needed.
+func (uc *BookingUseCase) completeWalletBooking(bookingEntity *entities.Booking, paymentEntity *entities.Payment, thLocation *time.Location) (*entitiesDtos.BookingResponse, *entitiesDtos.ErrorResponse) {
+	transaction := &entities.Transaction{
+		ID:              uuid.New().String(),
+		PaymentID:       paymentEntity.ID,
+		Method:          string(entities.MethodWallet),
+		Price:           paymentEntity.CreditUsed,
+		Status:          entities.TransactionCompleted,
+		TransactionDate: time.Now().In(thLocation),
+		ExpiresAt:       paymentEntity.ExpiresAt,
+	}
+
+	if err := uc.payment.CreateTransaction(transaction); err != nil {
+		log.Printf("Failed to create wallet transaction: %v", err)
+		uc.releaseHolds(bookingEntity.ID)
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: fmt.Sprintf("Failed to create transaction: %v", err),
+		}
+	}
+
+	if err := uc.bookingService.CompleteBooking(transaction.ID, paymentEntity.ID, bookingEntity.ID, bookingEntity.SlotID, bookingEntity.VendorID); err != nil {
+		log.Printf("Failed to complete wallet booking %s: %v", bookingEntity.ID, err)
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: fmt.Sprintf("Failed to complete booking: %v", err),
+		}
+	}
+
+	return &entitiesDtos.BookingResponse{
+		ID:            bookingEntity.ID,
+		SlotID:        bookingEntity.SlotID,
+		VendorID:      bookingEntity.VendorID,
+		TransactionID: transaction.ID,
+		BookingDate:   bookingEntity.BookingDate,
+		Price:         bookingEntity.Price,
+		Discount:      paymentEntity.Discount,
+		CreditUsed:    paymentEntity.CreditUsed,
+		AmountDue:     0,
+		Status:        entities.StatusCompleted,
+		Method:        entities.MethodWallet,
+		ExpiresAt:     transaction.ExpiresAt,
+		AddOns:        bookingEntity.AddOns,
+	}, nil
+}
+
+// withTerms references the terms acceptance a booking was made under from its confirmation.
+func withTerms(response *entitiesDtos.BookingResponse, acceptance *entities.TermsAcceptance) {
+	if acceptance != nil {
+		response.TermsAcceptanceID = acceptance.ID
+		response.TermsVersion = acceptance.Version
+	}
+}
+
+// releaseHolds gives back any wallet credit and promotion use a booking had reserved.
+func (uc *BookingUseCase) releaseHolds(bookingID string) {
+	if errRes := uc.wallet.RestoreCredit(bookingID); errRes != nil {
+		log.Printf("Warning: Error restoring wallet credit for booking ID %s: %v", bookingID, errRes.Message)
+	}
+	if errRes := uc.promotion.ReleasePromotion(bookingID); errRes != nil {
+		log.Printf("Warning: Error releasing promotion for booking ID %s: %v", bookingID, errRes.Message)
+	}
+}
+
 // Other methods remain the same...
 
 func (uc *BookingUseCase) GetBooking(bookingID string) (*entities.Booking, error) {
@@ -167,6 +367,26 @@ func (uc *BookingUseCase) GetBookingsByUser(userID string) ([]entities.Booking,
 	return uc.repo.GetBookingsByUser(userID)
 }
 
+// storePaymentQR moves the PromptPay QR out of the transaction row into media storage. The booking response
+// still carries the QR inline, so if storage fails the QR stays on the row as before.
+func (uc *BookingUseCase) storePaymentQR(transaction *entities.Transaction) {
+	if transaction.Image == "" {
+		return
+	}
+	qr, err := base64.StdEncoding.DecodeString(transaction.Image)
+	if err != nil {
+		return
+	}
+	media, errRes := uc.media.StoreImage(entities.MediaPaymentQR, transaction.ID, qr)
+	if errRes != nil {
+		log.Printf("Warning: Error storing payment QR of transaction %s: %s", transaction.ID, errRes.Message)
+		return
+	}
+	transaction.Image = ""
+	transaction.ImageID = media.ID
+	transaction.ImageURL = media.URL
+}
+
 func (uc *BookingUseCase) handlePayment(paymentEntity entities.Payment, paymentID string) (entitiesDtos.PromptPayResult, error) {
 	promptPayResult, errResp := uc.PaymentUseCase.PromptPay(paymentEntity, paymentID)
 	if errResp != nil {
@@ -235,6 +455,21 @@ func (uc *BookingUseCase) CancelBooking(cancelBookingReq *entitiesDtos.CancelBoo
 		}
 	}
 
+	if bookingEntity.VendorID != cancelBookingReq.VendorID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to cancel this booking",
+		}
+	}
+
+	// A combined booking is cancelled as a unit, through its lead booking
+	if group, member := uc.groupMember(bookingEntity); member {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Booking %s is one stall of a combined booking; cancel booking %s to release the whole group", bookingEntity.ID, group.LeadBookingID),
+		}
+	}
+
 	if bookingEntity.Payment == nil {
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
@@ -323,6 +558,20 @@ func (uc *BookingUseCase) CancelBooking(cancelBookingReq *entitiesDtos.CancelBoo
 			// Log the error but don't fail the cancellation process
 			log.Printf("Warning: Error updating slot status for slot ID %s: %v", bookingEntity.SlotID, err)
 		}
+		uc.bookingService.UpdateGroupSlots(bookingEntity.ID, cancelBookingReq.VendorID, entities.StatusAvailable)
+
+		if errRes := uc.ledger.RecordRefund(bookingEntity.ID, cancelBookingReq.RefundToWallet); errRes != nil {
+			log.Printf("Warning: Error recording refund in ledger for booking ID %s: %v", bookingEntity.ID, errRes.Message)
+		}
+
+		// Credit and the promotion use held by the booking always go back; the cash part reaches the wallet only when asked to
+		uc.releaseHolds(bookingEntity.ID)
+		if cancelBookingReq.RefundToWallet {
+			cashPaid := bookingEntity.Payment.Price - bookingEntity.Payment.CreditUsed
+			if errRes := uc.wallet.IssueRefundCredit(bookingEntity, cashPaid); errRes != nil {
+				log.Printf("Warning: Error issuing refund credit for booking ID %s: %v", bookingEntity.ID, errRes.Message)
+			}
+		}
 
 		return &entitiesDtos.BookingResponse{
 			ID:          bookingEntity.ID,
@@ -350,6 +599,8 @@ func (uc *BookingUseCase) CancelBooking(cancelBookingReq *entitiesDtos.CancelBoo
 			}
 		}
 
+		uc.releaseHolds(bookingEntity.ID)
+
 		return &entitiesDtos.BookingResponse{
 			ID:          bookingEntity.ID,
 			SlotID:      bookingEntity.SlotID,
@@ -366,3 +617,46 @@ func (uc *BookingUseCase) CancelBooking(cancelBookingReq *entitiesDtos.CancelBoo
 		}
 	}
 }
+
+// CancelMarketDay cancels every active booking of a market on one date, e.g. when the provider closes for rain.
+// Paid bookings are refunded, as wallet credit when requested.
+func (uc *BookingUseCase) CancelMarketDay(providerID string, req *entitiesDtos.CancelMarketDayRequest) ([]entitiesDtos.BookingResponse, *entitiesDtos.ErrorResponse) {
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid date format. Please use YYYY-MM-DD",
+		}
+	}
+
+	if errRes := checkMarketOwner(uc.repo, providerID, req.MarketID, "bookings"); errRes != nil {
+		return nil, errRes
+	}
+
+	bookings, err := uc.repo.GetActiveBookingsByMarketAndDate(req.MarketID, req.Date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get bookings: " + err.Error(),
+		}
+	}
+
+	cancelled := make([]entitiesDtos.BookingResponse, 0, len(bookings))
+	for _, booking := range bookings {
+		// The rest of a combined booking goes with its lead
+		if _, member := uc.groupMember(&booking); member {
+			continue
+		}
+		response, errRes := uc.CancelBooking(&entitiesDtos.CancelBookingRequest{
+			BookingID:      booking.ID,
+			VendorID:       booking.VendorID,
+			RefundToWallet: req.RefundToWallet,
+		})
+		if errRes != nil {
+			log.Printf("Warning: Error cancelling booking ID %s for market day: %v", booking.ID, errRes.Message)
+			continue
+		}
+		cancelled = append(cancelled, *response)
+	}
+
+	return cancelled, nil
+}
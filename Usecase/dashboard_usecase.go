@@ -20,10 +20,11 @@ func NewDashboardUseCase(repo *Repository.DashboardRepository, service *Services
 	}
 }
 func (uc *DashboardUseCase) updateAllMarketsStats() error {
-	// Get all market IDs
+	// Get the IDs of the markets that are trading; drafts have no bookings and archived stats stay as they were
 	var marketIDs []string
 	err := uc.repo.GetDB().Model(&entities.Market{}).
 		Select("id").
+		Where("status IN ?", []entities.MarketStatus{entities.MarketPublished, entities.MarketSuspended}).
 		Pluck("id", &marketIDs).Error
 	if err != nil {
 		return fmt.Errorf("failed to get market IDs: %v", err)
@@ -40,10 +41,16 @@ func (uc *DashboardUseCase) updateAllMarketsStats() error {
 }
 
 func (uc *DashboardUseCase) GetWeeklyData(marketID string) (*entities.DashboardResponse, error) {
-	// Update stats for the specific market before fetching weekly data
-	err := uc.repo.UpdateDashboardStats(marketID)
+	status, err := uc.repo.GetMarketStatus(marketID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update market stats: %v", err)
+		return nil, fmt.Errorf("failed to get market: %v", err)
+	}
+
+	// Update stats for the specific market before fetching weekly data; an archived market keeps its history
+	if status != entities.MarketArchived {
+		if err := uc.repo.UpdateDashboardStats(marketID); err != nil {
+			return nil, fmt.Errorf("failed to update market stats: %v", err)
+		}
 	}
 
 	// Fetch weekly stats for the given market ID
@@ -53,6 +60,7 @@ func (uc *DashboardUseCase) GetWeeklyData(marketID string) (*entities.DashboardR
 	}
 
 	return &entities.DashboardResponse{
-		Stats: weeklyStats, // Assigns the retrieved weekly stats directly
+		Stats:        weeklyStats, // Assigns the retrieved weekly stats directly
+		MarketStatus: status,
 	}, nil
 }
@@ -0,0 +1,465 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+// maxTemplateDays keeps one apply request from stamping years of slots by accident.
+const maxTemplateDays = 366
+
+type LayoutTemplateUseCase struct {
+	repo     Interfaces.ILayoutTemplate
+	versions contact.ILayoutVersionUseCase
+}
+
+var _ contact.ILayoutTemplateUseCase = (*LayoutTemplateUseCase)(nil)
+
+func NewLayoutTemplateUseCase(repo Interfaces.ILayoutTemplate, versions contact.ILayoutVersionUseCase) *LayoutTemplateUseCase {
+	return &LayoutTemplateUseCase{repo: repo, versions: versions}
+}
+
+func (uc *LayoutTemplateUseCase) CreateTemplate(providerID, marketID string, req *entitiesDtos.LayoutTemplateRequest) (*entities.LayoutTemplate, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	zones, errRes := uc.marketZones(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	templateID := uuid.New().String()
+	stalls, errRes := templateStalls(templateID, req, zones)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	template := &entities.LayoutTemplate{
+		ID:          templateID,
+		MarketID:    marketID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Stalls:      stalls,
+	}
+
+	if err := uc.repo.CreateTemplate(template); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create template: " + err.Error(),
+		}
+	}
+
+	return template, nil
+}
+
+func (uc *LayoutTemplateUseCase) UpdateTemplate(providerID, templateID string, req *entitiesDtos.LayoutTemplateRequest) (*entities.LayoutTemplate, *entitiesDtos.ErrorResponse) {
+	template, errRes := uc.ownTemplate(providerID, templateID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	zones, errRes := uc.marketZones(template.MarketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	stalls, errRes := templateStalls(template.ID, req, zones)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	template.Name = strings.TrimSpace(req.Name)
+	template.Description = req.Description
+	template.Stalls = stalls
+
+	if err := uc.repo.ReplaceTemplate(template); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to update template: " + err.Error(),
+		}
+	}
+
+	return template, nil
+}
+
+func (uc *LayoutTemplateUseCase) GetTemplates(providerID, marketID string) ([]entities.LayoutTemplate, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	templates, err := uc.repo.GetTemplatesByMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get templates: " + err.Error(),
+		}
+	}
+
+	return templates, nil
+}
+
+func (uc *LayoutTemplateUseCase) GetTemplate(providerID, templateID string) (*entities.LayoutTemplate, *entitiesDtos.ErrorResponse) {
+	return uc.ownTemplate(providerID, templateID)
+}
+
+func (uc *LayoutTemplateUseCase) DeleteTemplate(providerID, templateID string) *entitiesDtos.ErrorResponse {
+	template, errRes := uc.ownTemplate(providerID, templateID)
+	if errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repo.DeleteTemplate(template.ID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete template: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// ApplyTemplate stamps a template onto every matching date. Slots are keyed like ApplyLayout keys them,
+// so repeating the same request creates nothing new and only reports what is already there.
+func (uc *LayoutTemplateUseCase) ApplyTemplate(providerID, templateID string, req *entitiesDtos.ApplyTemplateRequest) (*entitiesDtos.TemplateApplyResponse, *entitiesDtos.ErrorResponse) {
+	template, errRes := uc.ownTemplate(providerID, templateID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	dates, errRes := templateDates(req)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return uc.stamp(providerID, template, dates)
+}
+
+// stamp creates missing slots, brings available ones in line with the template and leaves the rest alone.
+// What it changed is recorded as a layout version.
+func (uc *LayoutTemplateUseCase) stamp(createdBy string, template *entities.LayoutTemplate, dates []string) (*entitiesDtos.TemplateApplyResponse, *entitiesDtos.ErrorResponse) {
+	planned := make([]*entities.Slot, 0, len(dates)*len(template.Stalls))
+	slotIDs := make([]string, 0, cap(planned))
+	for _, date := range dates {
+		for _, stall := range template.Stalls {
+			slot := &entities.Slot{
+				ID:       templateSlotID(template.MarketID, stall, date),
+				MarketID: template.MarketID,
+				Zone:     stall.Zone,
+				ZoneID:   stall.ZoneID,
+				Name:     stall.Name,
+				Width:    stall.Width,
+				Height:   stall.Height,
+				Price:    stall.Price,
+				Status:   entities.StatusAvailable,
+				Category: stall.Category,
+				Date:     date,
+			}
+			slot.SlotGeometry = stall.SlotGeometry
+			planned = append(planned, slot)
+			slotIDs = append(slotIDs, slot.ID)
+		}
+	}
+
+	existingSlots, err := uc.repo.GetSlotsByIDs(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to fetch existing slots: " + err.Error(),
+		}
+	}
+	existing := make(map[string]*entities.Slot, len(existingSlots))
+	for _, slot := range existingSlots {
+		existing[slot.ID] = slot
+	}
+
+	// A pending booking has not flipped the slot to booked yet, so check bookings directly
+	bookedIDs, err := uc.repo.GetActiveBookingSlotIDs(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check slot bookings: " + err.Error(),
+		}
+	}
+	booked := make(map[string]bool, len(bookedIDs))
+	for _, id := range bookedIDs {
+		booked[id] = true
+	}
+
+	response := &entitiesDtos.TemplateApplyResponse{
+		TemplateID: template.ID,
+		Dates:      dates,
+		Created:    []string{},
+		Updated:    []string{},
+		Skipped:    []string{},
+	}
+	var created, updated, replaced []*entities.Slot
+	for _, slot := range planned {
+		current, exists := existing[slot.ID]
+		switch {
+		case !exists:
+			created = append(created, slot)
+			response.Created = append(response.Created, slot.ID)
+		case booked[slot.ID] || current.Status != entities.StatusAvailable || sameSlotShape(current, slot):
+			// Booked and maintenance slots are never reshaped under a vendor
+			response.Skipped = append(response.Skipped, slot.ID)
+		default:
+			updated = append(updated, slot)
+			replaced = append(replaced, current)
+			response.Updated = append(response.Updated, slot.ID)
+		}
+	}
+	if len(created) == 0 && len(updated) == 0 {
+		return response, nil
+	}
+
+	if err := uc.repo.SaveSlots(created, updated); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to apply template: " + err.Error(),
+		}
+	}
+	note := fmt.Sprintf("Template %s stamped", template.Name)
+	if errRes := uc.versions.RecordSlotChanges(createdBy, template.MarketID, note, replaced, append(created, updated...)); errRes != nil {
+		return nil, errRes
+	}
+
+	return response, nil
+}
+
+// StampTemplate applies a template without an ownership check, for the schedule generation job.
+func (uc *LayoutTemplateUseCase) StampTemplate(templateID string, dates []string) (*entitiesDtos.TemplateApplyResponse, *entitiesDtos.ErrorResponse) {
+	template, err := uc.repo.GetTemplate(templateID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get template: " + err.Error(),
+		}
+	}
+
+	return uc.stamp("", template, dates)
+}
+
+// UnstampTemplate takes a template's never-booked slots off the given dates.
+func (uc *LayoutTemplateUseCase) UnstampTemplate(templateID string, dates []string) ([]string, *entitiesDtos.ErrorResponse) {
+	template, err := uc.repo.GetTemplate(templateID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get template: " + err.Error(),
+		}
+	}
+
+	slotIDs := make([]string, 0, len(dates)*len(template.Stalls))
+	for _, date := range dates {
+		for _, stall := range template.Stalls {
+			slotIDs = append(slotIDs, templateSlotID(template.MarketID, stall, date))
+		}
+	}
+
+	existing, err := uc.repo.GetSlotsByIDs(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to fetch existing slots: " + err.Error(),
+		}
+	}
+	removed, err := uc.repo.DeleteUnusedSlots(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to remove slots: " + err.Error(),
+		}
+	}
+
+	gone := make(map[string]bool, len(removed))
+	for _, id := range removed {
+		gone[id] = true
+	}
+	var before []*entities.Slot
+	for _, slot := range existing {
+		if gone[slot.ID] {
+			before = append(before, slot)
+		}
+	}
+	note := fmt.Sprintf("Template %s taken off closed dates", template.Name)
+	if errRes := uc.versions.RecordSlotChanges("", template.MarketID, note, before, nil); errRes != nil {
+		return nil, errRes
+	}
+
+	return removed, nil
+}
+
+func (uc *LayoutTemplateUseCase) ownTemplate(providerID, templateID string) (*entities.LayoutTemplate, *entitiesDtos.ErrorResponse) {
+	template, err := uc.repo.GetTemplate(templateID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get template: " + err.Error(),
+		}
+	}
+
+	if errRes := checkMarketOwner(uc.repo, providerID, template.MarketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	return template, nil
+}
+
+func (uc *LayoutTemplateUseCase) marketZones(marketID string) (*layoutZones, *entitiesDtos.ErrorResponse) {
+	zones, err := uc.repo.GetZones(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get zones: " + err.Error(),
+		}
+	}
+	return newLayoutZones(zones), nil
+}
+
+func templateStalls(templateID string, req *entitiesDtos.LayoutTemplateRequest, zones *layoutZones) ([]entities.TemplateStall, *entitiesDtos.ErrorResponse) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Template name is required",
+		}
+	}
+
+	stalls := make([]entities.TemplateStall, 0)
+	seen := make(map[string]bool)
+	for _, zoneLayout := range req.Layout {
+		zone, errRes := zones.resolve(zoneLayout.ZoneID, zoneLayout.Zone)
+		if errRes != nil {
+			return nil, errRes
+		}
+		for _, stall := range zoneLayout.Stalls {
+			checked, errRes := checkStall(zones, zone, zone.Name, stall, seen)
+			if errRes != nil {
+				return nil, errRes
+			}
+			checked.ID = uuid.New().String()
+			checked.TemplateID = templateID
+			stalls = append(stalls, checked)
+		}
+	}
+
+	if len(stalls) == 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Template needs at least one stall",
+		}
+	}
+
+	return stalls, nil
+}
+
+// checkStall validates one stall of a layout request, filling in what it left out from its zone. Stall names
+// must be unique within scope, which seen keeps track of, and the zone's category rules count the stall there.
+func checkStall(zones *layoutZones, zone *entities.Zone, scope string, stall entitiesDtos.Stall, seen map[string]bool) (entities.TemplateStall, *entitiesDtos.ErrorResponse) {
+	stall = withDefaults(zone, stall)
+	key := scope + "/" + stall.Name
+	if stall.Name == "" || seen[key] {
+		return entities.TemplateStall{}, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Each stall needs a name unique within its zone: %q", key),
+		}
+	}
+	seen[key] = true
+
+	category, err := parseCategory(stall.StallType)
+	if err != nil {
+		return entities.TemplateStall{}, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Invalid category for stall %s: %s", stall.Name, err.Error()),
+		}
+	}
+	if stall.Width <= 0 || stall.Height <= 0 || stall.Price < 0 {
+		return entities.TemplateStall{}, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Stall %s needs a positive size and a non-negative price", stall.Name),
+		}
+	}
+	if errRes := zones.admit(zone, scope, stall.Name, category); errRes != nil {
+		return entities.TemplateStall{}, errRes
+	}
+	geometry, errRes := slotGeometry(stall.Name, stall.SlotGeometry)
+	if errRes != nil {
+		return entities.TemplateStall{}, errRes
+	}
+
+	return entities.TemplateStall{
+		Zone:         zone.Name,
+		ZoneID:       zone.ID,
+		Name:         stall.Name,
+		Width:        stall.Width,
+		Height:       stall.Height,
+		Price:        entities.RoundMoney(stall.Price),
+		Category:     category,
+		SlotGeometry: geometry,
+	}, nil
+}
+
+// templateDates expands the range, keeping only the requested weekdays.
+func templateDates(req *entitiesDtos.ApplyTemplateRequest) ([]string, *entitiesDtos.ErrorResponse) {
+	start, err := time.Parse("2006-01-02", req.DateRange.StartDate)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid start date format. Please use YYYY-MM-DD",
+		}
+	}
+	end, err := time.Parse("2006-01-02", req.DateRange.EndDate)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid end date format. Please use YYYY-MM-DD",
+		}
+	}
+	if end.Before(start) || end.Sub(start).Hours()/24 >= maxTemplateDays {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Date range must run forward and cover at most %d days", maxTemplateDays),
+		}
+	}
+
+	weekdays := make(map[time.Weekday]bool)
+	for _, day := range req.Weekdays {
+		if day < 0 || day > 6 {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Invalid weekday %d, use 0 (Sunday) to 6 (Saturday)", day),
+			}
+		}
+		weekdays[time.Weekday(day)] = true
+	}
+
+	dates := make([]string, 0)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if len(weekdays) == 0 || weekdays[day.Weekday()] {
+			dates = append(dates, day.Format("2006-01-02"))
+		}
+	}
+
+	if len(dates) == 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "No dates in the range fall on the requested weekdays",
+		}
+	}
+
+	return dates, nil
+}
+
+// templateSlotID matches the IDs ApplyLayout gives its slots.
+func templateSlotID(marketID string, stall entities.TemplateStall, date string) string {
+	return fmt.Sprintf("%s-%s-%s-%s", marketID, stall.Zone, stall.Name, date)
+}
+
+func sameSlotShape(a, b *entities.Slot) bool {
+	return a.ZoneID == b.ZoneID && a.Width == b.Width && a.Height == b.Height && a.Category == b.Category &&
+		entities.RoundMoney(a.Price) == entities.RoundMoney(b.Price) && a.SameGeometry(b.SlotGeometry)
+}
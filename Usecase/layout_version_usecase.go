@@ -0,0 +1,664 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"sort"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+type LayoutVersionUseCase struct {
+	repo Interfaces.ILayoutVersion
+}
+
+var _ contact.ILayoutVersionUseCase = (*LayoutVersionUseCase)(nil)
+
+func NewLayoutVersionUseCase(repo Interfaces.ILayoutVersion) *LayoutVersionUseCase {
+	return &LayoutVersionUseCase{
+		repo: repo,
+	}
+}
+
+// layoutPlan is what applying a layout would do, worked out before anything is written.
+type layoutPlan struct {
+	diff     *entitiesDtos.LayoutDiff
+	stalls   []entities.LayoutVersionStall
+	slotIDs  []string
+	created  []*entities.Slot
+	updated  []*entities.Slot
+	revived  []*entities.Slot
+	removed  []string
+	baseline *entities.LayoutVersion
+}
+
+// ApplyLayout replaces every date/zone pair named in the request with the stalls given for it. With dryRun set
+// nothing is written and only the diff is returned. Otherwise the change is recorded as a new layout version.
+func (uc *LayoutVersionUseCase) ApplyLayout(providerID, marketID string, req *entitiesDtos.LayoutRequest, dryRun bool) (*entitiesDtos.LayoutApplyResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	zones, err := uc.repo.GetZones(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get zones: " + err.Error(),
+		}
+	}
+
+	stalls, scopes, errRes := layoutStalls(req.Layout, newLayoutZones(zones))
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	plan, errRes := uc.plan(marketID, stalls, scopes)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if dryRun {
+		return &entitiesDtos.LayoutApplyResponse{Diff: plan.diff}, nil
+	}
+
+	version := &entities.LayoutVersion{
+		Source:    entities.VersionApply,
+		Note:      req.Note,
+		CreatedBy: providerID,
+	}
+	return uc.commit(marketID, plan, version)
+}
+
+// RollbackLayout puts the zones a version covered back the way that version left them, as a new version.
+func (uc *LayoutVersionUseCase) RollbackLayout(providerID, marketID string, target int, dryRun bool) (*entitiesDtos.LayoutApplyResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	previous, err := uc.repo.GetVersion(marketID, target)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get layout version: " + err.Error(),
+		}
+	}
+
+	// Versions saved before the market had zones only know them by name
+	zones, err := uc.repo.GetZones(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get zones: " + err.Error(),
+		}
+	}
+	known := newLayoutZones(zones)
+	for i := range previous.Stalls {
+		if previous.Stalls[i].ZoneID != "" {
+			continue
+		}
+		if zone, errRes := known.resolve("", previous.Stalls[i].Zone); errRes == nil {
+			previous.Stalls[i].ZoneID = zone.ID
+		}
+	}
+
+	plan, errRes := uc.plan(marketID, previous.Stalls, previous.Scopes)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if dryRun {
+		return &entitiesDtos.LayoutApplyResponse{Diff: plan.diff}, nil
+	}
+
+	version := &entities.LayoutVersion{
+		Source:     entities.VersionRollback,
+		RestoredOf: previous.Version,
+		Note:       fmt.Sprintf("Rollback to version %d", previous.Version),
+		CreatedBy:  providerID,
+	}
+	return uc.commit(marketID, plan, version)
+}
+
+func (uc *LayoutVersionUseCase) GetVersions(providerID, marketID string) ([]entities.LayoutVersion, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	versions, err := uc.repo.GetVersions(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get layout versions: " + err.Error(),
+		}
+	}
+
+	return versions, nil
+}
+
+func (uc *LayoutVersionUseCase) GetVersion(providerID, marketID string, version int) (*entities.LayoutVersion, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "layout"); errRes != nil {
+		return nil, errRes
+	}
+
+	layoutVersion, err := uc.repo.GetVersion(marketID, version)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get layout version: " + err.Error(),
+		}
+	}
+
+	return layoutVersion, nil
+}
+
+// plan compares the wanted stalls with the live slots in the same scopes.
+func (uc *LayoutVersionUseCase) plan(marketID string, stalls []entities.LayoutVersionStall, scopes []string) (*layoutPlan, *entitiesDtos.ErrorResponse) {
+	inScope := make(map[string]bool, len(scopes))
+	dateSet := make(map[string]bool)
+	for _, scope := range scopes {
+		inScope[scope] = true
+		dateSet[strings.SplitN(scope, "/", 2)[0]] = true
+	}
+	dates := make([]string, 0, len(dateSet))
+	for date := range dateSet {
+		dates = append(dates, date)
+	}
+
+	existingSlots, err := uc.repo.GetSlotsOnDates(marketID, dates)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to fetch existing slots: " + err.Error(),
+		}
+	}
+	live := make(map[string]*entities.Slot)
+	retired := make(map[string]*entities.Slot)
+	liveIDs := make([]string, 0)
+	for _, slot := range existingSlots {
+		if !inScope[entities.LayoutScope(slotDate(slot), slot.Zone)] {
+			continue
+		}
+		if slot.DeletedAt != nil {
+			retired[slot.ID] = slot
+			continue
+		}
+		live[slot.ID] = slot
+		liveIDs = append(liveIDs, slot.ID)
+	}
+	sort.Strings(liveIDs)
+
+	plan := &layoutPlan{
+		diff: &entitiesDtos.LayoutDiff{
+			MarketID:         marketID,
+			Scopes:           scopes,
+			Added:            []entitiesDtos.StallChange{},
+			Removed:          []entitiesDtos.StallChange{},
+			Modified:         []entitiesDtos.StallChange{},
+			AffectedBookings: []entitiesDtos.AffectedBooking{},
+			CanApply:         true,
+		},
+		stalls: make([]entities.LayoutVersionStall, 0, len(stalls)),
+	}
+
+	wanted := make(map[string]bool, len(stalls))
+	priceChanged := make(map[string]float64)
+	for _, stall := range stalls {
+		slot := &entities.Slot{
+			ID:       layoutSlotID(marketID, stall.Zone, stall.Name, stall.Date),
+			MarketID: marketID,
+			Zone:     stall.Zone,
+			ZoneID:   stall.ZoneID,
+			Name:     stall.Name,
+			Width:    stall.Width,
+			Height:   stall.Height,
+			Price:    stall.Price,
+			Status:   entities.StatusAvailable,
+			Category: stall.Category,
+			Date:     stall.Date,
+		}
+		slot.SlotGeometry = stall.SlotGeometry
+		wanted[slot.ID] = true
+		plan.slotIDs = append(plan.slotIDs, slot.ID)
+		plan.stalls = append(plan.stalls, entities.LayoutVersionStall{
+			Date:         stall.Date,
+			Zone:         stall.Zone,
+			ZoneID:       stall.ZoneID,
+			Name:         stall.Name,
+			Width:        stall.Width,
+			Height:       stall.Height,
+			Price:        stall.Price,
+			Category:     stall.Category,
+			SlotGeometry: stall.SlotGeometry,
+		})
+
+		change := stallChange(slot)
+		change.After = slotShape(slot)
+		current, exists := live[slot.ID]
+		switch {
+		case exists:
+			fields := changedFields(current, slot)
+			if len(fields) == 0 {
+				plan.diff.Unchanged++
+				continue
+			}
+			change.Before = slotShape(current)
+			change.Fields = fields
+			plan.diff.Modified = append(plan.diff.Modified, change)
+			plan.updated = append(plan.updated, slot)
+			if entities.RoundMoney(current.Price) != slot.Price {
+				priceChanged[slot.ID] = slot.Price
+			}
+		case retired[slot.ID] != nil:
+			plan.diff.Added = append(plan.diff.Added, change)
+			plan.revived = append(plan.revived, slot)
+		default:
+			plan.diff.Added = append(plan.diff.Added, change)
+			plan.created = append(plan.created, slot)
+		}
+	}
+
+	for _, id := range liveIDs {
+		if wanted[id] {
+			continue
+		}
+		change := stallChange(live[id])
+		change.Before = slotShape(live[id])
+		plan.diff.Removed = append(plan.diff.Removed, change)
+		plan.removed = append(plan.removed, id)
+	}
+
+	impacted := append([]string{}, plan.removed...)
+	for id := range priceChanged {
+		impacted = append(impacted, id)
+	}
+	bookings, err := uc.repo.GetActiveBookingsBySlotIDs(impacted)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check slot bookings: " + err.Error(),
+		}
+	}
+	for _, booking := range bookings {
+		affected := entitiesDtos.AffectedBooking{
+			BookingID: booking.ID,
+			SlotID:    booking.SlotID,
+			VendorID:  booking.VendorID,
+			Status:    booking.Status,
+			PaidPrice: booking.Price,
+			Reason:    "removed",
+		}
+		if price, ok := priceChanged[booking.SlotID]; ok {
+			affected.NewPrice = price
+			affected.Reason = "price_changed"
+		} else {
+			// A booked stall cannot be taken out from under its vendor
+			plan.diff.CanApply = false
+		}
+		plan.diff.AffectedBookings = append(plan.diff.AffectedBookings, affected)
+	}
+
+	baseline, errRes := uc.baseline(marketID, scopes, live)
+	if errRes != nil {
+		return nil, errRes
+	}
+	plan.baseline = baseline
+
+	return plan, nil
+}
+
+// baseline snapshots scopes that no version has covered yet, so the first versioned change to them can be undone.
+func (uc *LayoutVersionUseCase) baseline(marketID string, scopes []string, live map[string]*entities.Slot) (*entities.LayoutVersion, *entitiesDtos.ErrorResponse) {
+	covered, err := uc.repo.GetVersionScopes(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get layout versions: " + err.Error(),
+		}
+	}
+	seen := make(map[string]bool, len(covered))
+	for _, scope := range covered {
+		seen[scope] = true
+	}
+
+	uncovered := make(map[string]bool)
+	baselineScopes := make([]string, 0)
+	for _, scope := range scopes {
+		if !seen[scope] {
+			uncovered[scope] = true
+			baselineScopes = append(baselineScopes, scope)
+		}
+	}
+
+	stalls := make([]entities.LayoutVersionStall, 0)
+	for _, slot := range live {
+		date := slotDate(slot)
+		if !uncovered[entities.LayoutScope(date, slot.Zone)] {
+			continue
+		}
+		stalls = append(stalls, versionStall(slot))
+	}
+	if len(stalls) == 0 {
+		return nil, nil
+	}
+
+	return &entities.LayoutVersion{
+		Source: entities.VersionBaseline,
+		Scopes: baselineScopes,
+		Note:   "Layout as it was before versioning",
+		Stalls: stalls,
+	}, nil
+}
+
+func (uc *LayoutVersionUseCase) commit(marketID string, plan *layoutPlan, version *entities.LayoutVersion) (*entitiesDtos.LayoutApplyResponse, *entitiesDtos.ErrorResponse) {
+	if !plan.diff.CanApply {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Some stalls to be removed have active bookings; cancel or move those bookings first",
+		}
+	}
+
+	// Slots with any booking history are retired rather than deleted so the history survives
+	bookedIDs, err := uc.repo.GetBookedSlotIDs(plan.removed)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check slot bookings: " + err.Error(),
+		}
+	}
+	hasHistory := make(map[string]bool, len(bookedIDs))
+	for _, id := range bookedIDs {
+		hasHistory[id] = true
+	}
+	var deleted, retired []string
+	for _, id := range plan.removed {
+		if hasHistory[id] {
+			retired = append(retired, id)
+		} else {
+			deleted = append(deleted, id)
+		}
+	}
+
+	versions := make([]*entities.LayoutVersion, 0, 2)
+	if plan.baseline != nil {
+		plan.baseline.CreatedBy = version.CreatedBy
+		versions = append(versions, plan.baseline)
+	}
+	version.Scopes = plan.diff.Scopes
+	version.Stalls = plan.stalls
+	versions = append(versions, version)
+	identifyVersions(marketID, versions)
+
+	if err := uc.repo.ApplyLayout(versions, plan.created, plan.updated, plan.revived, deleted, retired); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to apply layout: " + err.Error(),
+		}
+	}
+
+	slots, err := uc.repo.GetSlotsByIDs(plan.slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Layout applied but the slots could not be reloaded: " + err.Error(),
+		}
+	}
+
+	return &entitiesDtos.LayoutApplyResponse{
+		Version: version,
+		Diff:    plan.diff,
+		Slots:   slots,
+	}, nil
+}
+
+// RecordSlotChanges records a version for slots reshaped outside ApplyLayout, such as by a template, a bulk
+// edit or a single slot edit, so the version history stays in step with the floor and a later rollback does
+// not quietly undo the change. before holds the changed slots as they were and after as they are now saved;
+// a slot only in before was removed. Scopes no version has covered yet get a baseline of the old state first.
+func (uc *LayoutVersionUseCase) RecordSlotChanges(createdBy, marketID, note string, before, after []*entities.Slot) *entitiesDtos.ErrorResponse {
+	if len(before) == 0 && len(after) == 0 {
+		return nil
+	}
+
+	scopes := make([]string, 0)
+	inScope := make(map[string]bool)
+	dates := make([]string, 0)
+	onDate := make(map[string]bool)
+	for _, slot := range append(append([]*entities.Slot{}, before...), after...) {
+		date := slotDate(slot)
+		if scope := entities.LayoutScope(date, slot.Zone); !inScope[scope] {
+			inScope[scope] = true
+			scopes = append(scopes, scope)
+		}
+		if !onDate[date] {
+			onDate[date] = true
+			dates = append(dates, date)
+		}
+	}
+
+	saved, err := uc.repo.GetSlotsOnDates(marketID, dates)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to fetch existing slots: " + err.Error(),
+		}
+	}
+	current := make(map[string]*entities.Slot)
+	for _, slot := range saved {
+		if slot.DeletedAt == nil && inScope[entities.LayoutScope(slotDate(slot), slot.Zone)] {
+			current[slot.ID] = slot
+		}
+	}
+
+	// The scopes as they were: the saved slots with the changed ones put back and the new ones taken out
+	previous := make(map[string]*entities.Slot, len(current))
+	for id, slot := range current {
+		previous[id] = slot
+	}
+	for _, slot := range after {
+		delete(previous, slot.ID)
+	}
+	for _, slot := range before {
+		previous[slot.ID] = slot
+	}
+	baseline, errRes := uc.baseline(marketID, scopes, previous)
+	if errRes != nil {
+		return errRes
+	}
+
+	ids := make([]string, 0, len(current))
+	for id := range current {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	stalls := make([]entities.LayoutVersionStall, 0, len(ids))
+	for _, id := range ids {
+		stalls = append(stalls, versionStall(current[id]))
+	}
+
+	versions := make([]*entities.LayoutVersion, 0, 2)
+	if baseline != nil {
+		baseline.CreatedBy = createdBy
+		versions = append(versions, baseline)
+	}
+	versions = append(versions, &entities.LayoutVersion{
+		Source:    entities.VersionEdit,
+		Scopes:    scopes,
+		Note:      note,
+		CreatedBy: createdBy,
+		Stalls:    stalls,
+	})
+	identifyVersions(marketID, versions)
+
+	if err := uc.repo.ApplyLayout(versions, nil, nil, nil, nil, nil); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Slots saved but the layout version could not be recorded: " + err.Error(),
+		}
+	}
+	return nil
+}
+
+// layoutStalls validates a layout request against the market's zones and flattens it into stalls plus the
+// date/zone pairs it replaces. A zone sent with no stalls clears that zone for the date.
+func layoutStalls(layout []entitiesDtos.ZoneLayout, zones *layoutZones) ([]entities.LayoutVersionStall, []string, *entitiesDtos.ErrorResponse) {
+	if len(layout) == 0 {
+		return nil, nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Layout needs at least one zone",
+		}
+	}
+
+	stalls := make([]entities.LayoutVersionStall, 0)
+	scopes := make([]string, 0)
+	seenScope := make(map[string]bool)
+	seenStall := make(map[string]bool)
+	for _, zoneLayout := range layout {
+		if zoneLayout.Date.IsZero() {
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Each zone needs a date",
+			}
+		}
+		zone, errRes := zones.resolve(zoneLayout.ZoneID, zoneLayout.Zone)
+		if errRes != nil {
+			return nil, nil, errRes
+		}
+		date := zoneLayout.Date.Format("2006-01-02")
+		scope := entities.LayoutScope(date, zone.Name)
+		if !seenScope[scope] {
+			seenScope[scope] = true
+			scopes = append(scopes, scope)
+		}
+
+		for _, stall := range zoneLayout.Stalls {
+			checked, errRes := checkStall(zones, zone, scope, stall, seenStall)
+			if errRes != nil {
+				return nil, nil, errRes
+			}
+			stalls = append(stalls, entities.LayoutVersionStall{
+				Date:         date,
+				Zone:         checked.Zone,
+				ZoneID:       checked.ZoneID,
+				Name:         checked.Name,
+				Width:        checked.Width,
+				Height:       checked.Height,
+				Price:        checked.Price,
+				Category:     checked.Category,
+				SlotGeometry: checked.SlotGeometry,
+			})
+		}
+	}
+
+	return stalls, scopes, nil
+}
+
+// identifyVersions gives new versions and their stalls their IDs. Version numbers are handed out on save.
+func identifyVersions(marketID string, versions []*entities.LayoutVersion) {
+	for _, v := range versions {
+		v.ID = uuid.New().String()
+		v.MarketID = marketID
+		for i := range v.Stalls {
+			v.Stalls[i].ID = uuid.New().String()
+			v.Stalls[i].VersionID = v.ID
+		}
+	}
+}
+
+func versionStall(slot *entities.Slot) entities.LayoutVersionStall {
+	return entities.LayoutVersionStall{
+		Date:         slotDate(slot),
+		Zone:         slot.Zone,
+		ZoneID:       slot.ZoneID,
+		Name:         slot.Name,
+		Width:        slot.Width,
+		Height:       slot.Height,
+		Price:        entities.RoundMoney(slot.Price),
+		Category:     slot.Category,
+		SlotGeometry: slot.SlotGeometry,
+	}
+}
+
+// layoutSlotID is the deterministic slot ID shared with layout templates.
+func layoutSlotID(marketID, zone, name, date string) string {
+	return fmt.Sprintf("%s-%s-%s-%s", marketID, zone, name, date)
+}
+
+// slotDate trims the time part some drivers add when reading a date column back.
+func slotDate(slot *entities.Slot) string {
+	if len(slot.Date) > 10 {
+		return slot.Date[:10]
+	}
+	return slot.Date
+}
+
+func stallChange(slot *entities.Slot) entitiesDtos.StallChange {
+	return entitiesDtos.StallChange{
+		SlotID: slot.ID,
+		Date:   slotDate(slot),
+		Zone:   slot.Zone,
+		Name:   slot.Name,
+	}
+}
+
+func slotShape(slot *entities.Slot) *entitiesDtos.StallShape {
+	return &entitiesDtos.StallShape{
+		Width:        slot.Width,
+		Height:       slot.Height,
+		Price:        entities.RoundMoney(slot.Price),
+		Category:     slot.Category,
+		SlotGeometry: slot.SlotGeometry,
+	}
+}
+
+func changedFields(current, next *entities.Slot) []string {
+	fields := make([]string, 0)
+	if current.ZoneID != next.ZoneID {
+		fields = append(fields, "zone")
+	}
+	if current.Width != next.Width {
+		fields = append(fields, "width")
+	}
+	if current.Height != next.Height {
+		fields = append(fields, "height")
+	}
+	if entities.RoundMoney(current.Price) != entities.RoundMoney(next.Price) {
+		fields = append(fields, "price")
+	}
+	if current.Category != next.Category {
+		fields = append(fields, "category")
+	}
+	if current.X != next.X || current.Y != next.Y {
+		fields = append(fields, "position")
+	}
+	if current.Rotation != next.Rotation {
+		fields = append(fields, "rotation")
+	}
+	if !(entities.SlotGeometry{Shape: current.Shape}).SameGeometry(entities.SlotGeometry{Shape: next.Shape}) {
+		fields = append(fields, "shape")
+	}
+	return fields
+}
+
+// slotGeometry checks a stall's placement and normalises its rotation to [0, 360).
+func slotGeometry(name string, geometry entities.SlotGeometry) (entities.SlotGeometry, *entitiesDtos.ErrorResponse) {
+	if geometry.X < 0 || geometry.Y < 0 {
+		return geometry, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Stall %s cannot sit at a negative position", name),
+		}
+	}
+	if len(geometry.Shape) > 0 && len(geometry.Shape) < 3 {
+		return geometry, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Stall %s needs at least three points for a polygon shape", name),
+		}
+	}
+
+	geometry.Rotation = math.Mod(geometry.Rotation, 360)
+	if geometry.Rotation < 0 {
+		geometry.Rotation += 360
+	}
+	return geometry, nil
+}
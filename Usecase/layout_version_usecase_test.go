@@ -0,0 +1,644 @@
+package Usecase
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+const layoutDate = "2024-06-01"
+
+// fakeLayoutRepo keeps one market's zones, slots, bookings and layout versions in memory.
+type fakeLayoutRepo struct {
+	providerID string
+	zones      []entities.Zone
+	slots      map[string]*entities.Slot
+	bookings   []entities.Booking
+	versions   []*entities.LayoutVersion
+}
+
+func (f *fakeLayoutRepo) GetSlotsOnDates(marketID string, dates []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	for _, slot := range f.slots {
+		for _, date := range dates {
+			if slot.MarketID == marketID && slotDate(slot) == date {
+				copied := *slot
+				slots = append(slots, &copied)
+			}
+		}
+	}
+	return slots, nil
+}
+
+func (f *fakeLayoutRepo) GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	for _, booking := range f.bookings {
+		if booking.Status != entities.StatusPending && booking.Status != entities.StatusCompleted {
+			continue
+		}
+		for _, slotID := range slotIDs {
+			if booking.SlotID == slotID {
+				bookings = append(bookings, booking)
+			}
+		}
+	}
+	return bookings, nil
+}
+
+func (f *fakeLayoutRepo) GetBookedSlotIDs(slotIDs []string) ([]string, error) {
+	var booked []string
+	for _, slotID := range slotIDs {
+		for _, booking := range f.bookings {
+			if booking.SlotID == slotID {
+				booked = append(booked, slotID)
+				break
+			}
+		}
+	}
+	return booked, nil
+}
+
+func (f *fakeLayoutRepo) ApplyLayout(versions []*entities.LayoutVersion, created, updated, revived []*entities.Slot, deleted, retired []string) error {
+	for _, version := range versions {
+		version.Version = len(f.versions) + 1
+		f.versions = append(f.versions, version)
+	}
+	for _, slot := range created {
+		f.slots[slot.ID] = slot
+	}
+	for _, slot := range updated {
+		current := f.slots[slot.ID]
+		current.ZoneID, current.Width, current.Height, current.Price = slot.ZoneID, slot.Width, slot.Height, slot.Price
+		current.Category, current.SlotGeometry = slot.Category, slot.SlotGeometry
+	}
+	for _, slot := range revived {
+		slot.Status, slot.Booker, slot.DeletedAt = entities.StatusAvailable, "", nil
+		f.slots[slot.ID] = slot
+	}
+	for _, id := range deleted {
+		delete(f.slots, id)
+	}
+	now := time.Now()
+	for _, id := range retired {
+		f.slots[id].Status, f.slots[id].DeletedAt = entities.StatusMaintenance, &now
+	}
+	return nil
+}
+
+func (f *fakeLayoutRepo) GetVersionScopes(marketID string) ([]string, error) {
+	scopes := make([]string, 0)
+	for _, version := range f.versions {
+		scopes = append(scopes, version.Scopes...)
+	}
+	return scopes, nil
+}
+
+func (f *fakeLayoutRepo) GetVersions(marketID string) ([]entities.LayoutVersion, error) {
+	versions := make([]entities.LayoutVersion, 0, len(f.versions))
+	for i := len(f.versions) - 1; i >= 0; i-- {
+		versions = append(versions, *f.versions[i])
+	}
+	return versions, nil
+}
+
+func (f *fakeLayoutRepo) GetVersion(marketID string, version int) (*entities.LayoutVersion, error) {
+	for _, v := range f.versions {
+		if v.Version == version {
+			copied := *v
+			copied.Stalls = append([]entities.LayoutVersionStall{}, v.Stalls...)
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("record not found")
+}
+
+func (f *fakeLayoutRepo) GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	for _, id := range slotIDs {
+		if slot, ok := f.slots[id]; ok {
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
+
+func (f *fakeLayoutRepo) GetZones(marketID string) ([]entities.Zone, error) {
+	return append([]entities.Zone{}, f.zones...), nil
+}
+
+func (f *fakeLayoutRepo) GetMarketProviderID(marketID string) (string, error) {
+	if marketID != "m1" {
+		return "", fmt.Errorf("record not found")
+	}
+	return f.providerID, nil
+}
+
+// newFakeLayoutRepo starts market m1 with stalls S1 and S2 in zone A, S9 in zone B and a retired S3 in zone A,
+// all on layoutDate.
+func newFakeLayoutRepo() *fakeLayoutRepo {
+	slot := func(zone, name string) *entities.Slot {
+		return &entities.Slot{
+			ID:       layoutSlotID("m1", zone, name, layoutDate),
+			MarketID: "m1",
+			Zone:     zone,
+			ZoneID:   "z" + zone,
+			Name:     name,
+			Width:    2,
+			Height:   2,
+			Price:    100,
+			Status:   entities.StatusAvailable,
+			Category: entities.CategoryFood,
+			Date:     layoutDate + "T00:00:00Z",
+		}
+	}
+	retired := slot("A", "S3")
+	retiredAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	retired.Status, retired.DeletedAt = entities.StatusMaintenance, &retiredAt
+
+	repo := &fakeLayoutRepo{
+		providerID: "p1",
+		zones: []entities.Zone{
+			{ID: "zA", MarketID: "m1", Name: "A"},
+			{ID: "zB", MarketID: "m1", Name: "B"},
+		},
+		slots: make(map[string]*entities.Slot),
+	}
+	for _, s := range []*entities.Slot{slot("A", "S1"), slot("A", "S2"), slot("B", "S9"), retired} {
+		repo.slots[s.ID] = s
+	}
+	return repo
+}
+
+func layoutStall(name string, width, height int, price float64) entitiesDtos.Stall {
+	return entitiesDtos.Stall{Name: name, Width: width, Height: height, Price: price, StallType: string(entities.CategoryFood)}
+}
+
+func zoneALayout(stalls ...entitiesDtos.Stall) *entitiesDtos.LayoutRequest {
+	date, _ := time.Parse("2006-01-02", layoutDate)
+	return &entitiesDtos.LayoutRequest{
+		Layout: []entitiesDtos.ZoneLayout{{ZoneID: "zA", Date: date, Stalls: append([]entitiesDtos.Stall{}, stalls...)}},
+	}
+}
+
+func changeNames(changes []entitiesDtos.StallChange) []string {
+	names := make([]string, 0, len(changes))
+	for _, change := range changes {
+		names = append(names, change.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func slotNames(slots []*entities.Slot) []string {
+	names := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		names = append(names, slot.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestLayoutPlanDiff(t *testing.T) {
+	s1 := layoutStall("S1", 2, 2, 100)
+	s2 := layoutStall("S2", 2, 2, 100)
+	booked := func(name string) entities.Booking {
+		return entities.Booking{ID: "b-" + name, SlotID: layoutSlotID("m1", "A", name, layoutDate), VendorID: "v1", Price: 100, Status: entities.StatusCompleted}
+	}
+
+	tests := []struct {
+		name          string
+		layout        *entitiesDtos.LayoutRequest
+		bookings      []entities.Booking
+		wantAdded     []string
+		wantRemoved   []string
+		wantModified  map[string][]string
+		wantUnchanged int
+		wantCreated   []string
+		wantRevived   []string
+		wantAffected  map[string]string
+		wantCanApply  bool
+	}{
+		{
+			name:          "same layout",
+			layout:        zoneALayout(s1, s2),
+			wantUnchanged: 2,
+			wantCanApply:  true,
+		},
+		{
+			name:          "new stall",
+			layout:        zoneALayout(s1, s2, layoutStall("S4", 3, 2, 120)),
+			wantAdded:     []string{"S4"},
+			wantUnchanged: 2,
+			wantCreated:   []string{"S4"},
+			wantCanApply:  true,
+		},
+		{
+			name:          "retired stall comes back",
+			layout:        zoneALayout(s1, s2, layoutStall("S3", 2, 2, 100)),
+			wantAdded:     []string{"S3"},
+			wantUnchanged: 2,
+			wantRevived:   []string{"S3"},
+			wantCanApply:  true,
+		},
+		{
+			name:          "free stall removed",
+			layout:        zoneALayout(s1),
+			wantRemoved:   []string{"S2"},
+			wantUnchanged: 1,
+			wantCanApply:  true,
+		},
+		{
+			name:          "cancelled booking does not block removal",
+			layout:        zoneALayout(s1),
+			bookings:      []entities.Booking{{ID: "old", SlotID: layoutSlotID("m1", "A", "S2", layoutDate), Status: entities.StatusCancelled}},
+			wantRemoved:   []string{"S2"},
+			wantUnchanged: 1,
+			wantCanApply:  true,
+		},
+		{
+			name:          "booked stall removed",
+			layout:        zoneALayout(s1),
+			bookings:      []entities.Booking{booked("S2")},
+			wantRemoved:   []string{"S2"},
+			wantUnchanged: 1,
+			wantAffected:  map[string]string{"b-S2": "removed"},
+		},
+		{
+			name:          "zone cleared",
+			layout:        zoneALayout(),
+			wantRemoved:   []string{"S1", "S2"},
+			wantUnchanged: 0,
+			wantCanApply:  true,
+		},
+		{
+			name:          "price change on a booked stall",
+			layout:        zoneALayout(layoutStall("S1", 2, 2, 150.004), s2),
+			bookings:      []entities.Booking{booked("S1")},
+			wantModified:  map[string][]string{"S1": {"price"}},
+			wantUnchanged: 1,
+			wantAffected:  map[string]string{"b-S1": "price_changed"},
+			wantCanApply:  true,
+		},
+		{
+			name: "resized and moved",
+			layout: zoneALayout(
+				entitiesDtos.Stall{Name: "S1", Width: 3, Height: 4, Price: 100, StallType: "food", SlotGeometry: entities.SlotGeometry{X: 10, Y: 5}},
+				entitiesDtos.Stall{Name: "S2", Width: 2, Height: 2, Price: 100, StallType: "crafts", SlotGeometry: entities.SlotGeometry{Rotation: -90}},
+			),
+			wantModified:  map[string][]string{"S1": {"width", "height", "position"}, "S2": {"category", "rotation"}},
+			wantUnchanged: 0,
+			wantCanApply:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeLayoutRepo()
+			repo.bookings = tt.bookings
+			uc := &LayoutVersionUseCase{repo: repo}
+
+			stalls, scopes, errRes := layoutStalls(tt.layout.Layout, newLayoutZones(repo.zones))
+			if errRes != nil {
+				t.Fatalf("layoutStalls() error = %v", errRes)
+			}
+			plan, errRes := uc.plan("m1", stalls, scopes)
+			if errRes != nil {
+				t.Fatalf("plan() error = %v", errRes)
+			}
+			diff := plan.diff
+
+			if got := changeNames(diff.Added); !reflect.DeepEqual(got, nonNil(tt.wantAdded)) {
+				t.Errorf("added = %v, want %v", got, tt.wantAdded)
+			}
+			if got := changeNames(diff.Removed); !reflect.DeepEqual(got, nonNil(tt.wantRemoved)) {
+				t.Errorf("removed = %v, want %v", got, tt.wantRemoved)
+			}
+			gotModified := make(map[string][]string)
+			for _, change := range diff.Modified {
+				gotModified[change.Name] = change.Fields
+			}
+			if len(gotModified) > 0 || len(tt.wantModified) > 0 {
+				if !reflect.DeepEqual(gotModified, tt.wantModified) {
+					t.Errorf("modified = %v, want %v", gotModified, tt.wantModified)
+				}
+			}
+			if diff.Unchanged != tt.wantUnchanged {
+				t.Errorf("unchanged = %d, want %d", diff.Unchanged, tt.wantUnchanged)
+			}
+			if got := slotNames(plan.created); !reflect.DeepEqual(got, nonNil(tt.wantCreated)) {
+				t.Errorf("created = %v, want %v", got, tt.wantCreated)
+			}
+			if got := slotNames(plan.revived); !reflect.DeepEqual(got, nonNil(tt.wantRevived)) {
+				t.Errorf("revived = %v, want %v", got, tt.wantRevived)
+			}
+			gotAffected := make(map[string]string)
+			for _, affected := range diff.AffectedBookings {
+				gotAffected[affected.BookingID] = affected.Reason
+				if affected.Reason == "price_changed" && affected.NewPrice != 150 {
+					t.Errorf("booking %s new price = %.2f, want 150.00", affected.BookingID, affected.NewPrice)
+				}
+			}
+			if len(gotAffected) > 0 || len(tt.wantAffected) > 0 {
+				if !reflect.DeepEqual(gotAffected, tt.wantAffected) {
+					t.Errorf("affected bookings = %v, want %v", gotAffected, tt.wantAffected)
+				}
+			}
+			if diff.CanApply != tt.wantCanApply {
+				t.Errorf("can apply = %v, want %v", diff.CanApply, tt.wantCanApply)
+			}
+		})
+	}
+}
+
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+func TestLayoutRollback(t *testing.T) {
+	tests := []struct {
+		name       string
+		providerID string
+		target     int
+		// prepare runs after the change being rolled back was applied
+		prepare      func(repo *fakeLayoutRepo)
+		wantCode     int
+		wantAdded    []string
+		wantRemoved  []string
+		wantModified []string
+		wantSlots    map[string]float64
+	}{
+		{
+			name:         "back to the baseline",
+			target:       1,
+			wantAdded:    []string{"S2"},
+			wantRemoved:  []string{"S4"},
+			wantModified: []string{"S1"},
+			wantSlots:    map[string]float64{"S1": 100, "S2": 100},
+		},
+		{
+			name:      "to the current version changes nothing",
+			target:    2,
+			wantSlots: map[string]float64{"S1": 150, "S4": 80},
+		},
+		{
+			name:   "version saved before the market had zones",
+			target: 1,
+			prepare: func(repo *fakeLayoutRepo) {
+				for i := range repo.versions[0].Stalls {
+					repo.versions[0].Stalls[i].ZoneID = ""
+				}
+			},
+			wantAdded:    []string{"S2"},
+			wantRemoved:  []string{"S4"},
+			wantModified: []string{"S1"},
+			wantSlots:    map[string]float64{"S1": 100, "S2": 100},
+		},
+		{
+			name:   "stall to be removed has a booking",
+			target: 1,
+			prepare: func(repo *fakeLayoutRepo) {
+				repo.bookings = append(repo.bookings, entities.Booking{ID: "b1", SlotID: layoutSlotID("m1", "A", "S4", layoutDate), Status: entities.StatusPending})
+			},
+			wantCode: 409,
+		},
+		{
+			name:       "another provider's market",
+			providerID: "p2",
+			target:     1,
+			wantCode:   403,
+		},
+		{
+			name:     "unknown version",
+			target:   9,
+			wantCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeLayoutRepo()
+			uc := &LayoutVersionUseCase{repo: repo}
+
+			// Raise S1's price, drop S2 and add S4. The first change to zone A also snapshots it as version 1.
+			applied, errRes := uc.ApplyLayout("p1", "m1", zoneALayout(layoutStall("S1", 2, 2, 150), layoutStall("S4", 2, 2, 80)), false)
+			if errRes != nil {
+				t.Fatalf("ApplyLayout() error = %v", errRes)
+			}
+			if applied.Version.Version != 2 || repo.versions[0].Source != entities.VersionBaseline {
+				t.Fatalf("versions = %d after a %s, want a baseline then version 2", applied.Version.Version, repo.versions[0].Source)
+			}
+			if tt.prepare != nil {
+				tt.prepare(repo)
+			}
+			providerID := tt.providerID
+			if providerID == "" {
+				providerID = "p1"
+			}
+
+			preview, errRes := uc.RollbackLayout(providerID, "m1", tt.target, true)
+			if errRes != nil && errRes.Code != tt.wantCode {
+				t.Fatalf("RollbackLayout() dry run error = %v, want code %d", errRes, tt.wantCode)
+			}
+			if errRes == nil {
+				if preview.Version != nil || len(repo.versions) != 2 {
+					t.Fatalf("dry run wrote a version")
+				}
+				if tt.wantCode == 0 {
+					if got := changeNames(preview.Diff.Added); !reflect.DeepEqual(got, nonNil(tt.wantAdded)) {
+						t.Errorf("added = %v, want %v", got, tt.wantAdded)
+					}
+					if got := changeNames(preview.Diff.Removed); !reflect.DeepEqual(got, nonNil(tt.wantRemoved)) {
+						t.Errorf("removed = %v, want %v", got, tt.wantRemoved)
+					}
+					if got := changeNames(preview.Diff.Modified); !reflect.DeepEqual(got, nonNil(tt.wantModified)) {
+						t.Errorf("modified = %v, want %v", got, tt.wantModified)
+					}
+				} else if preview.Diff.CanApply {
+					t.Errorf("dry run can apply, want it blocked")
+				}
+			}
+
+			result, errRes := uc.RollbackLayout(providerID, "m1", tt.target, false)
+			if tt.wantCode != 0 {
+				if errRes == nil || errRes.Code != tt.wantCode {
+					t.Fatalf("RollbackLayout() error = %v, want code %d", errRes, tt.wantCode)
+				}
+				if len(repo.versions) != 2 {
+					t.Errorf("%d versions after a refused rollback, want 2", len(repo.versions))
+				}
+				return
+			}
+			if errRes != nil {
+				t.Fatalf("RollbackLayout() error = %v", errRes)
+			}
+
+			if result.Version.Version != 3 || result.Version.Source != entities.VersionRollback || result.Version.RestoredOf != tt.target {
+				t.Errorf("version = %d %s of %d, want 3 rollback of %d",
+					result.Version.Version, result.Version.Source, result.Version.RestoredOf, tt.target)
+			}
+			gotSlots := make(map[string]float64)
+			for _, slot := range repo.slots {
+				if slot.Zone == "A" && slot.DeletedAt == nil {
+					gotSlots[slot.Name] = slot.Price
+					if slot.ZoneID != "zA" {
+						t.Errorf("slot %s zone ID = %q, want zA", slot.Name, slot.ZoneID)
+					}
+				}
+			}
+			if !reflect.DeepEqual(gotSlots, tt.wantSlots) {
+				t.Errorf("zone A slots = %v, want %v", gotSlots, tt.wantSlots)
+			}
+			if repo.slots[layoutSlotID("m1", "B", "S9", layoutDate)] == nil {
+				t.Errorf("zone B was touched by a zone A rollback")
+			}
+		})
+	}
+}
+
+func TestRecordSlotChanges(t *testing.T) {
+	s1, s2 := layoutSlotID("m1", "A", "S1", layoutDate), layoutSlotID("m1", "A", "S2", layoutDate)
+
+	tests := []struct {
+		name string
+		// edit changes the repo the way the caller's own write would and returns the slots before and after
+		edit        func(repo *fakeLayoutRepo) (before, after []*entities.Slot)
+		versioned   bool
+		wantSources []entities.LayoutVersionSource
+		wantBase    map[string]float64
+		wantLatest  map[string]float64
+	}{
+		{
+			name: "price edit on an unversioned zone",
+			edit: func(repo *fakeLayoutRepo) ([]*entities.Slot, []*entities.Slot) {
+				before := *repo.slots[s1]
+				repo.slots[s1].Price = 130
+				return []*entities.Slot{&before}, []*entities.Slot{repo.slots[s1]}
+			},
+			wantSources: []entities.LayoutVersionSource{entities.VersionBaseline, entities.VersionEdit},
+			wantBase:    map[string]float64{"S1": 100, "S2": 100},
+			wantLatest:  map[string]float64{"S1": 130, "S2": 100},
+		},
+		{
+			name: "stall removed",
+			edit: func(repo *fakeLayoutRepo) ([]*entities.Slot, []*entities.Slot) {
+				before := repo.slots[s2]
+				delete(repo.slots, s2)
+				return []*entities.Slot{before}, nil
+			},
+			wantSources: []entities.LayoutVersionSource{entities.VersionBaseline, entities.VersionEdit},
+			wantBase:    map[string]float64{"S1": 100, "S2": 100},
+			wantLatest:  map[string]float64{"S1": 100},
+		},
+		{
+			name: "stall created",
+			edit: func(repo *fakeLayoutRepo) ([]*entities.Slot, []*entities.Slot) {
+				created := &entities.Slot{ID: layoutSlotID("m1", "A", "S5", layoutDate), MarketID: "m1", Zone: "A", ZoneID: "zA", Name: "S5",
+					Width: 2, Height: 2, Price: 60, Category: entities.CategoryFood, Date: layoutDate}
+				repo.slots[created.ID] = created
+				return nil, []*entities.Slot{created}
+			},
+			wantSources: []entities.LayoutVersionSource{entities.VersionBaseline, entities.VersionEdit},
+			wantBase:    map[string]float64{"S1": 100, "S2": 100},
+			wantLatest:  map[string]float64{"S1": 100, "S2": 100, "S5": 60},
+		},
+		{
+			name:      "zone already versioned",
+			versioned: true,
+			edit: func(repo *fakeLayoutRepo) ([]*entities.Slot, []*entities.Slot) {
+				before := *repo.slots[s1]
+				repo.slots[s1].Price = 130
+				return []*entities.Slot{&before}, []*entities.Slot{repo.slots[s1]}
+			},
+			wantSources: []entities.LayoutVersionSource{entities.VersionApply, entities.VersionEdit},
+			wantLatest:  map[string]float64{"S1": 130, "S2": 100},
+		},
+		{
+			name: "nothing changed",
+			edit: func(repo *fakeLayoutRepo) ([]*entities.Slot, []*entities.Slot) {
+				return nil, nil
+			},
+			wantSources: []entities.LayoutVersionSource{},
+		},
+	}
+
+	stallPrices := func(version *entities.LayoutVersion) map[string]float64 {
+		prices := make(map[string]float64)
+		for _, stall := range version.Stalls {
+			prices[stall.Name] = stall.Price
+		}
+		return prices
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeLayoutRepo()
+			if tt.versioned {
+				repo.versions = append(repo.versions, &entities.LayoutVersion{Version: 1, Source: entities.VersionApply,
+					Scopes: []string{entities.LayoutScope(layoutDate, "A")}})
+			}
+			uc := &LayoutVersionUseCase{repo: repo}
+
+			before, after := tt.edit(repo)
+			if errRes := uc.RecordSlotChanges("p1", "m1", "edited", before, after); errRes != nil {
+				t.Fatalf("RecordSlotChanges() error = %v", errRes)
+			}
+
+			sources := make([]entities.LayoutVersionSource, 0, len(repo.versions))
+			for _, version := range repo.versions {
+				sources = append(sources, version.Source)
+			}
+			if !reflect.DeepEqual(sources, tt.wantSources) {
+				t.Fatalf("versions = %v, want %v", sources, tt.wantSources)
+			}
+			if len(repo.versions) == 0 {
+				return
+			}
+			latest := repo.versions[len(repo.versions)-1]
+			if got := stallPrices(latest); !reflect.DeepEqual(got, tt.wantLatest) {
+				t.Errorf("latest version = %v, want %v", got, tt.wantLatest)
+			}
+			if latest.CreatedBy != "p1" || latest.Note != "edited" {
+				t.Errorf("latest version by %q noted %q", latest.CreatedBy, latest.Note)
+			}
+			if tt.wantBase != nil {
+				if got := stallPrices(repo.versions[0]); !reflect.DeepEqual(got, tt.wantBase) {
+					t.Errorf("baseline = %v, want %v", got, tt.wantBase)
+				}
+			}
+		})
+	}
+}
+
+func TestRollbackUndoesRecordedEdit(t *testing.T) {
+	repo := newFakeLayoutRepo()
+	uc := &LayoutVersionUseCase{repo: repo}
+	s1 := layoutSlotID("m1", "A", "S1", layoutDate)
+
+	before := *repo.slots[s1]
+	repo.slots[s1].Price = 130
+	if errRes := uc.RecordSlotChanges("p1", "m1", "bulk edit", []*entities.Slot{&before}, []*entities.Slot{repo.slots[s1]}); errRes != nil {
+		t.Fatalf("RecordSlotChanges() error = %v", errRes)
+	}
+
+	current, errRes := uc.RollbackLayout("p1", "m1", 2, true)
+	if errRes != nil {
+		t.Fatalf("RollbackLayout() error = %v", errRes)
+	}
+	if n := len(current.Diff.Modified) + len(current.Diff.Added) + len(current.Diff.Removed); n != 0 {
+		t.Errorf("rolling back to the edit itself changes %d stalls, want none", n)
+	}
+
+	if _, errRes := uc.RollbackLayout("p1", "m1", 1, false); errRes != nil {
+		t.Fatalf("RollbackLayout() error = %v", errRes)
+	}
+	if price := repo.slots[s1].Price; price != 100 {
+		t.Errorf("S1 price after rolling back the edit = %.2f, want 100", price)
+	}
+}
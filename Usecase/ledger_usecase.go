@@ -0,0 +1,464 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"os"
+	"strconv"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+type LedgerUseCase struct {
+	repo    Interfaces.ILedger
+	service *Services.LedgerService
+}
+
+var _ contact.ILedgerUseCase = (*LedgerUseCase)(nil)
+
+func NewLedgerUseCase(repo Interfaces.ILedger, service *Services.LedgerService) *LedgerUseCase {
+	return &LedgerUseCase{
+		repo:    repo,
+		service: service,
+	}
+}
+
+// RecordCharge posts the vendor's payment for a completed booking and the platform fee taken from the provider.
+func (uc *LedgerUseCase) RecordCharge(bookingID string) *entitiesDtos.ErrorResponse {
+	booking, providerID, err := uc.repo.GetBookingParties(bookingID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to load booking for ledger: " + err.Error(),
+		}
+	}
+
+	amount := bookingAmount(booking)
+	cash := amount
+	if booking.Payment != nil {
+		cash = entities.RoundMoney(amount - booking.Payment.CreditUsed)
+	}
+
+	gateway, vendor, provider, errRes := uc.bookingAccounts(booking.VendorID, providerID)
+	if errRes != nil {
+		return errRes
+	}
+
+	// Cash received through the gateway tops up the vendor account; wallet credit is already sitting there
+	lines := make([]entities.JournalLine, 0, 4)
+	if cash > 0 {
+		lines = append(lines,
+			entities.JournalLine{AccountID: gateway.ID, Debit: cash},
+			entities.JournalLine{AccountID: vendor.ID, Credit: cash},
+		)
+	}
+	lines = append(lines,
+		entities.JournalLine{AccountID: vendor.ID, Debit: amount},
+		entities.JournalLine{AccountID: provider.ID, Credit: amount},
+	)
+	if errRes := uc.post(entities.JournalCharge, bookingID, "Booking charge", lines); errRes != nil {
+		return errRes
+	}
+
+	fee := platformFee(amount)
+	if fee <= 0 {
+		return nil
+	}
+
+	platform, errRes := uc.account(entities.AccountPlatform, entities.PlatformOwnerID, "Platform revenue")
+	if errRes != nil {
+		return errRes
+	}
+
+	return uc.post(entities.JournalFee, bookingID, "Platform fee", []entities.JournalLine{
+		{AccountID: provider.ID, Debit: fee},
+		{AccountID: platform.ID, Credit: fee},
+	})
+}
+
+// RecordRefund reverses the charge (and any platform fee) posted for a booking. A wallet refund leaves the
+// cash in clearing and moves the full amount back to the vendor account as credit instead.
+func (uc *LedgerUseCase) RecordRefund(bookingID string, toWallet bool) *entitiesDtos.ErrorResponse {
+	charge, err := uc.repo.GetEntryByReference(entities.JournalCharge, bookingID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to look up charge: " + err.Error(),
+		}
+	}
+	if charge == nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("No charge recorded for booking %s", bookingID),
+		}
+	}
+
+	booking, providerID, err := uc.repo.GetBookingParties(bookingID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to load booking for ledger: " + err.Error(),
+		}
+	}
+	gateway, vendor, _, errRes := uc.bookingAccounts(booking.VendorID, providerID)
+	if errRes != nil {
+		return errRes
+	}
+
+	// Mirror the charge lines so the refund exactly cancels them. For a wallet refund the gateway leg and the
+	// vendor credit paired with it stay in place.
+	lines := make([]entities.JournalLine, 0, len(charge.Lines)+2)
+	for _, line := range charge.Lines {
+		if toWallet && (line.AccountID == gateway.ID || (line.AccountID == vendor.ID && line.Credit > 0)) {
+			continue
+		}
+		lines = append(lines, entities.JournalLine{AccountID: line.AccountID, Debit: line.Credit, Credit: line.Debit})
+	}
+
+	fee, err := uc.repo.GetEntryByReference(entities.JournalFee, bookingID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to look up fee: " + err.Error(),
+		}
+	}
+	if fee != nil {
+		for _, line := range fee.Lines {
+			lines = append(lines, entities.JournalLine{AccountID: line.AccountID, Debit: line.Credit, Credit: line.Debit})
+		}
+	}
+
+	return uc.post(entities.JournalRefund, bookingID, "Booking refund", lines)
+}
+
+// RecordCreditIssued moves goodwill or promotional credit from its issuer to the vendor account.
+func (uc *LedgerUseCase) RecordCreditIssued(credit *entities.WalletCredit) *entitiesDtos.ErrorResponse {
+	issuer, errRes := uc.account(credit.IssuerType, credit.IssuerID, "")
+	if errRes != nil {
+		return errRes
+	}
+	vendor, errRes := uc.account(entities.AccountVendor, credit.VendorID, "")
+	if errRes != nil {
+		return errRes
+	}
+
+	return uc.post(entities.JournalCredit, credit.ID, fmt.Sprintf("Wallet credit (%s)", credit.Source), []entities.JournalLine{
+		{AccountID: issuer.ID, Debit: credit.Amount},
+		{AccountID: vendor.ID, Credit: credit.Amount},
+	})
+}
+
+// RecordCreditExpired returns the unused part of an expired credit lot to its issuer.
+func (uc *LedgerUseCase) RecordCreditExpired(credit *entities.WalletCredit, expiry *entities.WalletTransaction) *entitiesDtos.ErrorResponse {
+	issuer, errRes := uc.account(credit.IssuerType, credit.IssuerID, "")
+	if errRes != nil {
+		return errRes
+	}
+	vendor, errRes := uc.account(entities.AccountVendor, credit.VendorID, "")
+	if errRes != nil {
+		return errRes
+	}
+
+	return uc.post(entities.JournalExpiry, expiry.ID, "Wallet credit expired", []entities.JournalLine{
+		{AccountID: vendor.ID, Debit: expiry.Amount},
+		{AccountID: issuer.ID, Credit: expiry.Amount},
+	})
+}
+
+// RecordSettlement records a payout of the provider's balance from the gateway clearing account.
+func (uc *LedgerUseCase) RecordSettlement(req *entitiesDtos.SettlementRequest) (*entities.JournalEntry, *entitiesDtos.ErrorResponse) {
+	if req.ProviderID == "" || req.Amount <= 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Provider ID and a positive amount are required",
+		}
+	}
+
+	provider, errRes := uc.account(entities.AccountProvider, req.ProviderID, "")
+	if errRes != nil {
+		return nil, errRes
+	}
+	gateway, errRes := uc.account(entities.AccountGatewayClearing, entities.GatewayOwnerID, "PromptPay clearing")
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	amount := entities.RoundMoney(req.Amount)
+	description := req.Description
+	if description == "" {
+		description = "Provider settlement"
+	}
+
+	referenceID := uuid.New().String()
+	errRes = uc.post(entities.JournalSettlement, referenceID, description, []entities.JournalLine{
+		{AccountID: provider.ID, Debit: amount},
+		{AccountID: gateway.ID, Credit: amount},
+	})
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return uc.getEntry(entities.JournalSettlement, referenceID)
+}
+
+// RecordAdjustment posts a manual correction. The lines must balance like any other entry.
+func (uc *LedgerUseCase) RecordAdjustment(req *entitiesDtos.AdjustmentRequest) (*entities.JournalEntry, *entitiesDtos.ErrorResponse) {
+	if req.Description == "" || len(req.Lines) < 2 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "An adjustment needs a description and at least two lines",
+		}
+	}
+
+	lines := make([]entities.JournalLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		switch line.AccountType {
+		case entities.AccountVendor, entities.AccountProvider, entities.AccountPlatform, entities.AccountGatewayClearing:
+		default:
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Unknown account type: %s", line.AccountType),
+			}
+		}
+
+		account, errRes := uc.account(line.AccountType, line.OwnerID, "")
+		if errRes != nil {
+			return nil, errRes
+		}
+		lines = append(lines, entities.JournalLine{AccountID: account.ID, Debit: line.Debit, Credit: line.Credit})
+	}
+
+	referenceID := uuid.New().String()
+	if errRes := uc.post(entities.JournalAdjustment, referenceID, req.Description, lines); errRes != nil {
+		return nil, errRes
+	}
+
+	return uc.getEntry(entities.JournalAdjustment, referenceID)
+}
+
+// GetAccountBalance shows an account to its owner, or to an admin.
+func (uc *LedgerUseCase) GetAccountBalance(userID, role, accountID string) (*entitiesDtos.AccountBalanceResponse, *entitiesDtos.ErrorResponse) {
+	account, errRes := uc.readableAccount(userID, role, accountID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return uc.balanceOf(account)
+}
+
+// GetOwnerBalances lists the caller's own accounts; admins may list anyone's.
+func (uc *LedgerUseCase) GetOwnerBalances(userID, role, ownerID string) ([]entitiesDtos.AccountBalanceResponse, *entitiesDtos.ErrorResponse) {
+	if role != "admin" && ownerID != userID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to view these accounts",
+		}
+	}
+	accounts, err := uc.repo.GetAccountsByOwner(ownerID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve accounts: " + err.Error(),
+		}
+	}
+
+	balances := make([]entitiesDtos.AccountBalanceResponse, 0, len(accounts))
+	for i := range accounts {
+		balance, errRes := uc.balanceOf(&accounts[i])
+		if errRes != nil {
+			return nil, errRes
+		}
+		balances = append(balances, *balance)
+	}
+
+	return balances, nil
+}
+
+func (uc *LedgerUseCase) GetAccountEntries(userID, role, accountID string) ([]entities.JournalEntry, *entitiesDtos.ErrorResponse) {
+	if _, errRes := uc.readableAccount(userID, role, accountID); errRes != nil {
+		return nil, errRes
+	}
+	entries, err := uc.repo.GetEntriesByAccount(accountID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve journal entries: " + err.Error(),
+		}
+	}
+
+	return entries, nil
+}
+
+// readableAccount returns the account if the user owns it or is an admin. The platform and gateway accounts
+// are only for admins.
+func (uc *LedgerUseCase) readableAccount(userID, role, accountID string) (*entities.LedgerAccount, *entitiesDtos.ErrorResponse) {
+	account, err := uc.repo.GetAccount(accountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    404,
+				Message: "Account not found",
+			}
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve account: " + err.Error(),
+		}
+	}
+	if role != "admin" && account.OwnerID != userID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to view this account",
+		}
+	}
+	return account, nil
+}
+
+func (uc *LedgerUseCase) CheckInvariants() (*entitiesDtos.LedgerInvariantReport, *entitiesDtos.ErrorResponse) {
+	report, err := uc.service.CheckInvariants()
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: err.Error(),
+		}
+	}
+
+	return report, nil
+}
+
+// post validates that the lines balance and appends the entry. Posting the same kind and reference twice is a no-op.
+func (uc *LedgerUseCase) post(kind entities.JournalKind, referenceID, description string, lines []entities.JournalLine) *entitiesDtos.ErrorResponse {
+	existing, err := uc.repo.GetEntryByReference(kind, referenceID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check journal entry: " + err.Error(),
+		}
+	}
+	if existing != nil {
+		log.Printf("Ledger %s entry for %s already posted", kind, referenceID)
+		return nil
+	}
+
+	var debit, credit float64
+	for i := range lines {
+		lines[i].Debit = entities.RoundMoney(lines[i].Debit)
+		lines[i].Credit = entities.RoundMoney(lines[i].Credit)
+		if lines[i].Debit < 0 || lines[i].Credit < 0 || (lines[i].Debit == 0) == (lines[i].Credit == 0) {
+			return &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Each journal line must have either a positive debit or a positive credit",
+			}
+		}
+		debit += lines[i].Debit
+		credit += lines[i].Credit
+	}
+
+	if entities.RoundMoney(debit) != entities.RoundMoney(credit) {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Journal entry does not balance: debit %.2f, credit %.2f", debit, credit),
+		}
+	}
+
+	entry := &entities.JournalEntry{
+		ID:          uuid.New().String(),
+		Kind:        kind,
+		ReferenceID: referenceID,
+		Description: description,
+		Lines:       lines,
+	}
+	for i := range entry.Lines {
+		entry.Lines[i].ID = uuid.New().String()
+		entry.Lines[i].EntryID = entry.ID
+	}
+
+	if err := uc.repo.CreateEntry(entry); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to post journal entry: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (uc *LedgerUseCase) getEntry(kind entities.JournalKind, referenceID string) (*entities.JournalEntry, *entitiesDtos.ErrorResponse) {
+	entry, err := uc.repo.GetEntryByReference(kind, referenceID)
+	if err != nil || entry == nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve posted journal entry",
+		}
+	}
+	return entry, nil
+}
+
+func (uc *LedgerUseCase) account(accountType entities.LedgerAccountType, ownerID, name string) (*entities.LedgerAccount, *entitiesDtos.ErrorResponse) {
+	account, err := uc.repo.GetOrCreateAccount(accountType, ownerID, name)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: fmt.Sprintf("Failed to open %s account: %v", accountType, err),
+		}
+	}
+	return account, nil
+}
+
+func (uc *LedgerUseCase) bookingAccounts(vendorID, providerID string) (*entities.LedgerAccount, *entities.LedgerAccount, *entities.LedgerAccount, *entitiesDtos.ErrorResponse) {
+	gateway, errRes := uc.account(entities.AccountGatewayClearing, entities.GatewayOwnerID, "PromptPay clearing")
+	if errRes != nil {
+		return nil, nil, nil, errRes
+	}
+	vendor, errRes := uc.account(entities.AccountVendor, vendorID, "")
+	if errRes != nil {
+		return nil, nil, nil, errRes
+	}
+	provider, errRes := uc.account(entities.AccountProvider, providerID, "")
+	if errRes != nil {
+		return nil, nil, nil, errRes
+	}
+	return gateway, vendor, provider, nil
+}
+
+func (uc *LedgerUseCase) balanceOf(account *entities.LedgerAccount) (*entitiesDtos.AccountBalanceResponse, *entitiesDtos.ErrorResponse) {
+	debit, credit, err := uc.repo.GetAccountTotals(account.ID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to compute balance: " + err.Error(),
+		}
+	}
+
+	balance := credit - debit
+	if account.Type.IsDebitNormal() {
+		balance = debit - credit
+	}
+
+	return &entitiesDtos.AccountBalanceResponse{
+		Account:     *account,
+		TotalDebit:  entities.RoundMoney(debit),
+		TotalCredit: entities.RoundMoney(credit),
+		Balance:     entities.RoundMoney(balance),
+	}, nil
+}
+
+func bookingAmount(booking *entities.Booking) float64 {
+	if booking.Payment != nil {
+		return entities.RoundMoney(booking.Payment.Price)
+	}
+	return entities.RoundMoney(booking.Price)
+}
+
+// platformFee applies PLATFORM_FEE_PERCENT to a charge. An unset or invalid value means no fee.
+func platformFee(amount float64) float64 {
+	percent, err := strconv.ParseFloat(os.Getenv("PLATFORM_FEE_PERCENT"), 64)
+	if err != nil || percent <= 0 {
+		return 0
+	}
+	return entities.RoundMoney(amount * percent / 100)
+}
@@ -0,0 +1,293 @@
+package Usecase
+
+import (
+	"fmt"
+	"testing"
+	entities "tln-backend/Entities"
+)
+
+// fakeLedger keeps accounts and journal entries in memory and knows one booking.
+type fakeLedger struct {
+	accounts   []*entities.LedgerAccount
+	entries    []entities.JournalEntry
+	booking    *entities.Booking
+	providerID string
+}
+
+func (f *fakeLedger) GetOrCreateAccount(accountType entities.LedgerAccountType, ownerID, name string) (*entities.LedgerAccount, error) {
+	for _, account := range f.accounts {
+		if account.Type == accountType && account.OwnerID == ownerID {
+			return account, nil
+		}
+	}
+	account := &entities.LedgerAccount{ID: fmt.Sprintf("%s-%s", accountType, ownerID), Type: accountType, OwnerID: ownerID, Name: name}
+	f.accounts = append(f.accounts, account)
+	return account, nil
+}
+
+func (f *fakeLedger) GetAccount(accountID string) (*entities.LedgerAccount, error) {
+	for _, account := range f.accounts {
+		if account.ID == accountID {
+			return account, nil
+		}
+	}
+	return nil, fmt.Errorf("account not found")
+}
+
+func (f *fakeLedger) GetAccountsByOwner(ownerID string) ([]entities.LedgerAccount, error) {
+	var accounts []entities.LedgerAccount
+	for _, account := range f.accounts {
+		if account.OwnerID == ownerID {
+			accounts = append(accounts, *account)
+		}
+	}
+	return accounts, nil
+}
+
+func (f *fakeLedger) GetEntryByReference(kind entities.JournalKind, referenceID string) (*entities.JournalEntry, error) {
+	for i := range f.entries {
+		if f.entries[i].Kind == kind && f.entries[i].ReferenceID == referenceID {
+			return &f.entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeLedger) CreateEntry(entry *entities.JournalEntry) error {
+	f.entries = append(f.entries, *entry)
+	return nil
+}
+
+func (f *fakeLedger) GetEntriesByAccount(accountID string) ([]entities.JournalEntry, error) {
+	var entries []entities.JournalEntry
+	for _, entry := range f.entries {
+		for _, line := range entry.Lines {
+			if line.AccountID == accountID {
+				entries = append(entries, entry)
+				break
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeLedger) GetAccountTotals(accountID string) (float64, float64, error) {
+	var debit, credit float64
+	for _, entry := range f.entries {
+		for _, line := range entry.Lines {
+			if line.AccountID == accountID {
+				debit += line.Debit
+				credit += line.Credit
+			}
+		}
+	}
+	return debit, credit, nil
+}
+
+func (f *fakeLedger) GetLedgerTotals() (float64, float64, int64, error) {
+	var debit, credit float64
+	for _, entry := range f.entries {
+		for _, line := range entry.Lines {
+			debit += line.Debit
+			credit += line.Credit
+		}
+	}
+	return debit, credit, int64(len(f.entries)), nil
+}
+
+func (f *fakeLedger) GetUnbalancedEntryIDs() ([]string, error) {
+	var ids []string
+	for _, entry := range f.entries {
+		var debit, credit float64
+		for _, line := range entry.Lines {
+			debit += line.Debit
+			credit += line.Credit
+		}
+		if entities.RoundMoney(debit) != entities.RoundMoney(credit) {
+			ids = append(ids, entry.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeLedger) GetBookingParties(bookingID string) (*entities.Booking, string, error) {
+	if f.booking == nil || f.booking.ID != bookingID {
+		return nil, "", fmt.Errorf("booking not found")
+	}
+	return f.booking, f.providerID, nil
+}
+
+// net is what an account was credited less what it was debited.
+func (f *fakeLedger) net(accountType entities.LedgerAccountType, ownerID string) float64 {
+	account, _ := f.GetOrCreateAccount(accountType, ownerID, "")
+	debit, credit, _ := f.GetAccountTotals(account.ID)
+	return entities.RoundMoney(credit - debit)
+}
+
+func TestLedgerPostBalancing(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []entities.JournalLine
+		wantErr bool
+	}{
+		{
+			name: "balanced",
+			lines: []entities.JournalLine{
+				{AccountID: "a", Debit: 100},
+				{AccountID: "b", Credit: 60},
+				{AccountID: "c", Credit: 40},
+			},
+		},
+		{
+			name: "balanced after rounding to satang",
+			lines: []entities.JournalLine{
+				{AccountID: "a", Debit: 0.1},
+				{AccountID: "a", Debit: 0.2},
+				{AccountID: "b", Credit: 0.3},
+			},
+		},
+		{
+			name: "debit exceeds credit",
+			lines: []entities.JournalLine{
+				{AccountID: "a", Debit: 100},
+				{AccountID: "b", Credit: 99.99},
+			},
+			wantErr: true,
+		},
+		{
+			name: "line with both debit and credit",
+			lines: []entities.JournalLine{
+				{AccountID: "a", Debit: 50, Credit: 50},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty line",
+			lines: []entities.JournalLine{
+				{AccountID: "a", Debit: 10},
+				{AccountID: "b", Credit: 10},
+				{AccountID: "c"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative amount",
+			lines: []entities.JournalLine{
+				{AccountID: "a", Debit: -10},
+				{AccountID: "b", Credit: -10},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLedger{}
+			uc := &LedgerUseCase{repo: repo}
+
+			errRes := uc.post(entities.JournalAdjustment, "ref-1", tt.name, tt.lines)
+			if (errRes != nil) != tt.wantErr {
+				t.Fatalf("post() error = %v, wantErr %v", errRes, tt.wantErr)
+			}
+			wantEntries := 1
+			if tt.wantErr {
+				wantEntries = 0
+			}
+			if len(repo.entries) != wantEntries {
+				t.Errorf("posted %d entries, want %d", len(repo.entries), wantEntries)
+			}
+		})
+	}
+}
+
+func TestLedgerChargeAndRefundIdempotent(t *testing.T) {
+	tests := []struct {
+		name        string
+		price       float64
+		creditUsed  float64
+		feePercent  string
+		toWallet    bool
+		wantEntries int
+	}{
+		{name: "cash refund", price: 500, wantEntries: 2},
+		{name: "cash refund with fee", price: 500, feePercent: "5", wantEntries: 3},
+		{name: "part paid with credit", price: 500, creditUsed: 120, feePercent: "5", wantEntries: 3},
+		{name: "wallet refund", price: 500, feePercent: "5", toWallet: true, wantEntries: 3},
+		{name: "wallet refund part paid with credit", price: 333.33, creditUsed: 33.33, feePercent: "2.5", toWallet: true, wantEntries: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PLATFORM_FEE_PERCENT", tt.feePercent)
+			repo := &fakeLedger{
+				booking: &entities.Booking{
+					ID:       "booking-1",
+					VendorID: "vendor-1",
+					Price:    tt.price,
+					Payment:  &entities.Payment{Price: tt.price, CreditUsed: tt.creditUsed},
+				},
+				providerID: "provider-1",
+			}
+			uc := &LedgerUseCase{repo: repo}
+
+			// Payment callbacks and retries may record the same booking more than once
+			for i := 0; i < 2; i++ {
+				if errRes := uc.RecordCharge("booking-1"); errRes != nil {
+					t.Fatalf("RecordCharge() #%d: %v", i+1, errRes)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				if errRes := uc.RecordRefund("booking-1", tt.toWallet); errRes != nil {
+					t.Fatalf("RecordRefund() #%d: %v", i+1, errRes)
+				}
+			}
+
+			if len(repo.entries) != tt.wantEntries {
+				t.Errorf("posted %d entries, want %d", len(repo.entries), tt.wantEntries)
+			}
+			if unbalanced, _ := repo.GetUnbalancedEntryIDs(); len(unbalanced) > 0 {
+				t.Errorf("unbalanced entries: %v", unbalanced)
+			}
+			debit, credit, _, _ := repo.GetLedgerTotals()
+			if entities.RoundMoney(debit) != entities.RoundMoney(credit) {
+				t.Errorf("ledger totals debit %.2f, credit %.2f", debit, credit)
+			}
+
+			// A refund undoes the provider's income and the platform fee in full. A cash refund also returns
+			// the gateway money; a wallet refund keeps it and leaves the vendor holding it as credit.
+			cash := entities.RoundMoney(tt.price - tt.creditUsed)
+			wantVendor, wantGateway := 0.0, 0.0
+			if tt.toWallet {
+				wantVendor, wantGateway = cash, -cash
+			}
+			checks := []struct {
+				accountType entities.LedgerAccountType
+				ownerID     string
+				want        float64
+			}{
+				{entities.AccountProvider, "provider-1", 0},
+				{entities.AccountPlatform, entities.PlatformOwnerID, 0},
+				{entities.AccountVendor, "vendor-1", wantVendor},
+				{entities.AccountGatewayClearing, entities.GatewayOwnerID, wantGateway},
+			}
+			for _, check := range checks {
+				if got := repo.net(check.accountType, check.ownerID); got != check.want {
+					t.Errorf("%s account net = %.2f, want %.2f", check.accountType, got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLedgerRefundWithoutCharge(t *testing.T) {
+	repo := &fakeLedger{booking: &entities.Booking{ID: "booking-1", VendorID: "vendor-1", Price: 100}, providerID: "provider-1"}
+	uc := &LedgerUseCase{repo: repo}
+
+	errRes := uc.RecordRefund("booking-1", false)
+	if errRes == nil || errRes.Code != 409 {
+		t.Fatalf("RecordRefund() = %v, want a 409", errRes)
+	}
+	if len(repo.entries) != 0 {
+		t.Errorf("posted %d entries, want none", len(repo.entries))
+	}
+}
@@ -0,0 +1,113 @@
+package Usecase
+
+import (
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+)
+
+type MarketMapUseCase struct {
+	repo    Interfaces.IMarketMap
+	service *Services.MapService
+}
+
+func NewMarketMapUseCase(repo Interfaces.IMarketMap, service *Services.MapService) *MarketMapUseCase {
+	return &MarketMapUseCase{
+		repo:    repo,
+		service: service,
+	}
+}
+
+// GetMarketMap builds the geometry feed for a market day with every stall's availability.
+func (uc *MarketMapUseCase) GetMarketMap(marketID, date string) (*entitiesDtos.MarketMap, *entitiesDtos.ErrorResponse) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid date format. Please use YYYY-MM-DD",
+		}
+	}
+
+	market, err := uc.repo.GetMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+
+	slots, err := uc.repo.GetSlotsByDate(marketID, date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve slots: " + err.Error(),
+		}
+	}
+
+	slotIDs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		slotIDs = append(slotIDs, slot.ID)
+	}
+	bookings, err := uc.repo.GetActiveBookings(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check slot bookings: " + err.Error(),
+		}
+	}
+	bookingStatus := make(map[string]entities.BookingStatus, len(bookings))
+	for _, booking := range bookings {
+		if bookingStatus[booking.SlotID] != entities.StatusCompleted {
+			bookingStatus[booking.SlotID] = booking.Status
+		}
+	}
+
+	marketMap := &entitiesDtos.MarketMap{
+		MarketID:   market.ID,
+		MarketName: market.Name,
+		Date:       date,
+		Width:      market.FloorWidth,
+		Height:     market.FloorHeight,
+		Slots:      make([]entitiesDtos.MapSlot, 0, len(slots)),
+	}
+	for _, slot := range slots {
+		marketMap.Slots = append(marketMap.Slots, entitiesDtos.MapSlot{
+			ID:           slot.ID,
+			Name:         slot.Name,
+			Zone:         slot.Zone,
+			Category:     slot.Category,
+			Price:        entities.RoundMoney(slot.Price),
+			Availability: mapAvailability(slot, bookingStatus[slot.ID]),
+			Width:        slot.Width,
+			Height:       slot.Height,
+			SlotGeometry: slot.SlotGeometry,
+			Placed:       slot.Placed(),
+		})
+	}
+
+	uc.service.Arrange(marketMap)
+	return marketMap, nil
+}
+
+func (uc *MarketMapUseCase) RenderMarketMap(marketID, date string) ([]byte, *entitiesDtos.ErrorResponse) {
+	marketMap, errRes := uc.GetMarketMap(marketID, date)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return uc.service.RenderSVG(marketMap), nil
+}
+
+// mapAvailability looks at bookings as well as the slot status because a pending booking leaves the slot available.
+func mapAvailability(slot *entities.Slot, bookingStatus entities.BookingStatus) entitiesDtos.MapAvailability {
+	switch {
+	case slot.Status == entities.StatusMaintenance:
+		return entitiesDtos.MapUnavailable
+	case slot.Status == entities.StatusBooked || bookingStatus == entities.StatusCompleted:
+		return entitiesDtos.MapBooked
+	case bookingStatus == entities.StatusPending:
+		return entitiesDtos.MapHeld
+	}
+	return entitiesDtos.MapAvailable
+}
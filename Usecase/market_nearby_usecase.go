@@ -0,0 +1,185 @@
+package Usecase
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+const (
+	nearbyDefaultRadiusKm = 10
+	nearbyMaxRadiusKm     = 500
+)
+
+// GetNearbyMarkets lists the markets within a radius of a point, nearest first, a page at a time.
+func (uc *MarketUseCase) GetNearbyMarkets(query *entitiesDtos.NearbyMarketQuery) (*entitiesDtos.NearbyMarketResponse, *entitiesDtos.ErrorResponse) {
+	filter, errRes := parseNearbyMarkets(query)
+	if errRes != nil {
+		return nil, errRes
+	}
+	filter.MinLat, filter.MaxLat, filter.MinLng, filter.MaxLng = uc.search.BoundingBox(filter.Lat, filter.Lng, filter.RadiusKm)
+
+	distances, err := uc.repo.GetNearbyMarkets(filter)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to find nearby markets: " + err.Error(),
+		}
+	}
+
+	response := &entitiesDtos.NearbyMarketResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Total:    len(distances),
+		Markets:  []entitiesDtos.NearbyMarket{},
+	}
+	start := (filter.Page - 1) * filter.PageSize
+	if start >= len(distances) {
+		return response, nil
+	}
+	distances = distances[start:]
+	if len(distances) > filter.PageSize {
+		distances = distances[:filter.PageSize]
+	}
+
+	marketIDs := make([]string, 0, len(distances))
+	for _, distance := range distances {
+		marketIDs = append(marketIDs, distance.ID)
+	}
+	markets, err := uc.repo.GetMarketsByIDs(marketIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get markets: " + err.Error(),
+		}
+	}
+	byID := make(map[string]int, len(markets))
+	for i, market := range markets {
+		byID[market.ID] = i
+	}
+
+	for _, distance := range distances {
+		i, ok := byID[distance.ID]
+		if !ok {
+			continue
+		}
+		market := markets[i]
+		response.Markets = append(response.Markets, entitiesDtos.NearbyMarket{
+			ID:         market.ID,
+			Name:       market.Name,
+			Address:    market.Address,
+			Phone:      market.Phone,
+			Image:      market.ListImage(),
+			OpenTime:   market.OpenTime,
+			CloseTime:  market.CloseTime,
+			Latitude:   *market.Latitude,
+			Longitude:  *market.Longitude,
+			DistanceKm: math.Round(distance.DistanceKm*100) / 100,
+		})
+	}
+	return response, nil
+}
+
+func parseNearbyMarkets(query *entitiesDtos.NearbyMarketQuery) (*entitiesDtos.NearbyMarketFilter, *entitiesDtos.ErrorResponse) {
+	bad := func(message string) (*entitiesDtos.NearbyMarketFilter, *entitiesDtos.ErrorResponse) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	radius := query.RadiusKm
+	if radius == "" {
+		radius = strconv.Itoa(nearbyDefaultRadiusKm)
+	}
+	point, radiusKm, errRes := parseSearchPoint(query.Lat, query.Lng, radius)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if radiusKm > nearbyMaxRadiusKm {
+		return bad("radius_km can be at most " + strconv.Itoa(nearbyMaxRadiusKm))
+	}
+
+	filter := &entitiesDtos.NearbyMarketFilter{
+		GeoArea:  entitiesDtos.GeoArea{Lat: point[0], Lng: point[1], RadiusKm: radiusKm},
+		Page:     1,
+		PageSize: searchPageSize,
+	}
+	if query.Date != "" {
+		if _, err := time.Parse("2006-01-02", query.Date); err != nil {
+			return bad("Invalid date. Please use YYYY-MM-DD")
+		}
+		filter.Date = query.Date
+	}
+	if query.Available != "" {
+		available, err := strconv.ParseBool(query.Available)
+		if err != nil {
+			return bad("Invalid available: " + query.Available)
+		}
+		filter.Available = available
+	}
+	if query.Category != "" {
+		category, err := parseCategory(query.Category)
+		if err != nil {
+			return bad("Invalid category: " + query.Category)
+		}
+		filter.Category = category
+	}
+	if query.Page != "" {
+		page, err := strconv.Atoi(query.Page)
+		if err != nil || page < 1 {
+			return bad("Invalid page: " + query.Page)
+		}
+		filter.Page = page
+	}
+	if query.PageSize != "" {
+		pageSize, err := strconv.Atoi(query.PageSize)
+		if err != nil || pageSize < 1 {
+			return bad("Invalid page_size: " + query.PageSize)
+		}
+		filter.PageSize = pageSize
+	}
+	if filter.PageSize > searchMaxPageSize {
+		filter.PageSize = searchMaxPageSize
+	}
+	return filter, nil
+}
+
+// parseSearchPoint reads the point and radius of a distance search.
+func parseSearchPoint(lat, lng, radius string) ([]float64, float64, *entitiesDtos.ErrorResponse) {
+	latitude, latErr := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	longitude, lngErr := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	radiusKm, radiusErr := strconv.ParseFloat(strings.TrimSpace(radius), 64)
+	message := ""
+	switch {
+	case latErr != nil || lngErr != nil || checkCoordinates(&latitude, &longitude) != "":
+		message = "A distance search needs a valid lat and lng"
+	case radiusErr != nil || radiusKm <= 0:
+		message = "A distance search needs a radius_km above 0"
+	}
+	if message != "" {
+		return nil, 0, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+	return []float64{latitude, longitude}, radiusKm, nil
+}
+
+// checkCoordinates explains what is wrong with a market location, or returns "" when it is fine. A market
+// has both coordinates or neither.
+func checkCoordinates(lat, lng *float64) string {
+	switch {
+	case (lat == nil) != (lng == nil):
+		return "Latitude and longitude must be given together"
+	case lat == nil:
+		return ""
+	case *lat < -90 || *lat > 90:
+		return "Latitude must be between -90 and 90"
+	case *lng < -180 || *lng > 180:
+		return "Longitude must be between -180 and 180"
+	}
+	return ""
+}
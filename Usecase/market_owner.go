@@ -0,0 +1,27 @@
+package Usecase
+
+import entitiesDtos "tln-backend/Entities/dtos"
+
+// marketOwners looks up which provider runs a market.
+type marketOwners interface {
+	GetMarketProviderID(marketID string) (string, error)
+}
+
+// checkMarketOwner makes sure the provider runs the market before they manage part of it. what names that part
+// in the error, such as "zones".
+func checkMarketOwner(repo marketOwners, providerID, marketID, what string) *entitiesDtos.ErrorResponse {
+	ownerID, err := repo.GetMarketProviderID(marketID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+	if ownerID != providerID {
+		return &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to manage this market's " + what,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package Usecase
+
+import (
+	"fmt"
+	"testing"
+)
+
+type fakeMarketOwners map[string]string
+
+func (f fakeMarketOwners) GetMarketProviderID(marketID string) (string, error) {
+	if providerID, ok := f[marketID]; ok {
+		return providerID, nil
+	}
+	return "", fmt.Errorf("market not found")
+}
+
+func TestCheckMarketOwner(t *testing.T) {
+	owners := fakeMarketOwners{"m1": "p1"}
+	tests := []struct {
+		name        string
+		providerID  string
+		marketID    string
+		wantCode    int
+		wantMessage string
+	}{
+		{name: "own market", providerID: "p1", marketID: "m1"},
+		{name: "someone else's market", providerID: "p2", marketID: "m1", wantCode: 403, wantMessage: "You are not authorized to manage this market's zones"},
+		{name: "unknown market", providerID: "p1", marketID: "gone", wantCode: 404, wantMessage: "Failed to get market: market not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errRes := checkMarketOwner(owners, tt.providerID, tt.marketID, "zones")
+			if tt.wantCode == 0 {
+				if errRes != nil {
+					t.Fatalf("checkMarketOwner() error = %v", errRes)
+				}
+				return
+			}
+			if errRes == nil || errRes.Code != tt.wantCode || errRes.Message != tt.wantMessage {
+				t.Errorf("checkMarketOwner() = %+v, want %d %q", errRes, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
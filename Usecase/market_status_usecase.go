@@ -0,0 +1,205 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+var _ contact.IMarketUseCase = (*MarketUseCase)(nil)
+
+// marketTransitions lists where each status may go. An archived market can only come back as a draft, so
+// it is checked again before it is published.
+var marketTransitions = map[entities.MarketStatus][]entities.MarketStatus{
+	entities.MarketDraft:     {entities.MarketPublished, entities.MarketArchived},
+	entities.MarketPublished: {entities.MarketSuspended, entities.MarketArchived},
+	entities.MarketSuspended: {entities.MarketPublished, entities.MarketArchived},
+	entities.MarketArchived:  {entities.MarketDraft},
+}
+
+// ChangeStatus moves a market through its lifecycle for its provider or an admin. Publishing needs a
+// complete profile and a layout, suspending needs a reason, and archiving needs the market to have no
+// bookings still to come.
+func (uc *MarketUseCase) ChangeStatus(userID, role, marketID string, req *entitiesDtos.MarketStatusRequest) (*entities.Market, *entitiesDtos.ErrorResponse) {
+	market, errRes := uc.checkMarketManager(userID, role, marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	to := entities.MarketStatus(strings.ToLower(strings.TrimSpace(string(req.Status))))
+	reason := strings.TrimSpace(req.Reason)
+	allowed := false
+	for _, next := range marketTransitions[market.Status] {
+		allowed = allowed || next == to
+	}
+	switch {
+	case !allowed:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("A %s market cannot be moved to %q", market.Status, req.Status),
+		}
+	case utf8.RuneCountInString(reason) > 255:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Reason can be at most 255 characters",
+		}
+	}
+
+	switch to {
+	case entities.MarketPublished:
+		if errRes := uc.checkPublishable(market, role); errRes != nil {
+			return nil, errRes
+		}
+	case entities.MarketSuspended:
+		if reason == "" {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "A reason is required to suspend a market",
+			}
+		}
+	case entities.MarketArchived:
+		today := marketToday(marketLocation(market)).Format("2006-01-02")
+		upcoming, err := uc.repo.CountUpcomingBookings(market.ID, today)
+		if err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to check upcoming bookings: " + err.Error(),
+			}
+		}
+		if upcoming > 0 {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("The market still has %d upcoming bookings; cancel its market days before archiving it", upcoming),
+			}
+		}
+	}
+
+	change := &entities.MarketStatusChange{
+		ID:         uuid.New().String(),
+		MarketID:   market.ID,
+		FromStatus: market.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedBy:  userID,
+		Role:       role,
+	}
+	if err := uc.repo.ChangeMarketStatus(change); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Failed to change market status: " + err.Error(),
+		}
+	}
+
+	market.Status = to
+	return market, nil
+}
+
+// GetStatusHistory lists a market's status changes, newest first, for its provider or an admin.
+func (uc *MarketUseCase) GetStatusHistory(userID, role, marketID string) ([]entities.MarketStatusChange, *entitiesDtos.ErrorResponse) {
+	if _, errRes := uc.checkMarketManager(userID, role, marketID); errRes != nil {
+		return nil, errRes
+	}
+
+	changes, err := uc.repo.GetStatusChanges(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get market status history: " + err.Error(),
+		}
+	}
+	return changes, nil
+}
+
+// CheckBookable refuses bookings at markets that are not published.
+func (uc *MarketUseCase) CheckBookable(marketID string) *entitiesDtos.ErrorResponse {
+	market, errRes := uc.repo.GetMarketWithProviderByID(marketID)
+	if errRes != nil {
+		return errRes
+	}
+	if market.Status != entities.MarketPublished {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("The market is %s and is not taking bookings", market.Status),
+		}
+	}
+	return nil
+}
+
+// canViewMarket tells whether a user may see a market that is not published: only its provider and admins.
+func canViewMarket(market *entities.Market, userID, role string) bool {
+	return market.Status == entities.MarketPublished || role == "admin" || (role == "provider" && market.ProviderID == userID)
+}
+
+// checkMarketManager returns the market if the user is its provider or an admin.
+func (uc *MarketUseCase) checkMarketManager(userID, role, marketID string) (*entities.Market, *entitiesDtos.ErrorResponse) {
+	market, errRes := uc.repo.GetMarketWithProviderByID(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if role != "admin" && (role != "provider" || market.ProviderID != userID) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to manage this market's status",
+		}
+	}
+	return market, nil
+}
+
+// checkPublishable makes sure a market is ready to go public. A suspension an admin made can only be lifted
+// by an admin.
+func (uc *MarketUseCase) checkPublishable(market *entities.Market, role string) *entitiesDtos.ErrorResponse {
+	if market.Status == entities.MarketSuspended && role != "admin" {
+		suspension, err := uc.repo.GetLastStatusChange(market.ID, entities.MarketSuspended)
+		if err != nil {
+			return &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to check the market's suspension: " + err.Error(),
+			}
+		}
+		if suspension != nil && suspension.Role == "admin" {
+			return &entitiesDtos.ErrorResponse{
+				Code:    403,
+				Message: "This market was suspended by an admin; only an admin can publish it again",
+			}
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, field := range []struct {
+		name  string
+		empty bool
+	}{
+		{"address", strings.TrimSpace(market.Address) == ""},
+		{"description", strings.TrimSpace(market.Description) == ""},
+		{"opening and closing time", market.OpenTime == "" || market.CloseTime == ""},
+		{"location", market.Latitude == nil || market.Longitude == nil},
+		{"image", market.ImageID == "" && market.Image == ""},
+	} {
+		if field.empty {
+			missing = append(missing, field.name)
+		}
+	}
+
+	slots, err := uc.repo.CountLiveSlots(market.ID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check the market's layout: " + err.Error(),
+		}
+	}
+	if slots == 0 {
+		missing = append(missing, "a layout with at least one stall")
+	}
+
+	if len(missing) > 0 {
+		return &entitiesDtos.ErrorResponse{
+			Code:    422,
+			Message: "The market cannot be published yet, it is missing: " + strings.Join(missing, ", "),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package Usecase
+
+import (
+	"testing"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+)
+
+// fakeMarketStatusRepo holds one market run by p1 and the status changes made to it.
+type fakeMarketStatusRepo struct {
+	Interfaces.IMarket
+	market     entities.Market
+	liveSlots  int64
+	upcoming   int64
+	suspension *entities.MarketStatusChange
+	changes    []*entities.MarketStatusChange
+}
+
+func (f *fakeMarketStatusRepo) GetMarketWithProviderByID(marketID string) (*entities.Market, *entitiesDtos.ErrorResponse) {
+	market := f.market
+	return &market, nil
+}
+
+func (f *fakeMarketStatusRepo) ChangeMarketStatus(change *entities.MarketStatusChange) error {
+	f.changes = append(f.changes, change)
+	return nil
+}
+
+func (f *fakeMarketStatusRepo) GetLastStatusChange(marketID string, status entities.MarketStatus) (*entities.MarketStatusChange, error) {
+	return f.suspension, nil
+}
+
+func (f *fakeMarketStatusRepo) CountLiveSlots(marketID string) (int64, error) {
+	return f.liveSlots, nil
+}
+
+func (f *fakeMarketStatusRepo) CountUpcomingBookings(marketID, from string) (int64, error) {
+	return f.upcoming, nil
+}
+
+func TestChangeMarketStatus(t *testing.T) {
+	lat, lng := 13.75, 100.5
+	ready := entities.Market{ID: "m1", ProviderID: "p1", Name: "Night Market", Address: "1 Rama IV", Description: "Food and crafts",
+		OpenTime: "17:00", CloseTime: "23:00", Latitude: &lat, Longitude: &lng, Image: "market.jpg"}
+	with := func(status entities.MarketStatus) entities.Market {
+		market := ready
+		market.Status = status
+		return market
+	}
+	unfinished := with(entities.MarketDraft)
+	unfinished.Address, unfinished.Latitude, unfinished.Longitude = "", nil, nil
+
+	tests := []struct {
+		name       string
+		market     entities.Market
+		userID     string
+		role       string
+		to         entities.MarketStatus
+		reason     string
+		liveSlots  int64
+		upcoming   int64
+		suspension *entities.MarketStatusChange
+		wantCode   int
+	}{
+		{name: "publish a finished draft", market: with(entities.MarketDraft), userID: "p1", role: "provider", to: entities.MarketPublished, liveSlots: 4},
+		{name: "publish without a layout", market: with(entities.MarketDraft), userID: "p1", role: "provider", to: entities.MarketPublished, wantCode: 422},
+		{name: "publish with missing details", market: unfinished, userID: "p1", role: "provider", to: entities.MarketPublished, liveSlots: 4, wantCode: 422},
+		{name: "another provider's market", market: with(entities.MarketDraft), userID: "p2", role: "provider", to: entities.MarketPublished, liveSlots: 4, wantCode: 403},
+		{name: "vendors cannot change status", market: with(entities.MarketPublished), userID: "p1", role: "vendor", to: entities.MarketSuspended, reason: "rain", wantCode: 403},
+		{name: "suspend with a reason", market: with(entities.MarketPublished), userID: "p1", role: "provider", to: entities.MarketSuspended, reason: "Flooding"},
+		{name: "suspend without a reason", market: with(entities.MarketPublished), userID: "p1", role: "provider", to: entities.MarketSuspended, wantCode: 400},
+		{name: "a draft cannot be suspended", market: with(entities.MarketDraft), userID: "p1", role: "provider", to: entities.MarketSuspended, reason: "Flooding", wantCode: 409},
+		{name: "an archived market comes back as a draft", market: with(entities.MarketArchived), userID: "p1", role: "provider", to: entities.MarketDraft},
+		{name: "an archived market cannot be published", market: with(entities.MarketArchived), userID: "p1", role: "provider", to: entities.MarketPublished, liveSlots: 4, wantCode: 409},
+		{name: "archive with upcoming bookings", market: with(entities.MarketPublished), userID: "p1", role: "provider", to: entities.MarketArchived, upcoming: 2, wantCode: 409},
+		{name: "archive with no bookings left", market: with(entities.MarketSuspended), userID: "p1", role: "provider", to: entities.MarketArchived},
+		{name: "provider cannot lift an admin suspension", market: with(entities.MarketSuspended), userID: "p1", role: "provider", to: entities.MarketPublished,
+			liveSlots: 4, suspension: &entities.MarketStatusChange{Role: "admin"}, wantCode: 403},
+		{name: "admin lifts its own suspension", market: with(entities.MarketSuspended), userID: "a1", role: "admin", to: entities.MarketPublished,
+			liveSlots: 4, suspension: &entities.MarketStatusChange{Role: "admin"}},
+		{name: "provider lifts its own suspension", market: with(entities.MarketSuspended), userID: "p1", role: "provider", to: entities.MarketPublished,
+			liveSlots: 4, suspension: &entities.MarketStatusChange{Role: "provider"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeMarketStatusRepo{market: tt.market, liveSlots: tt.liveSlots, upcoming: tt.upcoming, suspension: tt.suspension}
+			uc := &MarketUseCase{repo: repo}
+
+			market, errRes := uc.ChangeStatus(tt.userID, tt.role, "m1", &entitiesDtos.MarketStatusRequest{Status: tt.to, Reason: tt.reason})
+			if tt.wantCode != 0 {
+				if errRes == nil || errRes.Code != tt.wantCode {
+					t.Fatalf("ChangeStatus() error = %v, want code %d", errRes, tt.wantCode)
+				}
+				if len(repo.changes) != 0 {
+					t.Errorf("a refused change was recorded")
+				}
+				return
+			}
+			if errRes != nil {
+				t.Fatalf("ChangeStatus() error = %v", errRes)
+			}
+			if market.Status != tt.to {
+				t.Errorf("market is %s, want %s", market.Status, tt.to)
+			}
+			if len(repo.changes) != 1 {
+				t.Fatalf("recorded %d changes, want 1", len(repo.changes))
+			}
+			change := repo.changes[0]
+			if change.FromStatus != tt.market.Status || change.ToStatus != tt.to || change.ChangedBy != tt.userID || change.Role != tt.role {
+				t.Errorf("change = %+v", change)
+			}
+		})
+	}
+}
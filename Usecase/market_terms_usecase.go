@@ -0,0 +1,245 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+// maxTermsLength caps the body of one version of a market's terms, in characters.
+const maxTermsLength = 20000
+
+type MarketTermsUseCase struct {
+	repo Interfaces.IMarketTerms
+}
+
+var _ contact.ITermsUseCase = (*MarketTermsUseCase)(nil)
+
+func NewMarketTermsUseCase(repo Interfaces.IMarketTerms) *MarketTermsUseCase {
+	return &MarketTermsUseCase{repo: repo}
+}
+
+// PublishTerms adds the next version of a market's terms. Vendors have to accept it before their next booking.
+func (uc *MarketTermsUseCase) PublishTerms(providerID, publishedBy, marketID string, req *entitiesDtos.MarketTermsRequest) (*entities.MarketTerms, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "terms"); errRes != nil {
+		return nil, errRes
+	}
+
+	title := strings.TrimSpace(req.Title)
+	body := strings.TrimSpace(req.Body)
+	message := ""
+	switch {
+	case body == "":
+		message = "Terms body is required"
+	case utf8.RuneCountInString(body) > maxTermsLength:
+		message = fmt.Sprintf("Terms can be at most %d characters", maxTermsLength)
+	case utf8.RuneCountInString(title) > 200:
+		message = "Title can be at most 200 characters"
+	}
+	if message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	version := 1
+	if current, err := uc.repo.GetCurrentTerms(marketID); err == nil {
+		// A new version makes every vendor accept again, so only publish one when something changed
+		if current.Title == title && current.Body == body {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("These terms are the same as version %d", current.Version),
+			}
+		}
+		version = current.Version + 1
+	}
+
+	terms := &entities.MarketTerms{
+		ID:          uuid.New().String(),
+		MarketID:    marketID,
+		Version:     version,
+		Title:       title,
+		Body:        body,
+		PublishedBy: publishedBy,
+	}
+	if err := uc.repo.CreateTerms(terms); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to publish terms: " + err.Error(),
+		}
+	}
+	return terms, nil
+}
+
+// GetTerms returns the terms in force at a market, and whether the signed-in vendor accepted them.
+func (uc *MarketTermsUseCase) GetTerms(marketID, userID, role string) (*entitiesDtos.MarketTermsResponse, *entitiesDtos.ErrorResponse) {
+	market, err := uc.repo.GetMarket(marketID)
+	if err != nil || !canViewMarket(market, userID, role) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Market not found",
+		}
+	}
+	terms, err := uc.repo.GetCurrentTerms(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "This market has no terms",
+		}
+	}
+
+	response := &entitiesDtos.MarketTermsResponse{Terms: terms}
+	if role == "vendor" {
+		if acceptance, err := uc.repo.GetAcceptance(terms.ID, userID); err == nil {
+			acceptance.Terms = nil
+			response.Accepted = true
+			response.Acceptance = acceptance
+		}
+	}
+	return response, nil
+}
+
+// GetTermsVersions lists every version of a market's terms for its provider, newest first.
+func (uc *MarketTermsUseCase) GetTermsVersions(providerID, marketID string) ([]entities.MarketTerms, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "terms"); errRes != nil {
+		return nil, errRes
+	}
+	versions, err := uc.repo.GetTermsVersions(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve terms: " + err.Error(),
+		}
+	}
+	return versions, nil
+}
+
+// AcceptTerms records a vendor agreeing to the version of a market's terms it read, with where it agreed
+// from. The version has to still be in force, so a vendor cannot agree to terms that changed under it.
+func (uc *MarketTermsUseCase) AcceptTerms(vendorID, marketID string, version int, ipAddress, userAgent string) (*entities.TermsAcceptance, *entitiesDtos.ErrorResponse) {
+	terms, err := uc.repo.GetCurrentTerms(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "This market has no terms",
+		}
+	}
+	if version != terms.Version {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Version %d is not in force; read and accept version %d", version, terms.Version),
+		}
+	}
+	if existing, err := uc.repo.GetAcceptance(terms.ID, vendorID); err == nil {
+		return existing, nil
+	}
+
+	if utf8.RuneCountInString(userAgent) > 255 {
+		userAgent = string([]rune(userAgent)[:255])
+	}
+	acceptance := &entities.TermsAcceptance{
+		ID:         uuid.New().String(),
+		TermsID:    terms.ID,
+		MarketID:   marketID,
+		Version:    terms.Version,
+		VendorID:   vendorID,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		AcceptedAt: time.Now(),
+	}
+	if err := uc.repo.CreateAcceptance(acceptance); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to accept terms: " + err.Error(),
+		}
+	}
+	acceptance.Terms = terms
+	return acceptance, nil
+}
+
+// GetMarketAcceptances lists which vendors accepted a market's terms, of one version when version is set.
+func (uc *MarketTermsUseCase) GetMarketAcceptances(providerID, marketID string, version int) ([]entities.TermsAcceptance, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "terms"); errRes != nil {
+		return nil, errRes
+	}
+	acceptances, err := uc.repo.GetMarketAcceptances(marketID, version)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve terms acceptances: " + err.Error(),
+		}
+	}
+	return acceptances, nil
+}
+
+func (uc *MarketTermsUseCase) GetVendorAcceptances(vendorID string) ([]entities.TermsAcceptance, *entitiesDtos.ErrorResponse) {
+	acceptances, err := uc.repo.GetVendorAcceptances(vendorID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve terms acceptances: " + err.Error(),
+		}
+	}
+	return acceptances, nil
+}
+
+// GetBookingTerms returns the terms a booking was made under and the vendor's acceptance of them, for
+// settling disputes with the vendor.
+func (uc *MarketTermsUseCase) GetBookingTerms(providerID, bookingID string) (*entities.TermsAcceptance, *entitiesDtos.ErrorResponse) {
+	booking, err := uc.repo.GetBooking(bookingID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Booking not found",
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, booking.MarketID, "terms"); errRes != nil {
+		return nil, errRes
+	}
+	if booking.TermsAcceptanceID == "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "This booking was made without market terms",
+		}
+	}
+
+	acceptance, err := uc.repo.GetAcceptanceByID(booking.TermsAcceptanceID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Terms acceptance not found",
+		}
+	}
+	return acceptance, nil
+}
+
+// CheckAccepted refuses bookings from vendors who have not accepted the terms in force at the market. It
+// returns the acceptance the booking is made under, or nil when the market has no terms. vendorID is the
+// signed-in vendor, so a booking can only ever cite its own vendor's acceptance.
+func (uc *MarketTermsUseCase) CheckAccepted(marketID, vendorID string) (*entities.TermsAcceptance, *entitiesDtos.ErrorResponse) {
+	if vendorID == "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    401,
+			Message: "Sign in as a vendor to accept this market's terms",
+		}
+	}
+	terms, err := uc.repo.GetCurrentTerms(marketID)
+	if err != nil {
+		return nil, nil
+	}
+	acceptance, err := uc.repo.GetAcceptance(terms.ID, vendorID)
+	if err != nil || acceptance.VendorID != vendorID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: fmt.Sprintf("Accept version %d of this market's terms before booking", terms.Version),
+		}
+	}
+	return acceptance, nil
+}
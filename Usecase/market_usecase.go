@@ -1,19 +1,29 @@
 package Usecase
 
 import (
+	"log"
+
 	"github.com/google/uuid"
 	entities "tln-backend/Entities"
 	entitiesDtos "tln-backend/Entities/dtos"
 	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
 )
 
 type MarketUseCase struct {
-	repo Interfaces.IMarket
+	repo    Interfaces.IMarket
+	search  *Services.SearchService
+	index   contact.ISearchIndex
+	ratings contact.IRatingUseCase
 }
 
-func NewMarketUseCase(repo Interfaces.IMarket) *MarketUseCase {
+func NewMarketUseCase(repo Interfaces.IMarket, search *Services.SearchService, index contact.ISearchIndex, ratings contact.IRatingUseCase) *MarketUseCase {
 	return &MarketUseCase{
-		repo: repo,
+		repo:    repo,
+		search:  search,
+		index:   index,
+		ratings: ratings,
 	}
 
 }
@@ -28,6 +38,25 @@ func (uc *MarketUseCase) CreateMarket(marketReq *entitiesDtos.MarketRequest) (*e
 		}
 	}
 
+	if marketReq.FloorWidth < 0 || marketReq.FloorHeight < 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Floor plan size cannot be negative",
+		}
+	}
+	if message := checkCoordinates(marketReq.Latitude, marketReq.Longitude); message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+	if message := checkImageURL("Image", marketReq.Image); message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
 	// Check if a market with the same name already exists
 	existingMarket, errRes := uc.repo.GetMarketByName(marketReq.Name)
 	if errRes != nil && errRes.Code != 404 { // If error is not "not found", return it
@@ -56,6 +85,10 @@ func (uc *MarketUseCase) CreateMarket(marketReq *entitiesDtos.MarketRequest) (*e
 		CloseTime:   marketReq.CloseTime,
 		Latitude:    marketReq.Latitude,
 		Longitude:   marketReq.Longitude,
+		FloorWidth:  marketReq.FloorWidth,
+		FloorHeight: marketReq.FloorHeight,
+		RosterOnly:  marketReq.RosterOnly,
+		Status:      entities.MarketDraft, // Public once its provider publishes it
 	}
 
 	// Save the market entity to the database
@@ -76,6 +109,8 @@ func (uc *MarketUseCase) CreateMarket(marketReq *entitiesDtos.MarketRequest) (*e
 		}
 	}
 
+	uc.reindexMarket(createdMarket)
+
 	// Return the created market with provider details
 	return createdMarket, nil
 }
@@ -83,13 +118,19 @@ func (uc *MarketUseCase) CreateMarket(marketReq *entitiesDtos.MarketRequest) (*e
 // edit market
 func (uc *MarketUseCase) EditMarket(marketID string, marketReq *entitiesDtos.MarketEditRequest) (*entities.Market, *entitiesDtos.ErrorResponse) {
 	// Check if the market exists
-	_, errRes := uc.repo.GetMarketByID(marketID)
+	existing, errRes := uc.repo.GetMarketByID(marketID)
 	if errRes != nil {
 		return nil, &entitiesDtos.ErrorResponse{
 			Code:    404,
 			Message: "Market not found",
 		}
 	}
+	if existing[0].Status == entities.MarketArchived {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Archived markets cannot be edited; restore the market as a draft first",
+		}
+	}
 
 	// Check if the provider exists
 	_, errRes = uc.repo.GetProviderByID(marketReq.ProviderID)
@@ -100,6 +141,27 @@ func (uc *MarketUseCase) EditMarket(marketID string, marketReq *entitiesDtos.Mar
 		}
 	}
 
+	if marketReq.FloorWidth < 0 || marketReq.FloorHeight < 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Floor plan size cannot be negative",
+		}
+	}
+	if message := checkCoordinates(marketReq.Latitude, marketReq.Longitude); message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+	for field, value := range map[string]string{"Image": marketReq.Image, "Layout image": marketReq.LayoutImage} {
+		if message := checkImageURL(field, value); message != "" {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: message,
+			}
+		}
+	}
+
 	// Call the EditMarket function in the repository with marketID and marketReq
 	_, err := uc.repo.EditMarket(marketID, marketReq)
 	if err != nil {
@@ -118,6 +180,8 @@ func (uc *MarketUseCase) EditMarket(marketID string, marketReq *entitiesDtos.Mar
 		}
 	}
 
+	uc.reindexMarket(editedMarket)
+
 	// Return the edited market with provider details
 	return editedMarket, nil
 }
@@ -136,7 +200,8 @@ func (uc *MarketUseCase) GetMarket() ([]entities.Market, *entitiesDtos.ErrorResp
 
 }
 
-func (uc *MarketUseCase) GetMarketByID(marketID string) ([]entities.Market, *entitiesDtos.ErrorResponse) {
+// GetMarketByID returns a market. Markets that are not published are only shown to their provider and admins.
+func (uc *MarketUseCase) GetMarketByID(marketID, userID, role string) ([]entities.Market, *entitiesDtos.ErrorResponse) {
 	market, errRes := uc.repo.GetMarketByID(marketID)
 	if errRes != nil {
 		return nil, &entitiesDtos.ErrorResponse{
@@ -144,6 +209,13 @@ func (uc *MarketUseCase) GetMarketByID(marketID string) ([]entities.Market, *ent
 			Message: "Failed to retrieve market: " + errRes.Message,
 		}
 	}
+	if !canViewMarket(&market[0], userID, role) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to retrieve market: Market not found",
+		}
+	}
+	market[0].Rating = uc.ratings.MarketRating(marketID)
 
 	return market, nil
 }
@@ -159,3 +231,11 @@ func (uc *MarketUseCase) GetMarketByProviderID(providerID string) ([]entities.Ma
 
 	return market, nil
 }
+
+// reindexMarket refreshes the market's search entry. The market is already saved, so a failure only leaves
+// search stale until the next reindex.
+func (uc *MarketUseCase) reindexMarket(market *entities.Market) {
+	if errRes := uc.index.IndexMarket(market); errRes != nil {
+		log.Printf("Warning: Error indexing market %s for search: %s", market.ID, errRes.Message)
+	}
+}
@@ -0,0 +1,342 @@
+package Usecase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"net/http"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+// maxImageURLLength keeps the older free-form image fields to links; files go through media uploads.
+const maxImageURLLength = 2048
+
+type MediaUseCase struct {
+	repo    Interfaces.IMedia
+	storage Services.MediaStorage
+	media   *Services.MediaService
+}
+
+var _ contact.IMediaStore = (*MediaUseCase)(nil)
+
+func NewMediaUseCase(repo Interfaces.IMedia, storage Services.MediaStorage, media *Services.MediaService) *MediaUseCase {
+	return &MediaUseCase{
+		repo:    repo,
+		storage: storage,
+		media:   media,
+	}
+}
+
+// MaxBytes is the largest upload accepted, so the handler can refuse bigger files before reading them.
+func (uc *MediaUseCase) MaxBytes() int64 {
+	return uc.media.MaxBytes()
+}
+
+// Upload stores an image with its thumbnail and attaches it to the market, slot or vendor it is for,
+// replacing the image that was there. Vendors may leave targetID empty for their own pictures. Application
+// photos and documents are only stored; the vendor lists them on an application afterwards.
+func (uc *MediaUseCase) Upload(userID, role, kind, targetID string, data []byte) (*entities.Media, *entitiesDtos.ErrorResponse) {
+	mediaKind, err := parseMediaKind(kind)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid kind: " + err.Error(),
+		}
+	}
+	targetID, errRes := uc.checkTarget(userID, role, mediaKind, targetID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	media, errRes := uc.store(mediaKind, targetID, userID, data)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if !mediaKind.Attached() {
+		if err := uc.repo.CreateMedia(media); err != nil {
+			uc.removeFiles(media.ID)
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to save image: " + err.Error(),
+			}
+		}
+		media.URL, media.ThumbnailURL = uc.URLs(media.ID)
+		return media, nil
+	}
+	previous, err := uc.repo.AttachMedia(media)
+	if err != nil {
+		uc.removeFiles(media.ID)
+		code := 500
+		if strings.HasSuffix(err.Error(), "not found") {
+			code = 404
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    code,
+			Message: "Failed to attach image: " + err.Error(),
+		}
+	}
+	if previous != "" {
+		uc.removeFiles(previous)
+	}
+
+	media.URL, media.ThumbnailURL = uc.URLs(media.ID)
+	return media, nil
+}
+
+// StoreImage keeps an image the server made, such as a payment QR. The caller saves the media ID on its
+// target itself.
+func (uc *MediaUseCase) StoreImage(kind entities.MediaKind, targetID string, data []byte) (*entities.Media, *entitiesDtos.ErrorResponse) {
+	media, errRes := uc.store(kind, targetID, "", data)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if err := uc.repo.CreateMedia(media); err != nil {
+		uc.removeFiles(media.ID)
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save image: " + err.Error(),
+		}
+	}
+
+	media.URL, media.ThumbnailURL = uc.URLs(media.ID)
+	return media, nil
+}
+
+func (uc *MediaUseCase) GetMedia(mediaID string) (*entities.Media, *entitiesDtos.ErrorResponse) {
+	media, err := uc.repo.GetMedia(mediaID)
+	if err != nil {
+		code := 500
+		if err.Error() == "media not found" {
+			code = 404
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    code,
+			Message: "Failed to get media: " + err.Error(),
+		}
+	}
+	return media, nil
+}
+
+// GetPublicMedia returns media anyone may look up. Application files are private to the vendor and the
+// markets it applies to, so they are only shown on their applications; slips only with their booking.
+func (uc *MediaUseCase) GetPublicMedia(mediaID string) (*entities.Media, *entitiesDtos.ErrorResponse) {
+	media, errRes := uc.GetMedia(mediaID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if media.Kind.Private() {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get media: media not found",
+		}
+	}
+	return media, nil
+}
+
+// DeleteMedia takes an image off what it belongs to and removes its files. Only whoever may upload that
+// image may delete it.
+func (uc *MediaUseCase) DeleteMedia(userID, role, mediaID string) *entitiesDtos.ErrorResponse {
+	media, errRes := uc.GetMedia(mediaID)
+	if errRes != nil {
+		return errRes
+	}
+	if _, errRes := uc.checkTarget(userID, role, media.Kind, media.TargetID); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repo.DetachMedia(media); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete media: " + err.Error(),
+		}
+	}
+	uc.removeFiles(media.ID)
+	return nil
+}
+
+// OpenFile serves a file behind a signed URL of local storage. Other storages hand out URLs of their own.
+func (uc *MediaUseCase) OpenFile(key, expires, signature string) ([]byte, string, *entitiesDtos.ErrorResponse) {
+	local, ok := uc.storage.(*Services.LocalStorage)
+	if !ok {
+		return nil, "", &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Media files are not served by this API",
+		}
+	}
+
+	data, err := local.Open(key, expires, signature)
+	if err != nil {
+		code := 403
+		if errors.Is(err, Services.ErrMediaNotFound) {
+			code = 404
+		}
+		return nil, "", &entitiesDtos.ErrorResponse{
+			Code:    code,
+			Message: "Failed to open media file: " + err.Error(),
+		}
+	}
+	return data, http.DetectContentType(data), nil
+}
+
+// URLs signs the URLs of a media file and its thumbnail. Entities call it through entities.MediaURLs as
+// they are loaded.
+func (uc *MediaUseCase) URLs(mediaID string) (string, string) {
+	if mediaID == "" {
+		return "", ""
+	}
+	url, err := uc.storage.SignedURL(Services.MediaKey(mediaID))
+	if err != nil {
+		log.Printf("Warning: Error signing media URL for %s: %v", mediaID, err)
+		return "", ""
+	}
+	thumbnailURL, err := uc.storage.SignedURL(Services.MediaThumbnailKey(mediaID))
+	if err != nil {
+		log.Printf("Warning: Error signing media thumbnail URL for %s: %v", mediaID, err)
+		return url, ""
+	}
+	return url, thumbnailURL
+}
+
+// checkTarget makes sure the user may set this kind of image on the target and returns the target,
+// which for a vendor's own pictures is the vendor.
+func (uc *MediaUseCase) checkTarget(userID, role string, kind entities.MediaKind, targetID string) (string, *entitiesDtos.ErrorResponse) {
+	switch kind {
+	case entities.MediaMarketImage, entities.MediaMarketLayout, entities.MediaSlotImage:
+		var providerID string
+		var err error
+		if kind == entities.MediaSlotImage {
+			providerID, err = uc.repo.GetSlotProviderID(targetID)
+		} else {
+			providerID, err = uc.repo.GetMarketProviderID(targetID)
+		}
+		if err != nil {
+			code := 500
+			if strings.HasSuffix(err.Error(), "not found") {
+				code = 404
+			}
+			return "", &entitiesDtos.ErrorResponse{
+				Code:    code,
+				Message: "Failed to get image target: " + err.Error(),
+			}
+		}
+		if role != "admin" && providerID != userID {
+			return "", &entitiesDtos.ErrorResponse{
+				Code:    403,
+				Message: "You are not authorized to manage this market's images",
+			}
+		}
+		return targetID, nil
+
+	case entities.MediaVendorImage, entities.MediaStallImage, entities.MediaApplicationPhoto, entities.MediaApplicationDocument:
+		if role == "admin" && targetID != "" {
+			return targetID, nil
+		}
+		if role != "vendor" || (targetID != "" && targetID != userID) {
+			return "", &entitiesDtos.ErrorResponse{
+				Code:    403,
+				Message: "Vendors can only manage their own pictures",
+			}
+		}
+		return userID, nil
+
+	default:
+		return "", &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: fmt.Sprintf("%s images cannot be changed directly", kind),
+		}
+	}
+}
+
+// store checks an image, makes its thumbnail and puts both in storage.
+func (uc *MediaUseCase) store(kind entities.MediaKind, targetID, ownerID string, data []byte) (*entities.Media, *entitiesDtos.ErrorResponse) {
+	if int64(len(data)) > uc.media.MaxBytes() {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    413,
+			Message: fmt.Sprintf("Image is larger than %d MB", uc.media.MaxBytes()>>20),
+		}
+	}
+	info, err := uc.media.Inspect(data)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    415,
+			Message: "Unsupported image: " + err.Error(),
+		}
+	}
+	thumbnail, err := uc.media.Thumbnail(data)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    415,
+			Message: "Unsupported image: " + err.Error(),
+		}
+	}
+
+	media := &entities.Media{
+		ID:          uuid.New().String(),
+		Kind:        kind,
+		TargetID:    targetID,
+		OwnerID:     ownerID,
+		ContentType: info.ContentType,
+		Size:        int64(len(data)),
+		Width:       info.Width,
+		Height:      info.Height,
+	}
+	if err := uc.storage.Put(Services.MediaKey(media.ID), info.ContentType, data); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    502,
+			Message: "Failed to store image: " + err.Error(),
+		}
+	}
+	if err := uc.storage.Put(Services.MediaThumbnailKey(media.ID), "image/jpeg", thumbnail); err != nil {
+		uc.removeFiles(media.ID)
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    502,
+			Message: "Failed to store thumbnail: " + err.Error(),
+		}
+	}
+	return media, nil
+}
+
+// removeFiles deletes a media file and its thumbnail. A file left behind only wastes space, so failures
+// are logged rather than returned.
+func (uc *MediaUseCase) removeFiles(mediaID string) {
+	for _, key := range []string{Services.MediaKey(mediaID), Services.MediaThumbnailKey(mediaID)} {
+		if err := uc.storage.Delete(key); err != nil {
+			log.Printf("Warning: Error deleting media file %s: %v", key, err)
+		}
+	}
+}
+
+func parseMediaKind(kind string) (entities.MediaKind, error) {
+	switch entities.MediaKind(strings.ToLower(kind)) {
+	case entities.MediaMarketImage:
+		return entities.MediaMarketImage, nil
+	case entities.MediaMarketLayout:
+		return entities.MediaMarketLayout, nil
+	case entities.MediaSlotImage:
+		return entities.MediaSlotImage, nil
+	case entities.MediaVendorImage:
+		return entities.MediaVendorImage, nil
+	case entities.MediaStallImage:
+		return entities.MediaStallImage, nil
+	case entities.MediaApplicationPhoto:
+		return entities.MediaApplicationPhoto, nil
+	case entities.MediaApplicationDocument:
+		return entities.MediaApplicationDocument, nil
+	default:
+		return "", fmt.Errorf("%q is not one of market_image, market_layout, slot_image, vendor_image, stall_image, application_photo, application_document", kind)
+	}
+}
+
+// checkImageURL refuses images pasted into the free-form image fields, which belong in media uploads.
+func checkImageURL(field, value string) string {
+	if strings.HasPrefix(strings.TrimSpace(strings.ToLower(value)), "data:") || len(value) > maxImageURLLength {
+		return field + " must be a link; upload image files through /Media instead"
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package Usecase
+
+import (
+	"github.com/google/uuid"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+type NotificationUseCase struct {
+	repo Interfaces.INotification
+}
+
+var _ contact.INotificationUseCase = (*NotificationUseCase)(nil)
+
+func NewNotificationUseCase(repo Interfaces.INotification) *NotificationUseCase {
+	return &NotificationUseCase{repo: repo}
+}
+
+// Notify puts a message in the user's inbox.
+func (uc *NotificationUseCase) Notify(userID string, kind entities.NotificationKind, title, message, refID string) *entitiesDtos.ErrorResponse {
+	notification := &entities.Notification{
+		ID:      uuid.New().String(),
+		UserID:  userID,
+		Kind:    kind,
+		Title:   title,
+		Message: message,
+		RefID:   refID,
+	}
+	if err := uc.repo.CreateNotification(notification); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create notification: " + err.Error(),
+		}
+	}
+	return nil
+}
+
+func (uc *NotificationUseCase) GetNotifications(userID string, unreadOnly bool) ([]entities.Notification, *entitiesDtos.ErrorResponse) {
+	notifications, err := uc.repo.GetNotifications(userID, unreadOnly)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get notifications: " + err.Error(),
+		}
+	}
+	return notifications, nil
+}
+
+func (uc *NotificationUseCase) MarkRead(userID, notificationID string) *entitiesDtos.ErrorResponse {
+	if err := uc.repo.MarkRead(userID, notificationID); err != nil {
+		if err.Error() == "notification not found" {
+			return &entitiesDtos.ErrorResponse{
+				Code:    404,
+				Message: "Notification not found",
+			}
+		}
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to mark notification as read: " + err.Error(),
+		}
+	}
+	return nil
+}
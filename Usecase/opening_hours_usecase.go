@@ -0,0 +1,446 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+const (
+	openDaysDefault = 14
+	openDaysMax     = 92
+	minutesPerDay   = 24 * 60
+	minutesPerWeek  = 7 * minutesPerDay
+)
+
+type OpeningHoursUseCase struct {
+	repo Interfaces.IOpeningHours
+}
+
+var _ contact.IOpeningHoursUseCase = (*OpeningHoursUseCase)(nil)
+
+func NewOpeningHoursUseCase(repo Interfaces.IOpeningHours) *OpeningHoursUseCase {
+	return &OpeningHoursUseCase{repo: repo}
+}
+
+// GetOpeningHours returns a market's usual week and its exceptions from today on.
+func (uc *OpeningHoursUseCase) GetOpeningHours(marketID string) (*entitiesDtos.OpeningHoursResponse, *entitiesDtos.ErrorResponse) {
+	market, errRes := uc.getMarket(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	location := marketLocation(market)
+
+	sessions, err := uc.repo.GetSessions(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get opening hours: " + err.Error(),
+		}
+	}
+	exceptions, err := uc.repo.GetExceptions(marketID, marketToday(location).Format("2006-01-02"), "")
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get opening exceptions: " + err.Error(),
+		}
+	}
+
+	return &entitiesDtos.OpeningHoursResponse{
+		MarketID:   marketID,
+		Timezone:   location.String(),
+		Sessions:   sessions,
+		Exceptions: exceptions,
+	}, nil
+}
+
+// SaveWeeklyHours replaces a market's usual week. Sessions may not overlap, including a late session running
+// into the next day's first one.
+func (uc *OpeningHoursUseCase) SaveWeeklyHours(providerID, marketID string, req *entitiesDtos.OpeningHoursRequest) (*entitiesDtos.OpeningHoursResponse, *entitiesDtos.ErrorResponse) {
+	market, errRes := uc.ownMarket(providerID, marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	timezone := market.Timezone
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Unknown timezone: " + req.Timezone,
+			}
+		}
+		timezone = req.Timezone
+	}
+
+	sessions := make([]entities.OpeningSession, 0, len(req.Sessions))
+	for _, session := range req.Sessions {
+		if session.Weekday < 0 || session.Weekday > 6 {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Invalid weekday %d, use 0 for Sunday to 6 for Saturday", session.Weekday),
+			}
+		}
+		hours, message := checkTimeRange(entities.TimeRange{Opens: session.Opens, Closes: session.Closes})
+		if message != "" {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: message,
+			}
+		}
+		sessions = append(sessions, entities.OpeningSession{
+			ID:       uuid.New().String(),
+			MarketID: marketID,
+			Weekday:  session.Weekday,
+			Opens:    hours.Opens,
+			Closes:   hours.Closes,
+		})
+	}
+	if message := checkOverlap(sessions, true); message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	if err := uc.repo.SaveWeeklyHours(marketID, timezone, sessions); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save opening hours: " + err.Error(),
+		}
+	}
+
+	return uc.GetOpeningHours(marketID)
+}
+
+// SaveException closes a market on a date or opens it with its own sessions, replacing any exception
+// already on that date.
+func (uc *OpeningHoursUseCase) SaveException(providerID, marketID string, req *entitiesDtos.OpeningExceptionRequest) (*entities.OpeningException, *entitiesDtos.ErrorResponse) {
+	market, errRes := uc.ownMarket(providerID, marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	bad := func(message string) (*entities.OpeningException, *entitiesDtos.ErrorResponse) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+	day, err := time.Parse("2006-01-02", req.Date)
+	switch {
+	case err != nil:
+		return bad("Invalid date format. Please use YYYY-MM-DD")
+	case day.Before(marketToday(marketLocation(market))):
+		return bad("Opening exceptions cannot be set for past dates")
+	case req.Kind != entities.ExceptionClosed && req.Kind != entities.ExceptionOpen:
+		return bad("Exception kind must be closed or open")
+	case req.Kind == entities.ExceptionClosed && len(req.Sessions) > 0:
+		return bad("A closed day has no sessions")
+	case req.Kind == entities.ExceptionOpen && len(req.Sessions) == 0:
+		return bad("An open day needs at least one session")
+	}
+
+	sessions := make([]entities.TimeRange, 0, len(req.Sessions))
+	daySessions := make([]entities.OpeningSession, 0, len(req.Sessions))
+	for _, session := range req.Sessions {
+		hours, message := checkTimeRange(session)
+		if message != "" {
+			return bad(message)
+		}
+		sessions = append(sessions, hours)
+		daySessions = append(daySessions, entities.OpeningSession{Opens: hours.Opens, Closes: hours.Closes})
+	}
+	if message := checkOverlap(daySessions, false); message != "" {
+		return bad(message)
+	}
+
+	exception := &entities.OpeningException{
+		ID:       uuid.New().String(),
+		MarketID: marketID,
+		Date:     req.Date,
+		Kind:     req.Kind,
+		Reason:   strings.TrimSpace(req.Reason),
+		Sessions: sessions,
+	}
+	if err := uc.repo.SaveException(exception); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save opening exception: " + err.Error(),
+		}
+	}
+
+	return exception, nil
+}
+
+func (uc *OpeningHoursUseCase) DeleteException(providerID, marketID, date string) *entitiesDtos.ErrorResponse {
+	if _, errRes := uc.ownMarket(providerID, marketID); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repo.DeleteException(marketID, date); err != nil {
+		code := 500
+		if err.Error() == "opening exception not found" {
+			code = 404
+		}
+		return &entitiesDtos.ErrorResponse{
+			Code:    code,
+			Message: "Failed to delete opening exception: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetOpenDays lists the dates a market opens over a number of days, 14 when 0, from a date, today in the
+// market's timezone by default.
+func (uc *OpeningHoursUseCase) GetOpenDays(marketID, from string, days int) (*entitiesDtos.OpenDaysResponse, *entitiesDtos.ErrorResponse) {
+	if days == 0 {
+		days = openDaysDefault
+	}
+	if days < 1 || days > openDaysMax {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("days must be between 1 and %d", openDaysMax),
+		}
+	}
+
+	market, errRes := uc.getMarket(marketID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	location := marketLocation(market)
+
+	start := marketToday(location)
+	if from != "" {
+		var err error
+		if start, err = time.Parse("2006-01-02", from); err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Invalid from date. Please use YYYY-MM-DD",
+			}
+		}
+	}
+	end := start.AddDate(0, 0, days-1)
+
+	hours, errRes := uc.loadHours(market, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	response := &entitiesDtos.OpenDaysResponse{
+		MarketID: marketID,
+		Timezone: location.String(),
+		From:     start.Format("2006-01-02"),
+		To:       end.Format("2006-01-02"),
+		Days:     make([]entitiesDtos.OpenDay, 0),
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		sessions, exception := hours.SessionsOn(day)
+		if len(sessions) == 0 {
+			continue
+		}
+
+		openDay := entitiesDtos.OpenDay{
+			Date:     day.Format("2006-01-02"),
+			Weekday:  int(day.Weekday()),
+			Sessions: make([]entitiesDtos.OpenSession, 0, len(sessions)),
+		}
+		if exception != nil {
+			openDay.Special = true
+			openDay.Reason = exception.Reason
+		}
+		for _, session := range sessions {
+			opensAt, closesAt, err := session.Span(day, location)
+			if err != nil {
+				continue
+			}
+			openDay.Sessions = append(openDay.Sessions, entitiesDtos.OpenSession{
+				Opens:    session.Opens,
+				Closes:   session.Closes,
+				OpensAt:  opensAt,
+				ClosesAt: closesAt,
+			})
+		}
+		response.Days = append(response.Days, openDay)
+	}
+
+	return response, nil
+}
+
+// CheckOpen refuses a market date on which the market does not open, by its own hours or by its slot schedule
+// and the platform holidays.
+func (uc *OpeningHoursUseCase) CheckOpen(marketID, date string) *entitiesDtos.ErrorResponse {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid date format. Please use YYYY-MM-DD",
+		}
+	}
+	market, errRes := uc.getMarket(marketID)
+	if errRes != nil {
+		return errRes
+	}
+	hours, errRes := uc.loadHours(market, date, date)
+	if errRes != nil {
+		return errRes
+	}
+
+	sessions, exception := hours.SessionsOn(day)
+	if len(sessions) > 0 {
+		return nil
+	}
+	message := fmt.Sprintf("The market is closed on %s", date)
+	if exception != nil && exception.Reason != "" {
+		message += ": " + exception.Reason
+	}
+	return &entitiesDtos.ErrorResponse{
+		Code:    409,
+		Message: message,
+	}
+}
+
+func (uc *OpeningHoursUseCase) loadHours(market *entities.Market, from, to string) (*entities.OpeningHours, *entitiesDtos.ErrorResponse) {
+	sessions, err := uc.repo.GetSessions(market.ID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get opening hours: " + err.Error(),
+		}
+	}
+	exceptions, err := uc.repo.GetExceptions(market.ID, from, to)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get opening exceptions: " + err.Error(),
+		}
+	}
+
+	// Markets that never set weekly hours open every day for their old open and close times
+	fallback := entities.TimeRange{Opens: "00:00", Closes: "23:59"}
+	if hours, message := checkTimeRange(entities.TimeRange{Opens: market.OpenTime, Closes: market.CloseTime}); message == "" {
+		fallback = hours
+	}
+
+	hours := &entities.OpeningHours{
+		Location:   marketLocation(market),
+		Default:    fallback,
+		Sessions:   sessions,
+		Exceptions: exceptions,
+	}
+
+	// The slot schedule's exceptions and the platform holidays close and open dates too
+	schedule, err := uc.repo.GetSchedule(market.ID)
+	if err != nil && err.Error() != "schedule not found" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get schedule: " + err.Error(),
+		}
+	}
+	if schedule != nil {
+		holidays, err := uc.repo.GetHolidays(from, to)
+		if err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to get holidays: " + err.Error(),
+			}
+		}
+		hours.WithSchedule(schedule, holidays)
+	}
+
+	return hours, nil
+}
+
+func (uc *OpeningHoursUseCase) getMarket(marketID string) (*entities.Market, *entitiesDtos.ErrorResponse) {
+	market, err := uc.repo.GetMarket(marketID)
+	if err != nil {
+		code := 500
+		if err.Error() == "market not found" {
+			code = 404
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    code,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+	return market, nil
+}
+
+func (uc *OpeningHoursUseCase) ownMarket(providerID, marketID string) (*entities.Market, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "opening hours"); errRes != nil {
+		return nil, errRes
+	}
+	return uc.getMarket(marketID)
+}
+
+func marketLocation(market *entities.Market) *time.Location {
+	for _, name := range []string{market.Timezone, "Asia/Bangkok"} {
+		if location, err := time.LoadLocation(name); name != "" && err == nil {
+			return location
+		}
+	}
+	return time.UTC
+}
+
+// marketToday is the current date in a location, as midnight UTC like a parsed YYYY-MM-DD.
+func marketToday(location *time.Location) time.Time {
+	now := time.Now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkTimeRange normalises a range to HH:MM or explains what is wrong with it.
+func checkTimeRange(hours entities.TimeRange) (entities.TimeRange, string) {
+	opens, openErr := time.Parse("15:04", strings.TrimSpace(hours.Opens))
+	closes, closeErr := time.Parse("15:04", strings.TrimSpace(hours.Closes))
+	switch {
+	case openErr != nil || closeErr != nil:
+		return hours, fmt.Sprintf("Invalid session %s-%s, use HH:MM", hours.Opens, hours.Closes)
+	case opens.Equal(closes):
+		return hours, fmt.Sprintf("Session %s-%s opens and closes at the same time", hours.Opens, hours.Closes)
+	}
+	return entities.TimeRange{Opens: opens.Format("15:04"), Closes: closes.Format("15:04")}, ""
+}
+
+type sessionSpan struct {
+	start, end int
+	session    entities.OpeningSession
+}
+
+// checkOverlap finds sessions that overlap, counting minutes from the start of the week. Over a whole week
+// Saturday night wraps into Sunday.
+func checkOverlap(sessions []entities.OpeningSession, week bool) string {
+	minutes := func(clock string) int {
+		t, _ := time.Parse("15:04", clock)
+		return t.Hour()*60 + t.Minute()
+	}
+	spans := make([]sessionSpan, 0, len(sessions))
+	for _, session := range sessions {
+		start := session.Weekday*minutesPerDay + minutes(session.Opens)
+		end := session.Weekday*minutesPerDay + minutes(session.Closes)
+		if end <= start {
+			end += minutesPerDay
+		}
+		spans = append(spans, sessionSpan{start: start, end: end, session: session})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	overlap := func(a, b entities.OpeningSession) string {
+		return fmt.Sprintf("Sessions %s-%s and %s-%s overlap", a.Opens, a.Closes, b.Opens, b.Closes)
+	}
+	for i := 1; i < len(spans); i++ {
+		if spans[i].start < spans[i-1].end {
+			return overlap(spans[i-1].session, spans[i].session)
+		}
+	}
+	if n := len(spans); week && n > 1 && spans[n-1].end-minutesPerWeek > spans[0].start {
+		return overlap(spans[n-1].session, spans[0].session)
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package Usecase
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	entities "tln-backend/Entities"
+	"tln-backend/Interfaces"
+)
+
+// fakeOpeningHoursRepo serves one market, open on Saturday evenings, with whatever schedule and holidays a
+// case sets up.
+type fakeOpeningHoursRepo struct {
+	Interfaces.IOpeningHours
+	exceptions []entities.OpeningException
+	schedule   *entities.MarketSchedule
+	holidays   []entities.Holiday
+}
+
+func (f *fakeOpeningHoursRepo) GetMarket(marketID string) (*entities.Market, error) {
+	if marketID != "m1" {
+		return nil, fmt.Errorf("market not found")
+	}
+	return &entities.Market{ID: "m1", ProviderID: "p1", Timezone: "Asia/Bangkok", OpenTime: "16:00", CloseTime: "22:00"}, nil
+}
+
+func (f *fakeOpeningHoursRepo) GetSessions(marketID string) ([]entities.OpeningSession, error) {
+	return []entities.OpeningSession{{MarketID: marketID, Weekday: 6, Opens: "17:00", Closes: "23:00"}}, nil
+}
+
+func (f *fakeOpeningHoursRepo) GetExceptions(marketID, from, to string) ([]entities.OpeningException, error) {
+	var exceptions []entities.OpeningException
+	for _, exception := range f.exceptions {
+		if exception.Date >= from && (to == "" || exception.Date <= to) {
+			exceptions = append(exceptions, exception)
+		}
+	}
+	return exceptions, nil
+}
+
+func (f *fakeOpeningHoursRepo) GetSchedule(marketID string) (*entities.MarketSchedule, error) {
+	if f.schedule == nil {
+		return nil, fmt.Errorf("schedule not found")
+	}
+	return f.schedule, nil
+}
+
+func (f *fakeOpeningHoursRepo) GetHolidays(from, to string) ([]entities.Holiday, error) {
+	var holidays []entities.Holiday
+	for _, holiday := range f.holidays {
+		if holiday.Date >= from && holiday.Date <= to {
+			holidays = append(holidays, holiday)
+		}
+	}
+	return holidays, nil
+}
+
+func TestCheckOpen(t *testing.T) {
+	// 2024-06-01 is a Saturday, 2024-06-04 a Tuesday
+	schedule := func(skipHolidays bool, exceptions ...entities.ScheduleException) *entities.MarketSchedule {
+		return &entities.MarketSchedule{ID: "s1", MarketID: "m1", Active: true, SkipHolidays: skipHolidays, Exceptions: exceptions}
+	}
+	holidays := []entities.Holiday{{Date: "2024-06-01", Name: "Visakha Bucha"}}
+
+	tests := []struct {
+		name       string
+		marketID   string
+		date       string
+		exceptions []entities.OpeningException
+		schedule   *entities.MarketSchedule
+		holidays   []entities.Holiday
+		wantCode   int
+		wantText   string
+	}{
+		{name: "usual market day", date: "2024-06-01"},
+		{name: "closed weekday", date: "2024-06-04", wantCode: 409, wantText: "closed on 2024-06-04"},
+		{name: "bad date", date: "01/06/2024", wantCode: 400},
+		{name: "unknown market", marketID: "gone", date: "2024-06-01", wantCode: 404},
+		{
+			name:       "opening exception closes",
+			date:       "2024-06-01",
+			exceptions: []entities.OpeningException{{MarketID: "m1", Date: "2024-06-01", Kind: entities.ExceptionClosed, Reason: "Flooding"}},
+			wantCode:   409,
+			wantText:   "Flooding",
+		},
+		{
+			name: "opening exception opens",
+			date: "2024-06-04",
+			exceptions: []entities.OpeningException{{MarketID: "m1", Date: "2024-06-04", Kind: entities.ExceptionOpen,
+				Sessions: []entities.TimeRange{{Opens: "18:00", Closes: "22:00"}}}},
+		},
+		{
+			name:     "schedule opens a closed weekday",
+			date:     "2024-06-04",
+			schedule: schedule(true, entities.ScheduleException{Date: "2024-06-04", Kind: entities.ExceptionOpen, Reason: "Festival"}),
+		},
+		{
+			name:     "schedule closes a market day",
+			date:     "2024-06-01",
+			schedule: schedule(true, entities.ScheduleException{Date: "2024-06-01", Kind: entities.ExceptionClosed, Reason: "Road works"}),
+			wantCode: 409,
+			wantText: "Road works",
+		},
+		{
+			name:     "inactive schedule is ignored",
+			date:     "2024-06-01",
+			schedule: &entities.MarketSchedule{MarketID: "m1", Exceptions: []entities.ScheduleException{{Date: "2024-06-01", Kind: entities.ExceptionClosed}}},
+		},
+		{
+			name:     "holiday closes a skipping schedule",
+			date:     "2024-06-01",
+			schedule: schedule(true),
+			holidays: holidays,
+			wantCode: 409,
+			wantText: "Visakha Bucha",
+		},
+		{name: "holiday kept by the schedule", date: "2024-06-01", schedule: schedule(false), holidays: holidays},
+		{name: "holiday without a schedule", date: "2024-06-01", holidays: holidays},
+		{
+			name:     "schedule exception opens a holiday",
+			date:     "2024-06-01",
+			schedule: schedule(true, entities.ScheduleException{Date: "2024-06-01", Kind: entities.ExceptionOpen}),
+			holidays: holidays,
+		},
+		{
+			name:       "market's own exception wins over the schedule",
+			date:       "2024-06-04",
+			exceptions: []entities.OpeningException{{MarketID: "m1", Date: "2024-06-04", Kind: entities.ExceptionClosed, Reason: "Private hire"}},
+			schedule:   schedule(true, entities.ScheduleException{Date: "2024-06-04", Kind: entities.ExceptionOpen}),
+			wantCode:   409,
+			wantText:   "Private hire",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marketID := tt.marketID
+			if marketID == "" {
+				marketID = "m1"
+			}
+			uc := &OpeningHoursUseCase{repo: &fakeOpeningHoursRepo{exceptions: tt.exceptions, schedule: tt.schedule, holidays: tt.holidays}}
+
+			errRes := uc.CheckOpen(marketID, tt.date)
+			if tt.wantCode == 0 {
+				if errRes != nil {
+					t.Fatalf("CheckOpen() error = %v", errRes)
+				}
+				return
+			}
+			if errRes == nil || errRes.Code != tt.wantCode {
+				t.Fatalf("CheckOpen() error = %v, want code %d", errRes, tt.wantCode)
+			}
+			if !strings.Contains(errRes.Message, tt.wantText) {
+				t.Errorf("message %q does not mention %q", errRes.Message, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestOpenDaysFollowSchedule(t *testing.T) {
+	repo := &fakeOpeningHoursRepo{
+		schedule: &entities.MarketSchedule{MarketID: "m1", Active: true, SkipHolidays: true, Exceptions: []entities.ScheduleException{
+			{Date: "2024-06-04", Kind: entities.ExceptionOpen, Reason: "Festival"},
+		}},
+		holidays: []entities.Holiday{{Date: "2024-06-08", Name: "Holiday"}},
+	}
+	uc := &OpeningHoursUseCase{repo: repo}
+
+	got, errRes := uc.GetOpenDays("m1", "2024-06-01", 15)
+	if errRes != nil {
+		t.Fatalf("GetOpenDays() error = %v", errRes)
+	}
+	var dates []string
+	for _, day := range got.Days {
+		dates = append(dates, day.Date)
+		if day.Date == "2024-06-04" && (!day.Special || day.Reason != "Festival" || len(day.Sessions) != 1 || day.Sessions[0].Opens != "16:00") {
+			t.Errorf("festival day = %+v, want a special day on the market's default hours", day)
+		}
+	}
+	if want := "2024-06-01 2024-06-04 2024-06-15"; strings.Join(dates, " ") != want {
+		t.Errorf("open days = %v, want %s", dates, want)
+	}
+}
@@ -0,0 +1,417 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+type PricingUseCase struct {
+	repo      Interfaces.IPricing
+	service   *Services.PricingService
+	amenities contact.IAmenityUseCase
+}
+
+var _ contact.IPricingUseCase = (*PricingUseCase)(nil)
+
+func NewPricingUseCase(repo Interfaces.IPricing, service *Services.PricingService, amenities contact.IAmenityUseCase) *PricingUseCase {
+	return &PricingUseCase{
+		repo:      repo,
+		service:   service,
+		amenities: amenities,
+	}
+}
+
+func (uc *PricingUseCase) CreateRule(providerID, marketID string, req *entitiesDtos.PricingRuleRequest) (*entities.PricingRule, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "pricing"); errRes != nil {
+		return nil, errRes
+	}
+
+	rule := &entities.PricingRule{
+		ID:       uuid.New().String(),
+		MarketID: marketID,
+	}
+	if errRes := fillPricingRule(rule, req); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.CreateRule(rule); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create pricing rule: " + err.Error(),
+		}
+	}
+
+	return rule, nil
+}
+
+func (uc *PricingUseCase) UpdateRule(providerID, ruleID string, req *entitiesDtos.PricingRuleRequest) (*entities.PricingRule, *entitiesDtos.ErrorResponse) {
+	rule, errRes := uc.ownRule(providerID, ruleID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if errRes := fillPricingRule(rule, req); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.SaveRule(rule); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to update pricing rule: " + err.Error(),
+		}
+	}
+
+	return rule, nil
+}
+
+func (uc *PricingUseCase) DeleteRule(providerID, ruleID string) *entitiesDtos.ErrorResponse {
+	if _, errRes := uc.ownRule(providerID, ruleID); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repo.DeleteRule(ruleID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete pricing rule: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (uc *PricingUseCase) GetRules(providerID, marketID string) ([]entities.PricingRule, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "pricing"); errRes != nil {
+		return nil, errRes
+	}
+
+	rules, err := uc.repo.GetRules(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get pricing rules: " + err.Error(),
+		}
+	}
+
+	return rules, nil
+}
+
+func (uc *PricingUseCase) CreateCombo(providerID, marketID string, req *entitiesDtos.ComboPriceRequest) (*entities.ComboPrice, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "pricing"); errRes != nil {
+		return nil, errRes
+	}
+
+	message := ""
+	switch {
+	case strings.TrimSpace(req.Name) == "":
+		message = "Combo name is required"
+	case req.Stalls < 2 || req.Stalls > maxGroupSlots:
+		message = fmt.Sprintf("A combo covers 2 to %d stalls", maxGroupSlots)
+	case req.Price < 0:
+		message = "Combo price cannot be negative"
+	}
+	if message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	combo := &entities.ComboPrice{
+		ID:       uuid.New().String(),
+		MarketID: marketID,
+		Name:     strings.TrimSpace(req.Name),
+		Zone:     req.Zone,
+		Stalls:   req.Stalls,
+		Price:    entities.RoundMoney(req.Price),
+	}
+	if err := uc.repo.CreateCombo(combo); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create combo price: " + err.Error(),
+		}
+	}
+
+	return combo, nil
+}
+
+func (uc *PricingUseCase) GetCombos(providerID, marketID string) ([]entities.ComboPrice, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "pricing"); errRes != nil {
+		return nil, errRes
+	}
+
+	combos, err := uc.repo.GetCombos(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get combo prices: " + err.Error(),
+		}
+	}
+
+	return combos, nil
+}
+
+func (uc *PricingUseCase) DeleteCombo(providerID, comboID string) *entitiesDtos.ErrorResponse {
+	combo, err := uc.repo.GetCombo(comboID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get combo price: " + err.Error(),
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, combo.MarketID, "pricing"); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.repo.DeleteCombo(comboID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete combo price: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// QuoteSlot prices a slot and the add-ons a vendor is considering.
+func (uc *PricingUseCase) QuoteSlot(slotID string, addOns []entitiesDtos.AddOnChoice) (*entitiesDtos.PriceQuote, *entitiesDtos.ErrorResponse) {
+	slot, err := uc.repo.GetSlot(slotID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get slot: " + err.Error(),
+		}
+	}
+
+	quote, errRes := uc.PriceSlot(slot, time.Now())
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	quote.AddOns, quote.AddOnTotal, errRes = uc.amenities.PriceAddOns(slot.MarketID, addOns)
+	if errRes != nil {
+		return nil, errRes
+	}
+	quote.Total = entities.RoundMoney(quote.Price + quote.AddOnTotal)
+	return quote, nil
+}
+
+// PriceSlot evaluates the market's rules for a slot as of the given moment. Bookings and promotion quotes both
+// price through here so a vendor is charged what they were quoted.
+func (uc *PricingUseCase) PriceSlot(slot *entities.Slot, at time.Time) (*entitiesDtos.PriceQuote, *entitiesDtos.ErrorResponse) {
+	date := slotDate(slot)
+	marketDay, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: fmt.Sprintf("Slot %s has an unreadable date %q", slot.ID, slot.Date),
+		}
+	}
+
+	rules, err := uc.repo.GetActiveRules(slot.MarketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get pricing rules: " + err.Error(),
+		}
+	}
+
+	location, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		location = time.UTC
+	}
+	local := at.In(location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	quote := &entitiesDtos.PriceQuote{
+		SlotID:    slot.ID,
+		MarketID:  slot.MarketID,
+		Date:      date,
+		SlotPrice: entities.RoundMoney(slot.Price),
+		LeadDays:  int(math.Round(marketDay.Sub(today).Hours() / 24)),
+	}
+
+	holiday, err := uc.repo.GetHoliday(date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check holidays: " + err.Error(),
+		}
+	}
+	if holiday != nil {
+		quote.Holiday = holiday.Name
+	}
+
+	taken, total, err := uc.repo.GetOccupancy(slot.MarketID, date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check occupancy: " + err.Error(),
+		}
+	}
+	if total > 0 {
+		quote.Occupancy = math.Round(float64(taken)/float64(total)*1000) / 1000
+	}
+
+	uc.service.Evaluate(slot, rules, quote)
+	quote.Total = quote.Price
+	return quote, nil
+}
+
+// QuoteGroup prices stalls a vendor is thinking of booking together.
+func (uc *PricingUseCase) QuoteGroup(slotIDs []string) (*entitiesDtos.GroupQuote, *entitiesDtos.ErrorResponse) {
+	slots, err := uc.repo.GetSlots(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get slots: " + err.Error(),
+		}
+	}
+	if len(slots) != len(slotIDs) || len(slots) < 2 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Give at least two existing slot IDs",
+		}
+	}
+	for _, slot := range slots[1:] {
+		if slot.MarketID != slots[0].MarketID {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Grouped slots must belong to one market",
+			}
+		}
+	}
+
+	return uc.PriceGroup(slots, time.Now())
+}
+
+// PriceGroup prices each stall through the rules and charges the market's combo price for the group instead of
+// the sum when one fits.
+func (uc *PricingUseCase) PriceGroup(slots []*entities.Slot, at time.Time) (*entitiesDtos.GroupQuote, *entitiesDtos.ErrorResponse) {
+	quote := &entitiesDtos.GroupQuote{
+		Slots: make([]entitiesDtos.PriceQuote, 0, len(slots)),
+	}
+	for _, slot := range slots {
+		slotQuote, errRes := uc.PriceSlot(slot, at)
+		if errRes != nil {
+			return nil, errRes
+		}
+		quote.Slots = append(quote.Slots, *slotQuote)
+		quote.ListPrice = entities.RoundMoney(quote.ListPrice + slotQuote.Price)
+	}
+	quote.Price = quote.ListPrice
+	if len(slots) == 0 {
+		return quote, nil
+	}
+
+	combos, err := uc.repo.GetCombos(slots[0].MarketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get combo prices: " + err.Error(),
+		}
+	}
+	if combo := uc.service.BestCombo(slots, combos); combo != nil {
+		quote.ComboID = combo.ID
+		quote.ComboName = combo.Name
+		quote.Price = combo.Price
+	}
+
+	return quote, nil
+}
+
+func (uc *PricingUseCase) ownRule(providerID, ruleID string) (*entities.PricingRule, *entitiesDtos.ErrorResponse) {
+	rule, err := uc.repo.GetRule(ruleID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get pricing rule: " + err.Error(),
+		}
+	}
+
+	if errRes := checkMarketOwner(uc.repo, providerID, rule.MarketID, "pricing"); errRes != nil {
+		return nil, errRes
+	}
+
+	return rule, nil
+}
+
+// fillPricingRule validates the request for its kind and copies it onto the rule.
+func fillPricingRule(rule *entities.PricingRule, req *entitiesDtos.PricingRuleRequest) *entitiesDtos.ErrorResponse {
+	multiplier := req.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	message := ""
+	switch req.Kind {
+	case entities.RuleBase:
+		if req.Price < 0 {
+			message = "Base rules need a non-negative price"
+		}
+	case entities.RuleWeekday:
+		if len(req.Weekdays) == 0 {
+			message = "Weekday rules need at least one weekday"
+		}
+		for _, day := range req.Weekdays {
+			if day < 0 || day > 6 {
+				message = fmt.Sprintf("Invalid weekday %d, use 0 (Sunday) to 6 (Saturday)", day)
+			}
+		}
+	case entities.RuleHoliday:
+	case entities.RuleEarlyBird:
+		if req.DaysBefore < 1 {
+			message = "Early bird rules need days_before of at least 1"
+		}
+	case entities.RuleLastMinute:
+		if req.DaysBefore < 0 {
+			message = "Last minute rules need a non-negative days_before"
+		}
+	case entities.RuleDemand:
+		if req.OccupancyThreshold <= 0 || req.OccupancyThreshold > 1 {
+			message = "Demand rules need an occupancy_threshold above 0 and at most 1"
+		}
+	default:
+		message = fmt.Sprintf("Invalid rule kind: %s", req.Kind)
+	}
+	switch {
+	case message != "":
+	case strings.TrimSpace(req.Name) == "":
+		message = "Pricing rule name is required"
+	case req.Kind != entities.RuleBase && multiplier < 0:
+		message = "Multiplier cannot be negative"
+	case req.Kind != entities.RuleBase && multiplier == 1 && req.Amount == 0:
+		message = "Adjustment rules need a multiplier other than 1 or a non-zero amount"
+	}
+	if message == "" && req.Category != "" {
+		if _, err := parseCategory(string(req.Category)); err != nil {
+			message = "Invalid category: " + err.Error()
+		}
+	}
+	if message != "" {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Kind = req.Kind
+	rule.Zone = req.Zone
+	rule.Category = req.Category
+	rule.Weekdays = req.Weekdays
+	rule.Price = entities.RoundMoney(req.Price)
+	rule.Multiplier = multiplier
+	rule.Amount = entities.RoundMoney(req.Amount)
+	rule.DaysBefore = req.DaysBefore
+	rule.OccupancyThreshold = req.OccupancyThreshold
+	rule.Priority = req.Priority
+	rule.Active = req.Active == nil || *req.Active
+	return nil
+}
@@ -0,0 +1,376 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+type PromotionUseCase struct {
+	repo    Interfaces.IPromotion
+	pricing contact.IPricingUseCase
+}
+
+var _ contact.IPromotionUseCase = (*PromotionUseCase)(nil)
+
+func NewPromotionUseCase(repo Interfaces.IPromotion, pricing contact.IPricingUseCase) *PromotionUseCase {
+	return &PromotionUseCase{
+		repo:    repo,
+		pricing: pricing,
+	}
+}
+
+func (uc *PromotionUseCase) CreatePromotion(providerID string, req *entitiesDtos.PromotionRequest) (*entities.Promotion, *entitiesDtos.ErrorResponse) {
+	if errRes := validatePromotion(req); errRes != nil {
+		return nil, errRes
+	}
+
+	marketID, zoneName := req.MarketID, ""
+	if req.ZoneID != "" {
+		zone, err := uc.repo.GetZone(req.ZoneID)
+		if err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    404,
+				Message: "Failed to get zone: " + err.Error(),
+			}
+		}
+		if marketID != "" && zone.MarketID != marketID {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Zone is not in the promotion's market",
+			}
+		}
+		marketID, zoneName = zone.MarketID, zone.Name
+	}
+
+	if marketID != "" {
+		if errRes := checkMarketOwner(uc.repo, providerID, marketID, "promotions"); errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	code := normalizePromoCode(req.Code)
+	if existing, _ := uc.repo.GetPromotionByCode(providerID, code); existing != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Promotion code %s already exists", code),
+		}
+	}
+
+	promotion := &entities.Promotion{
+		ID:               uuid.New().String(),
+		ProviderID:       providerID,
+		Code:             code,
+		Description:      req.Description,
+		DiscountType:     req.DiscountType,
+		DiscountValue:    entities.RoundMoney(req.DiscountValue),
+		MaxDiscount:      entities.RoundMoney(req.MaxDiscount),
+		MarketID:         marketID,
+		ZoneID:           req.ZoneID,
+		Zone:             zoneName,
+		Category:         req.Category,
+		StartDate:        req.StartDate,
+		EndDate:          req.EndDate,
+		ValidFrom:        req.ValidFrom,
+		ValidUntil:       req.ValidUntil,
+		UsageLimit:       req.UsageLimit,
+		PerVendorLimit:   req.PerVendorLimit,
+		FirstBookingOnly: req.FirstBookingOnly,
+		Status:           entities.PromoActive,
+	}
+
+	if err := uc.repo.CreatePromotion(promotion); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create promotion: " + err.Error(),
+		}
+	}
+
+	return promotion, nil
+}
+
+func (uc *PromotionUseCase) GetPromotions(providerID string) ([]entities.Promotion, *entitiesDtos.ErrorResponse) {
+	promotions, err := uc.repo.GetPromotionsByProvider(providerID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get promotions: " + err.Error(),
+		}
+	}
+
+	return promotions, nil
+}
+
+func (uc *PromotionUseCase) UpdatePromotionStatus(providerID, promotionID string, status entities.PromoStatus) (*entities.Promotion, *entitiesDtos.ErrorResponse) {
+	if status != entities.PromoActive && status != entities.PromoDisabled {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Invalid promotion status: %s", status),
+		}
+	}
+
+	promotion, errRes := uc.ownPromotion(providerID, promotionID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.UpdatePromotionStatus(promotion.ID, status); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to update promotion: " + err.Error(),
+		}
+	}
+
+	promotion.Status = status
+	return promotion, nil
+}
+
+func (uc *PromotionUseCase) GetRedemptions(providerID, promotionID string) ([]entities.Redemption, *entitiesDtos.ErrorResponse) {
+	promotion, errRes := uc.ownPromotion(providerID, promotionID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	redemptions, err := uc.repo.GetRedemptionsByPromotion(promotion.ID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get redemptions: " + err.Error(),
+		}
+	}
+
+	return redemptions, nil
+}
+
+// Quote shows a vendor what a code would take off a slot without reserving a use.
+func (uc *PromotionUseCase) Quote(req *entitiesDtos.PromotionQuoteRequest) (*entitiesDtos.PromotionQuoteResponse, *entitiesDtos.ErrorResponse) {
+	slot, err := uc.repo.GetSlot(req.SlotID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get slot: " + err.Error(),
+		}
+	}
+
+	// Codes discount the rule-based price the booking would charge, not the stored one
+	priced, errRes := uc.pricing.PriceSlot(slot, time.Now())
+	if errRes != nil {
+		return nil, errRes
+	}
+	slot.Price = priced.Price
+
+	promotion, discount, errRes := uc.evaluate(req.Code, slot, req.VendorID, slot.Date)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return &entitiesDtos.PromotionQuoteResponse{
+		Code:       promotion.Code,
+		SlotID:     slot.ID,
+		ListPrice:  slot.Price,
+		Discount:   discount,
+		FinalPrice: entities.RoundMoney(slot.Price - discount),
+	}, nil
+}
+
+// ApplyPromotion checks a code against the slot being booked and reserves one use of it for the booking's payment.
+func (uc *PromotionUseCase) ApplyPromotion(code string, slot *entities.Slot, vendorID, bookingDate, bookingID, paymentID string) (*entities.Redemption, *entitiesDtos.ErrorResponse) {
+	promotion, discount, errRes := uc.evaluate(code, slot, vendorID, bookingDate)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	redemption := &entities.Redemption{
+		ID:          uuid.New().String(),
+		PromotionID: promotion.ID,
+		PaymentID:   paymentID,
+		BookingID:   bookingID,
+		VendorID:    vendorID,
+		ListPrice:   slot.Price,
+		Discount:    discount,
+		Status:      entities.RedemptionApplied,
+	}
+
+	if err := uc.repo.ReserveRedemption(redemption); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Failed to apply promotion: " + err.Error(),
+		}
+	}
+
+	return redemption, nil
+}
+
+func (uc *PromotionUseCase) ReleasePromotion(bookingID string) *entitiesDtos.ErrorResponse {
+	if err := uc.repo.ReleaseRedemption(bookingID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to release promotion: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// evaluate finds the code for the slot's provider and works out its discount, checking everything except usage
+// limits. vendorID is the signed-in vendor, whom the per-vendor limits count against.
+func (uc *PromotionUseCase) evaluate(code string, slot *entities.Slot, vendorID, bookingDate string) (*entities.Promotion, float64, *entitiesDtos.ErrorResponse) {
+	if vendorID == "" {
+		return nil, 0, &entitiesDtos.ErrorResponse{
+			Code:    401,
+			Message: "Sign in as a vendor to use a promotion code",
+		}
+	}
+	providerID, err := uc.repo.GetMarketProviderID(slot.MarketID)
+	if err != nil {
+		return nil, 0, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+
+	promotion, err := uc.repo.GetPromotionByCode(providerID, normalizePromoCode(code))
+	if err != nil {
+		return nil, 0, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Invalid promotion code",
+		}
+	}
+
+	if reason := promotionMismatch(promotion, slot, bookingDate, time.Now()); reason != "" {
+		return nil, 0, &entitiesDtos.ErrorResponse{
+			Code:    422,
+			Message: "Promotion cannot be used: " + reason,
+		}
+	}
+
+	if promotion.FirstBookingOnly {
+		booked, err := uc.repo.HasCompletedBooking(vendorID, providerID)
+		if err != nil {
+			return nil, 0, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to check booking history: " + err.Error(),
+			}
+		}
+		if booked {
+			return nil, 0, &entitiesDtos.ErrorResponse{
+				Code:    422,
+				Message: "Promotion cannot be used: it is only for a first booking",
+			}
+		}
+	}
+
+	return promotion, promotionDiscount(promotion, slot.Price), nil
+}
+
+func (uc *PromotionUseCase) ownPromotion(providerID, promotionID string) (*entities.Promotion, *entitiesDtos.ErrorResponse) {
+	promotion, err := uc.repo.GetPromotion(promotionID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get promotion: " + err.Error(),
+		}
+	}
+	if promotion.ProviderID != providerID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to manage this promotion",
+		}
+	}
+
+	return promotion, nil
+}
+
+// promotionMismatch returns why a promotion does not apply to the slot, or an empty string when it does.
+func promotionMismatch(promotion *entities.Promotion, slot *entities.Slot, bookingDate string, now time.Time) string {
+	switch {
+	case promotion.Status != entities.PromoActive:
+		return "it is no longer active"
+	case promotion.ValidFrom != nil && now.Before(*promotion.ValidFrom):
+		return "it is not valid yet"
+	case promotion.ValidUntil != nil && now.After(*promotion.ValidUntil):
+		return "it has expired"
+	case promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit:
+		return "it has been fully redeemed"
+	case promotion.MarketID != "" && promotion.MarketID != slot.MarketID:
+		return "it is for a different market"
+	case !promotionZoneMatches(promotion, slot):
+		return "it is for a different zone"
+	case promotion.Category != "" && promotion.Category != slot.Category:
+		return "it is for a different category"
+	case promotion.StartDate != "" && bookingDate < promotion.StartDate:
+		return "the booking date is before the promotion period"
+	case promotion.EndDate != "" && bookingDate > promotion.EndDate:
+		return "the booking date is after the promotion period"
+	}
+	return ""
+}
+
+// promotionZoneMatches compares zones by ID. Promotions and slots from before zones had IDs only carry the zone's
+// name, so those compare by name.
+func promotionZoneMatches(promotion *entities.Promotion, slot *entities.Slot) bool {
+	if promotion.ZoneID != "" && slot.ZoneID != "" {
+		return promotion.ZoneID == slot.ZoneID
+	}
+	return promotion.Zone == "" || promotion.Zone == slot.Zone
+}
+
+// promotionDiscount never takes more off than the slot costs.
+func promotionDiscount(promotion *entities.Promotion, price float64) float64 {
+	discount := promotion.DiscountValue
+	if promotion.DiscountType == entities.DiscountPercent {
+		discount = price * promotion.DiscountValue / 100
+		if promotion.MaxDiscount > 0 {
+			discount = math.Min(discount, promotion.MaxDiscount)
+		}
+	}
+	return entities.RoundMoney(math.Min(discount, price))
+}
+
+func validatePromotion(req *entitiesDtos.PromotionRequest) *entitiesDtos.ErrorResponse {
+	message := ""
+	switch {
+	case strings.TrimSpace(req.Code) == "":
+		message = "Promotion code is required"
+	case req.DiscountType != entities.DiscountPercent && req.DiscountType != entities.DiscountFixed:
+		message = fmt.Sprintf("Invalid discount type: %s", req.DiscountType)
+	case req.DiscountValue <= 0:
+		message = "Discount value must be positive"
+	case req.DiscountType == entities.DiscountPercent && req.DiscountValue > 100:
+		message = "Percentage discount cannot exceed 100"
+	case req.UsageLimit < 0 || req.PerVendorLimit < 0:
+		message = "Usage limits cannot be negative"
+	case !validPromoDate(req.StartDate) || !validPromoDate(req.EndDate):
+		message = "Invalid date format. Please use YYYY-MM-DD"
+	case req.StartDate != "" && req.EndDate != "" && req.EndDate < req.StartDate:
+		message = "End date must not be before start date"
+	case req.ValidFrom != nil && req.ValidUntil != nil && req.ValidUntil.Before(*req.ValidFrom):
+		message = "Validity window ends before it starts"
+	}
+
+	if message != "" {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+	return nil
+}
+
+func validPromoDate(date string) bool {
+	if date == "" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
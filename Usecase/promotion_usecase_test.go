@@ -0,0 +1,247 @@
+package Usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	entities "tln-backend/Entities"
+	"tln-backend/Interfaces"
+)
+
+// fakePromotionRepo knows one provider's codes and whether vendors have booked with it before. Other IPromotion
+// methods are not used by evaluate or ApplyPromotion and panic if called.
+type fakePromotionRepo struct {
+	Interfaces.IPromotion
+	promotions  map[string]*entities.Promotion
+	booked      map[string]bool
+	redemptions []*entities.Redemption
+}
+
+func (f *fakePromotionRepo) GetMarketProviderID(marketID string) (string, error) {
+	if marketID != "m1" && marketID != "m2" {
+		return "", fmt.Errorf("record not found")
+	}
+	return "p1", nil
+}
+
+func (f *fakePromotionRepo) GetPromotionByCode(providerID, code string) (*entities.Promotion, error) {
+	promotion, ok := f.promotions[code]
+	if !ok || promotion.ProviderID != providerID {
+		return nil, fmt.Errorf("record not found")
+	}
+	return promotion, nil
+}
+
+// ReserveRedemption applies the usage and per-vendor limits the way the database transaction does.
+func (f *fakePromotionRepo) ReserveRedemption(redemption *entities.Redemption) error {
+	for _, promotion := range f.promotions {
+		if promotion.ID != redemption.PromotionID {
+			continue
+		}
+		if promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit {
+			return fmt.Errorf("promotion has been fully redeemed")
+		}
+		used := 0
+		for _, r := range f.redemptions {
+			if r.PromotionID == promotion.ID && r.VendorID == redemption.VendorID && r.Status == entities.RedemptionApplied {
+				used++
+			}
+		}
+		if promotion.PerVendorLimit > 0 && used >= promotion.PerVendorLimit {
+			return fmt.Errorf("you have already used this promotion the maximum number of times")
+		}
+		f.redemptions = append(f.redemptions, redemption)
+		promotion.UsedCount++
+		return nil
+	}
+	return fmt.Errorf("promotion not found")
+}
+
+func (f *fakePromotionRepo) HasCompletedBooking(vendorID, providerID string) (bool, error) {
+	return f.booked[vendorID], nil
+}
+
+func TestPromotionMismatch(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier, later := now.Add(-time.Hour), now.Add(time.Hour)
+	slot := &entities.Slot{MarketID: "m1", Zone: "A", ZoneID: "zA", Category: entities.CategoryFood}
+	legacySlot := &entities.Slot{MarketID: "m1", Zone: "A", Category: entities.CategoryFood}
+
+	tests := []struct {
+		name      string
+		promotion entities.Promotion
+		slot      *entities.Slot
+		date      string
+		want      string
+	}{
+		{name: "open code", promotion: entities.Promotion{}},
+		{name: "disabled", promotion: entities.Promotion{Status: entities.PromoDisabled}, want: "it is no longer active"},
+		{name: "not valid yet", promotion: entities.Promotion{ValidFrom: &later}, want: "it is not valid yet"},
+		{name: "expired", promotion: entities.Promotion{ValidUntil: &earlier}, want: "it has expired"},
+		{name: "inside the validity window", promotion: entities.Promotion{ValidFrom: &earlier, ValidUntil: &later}},
+		{name: "uses left", promotion: entities.Promotion{UsageLimit: 10, UsedCount: 9}},
+		{name: "fully redeemed", promotion: entities.Promotion{UsageLimit: 10, UsedCount: 10}, want: "it has been fully redeemed"},
+		{name: "unlimited uses", promotion: entities.Promotion{UsedCount: 1000}},
+		{name: "same market", promotion: entities.Promotion{MarketID: "m1"}},
+		{name: "other market", promotion: entities.Promotion{MarketID: "m2"}, want: "it is for a different market"},
+		{name: "same zone ID", promotion: entities.Promotion{ZoneID: "zA", Zone: "A"}},
+		{name: "other zone ID", promotion: entities.Promotion{ZoneID: "zB", Zone: "B"}, want: "it is for a different zone"},
+		{name: "renamed zone still matches by ID", promotion: entities.Promotion{ZoneID: "zA", Zone: "Old name"}},
+		{name: "zone with the same name in another market", promotion: entities.Promotion{ZoneID: "zA2", Zone: "A"}, want: "it is for a different zone"},
+		{name: "legacy promotion by zone name", promotion: entities.Promotion{Zone: "A"}},
+		{name: "legacy promotion, other zone name", promotion: entities.Promotion{Zone: "B"}, want: "it is for a different zone"},
+		{name: "legacy slot by zone name", promotion: entities.Promotion{ZoneID: "zA", Zone: "A"}, slot: legacySlot},
+		{name: "legacy slot, other zone name", promotion: entities.Promotion{ZoneID: "zB", Zone: "B"}, slot: legacySlot, want: "it is for a different zone"},
+		{name: "same category", promotion: entities.Promotion{Category: entities.CategoryFood}},
+		{name: "other category", promotion: entities.Promotion{Category: entities.CategoryCrafts}, want: "it is for a different category"},
+		{name: "first day of the period", promotion: entities.Promotion{StartDate: "2024-06-10", EndDate: "2024-06-20"}, date: "2024-06-10"},
+		{name: "last day of the period", promotion: entities.Promotion{StartDate: "2024-06-10", EndDate: "2024-06-20"}, date: "2024-06-20"},
+		{name: "before the period", promotion: entities.Promotion{StartDate: "2024-06-10"}, date: "2024-06-09", want: "the booking date is before the promotion period"},
+		{name: "after the period", promotion: entities.Promotion{EndDate: "2024-06-20"}, date: "2024-06-21", want: "the booking date is after the promotion period"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := tt.promotion
+			if promotion.Status == "" {
+				promotion.Status = entities.PromoActive
+			}
+			target := tt.slot
+			if target == nil {
+				target = slot
+			}
+			date := tt.date
+			if date == "" {
+				date = "2024-06-15"
+			}
+			if got := promotionMismatch(&promotion, target, date, now); got != tt.want {
+				t.Errorf("promotionMismatch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion entities.Promotion
+		price     float64
+		want      float64
+	}{
+		{name: "percent", promotion: entities.Promotion{DiscountType: entities.DiscountPercent, DiscountValue: 10}, price: 450, want: 45},
+		{name: "percent rounded to satang", promotion: entities.Promotion{DiscountType: entities.DiscountPercent, DiscountValue: 15}, price: 333.33, want: 50},
+		{name: "percent under the cap", promotion: entities.Promotion{DiscountType: entities.DiscountPercent, DiscountValue: 10, MaxDiscount: 100}, price: 500, want: 50},
+		{name: "percent over the cap", promotion: entities.Promotion{DiscountType: entities.DiscountPercent, DiscountValue: 50, MaxDiscount: 100}, price: 500, want: 100},
+		{name: "fixed", promotion: entities.Promotion{DiscountType: entities.DiscountFixed, DiscountValue: 80}, price: 500, want: 80},
+		{name: "fixed ignores the cap", promotion: entities.Promotion{DiscountType: entities.DiscountFixed, DiscountValue: 80, MaxDiscount: 50}, price: 500, want: 80},
+		{name: "fixed never exceeds the price", promotion: entities.Promotion{DiscountType: entities.DiscountFixed, DiscountValue: 800}, price: 500, want: 500},
+		{name: "free stall", promotion: entities.Promotion{DiscountType: entities.DiscountPercent, DiscountValue: 100}, price: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promotionDiscount(&tt.promotion, tt.price); got != tt.want {
+				t.Errorf("promotionDiscount() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionEvaluate(t *testing.T) {
+	repo := &fakePromotionRepo{
+		promotions: map[string]*entities.Promotion{
+			"SAVE10": {ID: "save10", ProviderID: "p1", Code: "SAVE10", DiscountType: entities.DiscountPercent, DiscountValue: 10, Status: entities.PromoActive},
+			"WELCOME": {ID: "welcome", ProviderID: "p1", Code: "WELCOME", DiscountType: entities.DiscountFixed, DiscountValue: 100,
+				FirstBookingOnly: true, Status: entities.PromoActive},
+			"ZONEB": {ID: "zoneb", ProviderID: "p1", Code: "ZONEB", DiscountType: entities.DiscountFixed, DiscountValue: 50,
+				ZoneID: "zB", Zone: "B", Status: entities.PromoActive},
+		},
+		booked: map[string]bool{"regular": true},
+	}
+	uc := &PromotionUseCase{repo: repo}
+	slot := &entities.Slot{ID: "s1", MarketID: "m1", Zone: "A", ZoneID: "zA", Price: 400, Category: entities.CategoryFood}
+	unknownMarket := &entities.Slot{ID: "s2", MarketID: "gone", Price: 400}
+
+	tests := []struct {
+		name         string
+		code         string
+		slot         *entities.Slot
+		vendorID     string
+		wantCode     int
+		wantDiscount float64
+	}{
+		{name: "code entered in lower case", code: " save10 ", vendorID: "regular", wantDiscount: 40},
+		{name: "first booking", code: "WELCOME", vendorID: "newcomer", wantDiscount: 100},
+		{name: "first booking code for a returning vendor", code: "WELCOME", vendorID: "regular", wantCode: 422},
+		{name: "code for another zone", code: "ZONEB", vendorID: "newcomer", wantCode: 422},
+		{name: "unknown code", code: "NOPE", vendorID: "newcomer", wantCode: 404},
+		{name: "market not found", code: "SAVE10", slot: unknownMarket, vendorID: "newcomer", wantCode: 404},
+		{name: "not signed in as a vendor", code: "SAVE10", wantCode: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.slot
+			if target == nil {
+				target = slot
+			}
+			promotion, discount, errRes := uc.evaluate(tt.code, target, tt.vendorID, "2024-06-15")
+			if tt.wantCode != 0 {
+				if errRes == nil || errRes.Code != tt.wantCode {
+					t.Fatalf("evaluate() error = %v, want code %d", errRes, tt.wantCode)
+				}
+				return
+			}
+			if errRes != nil {
+				t.Fatalf("evaluate() error = %v", errRes)
+			}
+			if promotion == nil || discount != tt.wantDiscount {
+				t.Errorf("evaluate() = %v, %.2f, want a discount of %.2f", promotion, discount, tt.wantDiscount)
+			}
+		})
+	}
+}
+
+func TestPromotionApplyLimits(t *testing.T) {
+	slot := &entities.Slot{ID: "s1", MarketID: "m1", Zone: "A", ZoneID: "zA", Price: 400, Category: entities.CategoryFood}
+
+	tests := []struct {
+		name           string
+		usageLimit     int
+		perVendorLimit int
+		vendors        []string
+		want           []int // 0 for an applied code, otherwise the error code
+	}{
+		{name: "unlimited", vendors: []string{"v1", "v1", "v2"}, want: []int{0, 0, 0}},
+		{name: "usage limit", usageLimit: 2, vendors: []string{"v1", "v2", "v3"}, want: []int{0, 0, 422}},
+		{name: "per vendor limit", perVendorLimit: 1, vendors: []string{"v1", "v1", "v2"}, want: []int{0, 409, 0}},
+		{name: "both limits", usageLimit: 3, perVendorLimit: 2, vendors: []string{"v1", "v1", "v1", "v2", "v3"}, want: []int{0, 0, 409, 0, 422}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePromotionRepo{
+				promotions: map[string]*entities.Promotion{
+					"LIMITED": {ID: "limited", ProviderID: "p1", Code: "LIMITED", DiscountType: entities.DiscountFixed, DiscountValue: 50,
+						UsageLimit: tt.usageLimit, PerVendorLimit: tt.perVendorLimit, Status: entities.PromoActive},
+				},
+			}
+			uc := &PromotionUseCase{repo: repo}
+
+			for i, vendorID := range tt.vendors {
+				bookingID := fmt.Sprintf("b%d", i+1)
+				redemption, errRes := uc.ApplyPromotion("LIMITED", slot, vendorID, "2024-06-15", bookingID, "pay-"+bookingID)
+				got := 0
+				if errRes != nil {
+					got = errRes.Code
+				}
+				if got != tt.want[i] {
+					t.Fatalf("use %d by %s = %d (%v), want %d", i+1, vendorID, got, errRes, tt.want[i])
+				}
+				if errRes == nil && (redemption.Discount != 50 || redemption.BookingID != bookingID) {
+					t.Errorf("use %d redemption = %+v", i+1, redemption)
+				}
+			}
+		})
+	}
+}
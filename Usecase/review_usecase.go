@@ -0,0 +1,437 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"math"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+// maxReviewLength caps review comments and replies, in characters.
+const maxReviewLength = 1000
+
+type ReviewUseCase struct {
+	repo Interfaces.IReview
+}
+
+var _ contact.IRatingUseCase = (*ReviewUseCase)(nil)
+
+func NewReviewUseCase(repo Interfaces.IReview) *ReviewUseCase {
+	return &ReviewUseCase{repo: repo}
+}
+
+// ReviewMarket records a vendor's review of the market day it booked, once that day is over.
+func (uc *ReviewUseCase) ReviewMarket(vendorID, bookingID string, req *entitiesDtos.MarketReviewRequest) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	booking, err := uc.repo.GetBooking(bookingID)
+	if err != nil || booking.VendorID != vendorID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Booking not found",
+		}
+	}
+	if message := checkScores(map[string]int{"Rating": req.Rating}, map[string]int{"Foot traffic": req.FootTraffic}); message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	review, errRes := uc.newReview(entities.ReviewOfMarket, booking, vendorID, "vendor", req.Rating, req.Comment)
+	if errRes != nil {
+		return nil, errRes
+	}
+	review.FootTraffic = req.FootTraffic
+	return uc.create(review)
+}
+
+// ReviewVendor records a market's rating of a vendor that booked there, once the market day is over.
+func (uc *ReviewUseCase) ReviewVendor(providerID, authorID, role, bookingID string, req *entitiesDtos.VendorReviewRequest) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	booking, err := uc.repo.GetBooking(bookingID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Booking not found",
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, booking.MarketID, "reviews"); errRes != nil {
+		return nil, errRes
+	}
+	required := map[string]int{"Rating": req.Rating, "Punctuality": req.Punctuality, "Cleanliness": req.Cleanliness}
+	if message := checkScores(required, nil); message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	review, errRes := uc.newReview(entities.ReviewOfVendor, booking, authorID, role, req.Rating, req.Comment)
+	if errRes != nil {
+		return nil, errRes
+	}
+	review.Punctuality = req.Punctuality
+	review.Cleanliness = req.Cleanliness
+	return uc.create(review)
+}
+
+// Reply sets the provider's public answer to a review of its market, replacing any earlier one.
+func (uc *ReviewUseCase) Reply(providerID, repliedBy, reviewID string, req *entitiesDtos.ReviewReplyRequest) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	review, err := uc.repo.GetReview(reviewID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Review not found",
+		}
+	}
+	if review.Kind != entities.ReviewOfMarket {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Only reviews of a market can be replied to",
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, review.MarketID, "reviews"); errRes != nil {
+		return nil, errRes
+	}
+
+	reply := strings.TrimSpace(req.Reply)
+	switch {
+	case reply == "":
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Reply is required",
+		}
+	case utf8.RuneCountInString(reply) > maxReviewLength:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Reply can be at most %d characters", maxReviewLength),
+		}
+	}
+
+	now := time.Now()
+	review.Reply = reply
+	review.RepliedBy = repliedBy
+	review.RepliedAt = &now
+	if err := uc.repo.UpdateReview(review); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save reply: " + err.Error(),
+		}
+	}
+	return review, nil
+}
+
+// GetMarketReviews lists what vendors said about a market, with its scores.
+func (uc *ReviewUseCase) GetMarketReviews(marketID string) (*entitiesDtos.ReviewListResponse, *entitiesDtos.ErrorResponse) {
+	if _, err := uc.repo.GetMarket(marketID); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Market not found",
+		}
+	}
+	return uc.list(entities.ReviewOfMarket, marketID, "")
+}
+
+// GetVendorReviews lists what markets said about a vendor, with its scores.
+func (uc *ReviewUseCase) GetVendorReviews(vendorID string) (*entitiesDtos.ReviewListResponse, *entitiesDtos.ErrorResponse) {
+	return uc.list(entities.ReviewOfVendor, "", vendorID)
+}
+
+// MarketRating averages a market's visible reviews, or returns nil if they cannot be read.
+func (uc *ReviewUseCase) MarketRating(marketID string) *entities.RatingSummary {
+	return uc.rating(entities.ReviewOfMarket, marketID, "")
+}
+
+// VendorRating averages what markets said about a vendor, or returns nil if it cannot be read.
+func (uc *ReviewUseCase) VendorRating(vendorID string) *entities.RatingSummary {
+	return uc.rating(entities.ReviewOfVendor, "", vendorID)
+}
+
+// ReportReview flags a review as abusive for an admin to look at. Each user reports a review at most once.
+func (uc *ReviewUseCase) ReportReview(reporterID, role, reviewID string, req *entitiesDtos.ReviewReportRequest) (*entities.ReviewReport, *entitiesDtos.ErrorResponse) {
+	review, err := uc.repo.GetReview(reviewID)
+	if err != nil || review.Status != entities.ReviewVisible {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Review not found",
+		}
+	}
+	if review.AuthorID == reporterID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "You cannot report your own review",
+		}
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	switch {
+	case reason == "":
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Reason is required",
+		}
+	case utf8.RuneCountInString(reason) > 500:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Reason can be at most 500 characters",
+		}
+	}
+
+	exists, err := uc.repo.ReportExists(reviewID, reporterID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check reports: " + err.Error(),
+		}
+	}
+	if exists {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "You already reported this review",
+		}
+	}
+
+	report := &entities.ReviewReport{
+		ID:         uuid.New().String(),
+		ReviewID:   reviewID,
+		ReporterID: reporterID,
+		Role:       role,
+		Reason:     reason,
+		Status:     entities.ReportOpen,
+	}
+	if err := uc.repo.CreateReport(report); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to report review: " + err.Error(),
+		}
+	}
+	return report, nil
+}
+
+// GetReports lists reports for admins, open ones by default.
+func (uc *ReviewUseCase) GetReports(status string) ([]entities.ReviewReport, *entitiesDtos.ErrorResponse) {
+	filter := entities.ReportStatus(strings.ToLower(strings.TrimSpace(status)))
+	switch filter {
+	case "":
+		filter = entities.ReportOpen
+	case "all":
+		filter = ""
+	case entities.ReportOpen, entities.ReportUpheld, entities.ReportDismissed:
+	default:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Unknown report status %q", status),
+		}
+	}
+
+	reports, err := uc.repo.GetReports(filter)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve reports: " + err.Error(),
+		}
+	}
+	return reports, nil
+}
+
+// ResolveReport settles an open report by hiding the review or dismissing the complaint. Every other open
+// report of the same review is settled the same way.
+func (uc *ReviewUseCase) ResolveReport(adminID, reportID string, req *entitiesDtos.ReportResolveRequest) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	report, err := uc.repo.GetReport(reportID)
+	if err != nil || report.Review == nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Report not found",
+		}
+	}
+	if report.Status != entities.ReportOpen {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "This report is already " + string(report.Status),
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(req.Action)) {
+	case "hide":
+		return uc.moderate(adminID, report.Review, entities.ReviewHidden, req.Note)
+	case "dismiss":
+		return uc.moderate(adminID, report.Review, report.Review.Status, "")
+	}
+	return nil, &entitiesDtos.ErrorResponse{
+		Code:    400,
+		Message: "Action must be hide or dismiss",
+	}
+}
+
+// ModerateReview lets an admin hide a review or put it back up, settling its open reports to match.
+func (uc *ReviewUseCase) ModerateReview(adminID, reviewID string, req *entitiesDtos.ReviewModerateRequest) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	review, err := uc.repo.GetReview(reviewID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Review not found",
+		}
+	}
+	status := entities.ReviewStatus(strings.ToLower(strings.TrimSpace(string(req.Status))))
+	if status != entities.ReviewVisible && status != entities.ReviewHidden {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Status must be visible or hidden",
+		}
+	}
+	return uc.moderate(adminID, review, status, req.Note)
+}
+
+func (uc *ReviewUseCase) moderate(adminID string, review *entities.Review, status entities.ReviewStatus, note string) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > 500 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Note can be at most 500 characters",
+		}
+	}
+
+	outcome := entities.ReportDismissed
+	if status == entities.ReviewHidden {
+		outcome = entities.ReportUpheld
+		review.ModerationNote = note
+	} else if review.Status == entities.ReviewHidden {
+		review.ModerationNote = ""
+	}
+	review.Status = status
+	if err := uc.repo.ModerateReview(review, outcome, adminID); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to moderate review: " + err.Error(),
+		}
+	}
+	return review, nil
+}
+
+// newReview starts a review of the market day a booking was for. Only paid bookings whose day is over can be
+// reviewed, once from each side.
+func (uc *ReviewUseCase) newReview(kind entities.ReviewKind, booking *entities.Booking, authorID, role string, rating int, comment string) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	if booking.Status != entities.StatusCompleted {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Only completed bookings can be reviewed",
+		}
+	}
+	market, err := uc.repo.GetMarket(booking.MarketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Market not found",
+		}
+	}
+	if !booking.BookingDate.Before(marketToday(marketLocation(market))) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "A booking can be reviewed once its market day is over",
+		}
+	}
+
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > maxReviewLength {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Comment can be at most %d characters", maxReviewLength),
+		}
+	}
+
+	exists, err := uc.repo.ReviewExists(kind, booking.MarketID, booking.VendorID, booking.BookingDate)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check reviews: " + err.Error(),
+		}
+	}
+	if exists {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "This market day has already been reviewed",
+		}
+	}
+
+	return &entities.Review{
+		ID:         uuid.New().String(),
+		Kind:       kind,
+		MarketID:   booking.MarketID,
+		VendorID:   booking.VendorID,
+		MarketDate: booking.BookingDate,
+		BookingID:  booking.ID,
+		AuthorID:   authorID,
+		Role:       role,
+		Rating:     rating,
+		Comment:    comment,
+		Status:     entities.ReviewVisible,
+	}, nil
+}
+
+func (uc *ReviewUseCase) create(review *entities.Review) (*entities.Review, *entitiesDtos.ErrorResponse) {
+	if err := uc.repo.CreateReview(review); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save review: " + err.Error(),
+		}
+	}
+	return review, nil
+}
+
+func (uc *ReviewUseCase) list(kind entities.ReviewKind, marketID, vendorID string) (*entitiesDtos.ReviewListResponse, *entitiesDtos.ErrorResponse) {
+	reviews, err := uc.repo.GetReviews(kind, marketID, vendorID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve reviews: " + err.Error(),
+		}
+	}
+	summary, err := uc.repo.GetRatingSummary(kind, marketID, vendorID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve rating: " + err.Error(),
+		}
+	}
+	return &entitiesDtos.ReviewListResponse{
+		Summary: roundSummary(summary),
+		Reviews: reviews,
+	}, nil
+}
+
+func (uc *ReviewUseCase) rating(kind entities.ReviewKind, marketID, vendorID string) *entities.RatingSummary {
+	summary, err := uc.repo.GetRatingSummary(kind, marketID, vendorID)
+	if err != nil {
+		log.Printf("Warning: Error getting %s rating of %s%s: %v", kind, marketID, vendorID, err)
+		return nil
+	}
+	return roundSummary(summary)
+}
+
+// checkScores makes sure every required score, and every optional score that was given, is 1 to 5.
+func checkScores(required, optional map[string]int) string {
+	for name, score := range required {
+		if score < 1 || score > 5 {
+			return name + " must be 1 to 5"
+		}
+	}
+	for name, score := range optional {
+		if score != 0 && (score < 1 || score > 5) {
+			return name + " must be 1 to 5"
+		}
+	}
+	return ""
+}
+
+// roundSummary rounds averages to one decimal, as they are shown.
+func roundSummary(summary *entities.RatingSummary) *entities.RatingSummary {
+	for _, average := range []*float64{&summary.Average, &summary.FootTraffic, &summary.Punctuality, &summary.Cleanliness} {
+		*average = math.Round(*average*10) / 10
+	}
+	return summary
+}
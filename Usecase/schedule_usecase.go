@@ -0,0 +1,334 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+)
+
+type ScheduleUseCase struct {
+	repo    Interfaces.ISchedule
+	service *Services.ScheduleService
+}
+
+func NewScheduleUseCase(repo Interfaces.ISchedule, service *Services.ScheduleService) *ScheduleUseCase {
+	return &ScheduleUseCase{
+		repo:    repo,
+		service: service,
+	}
+}
+
+// SaveSchedule creates or replaces a market's operating schedule and generates its slots straight away.
+func (uc *ScheduleUseCase) SaveSchedule(providerID, marketID string, req *entitiesDtos.ScheduleRequest) (*entitiesDtos.ScheduleResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "schedule"); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := validateSchedule(req); errRes != nil {
+		return nil, errRes
+	}
+
+	templateMarketID, err := uc.repo.GetTemplateMarketID(req.TemplateID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get template: " + err.Error(),
+		}
+	}
+	if templateMarketID != marketID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Template belongs to a different market",
+		}
+	}
+
+	schedule, err := uc.repo.GetScheduleByMarket(marketID)
+	if err != nil {
+		schedule = &entities.MarketSchedule{
+			ID:       uuid.New().String(),
+			MarketID: marketID,
+		}
+	}
+
+	interval := req.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+	schedule.TemplateID = req.TemplateID
+	schedule.Frequency = req.Frequency
+	schedule.Interval = interval
+	schedule.Weekdays = req.Weekdays
+	schedule.MonthWeeks = req.MonthWeeks
+	schedule.StartDate = req.StartDate
+	schedule.EndDate = req.EndDate
+	schedule.WeeksAhead = req.WeeksAhead
+	schedule.SkipHolidays = req.SkipHolidays == nil || *req.SkipHolidays
+	schedule.Active = req.Active == nil || *req.Active
+
+	if err := uc.repo.SaveSchedule(schedule); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save schedule: " + err.Error(),
+		}
+	}
+
+	if schedule.Active {
+		if _, err := uc.service.Generate(schedule); err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Schedule saved but slot generation failed: " + err.Error(),
+			}
+		}
+	}
+
+	return uc.scheduleResponse(schedule)
+}
+
+func (uc *ScheduleUseCase) GetSchedule(marketID string) (*entitiesDtos.ScheduleResponse, *entitiesDtos.ErrorResponse) {
+	schedule, err := uc.repo.GetScheduleByMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get schedule: " + err.Error(),
+		}
+	}
+
+	return uc.scheduleResponse(schedule)
+}
+
+// AddException closes or opens one date. Adding a second exception for the same date replaces the first.
+func (uc *ScheduleUseCase) AddException(providerID, marketID string, req *entitiesDtos.ScheduleExceptionRequest) (*entitiesDtos.ScheduleResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "schedule"); errRes != nil {
+		return nil, errRes
+	}
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Invalid date format. Please use YYYY-MM-DD",
+		}
+	}
+	if req.Kind != entities.ExceptionClosed && req.Kind != entities.ExceptionOpen {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Invalid exception kind: %s", req.Kind),
+		}
+	}
+
+	schedule, err := uc.repo.GetScheduleByMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get schedule: " + err.Error(),
+		}
+	}
+
+	exception := &entities.ScheduleException{
+		ID:         uuid.New().String(),
+		ScheduleID: schedule.ID,
+		Date:       req.Date,
+		Kind:       req.Kind,
+		Reason:     req.Reason,
+	}
+	for _, existing := range schedule.Exceptions {
+		if existing.Date == req.Date {
+			exception.ID, exception.CreatedAt = existing.ID, existing.CreatedAt
+		}
+	}
+
+	if err := uc.repo.SaveException(exception); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save exception: " + err.Error(),
+		}
+	}
+
+	return uc.regenerate(marketID)
+}
+
+func (uc *ScheduleUseCase) RemoveException(providerID, exceptionID string) (*entitiesDtos.ScheduleResponse, *entitiesDtos.ErrorResponse) {
+	exception, err := uc.repo.GetException(exceptionID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get exception: " + err.Error(),
+		}
+	}
+
+	marketID, err := uc.repo.GetScheduleMarketID(exception.ScheduleID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get schedule: " + err.Error(),
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "schedule"); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.DeleteException(exception.ID); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete exception: " + err.Error(),
+		}
+	}
+
+	return uc.regenerate(marketID)
+}
+
+// Generate runs the slot generation job for one market now instead of waiting for the nightly run.
+func (uc *ScheduleUseCase) Generate(providerID, marketID string) (*entitiesDtos.TemplateApplyResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "schedule"); errRes != nil {
+		return nil, errRes
+	}
+
+	schedule, err := uc.repo.GetScheduleByMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get schedule: " + err.Error(),
+		}
+	}
+	if !schedule.Active {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Schedule is not active",
+		}
+	}
+
+	result, err := uc.service.Generate(schedule)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to generate slots: " + err.Error(),
+		}
+	}
+
+	return result, nil
+}
+
+func (uc *ScheduleUseCase) SaveHoliday(req *entitiesDtos.HolidayRequest) (*entities.Holiday, *entitiesDtos.ErrorResponse) {
+	if _, err := time.Parse("2006-01-02", req.Date); err != nil || req.Name == "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "A holiday needs a name and a date in YYYY-MM-DD format",
+		}
+	}
+
+	holiday := &entities.Holiday{Date: req.Date, Name: req.Name}
+	if err := uc.repo.SaveHoliday(holiday); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save holiday: " + err.Error(),
+		}
+	}
+
+	return holiday, nil
+}
+
+func (uc *ScheduleUseCase) DeleteHoliday(date string) *entitiesDtos.ErrorResponse {
+	if err := uc.repo.DeleteHoliday(date); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete holiday: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (uc *ScheduleUseCase) GetHolidays(from, to string) ([]entities.Holiday, *entitiesDtos.ErrorResponse) {
+	if from == "" {
+		from = uc.service.Today().Format("2006-01-02")
+	}
+	if to == "" {
+		to = "9999-12-31"
+	}
+
+	holidays, err := uc.repo.GetHolidays(from, to)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get holidays: " + err.Error(),
+		}
+	}
+
+	return holidays, nil
+}
+
+// regenerate reloads the schedule so new exceptions are seen, then regenerates if it is active.
+func (uc *ScheduleUseCase) regenerate(marketID string) (*entitiesDtos.ScheduleResponse, *entitiesDtos.ErrorResponse) {
+	schedule, err := uc.repo.GetScheduleByMarket(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to reload schedule: " + err.Error(),
+		}
+	}
+
+	if schedule.Active {
+		if _, err := uc.service.Generate(schedule); err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Exception saved but slot generation failed: " + err.Error(),
+			}
+		}
+	}
+
+	return uc.scheduleResponse(schedule)
+}
+
+func (uc *ScheduleUseCase) scheduleResponse(schedule *entities.MarketSchedule) (*entitiesDtos.ScheduleResponse, *entitiesDtos.ErrorResponse) {
+	from := uc.service.Today()
+	to := from.AddDate(0, 0, 7*uc.service.WeeksAhead(schedule)-1)
+	open, _, err := uc.service.ScheduleDates(schedule, from, to)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to work out upcoming dates: " + err.Error(),
+		}
+	}
+
+	return &entitiesDtos.ScheduleResponse{
+		Schedule:      schedule,
+		UpcomingDates: open,
+	}, nil
+}
+
+func validateSchedule(req *entitiesDtos.ScheduleRequest) *entitiesDtos.ErrorResponse {
+	message := ""
+	switch {
+	case req.Frequency != entities.FrequencyWeekly && req.Frequency != entities.FrequencyMonthly:
+		message = fmt.Sprintf("Invalid frequency: %s", req.Frequency)
+	case len(req.Weekdays) == 0:
+		message = "At least one weekday is required"
+	case req.Frequency == entities.FrequencyMonthly && len(req.MonthWeeks) == 0:
+		message = "Monthly schedules need month_weeks, e.g. [1] for the first such weekday"
+	case !validPromoDate(req.StartDate) || req.StartDate == "" || !validPromoDate(req.EndDate):
+		message = "Invalid date format. Please use YYYY-MM-DD"
+	case req.EndDate != "" && req.EndDate < req.StartDate:
+		message = "End date must not be before start date"
+	case req.WeeksAhead < 0 || req.WeeksAhead > 52:
+		message = "weeks_ahead must be between 0 and 52"
+	}
+	for _, day := range req.Weekdays {
+		if day < 0 || day > 6 {
+			message = fmt.Sprintf("Invalid weekday %d, use 0 (Sunday) to 6 (Saturday)", day)
+		}
+	}
+	for _, week := range req.MonthWeeks {
+		if week != -1 && (week < 1 || week > 5) {
+			message = fmt.Sprintf("Invalid month week %d, use 1-5 or -1 for the last", week)
+		}
+	}
+
+	if message != "" {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+	return nil
+}
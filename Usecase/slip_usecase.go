@@ -0,0 +1,308 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+type SlipUseCase struct {
+	repo           Interfaces.ISlip
+	bookingRepo    contact.IBooking
+	slipService    *Services.SlipService
+	bookingService *Services.BookingService
+	media          contact.IMediaStore
+}
+
+func NewSlipUseCase(repo Interfaces.ISlip, bookingRepo contact.IBooking, slipService *Services.SlipService, bookingService *Services.BookingService, media contact.IMediaStore) *SlipUseCase {
+	return &SlipUseCase{
+		repo:           repo,
+		bookingRepo:    bookingRepo,
+		slipService:    slipService,
+		bookingService: bookingService,
+		media:          media,
+	}
+}
+
+// UploadSlip checks a transfer slip against a pending booking. The caller is the booking's vendor, or the market's
+// provider (providerID) uploading on the vendor's behalf. declaredAmount is what the vendor reads off the slip
+// and is required when the slip QR does not carry the amount itself. Either way the amount must match what is due.
+func (uc *SlipUseCase) UploadSlip(bookingID, vendorID, providerID string, image []byte, declaredAmount float64) (*entities.BankSlip, *entitiesDtos.ErrorResponse) {
+	booking, errRes := uc.callerBooking(bookingID, vendorID, providerID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if booking.Status != entities.StatusPending || booking.Payment == nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Booking is %s and cannot take a slip", booking.Status),
+		}
+	}
+
+	transactionID, err := uc.repo.GetPendingTransactionID(booking.Payment.ID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Booking has no payment awaiting a slip: " + err.Error(),
+		}
+	}
+
+	qr, err := uc.slipService.DecodeSlip(image)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    422,
+			Message: "Failed to read slip: " + err.Error(),
+		}
+	}
+
+	existing, err := uc.repo.GetSlipByTransRef(qr.TransRef)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check slip: " + err.Error(),
+		}
+	}
+	if existing != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "This slip has already been submitted",
+		}
+	}
+
+	amountDue := entities.RoundMoney(booking.Payment.Price - booking.Payment.CreditUsed)
+	amount, source := entities.RoundMoney(declaredAmount), entities.SlipAmountDeclared
+	if qr.HasAmount {
+		amount, source = qr.Amount, entities.SlipAmountQR
+	}
+	if amount <= 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    422,
+			Message: "This slip does not show the amount; enter the amount transferred",
+		}
+	}
+	if math.Abs(amount-amountDue) >= 0.01 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    422,
+			Message: fmt.Sprintf("Slip amount %.2f does not match the amount due %.2f", amount, amountDue),
+		}
+	}
+
+	slipID := uuid.New().String()
+	reviewBy := time.Now().Add(slipReviewWindow())
+	media, errRes := uc.media.StoreImage(entities.MediaBankSlip, slipID, image)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	slip := &entities.BankSlip{
+		ID:            slipID,
+		BookingID:     booking.ID,
+		PaymentID:     booking.Payment.ID,
+		TransactionID: transactionID,
+		VendorID:      booking.VendorID,
+		TransRef:      qr.TransRef,
+		BankCode:      qr.BankCode,
+		BankName:      qr.BankName,
+		Amount:        amount,
+		AmountSource:  source,
+		ImageID:       media.ID,
+		ImageURL:      media.URL,
+		ThumbnailURL:  media.ThumbnailURL,
+		Status:        entities.SlipPendingReview,
+		ReviewBy:      &reviewBy,
+	}
+
+	if err := uc.repo.CreateSlip(slip); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Failed to save slip: " + err.Error(),
+		}
+	}
+
+	// The money has been sent, so the booking waits for the review instead of the payment window. If nobody
+	// reviews the slip in time the booking expires as usual and the stall goes back on sale.
+	uc.bookingService.RemoveScheduled(booking.ID)
+	uc.bookingService.ScheduleBookingCancellation(transactionID, booking.ID, booking.SlotID, booking.VendorID, reviewBy)
+
+	if source == entities.SlipAmountQR && os.Getenv("SLIP_AUTO_APPROVE") == "true" {
+		if errRes := uc.approve(slip, booking, "", ""); errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	return slip, nil
+}
+
+// ReviewSlip lets the market's provider approve or reject a slip from the review queue. The reviewer is the
+// provider or the staff member acting for it.
+func (uc *SlipUseCase) ReviewSlip(providerID, reviewerID, slipID string, req *entitiesDtos.ReviewSlipRequest) (*entities.BankSlip, *entitiesDtos.ErrorResponse) {
+	slip, err := uc.repo.GetSlip(slipID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get slip: " + err.Error(),
+		}
+	}
+	if slip.Status != entities.SlipPendingReview {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Slip is already %s", slip.Status),
+		}
+	}
+
+	booking, err := uc.bookingRepo.GetBooking(slip.BookingID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get booking: " + err.Error(),
+		}
+	}
+
+	ownerID, err := uc.repo.GetMarketProviderID(booking.MarketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to resolve market provider: " + err.Error(),
+		}
+	}
+	if ownerID != providerID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to review this slip",
+		}
+	}
+
+	if req.Approve {
+		if errRes := uc.approve(slip, booking, reviewerID, req.Note); errRes != nil {
+			return nil, errRes
+		}
+		return slip, nil
+	}
+
+	now := time.Now()
+	if err := uc.repo.UpdateSlipReview(slip.ID, entities.SlipRejected, reviewerID, req.Note, now); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to reject slip: " + err.Error(),
+		}
+	}
+
+	// Give the vendor a fresh payment window to send another slip or pay by QR
+	if booking.Status == entities.StatusPending {
+		uc.bookingService.RemoveScheduled(booking.ID)
+		uc.bookingService.ScheduleBookingCancellation(slip.TransactionID, booking.ID, booking.SlotID, booking.VendorID, now.Add(30*time.Minute))
+	}
+
+	slip.Status, slip.ReviewedBy, slip.ReviewNote, slip.ReviewedAt = entities.SlipRejected, reviewerID, req.Note, &now
+	return slip, nil
+}
+
+// GetReviewQueue lists the provider's slips awaiting review, in one market when marketID is set.
+func (uc *SlipUseCase) GetReviewQueue(providerID, marketID string) ([]entities.BankSlip, *entitiesDtos.ErrorResponse) {
+	slips, err := uc.repo.GetSlipsByProvider(providerID, marketID, entities.SlipPendingReview)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get slips: " + err.Error(),
+		}
+	}
+
+	return slips, nil
+}
+
+// GetBookingSlips lists a booking's slips for its vendor or the market's provider.
+func (uc *SlipUseCase) GetBookingSlips(bookingID, vendorID, providerID string) ([]entities.BankSlip, *entitiesDtos.ErrorResponse) {
+	if _, errRes := uc.callerBooking(bookingID, vendorID, providerID); errRes != nil {
+		return nil, errRes
+	}
+
+	slips, err := uc.repo.GetSlipsByBooking(bookingID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get slips: " + err.Error(),
+		}
+	}
+
+	return slips, nil
+}
+
+// callerBooking gets a booking for its vendor, or for the provider of its market when vendorID is empty.
+func (uc *SlipUseCase) callerBooking(bookingID, vendorID, providerID string) (*entities.Booking, *entitiesDtos.ErrorResponse) {
+	booking, err := uc.bookingRepo.GetBooking(bookingID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get booking: " + err.Error(),
+		}
+	}
+
+	if vendorID != "" {
+		if booking.VendorID != vendorID {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    403,
+				Message: "You can only see slips for your own bookings",
+			}
+		}
+		return booking, nil
+	}
+
+	ownerID, err := uc.repo.GetMarketProviderID(booking.MarketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to resolve market provider: " + err.Error(),
+		}
+	}
+	if providerID == "" || ownerID != providerID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You are not authorized to see slips for this booking",
+		}
+	}
+	return booking, nil
+}
+
+// approve confirms the booking the slip paid for. reviewerID is empty for automatic approval.
+func (uc *SlipUseCase) approve(slip *entities.BankSlip, booking *entities.Booking, reviewerID, note string) *entitiesDtos.ErrorResponse {
+	if booking.Status != entities.StatusPending {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Booking is %s and can no longer be paid", booking.Status),
+		}
+	}
+
+	if err := uc.bookingService.CompleteBooking(slip.TransactionID, slip.PaymentID, booking.ID, booking.SlotID, booking.VendorID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to confirm booking: " + err.Error(),
+		}
+	}
+
+	now := time.Now()
+	if err := uc.repo.UpdateSlipReview(slip.ID, entities.SlipApproved, reviewerID, note, now); err != nil {
+		log.Printf("Warning: booking %s confirmed but slip %s not marked approved: %v", booking.ID, slip.ID, err)
+	}
+
+	slip.Status, slip.ReviewedBy, slip.ReviewNote, slip.ReviewedAt = entities.SlipApproved, reviewerID, note, &now
+	return nil
+}
+
+// slipReviewWindow reads SLIP_REVIEW_HOURS, how long a provider has to review a slip before the booking it pays
+// for expires. It defaults to 24 hours.
+func slipReviewWindow() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("SLIP_REVIEW_HOURS"))
+	if err != nil || hours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(hours) * time.Hour
+}
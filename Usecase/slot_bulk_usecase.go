@@ -0,0 +1,283 @@
+package Usecase
+
+import (
+	"fmt"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+// bulkEditMaxDays caps how many market days one bulk edit may span.
+const bulkEditMaxDays = 366
+
+// BulkEditSlots applies the same change to every selected slot of a market. Booked slots, and slots the change
+// would leave as they are, are skipped. With dryRun the result is only previewed; otherwise every change is
+// saved together or not at all.
+func (su *SlotUseCase) BulkEditSlots(providerID, marketID string, req *entitiesDtos.BulkSlotEditRequest, dryRun bool) (*entitiesDtos.BulkSlotEditResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(su.repo, providerID, marketID, "slots"); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := validateBulkEdit(req); errRes != nil {
+		return nil, errRes
+	}
+
+	slots, err := su.repo.SelectSlots(marketID, &req.Selector)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to select slots: " + err.Error(),
+		}
+	}
+	slotIDs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		slotIDs = append(slotIDs, slot.ID)
+	}
+	bookings, err := su.repo.GetActiveBookingsBySlotIDs(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get the slots' bookings: " + err.Error(),
+		}
+	}
+	booked := make(map[string]bool, len(bookings))
+	for _, booking := range bookings {
+		booked[booking.SlotID] = true
+	}
+
+	response := &entitiesDtos.BulkSlotEditResponse{
+		Matched: len(slots),
+		Changed: make([]entitiesDtos.BulkSlotChange, 0, len(slots)),
+		Skipped: make([]entitiesDtos.BulkSlotSkip, 0),
+	}
+	rules, errRes := su.zoneRules(marketID, req.Changes.Category != nil)
+	if errRes != nil {
+		return nil, errRes
+	}
+	changed := make([]*entities.Slot, 0, len(slots))
+	var reshapedBefore, reshaped []*entities.Slot
+	for _, slot := range slots {
+		if booked[slot.ID] || slot.Status == entities.StatusBooked {
+			response.Skipped = append(response.Skipped, bulkSkip(slot, "booked"))
+			continue
+		}
+
+		before := entitiesDtos.SlotState{Price: slot.Price, Category: slot.Category, Status: slot.Status}
+		after, errRes := applySlotChanges(slot, before, &req.Changes)
+		if errRes != nil {
+			return nil, errRes
+		}
+		if after == before {
+			response.Skipped = append(response.Skipped, bulkSkip(slot, "unchanged"))
+			continue
+		}
+		if after.Category != before.Category {
+			if errRes := rules.recategorise(slot, after.Category); errRes != nil {
+				skip := bulkSkip(slot, "zone_rules")
+				skip.Message = errRes.Message
+				response.Skipped = append(response.Skipped, skip)
+				continue
+			}
+		}
+
+		response.Changed = append(response.Changed, entitiesDtos.BulkSlotChange{
+			SlotID: slot.ID,
+			Name:   slot.Name,
+			Zone:   slot.Zone,
+			Date:   slotDate(slot),
+			Before: before,
+			After:  after,
+		})
+		if after.Price != before.Price || after.Category != before.Category {
+			previous := *slot
+			reshapedBefore = append(reshapedBefore, &previous)
+			reshaped = append(reshaped, slot)
+		}
+		slot.Price, slot.Category, slot.Status = after.Price, after.Category, after.Status
+		changed = append(changed, slot)
+	}
+
+	if dryRun || len(changed) == 0 {
+		return response, nil
+	}
+
+	if err := su.repo.BulkUpdateSlots(changed); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Failed to apply the bulk edit, nothing was changed: " + err.Error(),
+		}
+	}
+	response.Applied = true
+
+	// Price and category are part of the layout, so they are versioned like any other layout change
+	if len(reshaped) > 0 {
+		note := fmt.Sprintf("Bulk edit of %d slots", len(reshaped))
+		if errRes := su.versions.RecordSlotChanges(providerID, marketID, note, reshapedBefore, reshaped); errRes != nil {
+			return nil, errRes
+		}
+	}
+	return response, nil
+}
+
+// bulkZoneRules keeps a running count of each zone's stalls per day and category while a bulk edit moves
+// stalls between categories, so the edit respects the zones' restrictions and caps like a layout does.
+type bulkZoneRules struct {
+	su      *SlotUseCase
+	zones   *layoutZones
+	counted map[string]bool
+}
+
+// zoneRules loads the market's zones when the edit changes categories; otherwise there is nothing to check.
+func (su *SlotUseCase) zoneRules(marketID string, recategorising bool) (*bulkZoneRules, *entitiesDtos.ErrorResponse) {
+	if !recategorising {
+		return nil, nil
+	}
+	zones, err := su.repo.GetZones(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get zones: " + err.Error(),
+		}
+	}
+	return &bulkZoneRules{su: su, zones: newLayoutZones(zones), counted: make(map[string]bool)}, nil
+}
+
+// recategorise moves a slot's place in its zone's count to a new category, or says why the zone refuses it.
+func (r *bulkZoneRules) recategorise(slot *entities.Slot, category entities.Category) *entitiesDtos.ErrorResponse {
+	zone, ok := r.zones.byID[slot.ZoneID]
+	if !ok {
+		return nil
+	}
+	date := slotDate(slot)
+	if errRes := r.countDay(slot.MarketID, date); errRes != nil {
+		return errRes
+	}
+
+	scope := entities.LayoutScope(date, zone.Name)
+	r.zones.placed[scope][slot.Category]--
+	if errRes := r.zones.admit(zone, scope, slot.Name, category); errRes != nil {
+		r.zones.placed[scope][category]--
+		r.zones.placed[scope][slot.Category]++
+		return errRes
+	}
+	return nil
+}
+
+// countDay counts the stalls every zone already has on a date, the first time the date comes up.
+func (r *bulkZoneRules) countDay(marketID, date string) *entitiesDtos.ErrorResponse {
+	if r.counted[date] {
+		return nil
+	}
+	daySlots, err := r.su.repo.GetSlotsByDate(marketID, date)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get the day's slots: " + err.Error(),
+		}
+	}
+	r.counted[date] = true
+	for _, zone := range r.zones.byID {
+		if scope := entities.LayoutScope(date, zone.Name); r.zones.placed[scope] == nil {
+			r.zones.placed[scope] = make(map[entities.Category]int)
+		}
+	}
+	for _, slot := range daySlots {
+		if zone, ok := r.zones.byID[slot.ZoneID]; ok {
+			r.zones.placed[entities.LayoutScope(date, zone.Name)][slot.Category]++
+		}
+	}
+	return nil
+}
+
+func applySlotChanges(slot *entities.Slot, before entitiesDtos.SlotState, changes *entitiesDtos.SlotChanges) (entitiesDtos.SlotState, *entitiesDtos.ErrorResponse) {
+	after := before
+	switch {
+	case changes.Price != nil:
+		after.Price = *changes.Price
+	case changes.PricePercent != nil:
+		after.Price = before.Price * (1 + *changes.PricePercent/100)
+	case changes.PriceAmount != nil:
+		after.Price = before.Price + *changes.PriceAmount
+	}
+	after.Price = entities.RoundMoney(after.Price)
+	if after.Price < 0 {
+		return after, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("The change would make the price of slot %s on %s negative", slot.Name, slotDate(slot)),
+		}
+	}
+
+	if changes.Category != nil {
+		after.Category = *changes.Category
+	}
+	if changes.Status != nil {
+		after.Status = *changes.Status
+	}
+	return after, nil
+}
+
+func validateBulkEdit(req *entitiesDtos.BulkSlotEditRequest) *entitiesDtos.ErrorResponse {
+	bad := func(message string) *entitiesDtos.ErrorResponse {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	from, fromErr := time.Parse("2006-01-02", req.Selector.DateFrom)
+	to, toErr := time.Parse("2006-01-02", req.Selector.DateTo)
+	switch {
+	case fromErr != nil || toErr != nil:
+		return bad("Selector date_from and date_to are required. Please use YYYY-MM-DD")
+	case to.Before(from):
+		return bad("Selector date_to must not be before date_from")
+	case to.Sub(from) > bulkEditMaxDays*24*time.Hour:
+		return bad(fmt.Sprintf("A bulk edit can span at most %d days", bulkEditMaxDays))
+	}
+	if req.Selector.Category != "" {
+		category, err := parseCategory(string(req.Selector.Category))
+		if err != nil {
+			return bad("Invalid selector category: " + err.Error())
+		}
+		req.Selector.Category = category
+	}
+
+	changes := &req.Changes
+	prices := 0
+	for _, price := range []*float64{changes.Price, changes.PricePercent, changes.PriceAmount} {
+		if price != nil {
+			prices++
+		}
+	}
+	switch {
+	case prices == 0 && changes.Category == nil && changes.Status == nil:
+		return bad("The bulk edit changes nothing")
+	case prices > 1:
+		return bad("Give only one of price, price_percent and price_amount")
+	case changes.Price != nil && *changes.Price < 0:
+		return bad("Price cannot be negative")
+	case changes.PricePercent != nil && *changes.PricePercent <= -100:
+		return bad("price_percent must be above -100")
+	}
+	if changes.Category != nil {
+		category, err := parseCategory(string(*changes.Category))
+		if err != nil {
+			return bad("Invalid category: " + err.Error())
+		}
+		changes.Category = &category
+	}
+	if changes.Status != nil && *changes.Status != entities.StatusAvailable && *changes.Status != entities.StatusMaintenance {
+		return bad("A bulk edit can only set the status to available or maintenance")
+	}
+
+	return nil
+}
+
+func bulkSkip(slot *entities.Slot, reason string) entitiesDtos.BulkSlotSkip {
+	return entitiesDtos.BulkSlotSkip{
+		SlotID: slot.ID,
+		Name:   slot.Name,
+		Zone:   slot.Zone,
+		Date:   slotDate(slot),
+		Reason: reason,
+	}
+}
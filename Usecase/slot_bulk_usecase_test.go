@@ -0,0 +1,200 @@
+package Usecase
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+)
+
+// fakeBulkSlotRepo holds market m1's zones and slots for bulk edits.
+type fakeBulkSlotRepo struct {
+	Interfaces.ISlot
+	zones    []entities.Zone
+	slots    []*entities.Slot
+	bookings []entities.Booking
+	saved    []*entities.Slot
+}
+
+func (f *fakeBulkSlotRepo) GetMarketProviderID(marketID string) (string, error) {
+	if marketID != "m1" {
+		return "", fmt.Errorf("market not found")
+	}
+	return "p1", nil
+}
+
+func (f *fakeBulkSlotRepo) SelectSlots(marketID string, selector *entitiesDtos.SlotSelector) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	for _, slot := range f.slots {
+		if (selector.ZoneID == "" || slot.ZoneID == selector.ZoneID) && (selector.Category == "" || slot.Category == selector.Category) {
+			copied := *slot
+			slots = append(slots, &copied)
+		}
+	}
+	return slots, nil
+}
+
+func (f *fakeBulkSlotRepo) GetSlotsByDate(marketID, date string) ([]*entities.Slot, error) {
+	var slots []*entities.Slot
+	for _, slot := range f.slots {
+		if slot.Date == date {
+			copied := *slot
+			slots = append(slots, &copied)
+		}
+	}
+	return slots, nil
+}
+
+func (f *fakeBulkSlotRepo) GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error) {
+	return f.bookings, nil
+}
+
+func (f *fakeBulkSlotRepo) GetZones(marketID string) ([]entities.Zone, error) {
+	return append([]entities.Zone{}, f.zones...), nil
+}
+
+func (f *fakeBulkSlotRepo) BulkUpdateSlots(slots []*entities.Slot) error {
+	f.saved = slots
+	return nil
+}
+
+// fakeVersionRecorder notes the layout changes it is asked to record.
+type fakeVersionRecorder struct {
+	recorded [][]*entities.Slot
+}
+
+func (f *fakeVersionRecorder) RecordSlotChanges(createdBy, marketID, note string, before, after []*entities.Slot) *entitiesDtos.ErrorResponse {
+	f.recorded = append(f.recorded, after)
+	return nil
+}
+
+func TestBulkEditZoneRules(t *testing.T) {
+	stall := func(id, zoneID string, category entities.Category) *entities.Slot {
+		return &entities.Slot{ID: id, Name: id, MarketID: "m1", ZoneID: zoneID, Zone: zoneID, Category: category, Price: 100,
+			Status: entities.StatusAvailable, Date: "2024-06-01"}
+	}
+	zones := []entities.Zone{
+		{ID: "food-court", Name: "Food court", AllowedCategories: []entities.Category{entities.CategoryFood, entities.CategoryProduce}},
+		{ID: "hall", Name: "Hall", CategoryCaps: map[entities.Category]int{entities.CategoryFood: 2}},
+	}
+	food, clothes := entities.CategoryFood, entities.CategoryClothes
+	price := 120.0
+
+	tests := []struct {
+		name        string
+		slots       []*entities.Slot
+		bookings    []entities.Booking
+		selector    entitiesDtos.SlotSelector
+		changes     entitiesDtos.SlotChanges
+		wantChanged []string
+		wantSkipped map[string]string
+		wantVersion bool
+	}{
+		{
+			name:        "category the zone does not take",
+			slots:       []*entities.Slot{stall("f1", "food-court", entities.CategoryProduce)},
+			changes:     entitiesDtos.SlotChanges{Category: &clothes},
+			wantChanged: []string{},
+			wantSkipped: map[string]string{"f1": "zone_rules"},
+		},
+		{
+			name:        "category the zone takes",
+			slots:       []*entities.Slot{stall("f1", "food-court", entities.CategoryProduce)},
+			changes:     entitiesDtos.SlotChanges{Category: &food},
+			wantChanged: []string{"f1"},
+			wantSkipped: map[string]string{},
+			wantVersion: true,
+		},
+		{
+			name: "cap fills part way through",
+			slots: []*entities.Slot{stall("h0", "hall", entities.CategoryFood), stall("h1", "hall", entities.CategoryCrafts),
+				stall("h2", "hall", entities.CategoryCrafts)},
+			selector:    entitiesDtos.SlotSelector{Category: entities.CategoryCrafts},
+			changes:     entitiesDtos.SlotChanges{Category: &food},
+			wantChanged: []string{"h1"},
+			wantSkipped: map[string]string{"h2": "zone_rules"},
+			wantVersion: true,
+		},
+		{
+			name: "a booked stall keeps its place in the count",
+			slots: []*entities.Slot{stall("h0", "hall", entities.CategoryFood), stall("h1", "hall", entities.CategoryFood),
+				stall("h2", "hall", entities.CategoryCrafts)},
+			bookings:    []entities.Booking{{ID: "b1", SlotID: "h0"}},
+			changes:     entitiesDtos.SlotChanges{Category: &food},
+			wantChanged: []string{},
+			wantSkipped: map[string]string{"h0": "booked", "h1": "unchanged", "h2": "zone_rules"},
+		},
+		{
+			name:        "stall outside any zone",
+			slots:       []*entities.Slot{stall("x1", "", entities.CategoryCrafts)},
+			changes:     entitiesDtos.SlotChanges{Category: &food},
+			wantChanged: []string{"x1"},
+			wantSkipped: map[string]string{},
+			wantVersion: true,
+		},
+		{
+			name:        "status only is not a layout change",
+			slots:       []*entities.Slot{stall("f1", "food-court", entities.CategoryFood)},
+			changes:     entitiesDtos.SlotChanges{Status: func() *entities.SlotStatus { s := entities.StatusMaintenance; return &s }()},
+			wantChanged: []string{"f1"},
+			wantSkipped: map[string]string{},
+		},
+		{
+			name:        "price change is versioned",
+			slots:       []*entities.Slot{stall("f1", "food-court", entities.CategoryFood)},
+			changes:     entitiesDtos.SlotChanges{Price: &price},
+			wantChanged: []string{"f1"},
+			wantSkipped: map[string]string{},
+			wantVersion: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, dryRun := range []bool{true, false} {
+				repo := &fakeBulkSlotRepo{zones: zones, slots: tt.slots, bookings: tt.bookings}
+				versions := &fakeVersionRecorder{}
+				uc := &SlotUseCase{repo: repo, versions: versions}
+				selector := tt.selector
+				selector.DateFrom, selector.DateTo = "2024-06-01", "2024-06-01"
+
+				got, errRes := uc.BulkEditSlots("p1", "m1", &entitiesDtos.BulkSlotEditRequest{Selector: selector, Changes: tt.changes}, dryRun)
+				if errRes != nil {
+					t.Fatalf("BulkEditSlots(dryRun=%v) error = %v", dryRun, errRes)
+				}
+				changed := make([]string, 0, len(got.Changed))
+				for _, change := range got.Changed {
+					changed = append(changed, change.SlotID)
+				}
+				skipped := make(map[string]string, len(got.Skipped))
+				for _, skip := range got.Skipped {
+					skipped[skip.SlotID] = skip.Reason
+					if skip.Reason == "zone_rules" && skip.Message == "" {
+						t.Errorf("zone rule skip of %s does not say which rule", skip.SlotID)
+					}
+				}
+				if !reflect.DeepEqual(changed, tt.wantChanged) {
+					t.Errorf("dryRun=%v changed = %v, want %v", dryRun, changed, tt.wantChanged)
+				}
+				if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+					t.Errorf("dryRun=%v skipped = %v, want %v", dryRun, skipped, tt.wantSkipped)
+				}
+
+				wantSaved, wantRecorded := len(tt.wantChanged), 0
+				if dryRun {
+					wantSaved = 0
+				} else if tt.wantVersion {
+					wantRecorded = 1
+				}
+				if len(repo.saved) != wantSaved {
+					t.Errorf("dryRun=%v saved %d slots, want %d", dryRun, len(repo.saved), wantSaved)
+				}
+				if len(versions.recorded) != wantRecorded {
+					t.Errorf("dryRun=%v recorded %d versions, want %d", dryRun, len(versions.recorded), wantRecorded)
+				}
+			}
+		})
+	}
+}
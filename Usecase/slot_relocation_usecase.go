@@ -0,0 +1,256 @@
+package Usecase
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+)
+
+// deleteSlots carries out a relocation plan for the slots' active bookings, then soft-deletes the slots.
+// Refunds run first: if one fails nothing is deleted and the request can be sent again without the
+// bookings already refunded.
+func (su *SlotUseCase) deleteSlots(slots []*entities.Slot, req *entitiesDtos.SlotDeleteRequest) (*entitiesDtos.SlotDeleteResponse, *entitiesDtos.ErrorResponse) {
+	if req == nil {
+		req = &entitiesDtos.SlotDeleteRequest{}
+	}
+
+	byID := make(map[string]*entities.Slot, len(slots))
+	slotIDs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		byID[slot.ID] = slot
+		slotIDs = append(slotIDs, slot.ID)
+	}
+
+	bookings, err := su.repo.GetActiveBookingsBySlotIDs(slotIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get the slots' bookings: " + err.Error(),
+		}
+	}
+
+	moves, refunds, errRes := su.planRelocations(byID, bookings, req.Relocations)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	response := &entitiesDtos.SlotDeleteResponse{
+		DeletedSlotIDs: slotIDs,
+		Moved:          make([]entitiesDtos.BookingRelocation, 0, len(moves)),
+		Refunded:       make([]string, 0, len(refunds)),
+	}
+
+	// A combined booking is refunded as a whole, through its lead booking
+	cancelled := make(map[string]bool)
+	for _, booking := range refunds {
+		cancelID := booking.ID
+		if booking.GroupID != "" {
+			leadID, err := su.repo.GetGroupLeadBookingID(booking.GroupID)
+			if err != nil {
+				return nil, &entitiesDtos.ErrorResponse{
+					Code:    500,
+					Message: fmt.Sprintf("Failed to get the combined booking of booking %s: %v", booking.ID, err),
+				}
+			}
+			cancelID = leadID
+		}
+		if !cancelled[cancelID] {
+			_, errRes := su.bookings.CancelBooking(&entitiesDtos.CancelBookingRequest{
+				BookingID:      cancelID,
+				VendorID:       booking.VendorID,
+				RefundToWallet: req.RefundToWallet,
+			})
+			if errRes != nil {
+				return nil, &entitiesDtos.ErrorResponse{
+					Code:    errRes.Code,
+					Message: fmt.Sprintf("Failed to refund booking %s: %s", booking.ID, errRes.Message),
+				}
+			}
+			cancelled[cancelID] = true
+		}
+		response.Refunded = append(response.Refunded, booking.ID)
+		su.notifyRefund(booking, byID[booking.SlotID], req.RefundToWallet)
+	}
+
+	moveIDs := make(map[string]string, len(moves))
+	for _, move := range moves {
+		moveIDs[move.booking.ID] = move.to.ID
+	}
+	if err := su.repo.DeleteSlots(slotIDs, moveIDs); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete slots: " + err.Error(),
+		}
+	}
+
+	for _, move := range moves {
+		response.Moved = append(response.Moved, entitiesDtos.BookingRelocation{BookingID: move.booking.ID, SlotID: move.to.ID})
+		su.notifyMove(move)
+	}
+
+	return response, nil
+}
+
+type bookingMove struct {
+	booking  entities.Booking
+	from, to *entities.Slot
+}
+
+// planRelocations matches every active booking with an entry of the plan and checks each replacement slot is
+// a free, live slot of the same market day that is not being deleted itself.
+func (su *SlotUseCase) planRelocations(deleting map[string]*entities.Slot, bookings []entities.Booking, plan []entitiesDtos.BookingRelocation) ([]bookingMove, []entities.Booking, *entitiesDtos.ErrorResponse) {
+	entries := make(map[string]entitiesDtos.BookingRelocation, len(plan))
+	for _, entry := range plan {
+		if _, seen := entries[entry.BookingID]; seen {
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Booking %s appears more than once in the relocation plan", entry.BookingID),
+			}
+		}
+		entries[entry.BookingID] = entry
+	}
+
+	active := make(map[string]bool, len(bookings))
+	missing := make([]string, 0)
+	for _, booking := range bookings {
+		active[booking.ID] = true
+		if _, ok := entries[booking.ID]; !ok {
+			missing = append(missing, booking.ID)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "The slots have active bookings; give each a replacement slot or a refund in the relocation plan: " + strings.Join(missing, ", "),
+		}
+	}
+	for bookingID := range entries {
+		if !active[bookingID] {
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Booking %s is not an active booking of the slots being deleted", bookingID),
+			}
+		}
+	}
+
+	moves := make([]bookingMove, 0, len(bookings))
+	refunds := make([]entities.Booking, 0, len(bookings))
+	targeted := make(map[string]string, len(bookings))
+	for _, booking := range bookings {
+		entry := entries[booking.ID]
+		switch {
+		case entry.Refund && entry.SlotID != "":
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Booking %s must either move to a slot or be refunded, not both", booking.ID),
+			}
+		case entry.Refund:
+			refunds = append(refunds, booking)
+			continue
+		case entry.SlotID == "":
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Booking %s needs a replacement slot or a refund", booking.ID),
+			}
+		case booking.GroupID != "":
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("Booking %s is part of a combined booking and can only be refunded", booking.ID),
+			}
+		case deleting[entry.SlotID] != nil:
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Booking %s cannot move to slot %s because it is being deleted too", booking.ID, entry.SlotID),
+			}
+		}
+		if other, taken := targeted[entry.SlotID]; taken {
+			return nil, nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Bookings %s and %s cannot both move to slot %s", other, booking.ID, entry.SlotID),
+			}
+		}
+		targeted[entry.SlotID] = booking.ID
+
+		from := deleting[booking.SlotID]
+		to, errRes := su.replacementSlot(from, entry.SlotID)
+		if errRes != nil {
+			return nil, nil, errRes
+		}
+		moves = append(moves, bookingMove{booking: booking, from: from, to: to})
+	}
+
+	return moves, refunds, nil
+}
+
+func (su *SlotUseCase) replacementSlot(from *entities.Slot, slotID string) (*entities.Slot, *entitiesDtos.ErrorResponse) {
+	to, err := su.repo.GetSlots(slotID)
+	if err != nil {
+		if err.Error() == "slot not found" {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Replacement slot %s not found", slotID),
+			}
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve replacement slot: " + err.Error(),
+		}
+	}
+
+	message := ""
+	switch {
+	case to.MarketID != from.MarketID || slotDate(to) != slotDate(from):
+		message = fmt.Sprintf("Replacement slot %s must be in the same market on %s", slotID, slotDate(from))
+	case to.DeletedAt != nil || to.Status == entities.StatusMaintenance:
+		message = fmt.Sprintf("Replacement slot %s is not open for booking", slotID)
+	}
+	if message != "" {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	taken, err := su.repo.GetActiveBookingsBySlotIDs([]string{slotID})
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check replacement slot: " + err.Error(),
+		}
+	}
+	if len(taken) > 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Replacement slot %s already has a pending or confirmed booking", slotID),
+		}
+	}
+
+	return to, nil
+}
+
+func (su *SlotUseCase) notifyMove(move bookingMove) {
+	message := fmt.Sprintf("Stall %s (%s) on %s has been removed from the market layout. Your booking now holds stall %s (%s) at no extra cost.",
+		move.from.Name, move.from.Zone, slotDate(move.from), move.to.Name, move.to.Zone)
+	if errRes := su.notifications.Notify(move.booking.VendorID, entities.NotificationBookingMoved, "Your stall has moved", message, move.booking.ID); errRes != nil {
+		log.Printf("Warning: failed to notify vendor %s of moved booking %s: %v", move.booking.VendorID, move.booking.ID, errRes.Message)
+	}
+}
+
+func (su *SlotUseCase) notifyRefund(booking entities.Booking, slot *entities.Slot, toWallet bool) {
+	refund := "Any payment is refunded"
+	if toWallet {
+		refund = "Any payment is refunded as wallet credit"
+	}
+	if booking.GroupID != "" {
+		refund += " for the whole combined booking"
+	}
+	message := fmt.Sprintf("Stall %s (%s) on %s has been removed from the market layout and your booking was cancelled. %s.",
+		slot.Name, slot.Zone, slotDate(slot), refund)
+	if errRes := su.notifications.Notify(booking.VendorID, entities.NotificationBookingRefunded, "Your booking was cancelled", message, booking.ID); errRes != nil {
+		log.Printf("Warning: failed to notify vendor %s of refunded booking %s: %v", booking.VendorID, booking.ID, errRes.Message)
+	}
+}
@@ -0,0 +1,221 @@
+package Usecase
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+)
+
+// fakeSlotRepo answers the slot and booking lookups relocation planning makes. Other ISlot methods are not
+// used by the planner and panic if called.
+type fakeSlotRepo struct {
+	Interfaces.ISlot
+	slots    map[string]*entities.Slot
+	bookings []entities.Booking
+}
+
+func (f *fakeSlotRepo) GetSlots(slotID string) (*entities.Slot, error) {
+	slot, ok := f.slots[slotID]
+	if !ok || slot.DeletedAt != nil {
+		return nil, fmt.Errorf("slot not found")
+	}
+	return slot, nil
+}
+
+func (f *fakeSlotRepo) GetActiveBookingsBySlotIDs(slotIDs []string) ([]entities.Booking, error) {
+	var bookings []entities.Booking
+	for _, booking := range f.bookings {
+		for _, slotID := range slotIDs {
+			if booking.SlotID == slotID {
+				bookings = append(bookings, booking)
+			}
+		}
+	}
+	return bookings, nil
+}
+
+func TestPlanRelocations(t *testing.T) {
+	slot := func(id, marketID, date string, status entities.SlotStatus) *entities.Slot {
+		return &entities.Slot{ID: id, MarketID: marketID, Date: date + "T00:00:00Z", Status: status, Name: id}
+	}
+	deleting := map[string]*entities.Slot{
+		"old1": slot("old1", "m1", "2024-06-01", entities.StatusBooked),
+		"old2": slot("old2", "m1", "2024-06-01", entities.StatusBooked),
+	}
+	repo := &fakeSlotRepo{
+		slots: map[string]*entities.Slot{
+			"old1":         deleting["old1"],
+			"old2":         deleting["old2"],
+			"free1":        slot("free1", "m1", "2024-06-01", entities.StatusAvailable),
+			"free2":        slot("free2", "m1", "2024-06-01", entities.StatusAvailable),
+			"next-day":     slot("next-day", "m1", "2024-06-02", entities.StatusAvailable),
+			"other-market": slot("other-market", "m2", "2024-06-01", entities.StatusAvailable),
+			"maintenance":  slot("maintenance", "m1", "2024-06-01", entities.StatusMaintenance),
+			"taken":        slot("taken", "m1", "2024-06-01", entities.StatusBooked),
+		},
+		bookings: []entities.Booking{{ID: "held", SlotID: "taken"}},
+	}
+	uc := &SlotUseCase{repo: repo}
+
+	b1 := entities.Booking{ID: "b1", SlotID: "old1", VendorID: "v1"}
+	b2 := entities.Booking{ID: "b2", SlotID: "old2", VendorID: "v2"}
+	grouped := entities.Booking{ID: "g1", SlotID: "old1", VendorID: "v3", GroupID: "group-1"}
+
+	tests := []struct {
+		name        string
+		bookings    []entities.Booking
+		plan        []entitiesDtos.BookingRelocation
+		wantCode    int
+		wantMoves   map[string]string
+		wantRefunds []string
+	}{
+		{
+			name:      "no bookings, no plan",
+			wantMoves: map[string]string{},
+		},
+		{
+			name:        "refund everything",
+			bookings:    []entities.Booking{b1, b2},
+			plan:        []entitiesDtos.BookingRelocation{{BookingID: "b1", Refund: true}, {BookingID: "b2", Refund: true}},
+			wantMoves:   map[string]string{},
+			wantRefunds: []string{"b1", "b2"},
+		},
+		{
+			name:        "move one, refund the other",
+			bookings:    []entities.Booking{b1, b2},
+			plan:        []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "free1"}, {BookingID: "b2", Refund: true}},
+			wantMoves:   map[string]string{"b1": "free1"},
+			wantRefunds: []string{"b2"},
+		},
+		{
+			name:      "move both to different slots",
+			bookings:  []entities.Booking{b1, b2},
+			plan:      []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "free1"}, {BookingID: "b2", SlotID: "free2"}},
+			wantMoves: map[string]string{"b1": "free1", "b2": "free2"},
+		},
+		{
+			name:        "combined booking is refunded",
+			bookings:    []entities.Booking{grouped},
+			plan:        []entitiesDtos.BookingRelocation{{BookingID: "g1", Refund: true}},
+			wantMoves:   map[string]string{},
+			wantRefunds: []string{"g1"},
+		},
+		{
+			name:     "booking left out of the plan",
+			bookings: []entities.Booking{b1, b2},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", Refund: true}},
+			wantCode: 409,
+		},
+		{
+			name:     "plan names a booking not on the slots",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", Refund: true}, {BookingID: "stranger", Refund: true}},
+			wantCode: 400,
+		},
+		{
+			name:     "booking planned twice",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", Refund: true}, {BookingID: "b1", SlotID: "free1"}},
+			wantCode: 400,
+		},
+		{
+			name:     "both moved and refunded",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "free1", Refund: true}},
+			wantCode: 400,
+		},
+		{
+			name:     "neither moved nor refunded",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1"}},
+			wantCode: 400,
+		},
+		{
+			name:     "combined booking cannot move",
+			bookings: []entities.Booking{grouped},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "g1", SlotID: "free1"}},
+			wantCode: 409,
+		},
+		{
+			name:     "move onto a slot being deleted",
+			bookings: []entities.Booking{b1, b2},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "old2"}, {BookingID: "b2", Refund: true}},
+			wantCode: 400,
+		},
+		{
+			name:     "two bookings onto one slot",
+			bookings: []entities.Booking{b1, b2},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "free1"}, {BookingID: "b2", SlotID: "free1"}},
+			wantCode: 400,
+		},
+		{
+			name:     "replacement does not exist",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "nowhere"}},
+			wantCode: 400,
+		},
+		{
+			name:     "replacement on another day",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "next-day"}},
+			wantCode: 400,
+		},
+		{
+			name:     "replacement in another market",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "other-market"}},
+			wantCode: 400,
+		},
+		{
+			name:     "replacement under maintenance",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "maintenance"}},
+			wantCode: 400,
+		},
+		{
+			name:     "replacement already booked",
+			bookings: []entities.Booking{b1},
+			plan:     []entitiesDtos.BookingRelocation{{BookingID: "b1", SlotID: "taken"}},
+			wantCode: 409,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moves, refunds, errRes := uc.planRelocations(deleting, tt.bookings, tt.plan)
+			if tt.wantCode != 0 {
+				if errRes == nil || errRes.Code != tt.wantCode {
+					t.Fatalf("planRelocations() error = %v, want code %d", errRes, tt.wantCode)
+				}
+				return
+			}
+			if errRes != nil {
+				t.Fatalf("planRelocations() error = %v", errRes)
+			}
+
+			gotMoves := make(map[string]string, len(moves))
+			for _, move := range moves {
+				gotMoves[move.booking.ID] = move.to.ID
+				if move.from != deleting[move.booking.SlotID] {
+					t.Errorf("move of %s starts from %s, want %s", move.booking.ID, move.from.ID, move.booking.SlotID)
+				}
+			}
+			if !reflect.DeepEqual(gotMoves, tt.wantMoves) {
+				t.Errorf("moves = %v, want %v", gotMoves, tt.wantMoves)
+			}
+
+			var gotRefunds []string
+			for _, booking := range refunds {
+				gotRefunds = append(gotRefunds, booking.ID)
+			}
+			sort.Strings(gotRefunds)
+			if !reflect.DeepEqual(gotRefunds, tt.wantRefunds) {
+				t.Errorf("refunds = %v, want %v", gotRefunds, tt.wantRefunds)
+			}
+		})
+	}
+}
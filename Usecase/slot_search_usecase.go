@@ -0,0 +1,253 @@
+package Usecase
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+const (
+	searchDefaultDays = 28
+	searchMaxDays     = 92
+	searchPageSize    = 20
+	searchMaxPageSize = 100
+)
+
+type SlotSearchUseCase struct {
+	repo    Interfaces.ISlotSearch
+	service *Services.SearchService
+	pricing contact.IPricingUseCase
+}
+
+func NewSlotSearchUseCase(repo Interfaces.ISlotSearch, service *Services.SearchService, pricing contact.IPricingUseCase) *SlotSearchUseCase {
+	return &SlotSearchUseCase{
+		repo:    repo,
+		service: service,
+		pricing: pricing,
+	}
+}
+
+// SearchSlots finds free slots across every market, grouped by market and paged by market. Slots show the price
+// a vendor booking now would pay, and the price range is checked against that. Only the markets on the page are
+// priced, so the totals count every slot matching the other filters and a page can come back with fewer markets
+// when some had nothing in the price range.
+func (uc *SlotSearchUseCase) SearchSlots(query *entitiesDtos.SlotSearchQuery) (*entitiesDtos.SlotSearchResponse, *entitiesDtos.ErrorResponse) {
+	filter, origin, radiusKm, errRes := parseSlotSearch(query)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	response := &entitiesDtos.SlotSearchResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Markets:  []entitiesDtos.MarketSlots{},
+	}
+
+	if origin != nil {
+		area := &entitiesDtos.GeoArea{Lat: origin[0], Lng: origin[1], RadiusKm: radiusKm}
+		area.MinLat, area.MaxLat, area.MinLng, area.MaxLng = uc.service.BoundingBox(area.Lat, area.Lng, area.RadiusKm)
+		filter.Area = area
+	}
+
+	totalMarkets, totalSlots, err := uc.repo.CountAvailableSlots(filter)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to search slots: " + err.Error(),
+		}
+	}
+	response.TotalMarkets = int(totalMarkets)
+	response.TotalSlots = int(totalSlots)
+
+	page, err := uc.repo.SearchMarkets(filter)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to search markets: " + err.Error(),
+		}
+	}
+	if len(page) == 0 {
+		return response, nil
+	}
+	marketIDs := make([]string, 0, len(page))
+	var distances map[string]float64
+	if filter.Area != nil {
+		distances = make(map[string]float64, len(page))
+	}
+	for _, market := range page {
+		marketIDs = append(marketIDs, market.ID)
+		if distances != nil {
+			distances[market.ID] = market.DistanceKm
+		}
+	}
+
+	slots, err := uc.repo.SearchAvailableSlots(filter, marketIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to search slots: " + err.Error(),
+		}
+	}
+	slots, errRes = uc.priceSlots(slots, filter)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	markets, err := uc.repo.GetMarketsByIDs(marketIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get markets: " + err.Error(),
+		}
+	}
+	byID := make(map[string]entities.Market, len(markets))
+	for _, market := range markets {
+		byID[market.ID] = market
+	}
+
+	response.Markets = uc.service.GroupByMarket(marketIDs, slots, byID, distances)
+	return response, nil
+}
+
+// priceSlots quotes each slot as the pricing rules stand now, keeps the ones inside the filter's price range and
+// puts them cheapest first, earliest date first for the same price.
+func (uc *SlotSearchUseCase) priceSlots(slots []*entities.Slot, filter *entitiesDtos.SlotSearchFilter) ([]*entities.Slot, *entitiesDtos.ErrorResponse) {
+	now := time.Now()
+	priced := make([]*entities.Slot, 0, len(slots))
+	for _, slot := range slots {
+		quote, errRes := uc.pricing.PriceSlot(slot, now)
+		if errRes != nil {
+			return nil, errRes
+		}
+		if quote.Price < filter.MinPrice || (filter.MaxPrice > 0 && quote.Price > filter.MaxPrice) {
+			continue
+		}
+		slot.Price = quote.Price
+		priced = append(priced, slot)
+	}
+	sort.SliceStable(priced, func(i, j int) bool {
+		return priced[i].Price < priced[j].Price
+	})
+	return priced, nil
+}
+
+// parseSlotSearch validates the query and returns the search point and radius when one was given.
+func parseSlotSearch(query *entitiesDtos.SlotSearchQuery) (*entitiesDtos.SlotSearchFilter, []float64, float64, *entitiesDtos.ErrorResponse) {
+	bad := func(message string) (*entitiesDtos.SlotSearchFilter, []float64, float64, *entitiesDtos.ErrorResponse) {
+		return nil, nil, 0, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	location, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		location = time.UTC
+	}
+	from := time.Now().In(location)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	if query.From != "" {
+		if from, err = time.Parse("2006-01-02", query.From); err != nil {
+			return bad("Invalid from date. Please use YYYY-MM-DD")
+		}
+	}
+	to := from.AddDate(0, 0, searchDefaultDays)
+	if query.To != "" {
+		if to, err = time.Parse("2006-01-02", query.To); err != nil {
+			return bad("Invalid to date. Please use YYYY-MM-DD")
+		}
+	}
+	if to.Before(from) {
+		return bad("The to date must not be before the from date")
+	}
+	if to.Sub(from) > searchMaxDays*24*time.Hour {
+		return bad(fmt.Sprintf("A search can cover at most %d days", searchMaxDays))
+	}
+
+	filter := &entitiesDtos.SlotSearchFilter{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Zone:      strings.TrimSpace(query.Zone),
+		Amenities: make([]string, 0),
+	}
+	for _, name := range strings.Split(query.Amenities, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filter.Amenities = append(filter.Amenities, name)
+		}
+	}
+	if query.Category != "" {
+		category, err := parseCategory(query.Category)
+		if err != nil {
+			return bad("Invalid category: " + query.Category)
+		}
+		filter.Category = category
+	}
+
+	numbers := []struct {
+		name  string
+		value string
+		into  *float64
+	}{
+		{"min_price", query.MinPrice, &filter.MinPrice},
+		{"max_price", query.MaxPrice, &filter.MaxPrice},
+	}
+	for _, number := range numbers {
+		if number.value == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(number.value, 64)
+		if err != nil || value < 0 {
+			return bad(fmt.Sprintf("Invalid %s: %s", number.name, number.value))
+		}
+		*number.into = value
+	}
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return bad("min_price must not be above max_price")
+	}
+
+	integers := []struct {
+		name  string
+		value string
+		into  *int
+	}{
+		{"min_width", query.MinWidth, &filter.MinWidth},
+		{"min_height", query.MinHeight, &filter.MinHeight},
+		{"page", query.Page, &filter.Page},
+		{"page_size", query.PageSize, &filter.PageSize},
+	}
+	for _, integer := range integers {
+		if integer.value == "" {
+			continue
+		}
+		value, err := strconv.Atoi(integer.value)
+		if err != nil || value < 0 {
+			return bad(fmt.Sprintf("Invalid %s: %s", integer.name, integer.value))
+		}
+		*integer.into = value
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = searchPageSize
+	}
+	if filter.PageSize > searchMaxPageSize {
+		filter.PageSize = searchMaxPageSize
+	}
+
+	if query.Lat == "" && query.Lng == "" && query.RadiusKm == "" {
+		return filter, nil, 0, nil
+	}
+	point, radiusKm, errRes := parseSearchPoint(query.Lat, query.Lng, query.RadiusKm)
+	if errRes != nil {
+		return nil, nil, 0, errRes
+	}
+	return filter, point, radiusKm, nil
+}
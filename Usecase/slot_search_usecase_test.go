@@ -0,0 +1,160 @@
+package Usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+// fakeSlotSearchRepo serves free slots of markets that are already in search order, each a kilometre further
+// out than the last.
+type fakeSlotSearchRepo struct {
+	Interfaces.ISlotSearch
+	markets []entities.Market
+	slots   []*entities.Slot
+}
+
+func (f *fakeSlotSearchRepo) SearchMarkets(filter *entitiesDtos.SlotSearchFilter) ([]entitiesDtos.MarketDistance, error) {
+	page := make([]entitiesDtos.MarketDistance, 0)
+	for i, market := range f.markets {
+		if i < (filter.Page-1)*filter.PageSize || len(page) == filter.PageSize {
+			continue
+		}
+		found := entitiesDtos.MarketDistance{ID: market.ID}
+		if filter.Area != nil {
+			found.DistanceKm = float64(i+1) + 0.004
+		}
+		page = append(page, found)
+	}
+	return page, nil
+}
+
+func (f *fakeSlotSearchRepo) CountAvailableSlots(filter *entitiesDtos.SlotSearchFilter) (int64, int64, error) {
+	return int64(len(f.markets)), int64(len(f.slots)), nil
+}
+
+func (f *fakeSlotSearchRepo) SearchAvailableSlots(filter *entitiesDtos.SlotSearchFilter, marketIDs []string) ([]*entities.Slot, error) {
+	slots := make([]*entities.Slot, 0)
+	for _, slot := range f.slots {
+		for _, marketID := range marketIDs {
+			if slot.MarketID == marketID {
+				copied := *slot
+				slots = append(slots, &copied)
+			}
+		}
+	}
+	return slots, nil
+}
+
+func (f *fakeSlotSearchRepo) GetMarketsByIDs(marketIDs []string) ([]entities.Market, error) {
+	return f.markets, nil
+}
+
+// fakeSlotPricing quotes every slot at its stored price times its market's multiplier.
+type fakeSlotPricing struct {
+	contact.IPricingUseCase
+	multipliers map[string]float64
+}
+
+func (f *fakeSlotPricing) PriceSlot(slot *entities.Slot, at time.Time) (*entitiesDtos.PriceQuote, *entitiesDtos.ErrorResponse) {
+	return &entitiesDtos.PriceQuote{SlotID: slot.ID, Price: slot.Price * f.multipliers[slot.MarketID]}, nil
+}
+
+func TestSearchSlotsQuotesPrices(t *testing.T) {
+	repo := &fakeSlotSearchRepo{
+		markets: []entities.Market{{ID: "a", Name: "Asok"}, {ID: "b", Name: "Bang Na"}, {ID: "c", Name: "Chatuchak"}},
+		slots: []*entities.Slot{
+			{ID: "a1", MarketID: "a", Date: "2024-06-01", Price: 100},
+			{ID: "a2", MarketID: "a", Date: "2024-06-02", Price: 300},
+			{ID: "b1", MarketID: "b", Date: "2024-06-01", Price: 500},
+			{ID: "b2", MarketID: "b", Date: "2024-06-02", Price: 200},
+			{ID: "c1", MarketID: "c", Date: "2024-06-01", Price: 150},
+		},
+	}
+	pricing := &fakeSlotPricing{multipliers: map[string]float64{"a": 2, "b": 0.5, "c": 1}}
+
+	type market struct {
+		ID     string
+		Lowest float64
+		Slots  []string
+		Km     float64
+	}
+	tests := []struct {
+		name  string
+		query entitiesDtos.SlotSearchQuery
+		want  []market
+	}{
+		{
+			name:  "quoted prices, cheapest first within a market",
+			query: entitiesDtos.SlotSearchQuery{From: "2024-06-01", To: "2024-06-30"},
+			want: []market{
+				{ID: "a", Lowest: 200, Slots: []string{"a1", "a2"}},
+				{ID: "b", Lowest: 100, Slots: []string{"b2", "b1"}},
+				{ID: "c", Lowest: 150, Slots: []string{"c1"}},
+			},
+		},
+		{
+			name:  "price range checks the quoted price",
+			query: entitiesDtos.SlotSearchQuery{From: "2024-06-01", To: "2024-06-30", MinPrice: "150", MaxPrice: "250"},
+			want: []market{
+				{ID: "a", Lowest: 200, Slots: []string{"a1"}},
+				{ID: "b", Lowest: 250, Slots: []string{"b1"}},
+				{ID: "c", Lowest: 150, Slots: []string{"c1"}},
+			},
+		},
+		{
+			name:  "markets with nothing in range drop out of the page",
+			query: entitiesDtos.SlotSearchQuery{From: "2024-06-01", To: "2024-06-30", MaxPrice: "120"},
+			want:  []market{{ID: "b", Lowest: 100, Slots: []string{"b2"}}},
+		},
+		{
+			name:  "paged by market",
+			query: entitiesDtos.SlotSearchQuery{From: "2024-06-01", To: "2024-06-30", Page: "2", PageSize: "2"},
+			want:  []market{{ID: "c", Lowest: 150, Slots: []string{"c1"}}},
+		},
+		{
+			name:  "distances for a search around a point",
+			query: entitiesDtos.SlotSearchQuery{From: "2024-06-01", To: "2024-06-30", Lat: "13.7", Lng: "100.5", RadiusKm: "5", PageSize: "2"},
+			want: []market{
+				{ID: "a", Lowest: 200, Slots: []string{"a1", "a2"}, Km: 1},
+				{ID: "b", Lowest: 100, Slots: []string{"b2", "b1"}, Km: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewSlotSearchUseCase(repo, Services.NewSearchService(), pricing)
+			response, errRes := uc.SearchSlots(&tt.query)
+			if errRes != nil {
+				t.Fatalf("SearchSlots() error = %v", errRes)
+			}
+			if response.TotalMarkets != 3 || response.TotalSlots != 5 {
+				t.Errorf("totals = %d markets, %d slots, want 3 and 5", response.TotalMarkets, response.TotalSlots)
+			}
+
+			got := make([]market, 0)
+			for _, group := range response.Markets {
+				found := market{ID: group.MarketID, Lowest: group.LowestPrice, Slots: []string{}}
+				if group.DistanceKm != nil {
+					found.Km = *group.DistanceKm
+				}
+				for _, slot := range group.Slots {
+					found.Slots = append(found.Slots, slot.ID)
+				}
+				if group.Available != len(group.Slots) {
+					t.Errorf("market %s has %d available for %d slots", group.MarketID, group.Available, len(group.Slots))
+				}
+				got = append(got, found)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("markets = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,110 +11,30 @@ import (
 )
 
 type SlotUseCase struct {
-	repo Interfaces.ISlot
+	repo          Interfaces.ISlot
+	notifications contact.INotificationUseCase
+	amenities     contact.IAmenityUseCase
+	versions      contact.ILayoutVersionUseCase
+	bookings      contact.IBookingUseCase
 }
 
 var _ contact.ISlotUseCase = (*SlotUseCase)(nil)
 
-func NewSlotUseCase(repo Interfaces.ISlot) *SlotUseCase {
+func NewSlotUseCase(repo Interfaces.ISlot, notifications contact.INotificationUseCase, amenities contact.IAmenityUseCase, versions contact.ILayoutVersionUseCase) *SlotUseCase {
 	return &SlotUseCase{
-		repo: repo,
+		repo:          repo,
+		notifications: notifications,
+		amenities:     amenities,
+		versions:      versions,
 	}
 }
-func (su *SlotUseCase) CreateOrUpdateLayout(marketID string, layout []entitiesDtos.ZoneLayout) ([]*entities.Slot, *entitiesDtos.ErrorResponse) {
-	// Validate the marketID
-	marketExists, err := su.repo.CheckMarketExists(marketID)
-	if err != nil {
-		return nil, &entitiesDtos.ErrorResponse{
-			Code:    500,
-			Message: "Failed to check market existence: " + err.Error(),
-		}
-	}
-	if !marketExists {
-		return nil, &entitiesDtos.ErrorResponse{
-			Code:    400,
-			Message: fmt.Sprintf("Market with ID %s does not exist", marketID),
-		}
-	}
-
-	// Validate the layout
-	//if err := su.validateLayout(layout); err != nil {
-	//	return nil, &entitiesDtos.ErrorResponse{
-	//		Code:    400,
-	//		Message: "Invalid layout: " + err.Error(),
-	//	}
-	//}
-
-	// Get existing slots for the market
-	existingSlots, err := su.repo.GetSlotsByMarketID(marketID)
-	if err != nil {
-		return nil, &entitiesDtos.ErrorResponse{
-			Code:    500,
-			Message: "Failed to fetch existing slots: " + err.Error(),
-		}
-	}
-
-	// Create a map for quick lookup of existing slots
-	existingSlotMap := make(map[string]*entities.Slot)
-	for _, slot := range existingSlots {
-		key := fmt.Sprintf("%s-%s-%s-%s", slot.MarketID, slot.Zone, slot.Name, slot.Date)
-		existingSlotMap[key] = slot
-	}
-
-	slotsToUpsert := make([]*entities.Slot, 0)
-
-	// Process layout and prepare slots for upsertion
-	for _, zoneLayout := range layout {
-		for _, stall := range zoneLayout.Stalls {
-			uniqueSlotID := fmt.Sprintf("%s-%s-%s-%s", marketID, zoneLayout.Zone, stall.Name, zoneLayout.Date.Format("2006-01-02"))
-
-			category, err := parseCategory(stall.StallType)
-			if err != nil {
-				return nil, &entitiesDtos.ErrorResponse{
-					Code:    400,
-					Message: fmt.Sprintf("Invalid category for stall %s: %s", stall.Name, err.Error()),
-				}
-			}
 
-			slot := &entities.Slot{
-				ID:        uniqueSlotID,
-				MarketID:  marketID,
-				Zone:      zoneLayout.Zone,
-				Name:      stall.Name,
-				Width:     stall.Width,
-				Height:    stall.Height,
-				Price:     stall.Price,
-				Status:    entities.StatusAvailable,
-				Category:  category,
-				Date:      zoneLayout.Date.Format("2006-01-02"),
-				UpdatedAt: time.Now(),
-			}
-
-			if existingSlot, exists := existingSlotMap[uniqueSlotID]; exists {
-				// Update existing slot
-				slot.CreatedAt = existingSlot.CreatedAt
-			} else {
-				// Set CreatedAt for new slots
-				slot.CreatedAt = time.Now()
-			}
-
-			slotsToUpsert = append(slotsToUpsert, slot)
-		}
-	}
-
-	// Upsert slots
-	updatedSlots, err := su.repo.UpsertSlots(slotsToUpsert)
-	if err != nil {
-		return nil, &entitiesDtos.ErrorResponse{
-			Code:    500,
-			Message: "Failed to upsert slots: " + err.Error(),
-		}
-	}
-
-	return updatedSlots, nil
+// UseBookings sets what refunds bookings of deleted slots. Bookings depend on slots, so it is wired after both exist.
+func (su *SlotUseCase) UseBookings(bookings contact.IBookingUseCase) {
+	su.bookings = bookings
 }
 
-func (su *SlotUseCase) EditSlot(slotID string, updates *entitiesDtos.SlotUpdateDTO) (*entities.Slot, *entitiesDtos.ErrorResponse) {
+func (su *SlotUseCase) EditSlot(userID, slotID string, updates *entitiesDtos.SlotUpdateDTO) (*entities.Slot, *entitiesDtos.ErrorResponse) {
 	// Retrieve the existing slot
 	existingSlot, err := su.repo.GetSlots(slotID)
 	if err != nil {
@@ -130,6 +50,8 @@ func (su *SlotUseCase) EditSlot(slotID string, updates *entitiesDtos.SlotUpdateD
 		}
 	}
 
+	original := *existingSlot
+
 	// Apply updates
 	if updates.Name != nil {
 		existingSlot.Name = *updates.Name
@@ -140,8 +62,14 @@ func (su *SlotUseCase) EditSlot(slotID string, updates *entitiesDtos.SlotUpdateD
 	if updates.Height != nil {
 		existingSlot.Height = int(*updates.Height)
 	}
-	if updates.Price != 0 {
-		existingSlot.Price = updates.Price
+	if updates.Price != nil {
+		if *updates.Price < 0 {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Price cannot be negative",
+			}
+		}
+		existingSlot.Price = entities.RoundMoney(*updates.Price)
 	}
 	if updates.Category != "" {
 		existingSlot.Category = updates.Category
@@ -149,6 +77,42 @@ func (su *SlotUseCase) EditSlot(slotID string, updates *entitiesDtos.SlotUpdateD
 	if updates.Status != "" {
 		existingSlot.Status = updates.Status
 	}
+	if updates.X != nil || updates.Y != nil || updates.Rotation != nil || updates.Shape != nil {
+		geometry := existingSlot.SlotGeometry
+		if updates.X != nil {
+			geometry.X = *updates.X
+		}
+		if updates.Y != nil {
+			geometry.Y = *updates.Y
+		}
+		if updates.Rotation != nil {
+			geometry.Rotation = *updates.Rotation
+		}
+		if updates.Shape != nil {
+			geometry.Shape = updates.Shape
+		}
+
+		geometry, errRes := slotGeometry(existingSlot.Name, geometry)
+		if errRes != nil {
+			return nil, errRes
+		}
+		existingSlot.SlotGeometry = geometry
+	}
+
+	if updates.Adjacent != nil {
+		adjacent, errRes := su.adjacentSlots(existingSlot, *updates.Adjacent)
+		if errRes != nil {
+			return nil, errRes
+		}
+		existingSlot.Adjacent = adjacent
+	}
+	if updates.Amenities != nil {
+		amenities, errRes := su.amenities.CheckSlotAmenities(existingSlot.MarketID, *updates.Amenities)
+		if errRes != nil {
+			return nil, errRes
+		}
+		existingSlot.Amenities = amenities
+	}
 
 	// Update the slot in the repository
 	updatedSlot, err := su.repo.UpdateSlot(existingSlot)
@@ -159,58 +123,104 @@ func (su *SlotUseCase) EditSlot(slotID string, updates *entitiesDtos.SlotUpdateD
 		}
 	}
 
+	// A reshaped stall is part of the layout history; status, neighbours and amenities are not
+	if original.Name != updatedSlot.Name || len(changedFields(&original, updatedSlot)) > 0 {
+		note := fmt.Sprintf("Slot %s edited", updatedSlot.Name)
+		if errRes := su.versions.RecordSlotChanges(userID, updatedSlot.MarketID, note, []*entities.Slot{&original}, []*entities.Slot{updatedSlot}); errRes != nil {
+			return nil, errRes
+		}
+	}
+
 	return updatedSlot, nil
 }
 
-func (su *SlotUseCase) DeleteSlot(slotID string) *entitiesDtos.ErrorResponse {
+// adjacentSlots checks declared neighbours are other slots of the same market day.
+func (su *SlotUseCase) adjacentSlots(slot *entities.Slot, slotIDs []string) ([]string, *entitiesDtos.ErrorResponse) {
+	sameDay, err := su.repo.GetSlotsByDate(slot.MarketID, slotDate(slot))
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get the day's slots: " + err.Error(),
+		}
+	}
+	onDay := make(map[string]bool, len(sameDay))
+	for _, other := range sameDay {
+		onDay[other.ID] = other.DeletedAt == nil
+	}
+
+	adjacent := make([]string, 0, len(slotIDs))
+	seen := make(map[string]bool, len(slotIDs))
+	for _, id := range slotIDs {
+		if id == slot.ID || !onDay[id] {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Adjacent slot %s must be another slot of the same market day", id),
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			adjacent = append(adjacent, id)
+		}
+	}
+	return adjacent, nil
+}
+
+// DeleteSlot soft-deletes a slot. While it has pending or confirmed bookings, the request must say where each
+// one moves or that it is refunded.
+func (su *SlotUseCase) DeleteSlot(providerID, slotID string, req *entitiesDtos.SlotDeleteRequest) (*entitiesDtos.SlotDeleteResponse, *entitiesDtos.ErrorResponse) {
 	// Check if the slot exists
-	_, err := su.repo.GetSlots(slotID)
+	slot, err := su.repo.GetSlots(slotID)
 	if err != nil {
 		if err.Error() == "slot not found" {
-			return &entitiesDtos.ErrorResponse{
+			return nil, &entitiesDtos.ErrorResponse{
 				Code:    404,
 				Message: "Slot not found",
 			}
 		}
-		return &entitiesDtos.ErrorResponse{
+		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
 			Message: "Failed to retrieve slot: " + err.Error(),
 		}
 	}
-
-	// Delete the slot
-	if err := su.repo.DeleteSlot(slotID); err != nil {
-		return &entitiesDtos.ErrorResponse{
-			Code:    500,
-			Message: "Failed to delete slot: " + err.Error(),
-		}
+	if errRes := checkMarketOwner(su.repo, providerID, slot.MarketID, "slots"); errRes != nil {
+		return nil, errRes
 	}
 
-	return nil
+	return su.deleteSlots([]*entities.Slot{slot}, req)
 }
 
-func (su *SlotUseCase) DeleteSlotByDateAndZone(slotID string, zoneID string, date string) *entitiesDtos.ErrorResponse {
-
+// DeleteSlotByDateAndZone soft-deletes every slot of a zone on one market day, relocating their bookings
+// the same way as DeleteSlot.
+func (su *SlotUseCase) DeleteSlotByDateAndZone(providerID, marketID, zoneID, date string, req *entitiesDtos.SlotDeleteRequest) (*entitiesDtos.SlotDeleteResponse, *entitiesDtos.ErrorResponse) {
 	date = strings.TrimSpace(date)
-	fmt.Printf("Received parameters: slotID=%s, zoneID=%s, date=%s\n", slotID, zoneID, date)
 	_, err := time.Parse("2006-01-02", date)
 	if err != nil {
-		return &entitiesDtos.ErrorResponse{
+		return nil, &entitiesDtos.ErrorResponse{
 			Code:    400,
 			Message: "Invalid date format. Please use YYYY-MM-DD",
 		}
 	}
+	if errRes := checkMarketOwner(su.repo, providerID, marketID, "slots"); errRes != nil {
+		return nil, errRes
+	}
 
-	// Delete the slots
-	if err := su.repo.DeleteSlotByDateAndZone(slotID, zoneID, date); err != nil {
-		return &entitiesDtos.ErrorResponse{
+	slots, err := su.repo.GetSlotsByDateAndZone(marketID, zoneID, date)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
 			Code:    500,
-			Message: "Failed to delete slots: " + err.Error(),
+			Message: "Failed to get slots: " + err.Error(),
+		}
+	}
+	if len(slots) == 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "No slots found for the given zone and date",
 		}
 	}
 
-	return nil
+	return su.deleteSlots(slots, req)
 }
+
 func parseCategory(category string) (entities.Category, error) {
 	switch strings.ToLower(category) {
 	case "clothes", "clothing":
@@ -0,0 +1,408 @@
+package Usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"unicode/utf8"
+)
+
+// staffInviteTTL is how long an invite can be accepted.
+const staffInviteTTL = 7 * 24 * time.Hour
+
+type StaffUseCase struct {
+	repo Interfaces.IStaff
+	hash Interfaces.IHashService
+}
+
+func NewStaffUseCase(repo Interfaces.IStaff, hash Interfaces.IHashService) *StaffUseCase {
+	return &StaffUseCase{
+		repo: repo,
+		hash: hash,
+	}
+}
+
+// Invite adds a staff member to the provider with its market roles. The staff member picks a username and
+// password when accepting the invite; the token to do so is only returned here.
+func (uc *StaffUseCase) Invite(providerID string, req *entitiesDtos.StaffInviteRequest) (*entitiesDtos.StaffInviteResponse, *entitiesDtos.ErrorResponse) {
+	name := strings.TrimSpace(req.Name)
+	email := strings.TrimSpace(req.Email)
+	switch {
+	case name == "" || utf8.RuneCountInString(name) > 100:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Name is required and can be at most 100 characters",
+		}
+	case !validEmail(email):
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "A valid email is required",
+		}
+	case len(req.Assignments) == 0:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Assign the staff member to at least one market",
+		}
+	}
+
+	if _, err := uc.repo.GetStaffByEmail(email); err == nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "A staff member with this email already exists",
+		}
+	}
+
+	staffID := uuid.New().String()
+	assignments, errRes := uc.checkAssignments(providerID, staffID, req.Assignments)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	staff := &entities.StaffMember{
+		ID:          staffID,
+		ProviderID:  providerID,
+		Name:        name,
+		Email:       email,
+		Status:      entities.StaffInvited,
+		Assignments: assignments,
+	}
+	token, errRes := issueInvite(staff)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if err := uc.repo.CreateStaff(staff); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to invite staff member: " + err.Error(),
+		}
+	}
+
+	return inviteResponse(staff, token), nil
+}
+
+// Reinvite issues a new invite token to a staff member who has not accepted yet, replacing the old one.
+func (uc *StaffUseCase) Reinvite(providerID, staffID string) (*entitiesDtos.StaffInviteResponse, *entitiesDtos.ErrorResponse) {
+	staff, errRes := uc.getProviderStaff(providerID, staffID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if staff.Status != entities.StaffInvited {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("The staff member is %s, only pending invites can be sent again", staff.Status),
+		}
+	}
+
+	token, errRes := issueInvite(staff)
+	if errRes != nil {
+		return nil, errRes
+	}
+	if err := uc.repo.UpdateStaff(staff); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to renew invite: " + err.Error(),
+		}
+	}
+
+	return inviteResponse(staff, token), nil
+}
+
+// AcceptInvite sets the staff member's login and signs it in.
+func (uc *StaffUseCase) AcceptInvite(req *entitiesDtos.StaffAcceptRequest) (*entitiesDtos.StaffLoginResponse, *entitiesDtos.ErrorResponse) {
+	username := strings.TrimSpace(req.Username)
+	switch {
+	case username == "" || utf8.RuneCountInString(username) > 100:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Username is required and can be at most 100 characters",
+		}
+	case len(req.Password) < 8:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Password must be at least 8 characters",
+		}
+	}
+
+	staff, err := uc.repo.GetStaffByInviteToken(hashInviteToken(req.InviteToken))
+	if err != nil || staff.InviteExpiresAt == nil || time.Now().After(*staff.InviteExpiresAt) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Invite not found or expired; ask the provider to send it again",
+		}
+	}
+	if _, err := uc.repo.GetStaffByUsername(username); err == nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "Username is already taken",
+		}
+	}
+
+	hashedPassword, err := uc.hash.HashPassword(req.Password)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Error hashing password: " + err.Error(),
+		}
+	}
+	staff.Username = &username
+	staff.Password = hashedPassword
+	staff.Status = entities.StaffActive
+	staff.InviteTokenHash = ""
+	staff.InviteExpiresAt = nil
+	if err := uc.repo.UpdateStaff(staff); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to accept invite: " + err.Error(),
+		}
+	}
+
+	return uc.signIn(staff)
+}
+
+func (uc *StaffUseCase) Login(username, password string) (*entitiesDtos.StaffLoginResponse, *entitiesDtos.ErrorResponse) {
+	staff, err := uc.repo.GetStaffByUsername(strings.TrimSpace(username))
+	if err != nil || staff.Status != entities.StaffActive || uc.hash.CompareHashAndPassword(staff.Password, password) != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    401,
+			Message: "Invalid username or password",
+		}
+	}
+
+	return uc.signIn(staff)
+}
+
+func (uc *StaffUseCase) GetStaff(providerID string) ([]entities.StaffMember, *entitiesDtos.ErrorResponse) {
+	staff, err := uc.repo.GetStaffByProvider(providerID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve staff: " + err.Error(),
+		}
+	}
+	return staff, nil
+}
+
+// SetAssignments replaces the markets and roles of a staff member. No assignments leaves the staff member
+// able to sign in but not to do anything.
+func (uc *StaffUseCase) SetAssignments(providerID, staffID string, req *entitiesDtos.StaffAssignmentsRequest) (*entities.StaffMember, *entitiesDtos.ErrorResponse) {
+	staff, errRes := uc.getProviderStaff(providerID, staffID)
+	if errRes != nil {
+		return nil, errRes
+	}
+	assignments, errRes := uc.checkAssignments(providerID, staff.ID, req.Assignments)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.ReplaceAssignments(staff.ID, assignments); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save assignments: " + err.Error(),
+		}
+	}
+	staff.Assignments = assignments
+	return staff, nil
+}
+
+// DisableStaff stops a staff member from signing in and ends its current sessions.
+func (uc *StaffUseCase) DisableStaff(providerID, staffID string) (*entities.StaffMember, *entitiesDtos.ErrorResponse) {
+	staff, errRes := uc.getProviderStaff(providerID, staffID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	staff.Status = entities.StaffDisabled
+	staff.InviteTokenHash = ""
+	staff.InviteExpiresAt = nil
+	if err := uc.repo.UpdateStaff(staff); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to disable staff member: " + err.Error(),
+		}
+	}
+	return staff, nil
+}
+
+// GetAccess lists the markets a staff member works in and what it may do in each.
+func (uc *StaffUseCase) GetAccess(staffID string) (*entitiesDtos.StaffAccessResponse, *entitiesDtos.ErrorResponse) {
+	staff, err := uc.repo.GetStaffByID(staffID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Staff member not found",
+		}
+	}
+
+	marketIDs := make([]string, 0, len(staff.Assignments))
+	for _, assignment := range staff.Assignments {
+		marketIDs = append(marketIDs, assignment.MarketID)
+	}
+	names := make(map[string]string)
+	if len(marketIDs) > 0 {
+		markets, err := uc.repo.GetMarketsByIDs(marketIDs)
+		if err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to retrieve markets: " + err.Error(),
+			}
+		}
+		for _, market := range markets {
+			names[market.ID] = market.Name
+		}
+	}
+
+	access := &entitiesDtos.StaffAccessResponse{Staff: staff, Markets: make([]entitiesDtos.StaffMarketAccess, 0)}
+	for _, assignment := range staff.Assignments {
+		name, ok := names[assignment.MarketID]
+		if !ok {
+			continue // The market was deleted
+		}
+		access.Markets = append(access.Markets, entitiesDtos.StaffMarketAccess{
+			MarketID:    assignment.MarketID,
+			MarketName:  name,
+			Role:        assignment.Role,
+			Permissions: assignment.Role.Permissions(),
+		})
+	}
+	return access, nil
+}
+
+func (uc *StaffUseCase) getProviderStaff(providerID, staffID string) (*entities.StaffMember, *entitiesDtos.ErrorResponse) {
+	staff, err := uc.repo.GetStaffByID(staffID)
+	if err != nil || staff.ProviderID != providerID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Staff member not found",
+		}
+	}
+	return staff, nil
+}
+
+// checkAssignments turns requested assignments into entities, making sure every market belongs to the
+// provider and appears once.
+func (uc *StaffUseCase) checkAssignments(providerID, staffID string, reqs []entitiesDtos.StaffAssignmentRequest) ([]entities.StaffAssignment, *entitiesDtos.ErrorResponse) {
+	assignments := make([]entities.StaffAssignment, 0, len(reqs))
+	marketIDs := make([]string, 0, len(reqs))
+	seen := make(map[string]bool)
+	for _, req := range reqs {
+		role := entities.StaffRole(strings.ToLower(strings.TrimSpace(string(req.Role))))
+		switch {
+		case req.MarketID == "":
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Every assignment needs a market_id",
+			}
+		case !role.Valid():
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Unknown staff role %q; use manager, cashier or checker", req.Role),
+			}
+		case seen[req.MarketID]:
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "A staff member can have only one role per market",
+			}
+		}
+		seen[req.MarketID] = true
+		marketIDs = append(marketIDs, req.MarketID)
+		assignments = append(assignments, entities.StaffAssignment{
+			ID:       uuid.New().String(),
+			StaffID:  staffID,
+			MarketID: req.MarketID,
+			Role:     role,
+		})
+	}
+	if len(marketIDs) == 0 {
+		return assignments, nil
+	}
+
+	markets, err := uc.repo.GetMarketsByIDs(marketIDs)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve markets: " + err.Error(),
+		}
+	}
+	owned := make(map[string]bool)
+	for _, market := range markets {
+		owned[market.ID] = market.ProviderID == providerID
+	}
+	for _, marketID := range marketIDs {
+		if !owned[marketID] {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    403,
+				Message: fmt.Sprintf("Market %s is not one of your markets", marketID),
+			}
+		}
+	}
+	return assignments, nil
+}
+
+func (uc *StaffUseCase) signIn(staff *entities.StaffMember) (*entitiesDtos.StaffLoginResponse, *entitiesDtos.ErrorResponse) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["sub"] = staff.ID
+	claims["email"] = staff.Email
+	claims["exp"] = time.Now().Add(time.Hour * 72).Unix()
+	claims["role"] = "staff"
+	claims["iat"] = time.Now().Unix()
+
+	tokenString, err := token.SignedString([]byte(os.Getenv("JWT_SECRET_KEY")))
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to sign in: " + err.Error(),
+		}
+	}
+	return &entitiesDtos.StaffLoginResponse{
+		AccessToken: tokenString,
+		StaffID:     staff.ID,
+		ProviderID:  staff.ProviderID,
+	}, nil
+}
+
+// issueInvite gives the staff member a fresh invite token and returns it. Only its hash is stored.
+func issueInvite(staff *entities.StaffMember) (string, *entitiesDtos.ErrorResponse) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create invite: " + err.Error(),
+		}
+	}
+	token := hex.EncodeToString(raw)
+	expires := time.Now().Add(staffInviteTTL)
+	staff.InviteTokenHash = hashInviteToken(token)
+	staff.InviteExpiresAt = &expires
+	return token, nil
+}
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+// inviteResponse adds a link to accept the invite when STAFF_INVITE_URL names the page that does it.
+func inviteResponse(staff *entities.StaffMember, token string) *entitiesDtos.StaffInviteResponse {
+	response := &entitiesDtos.StaffInviteResponse{Staff: staff, InviteToken: token}
+	if page := os.Getenv("STAFF_INVITE_URL"); page != "" {
+		response.InviteURL = page + "?token=" + url.QueryEscape(token)
+	}
+	return response
+}
+
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email && utf8.RuneCountInString(email) <= 100
+}
@@ -0,0 +1,326 @@
+package Usecase
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+const (
+	textSearchMaxQuery = 100
+	textSearchMaxTerms = 32
+	// textSearchCoverage is the share of query terms a result must contain. Thai queries become many
+	// character pairs, so a result may miss a few and still be what was meant.
+	textSearchCoverage = 0.6
+	// textSearchSnippet is how much of a long description a highlight shows.
+	textSearchSnippet = 160
+)
+
+type TextSearchUseCase struct {
+	repo   Interfaces.ITextSearch
+	text   *Services.TextSearchService
+	search *Services.SearchService
+}
+
+var _ contact.ISearchIndex = (*TextSearchUseCase)(nil)
+
+func NewTextSearchUseCase(repo Interfaces.ITextSearch, text *Services.TextSearchService, search *Services.SearchService) *TextSearchUseCase {
+	return &TextSearchUseCase{
+		repo:   repo,
+		text:   text,
+		search: search,
+	}
+}
+
+// Search finds markets and vendor stalls whose text matches the query, best first, a page at a time.
+func (uc *TextSearchUseCase) Search(query *entitiesDtos.TextSearchQuery) (*entitiesDtos.TextSearchResponse, *entitiesDtos.ErrorResponse) {
+	filter, errRes := uc.parseTextSearch(query)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	matches, err := uc.repo.MatchDocuments(filter)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to search: " + err.Error(),
+		}
+	}
+	// A result's score is the average weight of the fields each query term was found in
+	scores := make([]float64, len(matches))
+	for i, match := range matches {
+		scores[i] = math.Round(match.Weight/float64(len(filter.Terms))*100) / 100
+	}
+	order := make([]int, len(matches))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	response := &entitiesDtos.TextSearchResponse{
+		Query:    strings.TrimSpace(query.Q),
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Total:    len(matches),
+		Results:  []entitiesDtos.TextSearchHit{},
+	}
+	start := (filter.Page - 1) * filter.PageSize
+	if start >= len(order) {
+		return response, nil
+	}
+	order = order[start:]
+	if len(order) > filter.PageSize {
+		order = order[:filter.PageSize]
+	}
+
+	ids := map[entities.SearchKind][]string{}
+	for _, i := range order {
+		ids[matches[i].Kind] = append(ids[matches[i].Kind], matches[i].RefID)
+	}
+	markets, err := uc.repo.GetMarketsByIDs(ids[entities.SearchMarket])
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get markets: " + err.Error(),
+		}
+	}
+	profiles, err := uc.repo.GetVendorProfilesByIDs(ids[entities.SearchVendor])
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get vendor profiles: " + err.Error(),
+		}
+	}
+	marketsByID := make(map[string]entities.Market, len(markets))
+	for _, market := range markets {
+		marketsByID[market.ID] = market
+	}
+	profilesByID := make(map[string]entities.VendorProfile, len(profiles))
+	for _, profile := range profiles {
+		profilesByID[profile.VendorID] = profile
+	}
+
+	for _, i := range order {
+		match := matches[i]
+		var hit entitiesDtos.TextSearchHit
+		switch match.Kind {
+		case entities.SearchMarket:
+			market, ok := marketsByID[match.RefID]
+			if !ok {
+				continue
+			}
+			hit = uc.marketHit(&market, filter)
+		case entities.SearchVendor:
+			profile, ok := profilesByID[match.RefID]
+			if !ok {
+				continue
+			}
+			hit = uc.vendorHit(&profile, filter)
+		}
+		hit.Score = scores[i]
+		response.Results = append(response.Results, hit)
+	}
+	return response, nil
+}
+
+// IndexMarket writes a market's name, address and description to the search index.
+func (uc *TextSearchUseCase) IndexMarket(market *entities.Market) *entitiesDtos.ErrorResponse {
+	weights := uc.text.IndexTerms(
+		Services.WeightedText{Text: market.Name, Weight: 3},
+		Services.WeightedText{Text: market.Address, Weight: 1},
+		Services.WeightedText{Text: market.Description, Weight: 1},
+	)
+	if err := uc.repo.SaveDocument(entities.SearchMarket, market.ID, weights); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to index market: " + err.Error(),
+		}
+	}
+	return nil
+}
+
+// IndexVendorProfile writes a vendor's stall name, products, category and description to the search index.
+func (uc *TextSearchUseCase) IndexVendorProfile(profile *entities.VendorProfile) *entitiesDtos.ErrorResponse {
+	weights := uc.text.IndexTerms(
+		Services.WeightedText{Text: profile.StallName, Weight: 3},
+		Services.WeightedText{Text: strings.Join(profile.Products, " "), Weight: 2},
+		Services.WeightedText{Text: string(profile.Category), Weight: 1},
+		Services.WeightedText{Text: profile.Description, Weight: 1},
+	)
+	if err := uc.repo.SaveDocument(entities.SearchVendor, profile.VendorID, weights); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to index vendor profile: " + err.Error(),
+		}
+	}
+	return nil
+}
+
+// Reindex rebuilds the search index for every live market and every vendor profile, for data saved before
+// the index existed or after the way text is split has changed.
+func (uc *TextSearchUseCase) Reindex() (*entitiesDtos.ReindexResponse, *entitiesDtos.ErrorResponse) {
+	markets, err := uc.repo.GetMarkets()
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get markets: " + err.Error(),
+		}
+	}
+	profiles, err := uc.repo.GetVendorProfiles()
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get vendor profiles: " + err.Error(),
+		}
+	}
+
+	response := &entitiesDtos.ReindexResponse{}
+	for i := range markets {
+		if errRes := uc.IndexMarket(&markets[i]); errRes != nil {
+			return nil, errRes
+		}
+		response.Markets++
+	}
+	for i := range profiles {
+		if errRes := uc.IndexVendorProfile(&profiles[i]); errRes != nil {
+			return nil, errRes
+		}
+		response.Vendors++
+	}
+	return response, nil
+}
+
+func (uc *TextSearchUseCase) marketHit(market *entities.Market, filter *entitiesDtos.TextSearchFilter) entitiesDtos.TextSearchHit {
+	hit := entitiesDtos.TextSearchHit{
+		Kind:  entities.SearchMarket,
+		ID:    market.ID,
+		Name:  market.Name,
+		Image: market.ListImage(),
+		Highlights: uc.highlights(map[string]string{
+			"name":        market.Name,
+			"address":     market.Address,
+			"description": market.Description,
+		}, filter.Terms),
+	}
+	if filter.Area != nil && market.Latitude != nil && market.Longitude != nil {
+		distance := uc.search.DistanceKm(filter.Area.Lat, filter.Area.Lng, *market.Latitude, *market.Longitude)
+		distance = math.Round(distance*100) / 100
+		hit.DistanceKm = &distance
+	}
+	return hit
+}
+
+func (uc *TextSearchUseCase) vendorHit(profile *entities.VendorProfile, filter *entitiesDtos.TextSearchFilter) entitiesDtos.TextSearchHit {
+	return entitiesDtos.TextSearchHit{
+		Kind:     entities.SearchVendor,
+		ID:       profile.VendorID,
+		Name:     profile.StallName,
+		Image:    profile.ListImage(),
+		Category: profile.Category,
+		Highlights: uc.highlights(map[string]string{
+			"stall_name":  profile.StallName,
+			"products":    strings.Join(profile.Products, ", "),
+			"description": profile.Description,
+		}, filter.Terms),
+	}
+}
+
+func (uc *TextSearchUseCase) highlights(fields map[string]string, terms []string) map[string]string {
+	highlights := make(map[string]string)
+	for name, text := range fields {
+		maxRunes := 0
+		if name == "description" {
+			maxRunes = textSearchSnippet
+		}
+		if highlight := uc.text.Highlight(text, terms, maxRunes); highlight != "" {
+			highlights[name] = highlight
+		}
+	}
+	return highlights
+}
+
+func (uc *TextSearchUseCase) parseTextSearch(query *entitiesDtos.TextSearchQuery) (*entitiesDtos.TextSearchFilter, *entitiesDtos.ErrorResponse) {
+	bad := func(message string) (*entitiesDtos.TextSearchFilter, *entitiesDtos.ErrorResponse) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	q := strings.TrimSpace(query.Q)
+	if utf8.RuneCountInString(q) > textSearchMaxQuery {
+		return bad("A search can be at most " + strconv.Itoa(textSearchMaxQuery) + " characters")
+	}
+	terms := uc.text.Terms(q)
+	if len(terms) == 0 {
+		return bad("Please enter something to search for")
+	}
+	if len(terms) > textSearchMaxTerms {
+		terms = terms[:textSearchMaxTerms]
+	}
+
+	filter := &entitiesDtos.TextSearchFilter{
+		Terms:      terms,
+		MinMatched: int(math.Ceil(float64(len(terms)) * textSearchCoverage)),
+		Page:       1,
+		PageSize:   searchPageSize,
+	}
+	switch query.Type {
+	case "", "all":
+		filter.Kinds = []entities.SearchKind{entities.SearchMarket, entities.SearchVendor}
+	case string(entities.SearchMarket), string(entities.SearchVendor):
+		filter.Kinds = []entities.SearchKind{entities.SearchKind(query.Type)}
+	default:
+		return bad("Search type must be market, vendor or all")
+	}
+	if query.Category != "" {
+		category, err := parseCategory(query.Category)
+		if err != nil {
+			return bad("Invalid category: " + query.Category)
+		}
+		filter.Category = category
+	}
+	if query.Date != "" {
+		if _, err := time.Parse("2006-01-02", query.Date); err != nil {
+			return bad("Invalid date. Please use YYYY-MM-DD")
+		}
+		filter.Date = query.Date
+	}
+	if query.Lat != "" || query.Lng != "" || query.RadiusKm != "" {
+		point, radiusKm, errRes := parseSearchPoint(query.Lat, query.Lng, query.RadiusKm)
+		if errRes != nil {
+			return nil, errRes
+		}
+		area := &entitiesDtos.GeoArea{Lat: point[0], Lng: point[1], RadiusKm: radiusKm}
+		area.MinLat, area.MaxLat, area.MinLng, area.MaxLng = uc.search.BoundingBox(area.Lat, area.Lng, area.RadiusKm)
+		filter.Area = area
+	}
+	if query.Page != "" {
+		page, err := strconv.Atoi(query.Page)
+		if err != nil || page < 1 {
+			return bad("Invalid page: " + query.Page)
+		}
+		filter.Page = page
+	}
+	if query.PageSize != "" {
+		pageSize, err := strconv.Atoi(query.PageSize)
+		if err != nil || pageSize < 1 {
+			return bad("Invalid page_size: " + query.PageSize)
+		}
+		filter.PageSize = pageSize
+	}
+	if filter.PageSize > searchMaxPageSize {
+		filter.PageSize = searchMaxPageSize
+	}
+	return filter, nil
+}
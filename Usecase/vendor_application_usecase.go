@@ -0,0 +1,463 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"log"
+	"strings"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+// maxApplicationFiles caps the photos, and separately the documents, sent with one application.
+const maxApplicationFiles = 10
+
+// applicationDecisions lists what the market may move an application to from each status. Vendors move
+// their own applications back to pending by applying again.
+var applicationDecisions = map[entities.ApplicationStatus][]entities.ApplicationStatus{
+	entities.ApplicationPending:          {entities.ApplicationApproved, entities.ApplicationRejected, entities.ApplicationChangesRequested, entities.ApplicationBlacklisted},
+	entities.ApplicationChangesRequested: {entities.ApplicationRejected, entities.ApplicationBlacklisted},
+	entities.ApplicationApproved:         {entities.ApplicationRevoked, entities.ApplicationBlacklisted},
+	entities.ApplicationRejected:         {entities.ApplicationBlacklisted},
+	entities.ApplicationRevoked:          {entities.ApplicationBlacklisted},
+	entities.ApplicationBlacklisted:      {entities.ApplicationRevoked}, // Lifting the blacklist lets the vendor apply again
+}
+
+type VendorApplicationUseCase struct {
+	repo          Interfaces.IVendorApplication
+	notifications contact.INotificationUseCase
+}
+
+var _ contact.IRosterUseCase = (*VendorApplicationUseCase)(nil)
+
+func NewVendorApplicationUseCase(repo Interfaces.IVendorApplication, notifications contact.INotificationUseCase) *VendorApplicationUseCase {
+	return &VendorApplicationUseCase{
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+// Apply submits a vendor's application to a market's roster, or resubmits it after the market asked for
+// changes, turned it down or revoked it.
+func (uc *VendorApplicationUseCase) Apply(vendorID, marketID string, req *entitiesDtos.VendorApplicationRequest) (*entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	market, err := uc.repo.GetMarket(marketID)
+	if err != nil || market.Status != entities.MarketPublished {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Market not found",
+		}
+	}
+
+	application, errRes := uc.checkApplication(vendorID, req)
+	if errRes != nil {
+		return nil, errRes
+	}
+	now := time.Now()
+	application.SubmittedAt = &now
+
+	existing, err := uc.repo.GetVendorApplication(marketID, vendorID)
+	if err != nil {
+		application.ID = uuid.New().String()
+		application.MarketID = marketID
+		application.VendorID = vendorID
+		event := applicationEvent(application.ID, "", entities.ApplicationPending, "", vendorID, "vendor")
+		if err := uc.repo.CreateApplication(application, event); err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to submit application: " + err.Error(),
+			}
+		}
+		return application, nil
+	}
+
+	switch existing.Status {
+	case entities.ApplicationApproved:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "You are already on this market's roster",
+		}
+	case entities.ApplicationBlacklisted:
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "This market does not accept applications from you: " + existing.ReviewNote,
+		}
+	}
+
+	application.ID = existing.ID
+	application.MarketID = existing.MarketID
+	application.VendorID = existing.VendorID
+	application.CreatedAt = existing.CreatedAt
+	application.ReviewNote = existing.ReviewNote
+	application.ReviewedBy = existing.ReviewedBy
+	application.ReviewedAt = existing.ReviewedAt
+	event := applicationEvent(application.ID, existing.Status, entities.ApplicationPending, "", vendorID, "vendor")
+	if errRes := uc.save(application, existing.Status, event); errRes != nil {
+		return nil, errRes
+	}
+	return application, nil
+}
+
+// Review records the market's decision on an application: approving, rejecting, asking for changes, revoking
+// an approval, blacklisting the vendor or lifting a blacklist. Every decision but approval needs a note for
+// the vendor.
+func (uc *VendorApplicationUseCase) Review(providerID, reviewerID, role, applicationID string, req *entitiesDtos.ApplicationReviewRequest) (*entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	application, err := uc.repo.GetApplication(applicationID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Application not found",
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, application.MarketID, "vendors"); errRes != nil {
+		return nil, errRes
+	}
+
+	to := entities.ApplicationStatus(strings.ToLower(strings.TrimSpace(string(req.Status))))
+	if errRes := uc.decide(application, to, req.Note, reviewerID, role); errRes != nil {
+		return nil, errRes
+	}
+	return application, nil
+}
+
+// Blacklist bars a vendor from a market, whether or not it ever applied.
+func (uc *VendorApplicationUseCase) Blacklist(providerID, reviewerID, role, marketID string, req *entitiesDtos.BlacklistRequest) (*entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "vendors"); errRes != nil {
+		return nil, errRes
+	}
+
+	if application, err := uc.repo.GetVendorApplication(marketID, req.VendorID); err == nil {
+		if errRes := uc.decide(application, entities.ApplicationBlacklisted, req.Reason, reviewerID, role); errRes != nil {
+			return nil, errRes
+		}
+		return application, nil
+	}
+
+	reason, errRes := checkReviewNote(entities.ApplicationBlacklisted, req.Reason)
+	if errRes != nil {
+		return nil, errRes
+	}
+	exists, err := uc.repo.VendorExists(req.VendorID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get vendor: " + err.Error(),
+		}
+	}
+	if !exists {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Vendor not found",
+		}
+	}
+
+	now := time.Now()
+	application := &entities.VendorApplication{
+		ID:          uuid.New().String(),
+		MarketID:    marketID,
+		VendorID:    req.VendorID,
+		Status:      entities.ApplicationBlacklisted,
+		Products:    []string{},
+		PhotoIDs:    []string{},
+		DocumentIDs: []string{},
+		Photos:      []entities.MediaFile{},
+		Documents:   []entities.MediaFile{},
+		ReviewNote:  reason,
+		ReviewedBy:  reviewerID,
+		ReviewedAt:  &now,
+	}
+	event := applicationEvent(application.ID, "", entities.ApplicationBlacklisted, reason, reviewerID, role)
+	if err := uc.repo.CreateApplication(application, event); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to blacklist vendor: " + err.Error(),
+		}
+	}
+	uc.notifyVendor(application)
+	return application, nil
+}
+
+// GetMarketApplications lists a market's applications for its provider. Filtering on approved gives the
+// market's roster.
+func (uc *VendorApplicationUseCase) GetMarketApplications(providerID, marketID, status string) ([]entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "vendors"); errRes != nil {
+		return nil, errRes
+	}
+	filter := entities.ApplicationStatus(strings.ToLower(strings.TrimSpace(status)))
+	if _, ok := applicationDecisions[filter]; filter != "" && !ok {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Unknown application status %q", status),
+		}
+	}
+
+	applications, err := uc.repo.GetMarketApplications(marketID, filter)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve applications: " + err.Error(),
+		}
+	}
+	return applications, nil
+}
+
+// GetApplication returns an application to the provider of its market.
+func (uc *VendorApplicationUseCase) GetApplication(providerID, applicationID string) (*entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	application, err := uc.repo.GetApplication(applicationID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Application not found",
+		}
+	}
+	if errRes := checkMarketOwner(uc.repo, providerID, application.MarketID, "vendors"); errRes != nil {
+		return nil, errRes
+	}
+	return application, nil
+}
+
+// GetApplicationHistory lists every status change of an application, oldest first.
+func (uc *VendorApplicationUseCase) GetApplicationHistory(providerID, applicationID string) ([]entities.VendorApplicationEvent, *entitiesDtos.ErrorResponse) {
+	if _, errRes := uc.GetApplication(providerID, applicationID); errRes != nil {
+		return nil, errRes
+	}
+	events, err := uc.repo.GetApplicationEvents(applicationID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve application history: " + err.Error(),
+		}
+	}
+	return events, nil
+}
+
+func (uc *VendorApplicationUseCase) GetVendorApplications(vendorID string) ([]entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	applications, err := uc.repo.GetVendorApplications(vendorID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve applications: " + err.Error(),
+		}
+	}
+	return applications, nil
+}
+
+// CheckVendor refuses bookings from vendors the market blacklisted, and from vendors off the roster of a
+// roster-only market. vendorID is the signed-in vendor, never one named in the request.
+func (uc *VendorApplicationUseCase) CheckVendor(marketID, vendorID string) *entitiesDtos.ErrorResponse {
+	if vendorID == "" {
+		return &entitiesDtos.ErrorResponse{
+			Code:    401,
+			Message: "Sign in as a vendor to book this market",
+		}
+	}
+	market, err := uc.repo.GetMarket(marketID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Market not found",
+		}
+	}
+
+	status := entities.ApplicationStatus("")
+	if application, err := uc.repo.GetVendorApplication(marketID, vendorID); err == nil {
+		status = application.Status
+	}
+	switch {
+	case status == entities.ApplicationBlacklisted:
+		return &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "This market does not take bookings from you",
+		}
+	case market.RosterOnly && status != entities.ApplicationApproved:
+		return &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "This market only takes bookings from approved vendors; apply to join its roster first",
+		}
+	}
+	return nil
+}
+
+// decide moves an application to a status the market chose and lets the vendor know.
+func (uc *VendorApplicationUseCase) decide(application *entities.VendorApplication, to entities.ApplicationStatus, note, reviewerID, role string) *entitiesDtos.ErrorResponse {
+	allowed := false
+	for _, next := range applicationDecisions[application.Status] {
+		allowed = allowed || next == to
+	}
+	if !allowed {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("A %s application cannot be moved to %q", application.Status, to),
+		}
+	}
+	note, errRes := checkReviewNote(to, note)
+	if errRes != nil {
+		return errRes
+	}
+
+	from := application.Status
+	now := time.Now()
+	application.Status = to
+	application.ReviewNote = note
+	application.ReviewedBy = reviewerID
+	application.ReviewedAt = &now
+	if errRes := uc.save(application, from, applicationEvent(application.ID, from, to, note, reviewerID, role)); errRes != nil {
+		return errRes
+	}
+	uc.notifyVendor(application)
+	return nil
+}
+
+func (uc *VendorApplicationUseCase) save(application *entities.VendorApplication, from entities.ApplicationStatus, event *entities.VendorApplicationEvent) *entitiesDtos.ErrorResponse {
+	updated, err := uc.repo.UpdateApplication(application, from, event)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save application: " + err.Error(),
+		}
+	}
+	if !updated {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: "The application was changed by someone else; reload it and try again",
+		}
+	}
+	return nil
+}
+
+// checkApplication turns what a vendor sent into a pending application, making sure every photo and
+// document is one the vendor uploaded for applications.
+func (uc *VendorApplicationUseCase) checkApplication(vendorID string, req *entitiesDtos.VendorApplicationRequest) (*entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+	bad := func(message string) (*entities.VendorApplication, *entitiesDtos.ErrorResponse) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	stallName := strings.TrimSpace(req.StallName)
+	switch {
+	case stallName == "":
+		return bad("Stall name is required")
+	case utf8.RuneCountInString(stallName) > 100:
+		return bad("Stall name can be at most 100 characters")
+	case len(req.Products) > maxProfileProducts:
+		return bad("An application can list at most 20 products")
+	case len(req.PhotoIDs) > maxApplicationFiles || len(req.DocumentIDs) > maxApplicationFiles:
+		return bad(fmt.Sprintf("An application can have at most %d photos and %d documents", maxApplicationFiles, maxApplicationFiles))
+	}
+
+	application := &entities.VendorApplication{
+		Status:      entities.ApplicationPending,
+		StallName:   stallName,
+		Description: strings.TrimSpace(req.Description),
+		Products:    make([]string, 0, len(req.Products)),
+	}
+	if req.Category != "" {
+		category, err := parseCategory(string(req.Category))
+		if err != nil {
+			return bad("Invalid category: " + err.Error())
+		}
+		application.Category = category
+	}
+	for _, product := range req.Products {
+		if product = strings.TrimSpace(product); product != "" {
+			application.Products = append(application.Products, product)
+		}
+	}
+
+	var errRes *entitiesDtos.ErrorResponse
+	if application.PhotoIDs, errRes = uc.checkFiles(vendorID, entities.MediaApplicationPhoto, req.PhotoIDs); errRes != nil {
+		return nil, errRes
+	}
+	if application.DocumentIDs, errRes = uc.checkFiles(vendorID, entities.MediaApplicationDocument, req.DocumentIDs); errRes != nil {
+		return nil, errRes
+	}
+	application.Photos = entities.MediaFiles(application.PhotoIDs)
+	application.Documents = entities.MediaFiles(application.DocumentIDs)
+	return application, nil
+}
+
+func (uc *VendorApplicationUseCase) checkFiles(vendorID string, kind entities.MediaKind, mediaIDs []string) ([]string, *entitiesDtos.ErrorResponse) {
+	ids := make([]string, 0, len(mediaIDs))
+	seen := make(map[string]bool)
+	for _, id := range mediaIDs {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	media, err := uc.repo.GetVendorMedia(vendorID, kind, ids)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get uploads: " + err.Error(),
+		}
+	}
+	if len(media) != len(ids) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Every file must be one of your own %s uploads", kind),
+		}
+	}
+	return ids, nil
+}
+
+// notifyVendor tells the vendor what the market decided. The decision is already saved, so a failure is
+// only logged.
+func (uc *VendorApplicationUseCase) notifyVendor(application *entities.VendorApplication) {
+	titles := map[entities.ApplicationStatus]string{
+		entities.ApplicationApproved:         "You are on the market's roster",
+		entities.ApplicationRejected:         "Your market application was declined",
+		entities.ApplicationChangesRequested: "Your market application needs changes",
+		entities.ApplicationRevoked:          "Your place on the market's roster was withdrawn",
+		entities.ApplicationBlacklisted:      "You can no longer book at this market",
+	}
+	title, ok := titles[application.Status]
+	if !ok {
+		return
+	}
+	message := title + "."
+	if application.ReviewNote != "" {
+		message = title + ": " + application.ReviewNote
+	}
+	if errRes := uc.notifications.Notify(application.VendorID, entities.NotificationApplication, title, message, application.ID); errRes != nil {
+		log.Printf("Warning: Error notifying vendor %s about application %s: %s", application.VendorID, application.ID, errRes.Message)
+	}
+}
+
+func checkReviewNote(to entities.ApplicationStatus, note string) (string, *entitiesDtos.ErrorResponse) {
+	note = strings.TrimSpace(note)
+	switch {
+	case note == "" && to != entities.ApplicationApproved:
+		return "", &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "A note for the vendor is required unless approving",
+		}
+	case utf8.RuneCountInString(note) > 500:
+		return "", &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Note can be at most 500 characters",
+		}
+	}
+	return note, nil
+}
+
+func applicationEvent(applicationID string, from, to entities.ApplicationStatus, note, actorID, role string) *entities.VendorApplicationEvent {
+	return &entities.VendorApplicationEvent{
+		ID:            uuid.New().String(),
+		ApplicationID: applicationID,
+		FromStatus:    from,
+		ToStatus:      to,
+		Note:          note,
+		ActorID:       actorID,
+		Role:          role,
+	}
+}
@@ -0,0 +1,114 @@
+package Usecase
+
+import (
+	"log"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+	"unicode/utf8"
+)
+
+const maxProfileProducts = 20
+
+type VendorProfileUseCase struct {
+	repo    Interfaces.IVendorProfile
+	index   contact.ISearchIndex
+	ratings contact.IRatingUseCase
+}
+
+func NewVendorProfileUseCase(repo Interfaces.IVendorProfile, index contact.ISearchIndex, ratings contact.IRatingUseCase) *VendorProfileUseCase {
+	return &VendorProfileUseCase{
+		repo:    repo,
+		index:   index,
+		ratings: ratings,
+	}
+}
+
+// SaveProfile sets a vendor's stall profile and refreshes it in the search index.
+func (uc *VendorProfileUseCase) SaveProfile(vendorID string, req *entitiesDtos.VendorProfileRequest) (*entities.VendorProfile, *entitiesDtos.ErrorResponse) {
+	bad := func(message string) (*entities.VendorProfile, *entitiesDtos.ErrorResponse) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	stallName := strings.TrimSpace(req.StallName)
+	switch {
+	case stallName == "":
+		return bad("Stall name is required")
+	case utf8.RuneCountInString(stallName) > 100:
+		return bad("Stall name can be at most 100 characters")
+	case len(req.Products) > maxProfileProducts:
+		return bad("A stall can list at most 20 products")
+	}
+	if message := checkImageURL("Image", req.Image); message != "" {
+		return bad(message)
+	}
+
+	profile := &entities.VendorProfile{
+		VendorID:    vendorID,
+		StallName:   stallName,
+		Description: strings.TrimSpace(req.Description),
+		Products:    make([]string, 0, len(req.Products)),
+		Image:       req.Image,
+		Public:      true,
+	}
+	if existing, err := uc.repo.GetProfile(vendorID); err == nil {
+		profile.CreatedAt = existing.CreatedAt
+		profile.ImageID = existing.ImageID
+	}
+	if req.Category != "" {
+		category, err := parseCategory(string(req.Category))
+		if err != nil {
+			return bad("Invalid category: " + err.Error())
+		}
+		profile.Category = category
+	}
+	for _, product := range req.Products {
+		if product = strings.TrimSpace(product); product != "" {
+			profile.Products = append(profile.Products, product)
+		}
+	}
+	if req.Public != nil {
+		profile.Public = *req.Public
+	}
+
+	if err := uc.repo.SaveProfile(profile); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to save vendor profile: " + err.Error(),
+		}
+	}
+	if errRes := uc.index.IndexVendorProfile(profile); errRes != nil {
+		log.Printf("Warning: failed to index vendor profile %s: %v", vendorID, errRes.Message)
+	}
+
+	return profile, nil
+}
+
+// GetProfile returns a vendor's stall profile. Hidden profiles are only shown to their vendor.
+func (uc *VendorProfileUseCase) GetProfile(vendorID, viewerID string) (*entities.VendorProfile, *entitiesDtos.ErrorResponse) {
+	profile, err := uc.repo.GetProfile(vendorID)
+	if err != nil {
+		code := 500
+		if err.Error() == "vendor profile not found" {
+			code = 404
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    code,
+			Message: "Failed to get vendor profile: " + err.Error(),
+		}
+	}
+	if !profile.Public && viewerID != vendorID {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get vendor profile: vendor profile not found",
+		}
+	}
+	profile.Rating = uc.ratings.VendorRating(vendorID)
+
+	return profile, nil
+}
@@ -0,0 +1,294 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+	"strconv"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/Services"
+	"tln-backend/contact"
+)
+
+type WalletUseCase struct {
+	repo    Interfaces.IWallet
+	ledger  contact.ILedgerUseCase
+	service *Services.WalletService
+}
+
+var _ contact.IWalletUseCase = (*WalletUseCase)(nil)
+
+func NewWalletUseCase(repo Interfaces.IWallet, ledger contact.ILedgerUseCase, service *Services.WalletService) *WalletUseCase {
+	return &WalletUseCase{
+		repo:    repo,
+		ledger:  ledger,
+		service: service,
+	}
+}
+
+// IssueCredit grants goodwill or promotional credit. Providers fund it from their own balance, admins from the platform.
+// Providers can only credit vendors who have booked with them, up to WALLET_PROVIDER_CREDIT_LIMIT unspent per vendor.
+func (uc *WalletUseCase) IssueCredit(issuerType entities.LedgerAccountType, issuerID string, req *entitiesDtos.IssueCreditRequest) (*entities.WalletCredit, *entitiesDtos.ErrorResponse) {
+	if req.VendorID == "" || req.Amount <= 0 {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Vendor ID and a positive amount are required",
+		}
+	}
+	if req.Source != entities.CreditSourceGoodwill && req.Source != entities.CreditSourcePromotion {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Invalid credit source: %s", req.Source),
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: "Expiry date must be in the future",
+		}
+	}
+
+	amount := entities.RoundMoney(req.Amount)
+	if issuerType == entities.AccountProvider {
+		if errRes := uc.checkProviderCredit(issuerID, req.VendorID, amount); errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	credit := &entities.WalletCredit{
+		ID:         uuid.New().String(),
+		VendorID:   req.VendorID,
+		Source:     req.Source,
+		IssuerType: issuerType,
+		IssuerID:   issuerID,
+		Amount:     amount,
+		Remaining:  amount,
+		Note:       req.Note,
+		ExpiresAt:  req.ExpiresAt,
+	}
+
+	if err := uc.repo.CreateCredit(credit); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to issue credit: " + err.Error(),
+		}
+	}
+
+	if errRes := uc.ledger.RecordCreditIssued(credit); errRes != nil {
+		return nil, errRes
+	}
+
+	return credit, nil
+}
+
+// checkProviderCredit keeps providers to vendors they know and to a bounded unspent balance per vendor.
+func (uc *WalletUseCase) checkProviderCredit(providerID, vendorID string, amount float64) *entitiesDtos.ErrorResponse {
+	booked, err := uc.repo.HasProviderBooking(providerID, vendorID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check vendor bookings: " + err.Error(),
+		}
+	}
+	if !booked {
+		return &entitiesDtos.ErrorResponse{
+			Code:    403,
+			Message: "You can only issue credit to vendors who have booked in your markets",
+		}
+	}
+
+	issued, err := uc.repo.GetIssuedBalance(providerID, vendorID, time.Now())
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check issued credit: " + err.Error(),
+		}
+	}
+	if limit := providerCreditLimit(); entities.RoundMoney(issued+amount) > limit {
+		return &entitiesDtos.ErrorResponse{
+			Code:    422,
+			Message: fmt.Sprintf("This vendor would hold %.2f of your unspent credit; the limit is %.2f", entities.RoundMoney(issued+amount), limit),
+		}
+	}
+
+	return nil
+}
+
+// IssueRefundCredit turns the cash part of a refunded booking into provider-funded credit.
+// The ledger side is posted by the booking refund itself.
+func (uc *WalletUseCase) IssueRefundCredit(booking *entities.Booking, amount float64) *entitiesDtos.ErrorResponse {
+	amount = entities.RoundMoney(amount)
+	if amount <= 0 {
+		return nil
+	}
+
+	providerID, err := uc.repo.GetMarketProviderID(booking.MarketID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to resolve market provider: " + err.Error(),
+		}
+	}
+
+	credit := &entities.WalletCredit{
+		ID:         uuid.New().String(),
+		VendorID:   booking.VendorID,
+		Source:     entities.CreditSourceRefund,
+		IssuerType: entities.AccountProvider,
+		IssuerID:   providerID,
+		BookingID:  booking.ID,
+		Amount:     amount,
+		Remaining:  amount,
+		Note:       fmt.Sprintf("Refund for booking on %s", booking.BookingDate.Format("2006-01-02")),
+		ExpiresAt:  refundCreditExpiry(),
+	}
+
+	if err := uc.repo.CreateCredit(credit); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to issue refund credit: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// RedeemCredit spends the vendor's credit on a booking in the market. Provider-funded credit only pays at that
+// provider's own markets.
+func (uc *WalletUseCase) RedeemCredit(vendorID, marketID, bookingID string, amount float64) (float64, *entitiesDtos.ErrorResponse) {
+	providerID, err := uc.repo.GetMarketProviderID(marketID)
+	if err != nil {
+		return 0, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get market: " + err.Error(),
+		}
+	}
+
+	redeemed, err := uc.repo.RedeemCredit(vendorID, providerID, bookingID, entities.RoundMoney(amount), time.Now())
+	if err != nil {
+		return 0, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to redeem wallet credit: " + err.Error(),
+		}
+	}
+
+	return redeemed, nil
+}
+
+func (uc *WalletUseCase) RestoreCredit(bookingID string) *entitiesDtos.ErrorResponse {
+	if _, err := uc.repo.RestoreCredit(bookingID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to restore wallet credit: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// GetBalance shows a vendor's credit to the vendor, admins and providers the vendor has booked with.
+func (uc *WalletUseCase) GetBalance(userID, role, vendorID string) (*entitiesDtos.WalletBalanceResponse, *entitiesDtos.ErrorResponse) {
+	if errRes := uc.checkReader(userID, role, vendorID); errRes != nil {
+		return nil, errRes
+	}
+
+	credits, err := uc.repo.GetActiveCredits(vendorID, time.Now())
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve wallet credits: " + err.Error(),
+		}
+	}
+
+	var balance float64
+	for _, credit := range credits {
+		balance += credit.Remaining
+	}
+
+	return &entitiesDtos.WalletBalanceResponse{
+		VendorID: vendorID,
+		Balance:  entities.RoundMoney(balance),
+		Credits:  credits,
+	}, nil
+}
+
+// GetVendorTransactions shows a vendor's wallet history to the same callers as GetBalance.
+func (uc *WalletUseCase) GetVendorTransactions(userID, role, vendorID string) ([]entities.WalletTransaction, *entitiesDtos.ErrorResponse) {
+	if errRes := uc.checkReader(userID, role, vendorID); errRes != nil {
+		return nil, errRes
+	}
+
+	transactions, err := uc.repo.GetTransactionsByVendor(vendorID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve wallet history: " + err.Error(),
+		}
+	}
+
+	return transactions, nil
+}
+
+// checkReader lets the vendor itself and admins see a wallet, and providers with a booking from the vendor in
+// one of their markets.
+func (uc *WalletUseCase) checkReader(userID, role, vendorID string) *entitiesDtos.ErrorResponse {
+	switch role {
+	case "admin":
+		return nil
+	case "vendor":
+		if userID == vendorID {
+			return nil
+		}
+	case "provider":
+		booked, err := uc.repo.HasProviderBooking(userID, vendorID)
+		if err != nil {
+			return &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to check vendor bookings: " + err.Error(),
+			}
+		}
+		if booked {
+			return nil
+		}
+	}
+	return &entitiesDtos.ErrorResponse{
+		Code:    403,
+		Message: "You are not authorized to view this wallet",
+	}
+}
+
+func (uc *WalletUseCase) GetIssuerTransactions(issuerID string) ([]entities.WalletTransaction, *entitiesDtos.ErrorResponse) {
+	transactions, err := uc.repo.GetTransactionsByIssuer(issuerID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to retrieve wallet history: " + err.Error(),
+		}
+	}
+
+	return transactions, nil
+}
+
+// providerCreditLimit reads WALLET_PROVIDER_CREDIT_LIMIT, the most goodwill and promotion credit one provider may
+// have outstanding with one vendor. It defaults to 5000 baht.
+func providerCreditLimit() float64 {
+	limit, err := strconv.ParseFloat(os.Getenv("WALLET_PROVIDER_CREDIT_LIMIT"), 64)
+	if err != nil || limit <= 0 {
+		return 5000
+	}
+	return limit
+}
+
+// refundCreditExpiry reads WALLET_REFUND_EXPIRY_DAYS. Refund credit never expires when it is unset.
+func refundCreditExpiry() *time.Time {
+	days, err := strconv.Atoi(os.Getenv("WALLET_REFUND_EXPIRY_DAYS"))
+	if err != nil || days <= 0 {
+		return nil
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, days)
+	return &expiresAt
+}
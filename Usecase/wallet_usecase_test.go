@@ -0,0 +1,188 @@
+package Usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+// fakeWallet holds credit lots in memory and knows which vendors have booked with which providers.
+type fakeWallet struct {
+	Interfaces.IWallet
+	lots      []entities.WalletCredit
+	customers map[string][]string // provider ID to the vendors who booked with it
+	redeemed  map[string]float64
+}
+
+func (f *fakeWallet) CreateCredit(credit *entities.WalletCredit) error {
+	f.lots = append(f.lots, *credit)
+	return nil
+}
+
+func (f *fakeWallet) HasProviderBooking(providerID, vendorID string) (bool, error) {
+	for _, customer := range f.customers[providerID] {
+		if customer == vendorID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeWallet) GetIssuedBalance(issuerID, vendorID string, now time.Time) (float64, error) {
+	var balance float64
+	for _, lot := range f.lots {
+		if lot.IssuerID == issuerID && lot.VendorID == vendorID && lot.Source != entities.CreditSourceRefund {
+			balance += lot.Remaining
+		}
+	}
+	return entities.RoundMoney(balance), nil
+}
+
+func (f *fakeWallet) GetMarketProviderID(marketID string) (string, error) {
+	providers := map[string]string{"m1": "p1", "m2": "p2"}
+	if providerID, ok := providers[marketID]; ok {
+		return providerID, nil
+	}
+	return "", fmt.Errorf("market not found")
+}
+
+func (f *fakeWallet) RedeemCredit(vendorID, providerID, bookingID string, amount float64, now time.Time) (float64, error) {
+	var vendorLots []entities.WalletCredit
+	for _, lot := range f.lots {
+		if lot.VendorID == vendorID {
+			vendorLots = append(vendorLots, lot)
+		}
+	}
+	var redeemed float64
+	for _, take := range entities.PlanRedemption(vendorLots, providerID, amount, now) {
+		f.redeemed[take.Credit.ID] += take.Amount
+		redeemed = entities.RoundMoney(redeemed + take.Amount)
+	}
+	return redeemed, nil
+}
+
+type fakeLedgerUseCase struct {
+	contact.ILedgerUseCase
+	issued int
+}
+
+func (f *fakeLedgerUseCase) RecordCreditIssued(credit *entities.WalletCredit) *entitiesDtos.ErrorResponse {
+	f.issued++
+	return nil
+}
+
+func TestWalletIssueCredit(t *testing.T) {
+	t.Setenv("WALLET_PROVIDER_CREDIT_LIMIT", "1000")
+	held := entities.WalletCredit{ID: "held", VendorID: "v1", Source: entities.CreditSourceGoodwill, IssuerType: entities.AccountProvider,
+		IssuerID: "p1", Amount: 800, Remaining: 800}
+	refund := entities.WalletCredit{ID: "refund", VendorID: "v1", Source: entities.CreditSourceRefund, IssuerType: entities.AccountProvider,
+		IssuerID: "p1", Amount: 5000, Remaining: 5000}
+
+	tests := []struct {
+		name       string
+		issuerType entities.LedgerAccountType
+		issuerID   string
+		vendorID   string
+		amount     float64
+		source     entities.WalletCreditSource
+		lots       []entities.WalletCredit
+		wantCode   int
+	}{
+		{name: "provider credits a customer", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v1", amount: 200},
+		{name: "provider credits a stranger", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v2", amount: 200, wantCode: 403},
+		{name: "up to the limit", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v1", amount: 200, lots: []entities.WalletCredit{held}},
+		{name: "over the limit", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v1", amount: 200.01, lots: []entities.WalletCredit{held}, wantCode: 422},
+		{name: "refund credit does not count", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v1", amount: 1000, lots: []entities.WalletCredit{refund}},
+		{name: "single grant over the limit", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v1", amount: 1500, wantCode: 422},
+		{name: "admin credits anyone without a limit", issuerType: entities.AccountPlatform, issuerID: entities.PlatformOwnerID, vendorID: "v2", amount: 1500},
+		{name: "refund is not a source to issue", issuerType: entities.AccountPlatform, issuerID: entities.PlatformOwnerID, vendorID: "v2", amount: 10,
+			source: entities.CreditSourceRefund, wantCode: 400},
+		{name: "amount must be positive", issuerType: entities.AccountProvider, issuerID: "p1", vendorID: "v1", amount: 0, wantCode: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeWallet{lots: append([]entities.WalletCredit{}, tt.lots...), customers: map[string][]string{"p1": {"v1"}}}
+			ledger := &fakeLedgerUseCase{}
+			uc := &WalletUseCase{repo: repo, ledger: ledger}
+			source := tt.source
+			if source == "" {
+				source = entities.CreditSourceGoodwill
+			}
+
+			credit, errRes := uc.IssueCredit(tt.issuerType, tt.issuerID, &entitiesDtos.IssueCreditRequest{VendorID: tt.vendorID, Amount: tt.amount, Source: source})
+			if tt.wantCode != 0 {
+				if errRes == nil || errRes.Code != tt.wantCode {
+					t.Fatalf("IssueCredit() error = %v, want code %d", errRes, tt.wantCode)
+				}
+				if len(repo.lots) != len(tt.lots) || ledger.issued != 0 {
+					t.Errorf("a refused grant was stored")
+				}
+				return
+			}
+			if errRes != nil {
+				t.Fatalf("IssueCredit() error = %v", errRes)
+			}
+			if credit.IssuerType != tt.issuerType || credit.IssuerID != tt.issuerID || credit.Remaining != tt.amount {
+				t.Errorf("credit = %+v", credit)
+			}
+			if ledger.issued != 1 {
+				t.Errorf("ledger postings = %d, want 1", ledger.issued)
+			}
+		})
+	}
+}
+
+func TestWalletRedeemCreditByMarket(t *testing.T) {
+	lots := []entities.WalletCredit{
+		{ID: "p1-lot", VendorID: "v1", IssuerType: entities.AccountProvider, IssuerID: "p1", Remaining: 100},
+		{ID: "p2-lot", VendorID: "v1", IssuerType: entities.AccountProvider, IssuerID: "p2", Remaining: 100},
+		{ID: "platform-lot", VendorID: "v1", IssuerType: entities.AccountPlatform, IssuerID: entities.PlatformOwnerID, Remaining: 50},
+	}
+
+	tests := []struct {
+		name     string
+		marketID string
+		amount   float64
+		want     float64
+		wantLots map[string]float64
+		wantCode int
+	}{
+		{name: "first provider's market", marketID: "m1", amount: 300, want: 150, wantLots: map[string]float64{"p1-lot": 100, "platform-lot": 50}},
+		{name: "second provider's market", marketID: "m2", amount: 120, want: 120, wantLots: map[string]float64{"p2-lot": 100, "platform-lot": 20}},
+		{name: "unknown market", marketID: "gone", amount: 10, wantCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeWallet{lots: lots, redeemed: make(map[string]float64)}
+			uc := &WalletUseCase{repo: repo}
+
+			got, errRes := uc.RedeemCredit("v1", tt.marketID, "b1", tt.amount)
+			if tt.wantCode != 0 {
+				if errRes == nil || errRes.Code != tt.wantCode {
+					t.Fatalf("RedeemCredit() error = %v, want code %d", errRes, tt.wantCode)
+				}
+				return
+			}
+			if errRes != nil {
+				t.Fatalf("RedeemCredit() error = %v", errRes)
+			}
+			if got != tt.want {
+				t.Errorf("RedeemCredit() = %.2f, want %.2f", got, tt.want)
+			}
+			if len(repo.redeemed) != len(tt.wantLots) {
+				t.Errorf("spent from %v, want %v", repo.redeemed, tt.wantLots)
+			}
+			for id, want := range tt.wantLots {
+				if repo.redeemed[id] != want {
+					t.Errorf("spent %.2f from %s, want %.2f", repo.redeemed[id], id, want)
+				}
+			}
+		})
+	}
+}
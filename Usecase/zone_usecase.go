@@ -0,0 +1,359 @@
+package Usecase
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+	entities "tln-backend/Entities"
+	entitiesDtos "tln-backend/Entities/dtos"
+	"tln-backend/Interfaces"
+	"tln-backend/contact"
+)
+
+type ZoneUseCase struct {
+	repo Interfaces.IZone
+}
+
+var _ contact.IZoneUseCase = (*ZoneUseCase)(nil)
+
+var zoneColour = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func NewZoneUseCase(repo Interfaces.IZone) *ZoneUseCase {
+	return &ZoneUseCase{repo: repo}
+}
+
+func (uc *ZoneUseCase) CreateZone(providerID, marketID string, req *entitiesDtos.ZoneRequest) (*entities.Zone, *entitiesDtos.ErrorResponse) {
+	if errRes := checkMarketOwner(uc.repo, providerID, marketID, "zones"); errRes != nil {
+		return nil, errRes
+	}
+
+	zone := &entities.Zone{
+		ID:       uuid.New().String(),
+		MarketID: marketID,
+	}
+	if errRes := fillZone(zone, req); errRes != nil {
+		return nil, errRes
+	}
+	if errRes := uc.checkNameFree(zone); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.repo.CreateZone(zone); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to create zone: " + err.Error(),
+		}
+	}
+
+	return zone, nil
+}
+
+func (uc *ZoneUseCase) UpdateZone(providerID, zoneID string, req *entitiesDtos.ZoneRequest) (*entities.Zone, *entitiesDtos.ErrorResponse) {
+	zone, errRes := uc.ownZone(providerID, zoneID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	name := zone.Name
+	if errRes := fillZone(zone, req); errRes != nil {
+		return nil, errRes
+	}
+	if zone.Name != name {
+		// The name is part of every slot ID laid out in the zone, so it is fixed once stalls exist
+		inUse, err := uc.repo.CountZoneSlots(zone.ID)
+		if err != nil {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to check zone slots: " + err.Error(),
+			}
+		}
+		if inUse > 0 {
+			return nil, &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: "A zone with stalls laid out cannot be renamed",
+			}
+		}
+		if errRes := uc.checkNameFree(zone); errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	if err := uc.repo.SaveZone(zone); err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to update zone: " + err.Error(),
+		}
+	}
+
+	return zone, nil
+}
+
+func (uc *ZoneUseCase) DeleteZone(providerID, zoneID string) *entitiesDtos.ErrorResponse {
+	zone, errRes := uc.ownZone(providerID, zoneID)
+	if errRes != nil {
+		return errRes
+	}
+
+	inUse, err := uc.repo.CountZoneSlots(zone.ID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to check zone slots: " + err.Error(),
+		}
+	}
+	if inUse > 0 {
+		return &entitiesDtos.ErrorResponse{
+			Code:    409,
+			Message: fmt.Sprintf("Zone %s still has %d stalls in layouts or templates; remove them first", zone.Name, inUse),
+		}
+	}
+
+	if err := uc.repo.DeleteZone(zone.ID); err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to delete zone: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (uc *ZoneUseCase) GetZones(marketID string) ([]entities.Zone, *entitiesDtos.ErrorResponse) {
+	zones, err := uc.repo.GetZones(marketID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get zones: " + err.Error(),
+		}
+	}
+
+	return zones, nil
+}
+
+// CheckBooking enforces the zones' category restrictions and caps on the slots of one booking. Layouts are
+// checked when applied, but a provider can tighten a zone after stalls have been laid out. A group's own stalls
+// count towards the cap together, on top of what the zone already has booked.
+func (uc *ZoneUseCase) CheckBooking(slots ...*entities.Slot) *entitiesDtos.ErrorResponse {
+	for _, load := range entities.ZoneLoads(slots) {
+		zone, err := uc.repo.GetZone(load.ZoneID)
+		if err != nil {
+			return &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to get zone: " + err.Error(),
+			}
+		}
+
+		if !zone.Allows(load.Category) {
+			return &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("Zone %s no longer takes %s stalls", zone.Name, load.Category),
+			}
+		}
+
+		limit := zone.Cap(load.Category)
+		if limit == 0 {
+			continue
+		}
+		booked, err := uc.repo.CountZoneBookings(zone.ID, load.Date, load.Category, load.SlotIDs)
+		if err != nil {
+			return &entitiesDtos.ErrorResponse{
+				Code:    500,
+				Message: "Failed to check zone bookings: " + err.Error(),
+			}
+		}
+		if !zone.Fits(load.Category, int(booked), len(load.SlotIDs)) {
+			return &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("Zone %s has %d of its %d %s stalls booked for %s, no room for %d more", zone.Name, booked, limit, load.Category, load.Date, len(load.SlotIDs)),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *ZoneUseCase) checkNameFree(zone *entities.Zone) *entitiesDtos.ErrorResponse {
+	zones, err := uc.repo.GetZones(zone.MarketID)
+	if err != nil {
+		return &entitiesDtos.ErrorResponse{
+			Code:    500,
+			Message: "Failed to get zones: " + err.Error(),
+		}
+	}
+	for _, other := range zones {
+		if other.ID != zone.ID && strings.EqualFold(other.Name, zone.Name) {
+			return &entitiesDtos.ErrorResponse{
+				Code:    409,
+				Message: fmt.Sprintf("The market already has a zone named %s", other.Name),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *ZoneUseCase) ownZone(providerID, zoneID string) (*entities.Zone, *entitiesDtos.ErrorResponse) {
+	zone, err := uc.repo.GetZone(zoneID)
+	if err != nil {
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    404,
+			Message: "Failed to get zone: " + err.Error(),
+		}
+	}
+
+	if errRes := checkMarketOwner(uc.repo, providerID, zone.MarketID, "zones"); errRes != nil {
+		return nil, errRes
+	}
+
+	return zone, nil
+}
+
+func fillZone(zone *entities.Zone, req *entitiesDtos.ZoneRequest) *entitiesDtos.ErrorResponse {
+	name := strings.TrimSpace(req.Name)
+	message := ""
+	switch {
+	case name == "":
+		message = "Zone name is required"
+	case strings.Contains(name, "/"):
+		message = "Zone name cannot contain '/'"
+	case req.Colour != "" && !zoneColour.MatchString(req.Colour):
+		message = "Zone colour must look like #1a2b3c"
+	case req.DefaultPrice < 0 || req.DefaultWidth < 0 || req.DefaultHeight < 0:
+		message = "Zone defaults cannot be negative"
+	}
+	if message != "" {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: message,
+		}
+	}
+
+	allowed := make([]entities.Category, 0, len(req.AllowedCategories))
+	for _, category := range req.AllowedCategories {
+		parsed, err := parseCategory(string(category))
+		if err != nil {
+			return &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Invalid allowed category: " + err.Error(),
+			}
+		}
+		allowed = append(allowed, parsed)
+	}
+	caps := make(map[entities.Category]int, len(req.CategoryCaps))
+	for category, limit := range req.CategoryCaps {
+		parsed, err := parseCategory(string(category))
+		if err != nil {
+			return &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: "Invalid capped category: " + err.Error(),
+			}
+		}
+		if limit < 0 {
+			return &entitiesDtos.ErrorResponse{
+				Code:    400,
+				Message: fmt.Sprintf("Cap for %s cannot be negative", parsed),
+			}
+		}
+		if limit > 0 {
+			caps[parsed] = limit
+		}
+	}
+
+	zone.Name = name
+	zone.Colour = req.Colour
+	zone.Description = req.Description
+	zone.DefaultPrice = entities.RoundMoney(req.DefaultPrice)
+	zone.DefaultWidth = req.DefaultWidth
+	zone.DefaultHeight = req.DefaultHeight
+	zone.AllowedCategories = allowed
+	zone.CategoryCaps = caps
+	return nil
+}
+
+// layoutZones resolves the zones a layout or template names and counts the stalls placed in each, so a layout
+// cannot exceed the zone's caps.
+type layoutZones struct {
+	byID   map[string]*entities.Zone
+	byName map[string]*entities.Zone
+	placed map[string]map[entities.Category]int
+}
+
+func newLayoutZones(zones []entities.Zone) *layoutZones {
+	lz := &layoutZones{
+		byID:   make(map[string]*entities.Zone, len(zones)),
+		byName: make(map[string]*entities.Zone, len(zones)),
+		placed: make(map[string]map[entities.Category]int),
+	}
+	for i := range zones {
+		lz.byID[zones[i].ID] = &zones[i]
+		lz.byName[strings.ToLower(zones[i].Name)] = &zones[i]
+	}
+	return lz
+}
+
+// resolve finds a zone by ID, falling back to its name for clients that still send the zone's text.
+func (lz *layoutZones) resolve(zoneID, name string) (*entities.Zone, *entitiesDtos.ErrorResponse) {
+	if zoneID != "" {
+		if zone, ok := lz.byID[zoneID]; ok {
+			return zone, nil
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Zone %s does not belong to this market", zoneID),
+		}
+	}
+	if name != "" {
+		if zone, ok := lz.byName[strings.ToLower(strings.TrimSpace(name))]; ok {
+			return zone, nil
+		}
+		return nil, &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Zone %q does not exist; create it before laying out stalls", name),
+		}
+	}
+	return nil, &entitiesDtos.ErrorResponse{
+		Code:    400,
+		Message: "Each zone needs a zone_id",
+	}
+}
+
+// withDefaults fills in what a stall left out from its zone.
+func withDefaults(zone *entities.Zone, stall entitiesDtos.Stall) entitiesDtos.Stall {
+	if stall.Width == 0 {
+		stall.Width = zone.DefaultWidth
+	}
+	if stall.Height == 0 {
+		stall.Height = zone.DefaultHeight
+	}
+	if stall.Price == 0 {
+		stall.Price = zone.DefaultPrice
+	}
+	if stall.StallType == "" && len(zone.AllowedCategories) == 1 {
+		stall.StallType = string(zone.AllowedCategories[0])
+	}
+	return stall
+}
+
+// admit checks one more stall of the category against the zone. scope is the date and zone for a layout,
+// or only the zone for a template, whose stalls repeat on every date.
+func (lz *layoutZones) admit(zone *entities.Zone, scope, stallName string, category entities.Category) *entitiesDtos.ErrorResponse {
+	if !zone.Allows(category) {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Zone %s does not take %s stalls like %s", zone.Name, category, stallName),
+		}
+	}
+
+	if lz.placed[scope] == nil {
+		lz.placed[scope] = make(map[entities.Category]int)
+	}
+	lz.placed[scope][category]++
+	if limit := zone.Cap(category); limit > 0 && lz.placed[scope][category] > limit {
+		return &entitiesDtos.ErrorResponse{
+			Code:    400,
+			Message: fmt.Sprintf("Zone %s takes at most %d %s stalls", zone.Name, limit, category),
+		}
+	}
+	return nil
+}
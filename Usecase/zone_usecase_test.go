@@ -0,0 +1,122 @@
+package Usecase
+
+import (
+	"strings"
+	"testing"
+	entities "tln-backend/Entities"
+	"tln-backend/Interfaces"
+)
+
+// fakeZoneRepo knows its zones and which slots are already booked.
+type fakeZoneRepo struct {
+	Interfaces.IZone
+	zones  map[string]*entities.Zone
+	booked []*entities.Slot
+}
+
+func (f *fakeZoneRepo) GetZone(zoneID string) (*entities.Zone, error) {
+	return f.zones[zoneID], nil
+}
+
+func (f *fakeZoneRepo) CountZoneBookings(zoneID, date string, category entities.Category, excludeSlotIDs []string) (int64, error) {
+	var count int64
+	for _, slot := range f.booked {
+		excluded := false
+		for _, id := range excludeSlotIDs {
+			excluded = excluded || id == slot.ID
+		}
+		if !excluded && slot.ZoneID == zoneID && slot.Date == date && slot.Category == category {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestZoneCheckBooking(t *testing.T) {
+	stall := func(id, zoneID string, category entities.Category) *entities.Slot {
+		return &entities.Slot{ID: id, ZoneID: zoneID, Category: category, Date: "2024-06-01"}
+	}
+	zones := map[string]*entities.Zone{
+		"food-court": {ID: "food-court", Name: "Food court", AllowedCategories: []entities.Category{entities.CategoryFood},
+			CategoryCaps: map[entities.Category]int{entities.CategoryFood: 3}},
+		"hall": {ID: "hall", Name: "Hall", CategoryCaps: map[entities.Category]int{entities.CategoryClothes: 2}},
+	}
+
+	tests := []struct {
+		name     string
+		booked   []*entities.Slot
+		slots    []*entities.Slot
+		wantCode int
+		wantText string
+	}{
+		{name: "one stall in an empty zone", slots: []*entities.Slot{stall("f1", "food-court", entities.CategoryFood)}},
+		{
+			name:   "last place taken by a single stall",
+			booked: []*entities.Slot{stall("f1", "food-court", entities.CategoryFood), stall("f2", "food-court", entities.CategoryFood)},
+			slots:  []*entities.Slot{stall("f3", "food-court", entities.CategoryFood)},
+		},
+		{
+			name:     "zone full",
+			booked:   []*entities.Slot{stall("f1", "food-court", entities.CategoryFood), stall("f2", "food-court", entities.CategoryFood), stall("f3", "food-court", entities.CategoryFood)},
+			slots:    []*entities.Slot{stall("f4", "food-court", entities.CategoryFood)},
+			wantCode: 409,
+			wantText: "Food court",
+		},
+		{
+			name:   "group fits exactly",
+			booked: []*entities.Slot{stall("f1", "food-court", entities.CategoryFood)},
+			slots:  []*entities.Slot{stall("f2", "food-court", entities.CategoryFood), stall("f3", "food-court", entities.CategoryFood)},
+		},
+		{
+			name:   "group larger than the place left",
+			booked: []*entities.Slot{stall("f1", "food-court", entities.CategoryFood), stall("f2", "food-court", entities.CategoryFood)},
+			slots: []*entities.Slot{stall("f3", "food-court", entities.CategoryFood), stall("f4", "food-court", entities.CategoryFood),
+				stall("f5", "food-court", entities.CategoryFood), stall("f6", "food-court", entities.CategoryFood)},
+			wantCode: 409,
+			wantText: "no room for 4 more",
+		},
+		{
+			name: "group over the cap of an empty zone",
+			slots: []*entities.Slot{stall("c1", "hall", entities.CategoryClothes), stall("c2", "hall", entities.CategoryClothes),
+				stall("c3", "hall", entities.CategoryClothes)},
+			wantCode: 409,
+		},
+		{
+			name: "group split across categories",
+			slots: []*entities.Slot{stall("c1", "hall", entities.CategoryClothes), stall("c2", "hall", entities.CategoryClothes),
+				stall("x1", "hall", entities.CategoryCrafts), stall("x2", "hall", entities.CategoryCrafts)},
+		},
+		{
+			name:   "another day does not count",
+			booked: []*entities.Slot{{ID: "c0", ZoneID: "hall", Category: entities.CategoryClothes, Date: "2024-06-08"}},
+			slots:  []*entities.Slot{stall("c1", "hall", entities.CategoryClothes), stall("c2", "hall", entities.CategoryClothes)},
+		},
+		{
+			name:     "category not allowed",
+			slots:    []*entities.Slot{stall("f1", "food-court", entities.CategoryClothes)},
+			wantCode: 409,
+			wantText: "no longer takes clothes",
+		},
+		{name: "stall outside any zone", slots: []*entities.Slot{stall("s1", "", entities.CategoryFood)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &ZoneUseCase{repo: &fakeZoneRepo{zones: zones, booked: tt.booked}}
+
+			errRes := uc.CheckBooking(tt.slots...)
+			if tt.wantCode == 0 {
+				if errRes != nil {
+					t.Fatalf("CheckBooking() error = %v", errRes)
+				}
+				return
+			}
+			if errRes == nil || errRes.Code != tt.wantCode {
+				t.Fatalf("CheckBooking() error = %v, want code %d", errRes, tt.wantCode)
+			}
+			if !strings.Contains(errRes.Message, tt.wantText) {
+				t.Errorf("message %q does not mention %q", errRes.Message, tt.wantText)
+			}
+		})
+	}
+}
@@ -14,14 +14,49 @@ type IBookingService interface {
 type ISlotUseCase interface {
 	UpdateSlotStatus(slotID, vendorID string, status entities.SlotStatus) (*entities.Slot, *entitiesDtos.ErrorResponse)
 }
+
+type ILedgerUseCase interface {
+	RecordCharge(bookingID string) *entitiesDtos.ErrorResponse
+	RecordRefund(bookingID string, toWallet bool) *entitiesDtos.ErrorResponse
+	RecordCreditIssued(credit *entities.WalletCredit) *entitiesDtos.ErrorResponse
+	RecordCreditExpired(credit *entities.WalletCredit, expiry *entities.WalletTransaction) *entitiesDtos.ErrorResponse
+}
+
+type IWalletUseCase interface {
+	RedeemCredit(vendorID, marketID, bookingID string, amount float64) (float64, *entitiesDtos.ErrorResponse)
+	RestoreCredit(bookingID string) *entitiesDtos.ErrorResponse
+	IssueRefundCredit(booking *entities.Booking, amount float64) *entitiesDtos.ErrorResponse
+}
+type IPromotionUseCase interface {
+	ApplyPromotion(code string, slot *entities.Slot, vendorID, bookingDate, bookingID, paymentID string) (*entities.Redemption, *entitiesDtos.ErrorResponse)
+	ReleasePromotion(bookingID string) *entitiesDtos.ErrorResponse
+}
+type ILayoutVersionUseCase interface {
+	RecordSlotChanges(createdBy, marketID, note string, before, after []*entities.Slot) *entitiesDtos.ErrorResponse
+}
+type ILayoutTemplateUseCase interface {
+	StampTemplate(templateID string, dates []string) (*entitiesDtos.TemplateApplyResponse, *entitiesDtos.ErrorResponse)
+	UnstampTemplate(templateID string, dates []string) ([]string, *entitiesDtos.ErrorResponse)
+}
 type IBooking interface {
 	CreateBooking(booking *entities.Booking) error
 	GetBookingsByMarket(marketID string) ([]entities.Booking, error)
 	//IsBookingExists(bookingReq *entitiesDtos.BookingRequest) (bool, error)
 	GetBooking(bookingID string) (*entities.Booking, error)
 	UpdateBookingStatus(bookingID string, status entities.BookingStatus) (*entities.Booking, error)
-	IsSlotAvailable(bookingReq *entitiesDtos.BookingRequest) error
+	IsSlotAvailable(bookingReq *entitiesDtos.BookingRequest) (*entities.Slot, error)
 	GetBookingsByUser(userID string) ([]entities.Booking, error)
+	GetActiveBookingsByMarketAndDate(marketID, date string) ([]entities.Booking, error)
+	GetSlotsByIDs(slotIDs []string) ([]*entities.Slot, error)
+	CreateBookingGroup(group *entities.BookingGroup, bookings []*entities.Booking) error
+	GetBookingGroup(groupID string) (*entities.BookingGroup, error)
+	GetGroupMemberSlotIDs(leadBookingID string) ([]string, error)
+	GetDaySlots(marketID, date string) ([]*entities.Slot, error)
+	GetDayBookings(marketID, date string) ([]entities.Booking, error)
+	GetMarketProviderID(marketID string) (string, error)
+	GetMarket(marketID string) (*entities.Market, error)
+	CheckInBooking(bookingID, checkedInBy string, at time.Time) (bool, error)
+	ExpireSlips(bookingID string) error
 }
 
 type IPayment interface {
@@ -33,3 +68,44 @@ type IPayment interface {
 	CreatePayment(payment *entities.Payment) error
 	UpdateTransaction(TransactionID string, Status entities.TransactionStatus) (*entities.Transaction, error)
 }
+type IPricingUseCase interface {
+	PriceSlot(slot *entities.Slot, at time.Time) (*entitiesDtos.PriceQuote, *entitiesDtos.ErrorResponse)
+	PriceGroup(slots []*entities.Slot, at time.Time) (*entitiesDtos.GroupQuote, *entitiesDtos.ErrorResponse)
+}
+type IZoneUseCase interface {
+	CheckBooking(slots ...*entities.Slot) *entitiesDtos.ErrorResponse
+}
+type IBookingUseCase interface {
+	CancelBooking(cancelBookingReq *entitiesDtos.CancelBookingRequest) (*entitiesDtos.BookingResponse, *entitiesDtos.ErrorResponse)
+}
+type ISearchIndex interface {
+	IndexMarket(market *entities.Market) *entitiesDtos.ErrorResponse
+	IndexVendorProfile(profile *entities.VendorProfile) *entitiesDtos.ErrorResponse
+}
+type IMediaStore interface {
+	StoreImage(kind entities.MediaKind, targetID string, data []byte) (*entities.Media, *entitiesDtos.ErrorResponse)
+}
+type IMarketUseCase interface {
+	CheckBookable(marketID string) *entitiesDtos.ErrorResponse
+}
+type IRosterUseCase interface {
+	CheckVendor(marketID, vendorID string) *entitiesDtos.ErrorResponse
+}
+type IRatingUseCase interface {
+	MarketRating(marketID string) *entities.RatingSummary
+	VendorRating(vendorID string) *entities.RatingSummary
+}
+type ITermsUseCase interface {
+	CheckAccepted(marketID, vendorID string) (*entities.TermsAcceptance, *entitiesDtos.ErrorResponse)
+}
+type IOpeningHoursUseCase interface {
+	CheckOpen(marketID, date string) *entitiesDtos.ErrorResponse
+}
+type IAmenityUseCase interface {
+	PriceAddOns(marketID string, choices []entitiesDtos.AddOnChoice) ([]entities.AddOn, float64, *entitiesDtos.ErrorResponse)
+	CheckSlotAmenities(marketID string, amenityIDs []string) ([]string, *entitiesDtos.ErrorResponse)
+	AmenityNames(marketID string) (map[string]string, *entitiesDtos.ErrorResponse)
+}
+type INotificationUseCase interface {
+	Notify(userID string, kind entities.NotificationKind, title, message, refID string) *entitiesDtos.ErrorResponse
+}
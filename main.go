@@ -30,12 +30,12 @@ func main() {
 		log.Fatal(err)
 	}
 
-	allHandlers, userRepo, providerRepo, err := App.InitializeHandlers(db)
+	allHandlers, userRepo, providerRepo, staffRepo, adminRepo, err := App.InitializeHandlers(db)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	server := App.InitializeServer(userRepo, providerRepo)
+	server := App.InitializeServer(userRepo, providerRepo, staffRepo, adminRepo)
 	server.MapHandlers(allHandlers)
 
 	address := fmt.Sprintf("%s:%s", config.App.Host, config.App.Port)